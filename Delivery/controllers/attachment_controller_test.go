@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type attachmentControllerSuite struct {
+	suite.Suite
+	usecase       *mocks.AttachmentUsecase
+	testingServer *httptest.Server
+}
+
+func (suite *attachmentControllerSuite) SetupTest() {
+	suite.usecase = new(mocks.AttachmentUsecase)
+	controller := NewAttachmentController(suite.usecase)
+
+	router := gin.Default()
+	router.Use(func(c *gin.Context) { c.Set("user_id", "u1") })
+	router.POST("/tasks/:id/attachments", controller.Upload)
+	router.GET("/tasks/:id/attachments/:attachment_id", controller.Download)
+	suite.testingServer = httptest.NewServer(router)
+}
+
+func (suite *attachmentControllerSuite) TearDownTest() {
+	suite.testingServer.Close()
+}
+
+func (suite *attachmentControllerSuite) TestUpload_StreamsFilePart() {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("note", "ignored")
+	part, _ := form.CreateFormFile("file", "notes.txt")
+	part.Write([]byte("hello"))
+	form.Close()
+
+	suite.usecase.On("Upload", mock.Anything, "t1", "notes.txt", mock.Anything, "u1").
+		Return(func(_ context.Context, _ string, _ string, r io.Reader, _ string) (*domain.Attachment, error) {
+			content, _ := io.ReadAll(r)
+			return &domain.Attachment{Filename: "notes.txt", Size: int64(len(content))}, nil
+		})
+
+	response, err := http.Post(fmt.Sprintf("%s/tasks/t1/attachments", suite.testingServer.URL), form.FormDataContentType(), &body)
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusCreated, response.StatusCode)
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *attachmentControllerSuite) TestUpload_TooLarge() {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "big.bin")
+	part.Write([]byte("payload"))
+	form.Close()
+	suite.usecase.On("Upload", mock.Anything, "t1", "big.bin", mock.Anything, "u1").Return(nil, domain.ErrAttachmentTooLarge)
+
+	response, err := http.Post(fmt.Sprintf("%s/tasks/t1/attachments", suite.testingServer.URL), form.FormDataContentType(), &body)
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusRequestEntityTooLarge, response.StatusCode)
+}
+
+func (suite *attachmentControllerSuite) TestDownload_Range() {
+	file, err := os.CreateTemp(suite.T().TempDir(), "blob")
+	suite.Require().NoError(err)
+	file.WriteString("0123456789")
+	file.Seek(0, io.SeekStart)
+	suite.usecase.On("Open", mock.Anything, "t1", "a1").Return(&domain.Attachment{Filename: "page.html", Content_type: "text/html; charset=utf-8", Checksum: "abc"}, file, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/tasks/t1/attachments/a1", suite.testingServer.URL), nil)
+	req.Header.Set("Range", "bytes=2-5")
+	response, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	content, _ := io.ReadAll(response.Body)
+	suite.Equal(http.StatusPartialContent, response.StatusCode)
+	suite.Equal("2345", string(content))
+	suite.Equal(`attachment; filename=page.html`, response.Header.Get("Content-Disposition"), "HTML is never rendered inline")
+	suite.Equal("nosniff", response.Header.Get("X-Content-Type-Options"))
+}
+
+func (suite *attachmentControllerSuite) TestDownload_NotFound() {
+	suite.usecase.On("Open", mock.Anything, "t1", "missing").Return(nil, nil, domain.ErrAttachmentNotFound)
+
+	response, err := http.Get(fmt.Sprintf("%s/tasks/t1/attachments/missing", suite.testingServer.URL))
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusNotFound, response.StatusCode)
+}
+
+func TestAttachmentController(t *testing.T) {
+	suite.Run(t, new(attachmentControllerSuite))
+}
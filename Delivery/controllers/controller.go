@@ -1,8 +1,15 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 	domain "task-manger-api_test/Domain"
 
 	"github.com/gin-gonic/gin"
@@ -16,59 +23,1426 @@ type UserController struct {
 	UserUsecase domain.UserUsecase
 }
 
+type OrganizationController struct {
+	OrganizationUsecase domain.OrganizationUsecase
+	UserUsecase         domain.UserUsecase
+}
+
+type InvitationController struct {
+	InvitationUsecase domain.InvitationUsecase
+}
+
+type ImpersonationController struct {
+	ImpersonationUsecase domain.ImpersonationUsecase
+}
+
+type CommentController struct {
+	CommentUsecase domain.CommentUsecase
+}
+
+type AttachmentController struct {
+	AttachmentUsecase domain.AttachmentUsecase
+}
+
+type TimeTrackingController struct {
+	TimeTrackingUsecase domain.TimeTrackingUsecase
+}
+
+type CustomFieldController struct {
+	CustomFieldUsecase domain.CustomFieldUsecase
+}
+
+type ViewController struct {
+	ViewUsecase domain.ViewUsecase
+}
+
+type TaskSearchController struct {
+	TaskSearchUsecase domain.TaskSearchUsecase
+}
+
+type TaskBulkController struct {
+	TaskBulkUsecase domain.TaskBulkUsecase
+}
+
+type TaskImportController struct {
+	TaskImportUsecase domain.TaskImportUsecase
+}
+
+type CalendarController struct {
+	CalendarUsecase domain.CalendarUsecase
+}
+
+type WebhookController struct {
+	WebhookUsecase domain.WebhookUsecase
+}
+
+type TaskTemplateController struct {
+	TaskTemplateUsecase domain.TaskTemplateUsecase
+}
+
+type PrivacyController struct {
+	PrivacyUsecase domain.PrivacyUsecase
+}
+
+type OIDCController struct {
+	Provider    domain.IdentityProvider
+	UserUsecase domain.UserUsecase
+}
+
 func NewTaskController(taskUsecase domain.TaskUsecase) domain.TaskController {
 	return &TaskController{
 		TaskUsecase: taskUsecase,
 	}
 }
 
-func NewUserController(userUsecase domain.UserUsecase) domain.UserController {
-	return &UserController{
-		UserUsecase: userUsecase,
+func NewUserController(userUsecase domain.UserUsecase) domain.UserController {
+	return &UserController{
+		UserUsecase: userUsecase,
+	}
+}
+func NewOIDCController(provider domain.IdentityProvider, userUsecase domain.UserUsecase) domain.OIDCController {
+	return &OIDCController{
+		Provider:    provider,
+		UserUsecase: userUsecase,
+	}
+}
+
+func NewInvitationController(invitationUsecase domain.InvitationUsecase) domain.InvitationController {
+	return &InvitationController{
+		InvitationUsecase: invitationUsecase,
+	}
+}
+
+func NewImpersonationController(impersonationUsecase domain.ImpersonationUsecase) domain.ImpersonationController {
+	return &ImpersonationController{
+		ImpersonationUsecase: impersonationUsecase,
+	}
+}
+
+func NewCommentController(commentUsecase domain.CommentUsecase) domain.CommentController {
+	return &CommentController{
+		CommentUsecase: commentUsecase,
+	}
+}
+
+func NewAttachmentController(attachmentUsecase domain.AttachmentUsecase) domain.AttachmentController {
+	return &AttachmentController{
+		AttachmentUsecase: attachmentUsecase,
+	}
+}
+
+func NewTimeTrackingController(timeTrackingUsecase domain.TimeTrackingUsecase) domain.TimeTrackingController {
+	return &TimeTrackingController{
+		TimeTrackingUsecase: timeTrackingUsecase,
+	}
+}
+
+func NewCustomFieldController(customFieldUsecase domain.CustomFieldUsecase) domain.CustomFieldController {
+	return &CustomFieldController{
+		CustomFieldUsecase: customFieldUsecase,
+	}
+}
+
+func NewViewController(viewUsecase domain.ViewUsecase) domain.ViewController {
+	return &ViewController{
+		ViewUsecase: viewUsecase,
+	}
+}
+
+func NewTaskSearchController(taskSearchUsecase domain.TaskSearchUsecase) domain.TaskSearchController {
+	return &TaskSearchController{
+		TaskSearchUsecase: taskSearchUsecase,
+	}
+}
+
+func NewTaskTemplateController(taskTemplateUsecase domain.TaskTemplateUsecase) domain.TaskTemplateController {
+	return &TaskTemplateController{
+		TaskTemplateUsecase: taskTemplateUsecase,
+	}
+}
+
+func NewPrivacyController(privacyUsecase domain.PrivacyUsecase) domain.PrivacyController {
+	return &PrivacyController{
+		PrivacyUsecase: privacyUsecase,
+	}
+}
+
+func NewOrganizationController(organizationUsecase domain.OrganizationUsecase, userUsecase domain.UserUsecase) domain.OrganizationController {
+	return &OrganizationController{
+		OrganizationUsecase: organizationUsecase,
+		UserUsecase:         userUsecase,
+	}
+}
+
+//user controllers
+func (uc *UserController) Signup(c *gin.Context){
+	var user domain.User
+
+	if err := c.BindJSON(&user); err != nil{
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	result := uc.UserUsecase.Create(c, &user)
+	if result != nil{
+		c.JSON(errorStatus(result, http.StatusBadRequest), domain.ErrorResponse{Message: result.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{
+		Message: "User registered successfully",
+	})
+}
+
+func (uc *UserController) Login(c *gin.Context){
+	var user domain.User
+	if err := c.BindJSON(&user); err != nil{
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	token, refreshToken, err := uc.UserUsecase.HandleLogin(domain.WithClient(c, clientInfo(c)), &user)
+	if err != nil{
+		c.JSON(http.StatusBadRequest, gin.H{"error":err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User loged in successfully!", "token":token, "refresh_token":refreshToken})
+}
+
+func (uc *UserController) PromoteUser(c *gin.Context){
+	var userID = c.Param("id")
+	err := uc.UserUsecase.Update(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{Message: "User promoted to ADMIN"})
+}
+
+func (uc *UserController) Me(c *gin.Context){
+	user, err := uc.UserUsecase.FetchByUserID(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get profile", Data: user})
+}
+
+func (uc *UserController) UpdateMe(c *gin.Context){
+	var update domain.ProfileUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	user, err := uc.UserUsecase.UpdateProfile(c, c.GetString("user_id"), update)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Profile updated", Data: user})
+}
+
+func (uc *UserController) ChangePassword(c *gin.Context){
+	var change domain.PasswordChange
+	if err := c.ShouldBindJSON(&change); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := uc.UserUsecase.ChangePassword(c, c.GetString("user_id"), change); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Password changed"})
+}
+
+func (uc *UserController) DeleteMe(c *gin.Context){
+	var body struct {
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "password is required to delete your account"})
+		return
+	}
+
+	if err := uc.UserUsecase.DeleteAccount(c, c.GetString("user_id"), body.Password); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Account deleted"})
+}
+
+func (uc *UserController) FetchSessions(c *gin.Context){
+	sessions, err := uc.UserUsecase.FetchSessions(c, c.GetString("user_id"), c.GetString("session_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get sessions", Data: sessions})
+}
+
+func (uc *UserController) RevokeSession(c *gin.Context){
+	if err := uc.UserUsecase.RevokeSession(c, c.GetString("user_id"), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Session revoked"})
+}
+
+func (uc *UserController) FetchAll(c *gin.Context){
+	page, _ := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	filter := domain.UserFilter{
+		Query: c.Query("q"),
+		User_type: c.Query("user_type"),
+		Page: page,
+		Limit: limit,
+	}
+
+	users, err := uc.UserUsecase.FetchAll(c, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get users", Data: users})
+}
+
+func (uc *UserController) FetchByUserID(c *gin.Context){
+	userID := c.Param("id")
+	user, err := uc.UserUsecase.FetchByUserID(c, userID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: fmt.Sprintf("Success to get user with id %v", userID), Data: user})
+}
+
+func (uc *UserController) Deactivate(c *gin.Context){
+	uc.adminAction(c, "deactivate", func(userID string) error { return uc.UserUsecase.SetActive(c, userID, false) }, "User deactivated")
+}
+
+func (uc *UserController) Reactivate(c *gin.Context){
+	uc.adminAction(c, "reactivate", func(userID string) error { return uc.UserUsecase.SetActive(c, userID, true) }, "User reactivated")
+}
+
+func (uc *UserController) Demote(c *gin.Context){
+	uc.adminAction(c, "demote", func(userID string) error { return uc.UserUsecase.Demote(c, userID) }, "User demoted to USER")
+}
+
+func (uc *UserController) Delete(c *gin.Context){
+	uc.adminAction(c, "delete", func(userID string) error { return uc.UserUsecase.Delete(c, userID) }, "User deleted")
+}
+
+// adminAction runs an admin-only change against the user in the :id param,
+// refusing to let admins lock themselves out.
+func (uc *UserController) adminAction(c *gin.Context, verb string, action func(userID string) error, message string){
+	userID := c.Param("id")
+	if userID == c.GetString("user_id") {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: fmt.Sprintf("you cannot %s your own account", verb)})
+		return
+	}
+
+	if err := action(userID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: message})
+}
+
+// clientInfo describes the device behind a sign-in, for its session record.
+func clientInfo(c *gin.Context) domain.ClientInfo {
+	return domain.ClientInfo{User_agent: c.Request.UserAgent(), Ip: c.ClientIP()}
+}
+
+// errorStatus maps well-known domain errors to their HTTP status.
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrInvitationNotFound),
+		errors.Is(err, domain.ErrSessionNotFound), errors.Is(err, domain.ErrTaskNotFound),
+		errors.Is(err, domain.ErrCommentNotFound), errors.Is(err, domain.ErrAttachmentNotFound),
+		errors.Is(err, domain.ErrChecklistItemNotFound), errors.Is(err, domain.ErrNoRunningTimer),
+		errors.Is(err, domain.ErrTimeEntryNotFound), errors.Is(err, domain.ErrCustomFieldNotFound),
+		errors.Is(err, domain.ErrTaskTemplateNotFound), errors.Is(err, domain.ErrViewNotFound),
+		errors.Is(err, domain.ErrImportJobNotFound), errors.Is(err, domain.ErrCalendarFeedNotFound),
+		errors.Is(err, domain.ErrWebhookNotFound), errors.Is(err, domain.ErrWebhookDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrIncorrectPassword), errors.Is(err, domain.ErrUserDeactivated),
+		errors.Is(err, domain.ErrNotMember), errors.Is(err, domain.ErrNoTenant), errors.Is(err, domain.ErrOwnerRequired),
+		errors.Is(err, domain.ErrRegistrationClosed), errors.Is(err, domain.ErrCannotImpersonate),
+		errors.Is(err, domain.ErrNotCommentAuthor), errors.Is(err, domain.ErrNotUploader),
+		errors.Is(err, domain.ErrNotTimeEntryOwner), errors.Is(err, domain.ErrNotViewOwner):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrLastOwner), errors.Is(err, domain.ErrEmailTaken), errors.Is(err, domain.ErrEmailNotVerified),
+		errors.Is(err, domain.ErrInvitationPending), errors.Is(err, domain.ErrUserErased),
+		errors.Is(err, domain.ErrWebhookDisabled):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrChecklistChanged), errors.Is(err, domain.ErrTimerRunning),
+		errors.Is(err, domain.ErrCustomFieldExists), errors.Is(err, domain.ErrCustomFieldInUse):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidChecklistOrder), errors.Is(err, domain.ErrInvalidTimeEntry),
+		errors.Is(err, domain.ErrInvalidTimeRange), errors.Is(err, domain.ErrInvalidCustomField),
+		errors.Is(err, domain.ErrInvalidTaskQuery), errors.Is(err, domain.ErrInvalidTaskTemplate),
+		errors.Is(err, domain.ErrInvalidSearch), errors.Is(err, domain.ErrInvalidFilter),
+		errors.Is(err, domain.ErrInvalidView), errors.Is(err, domain.ErrInvalidBulkRequest),
+		errors.Is(err, domain.ErrInvalidImport), errors.Is(err, domain.ErrInvalidWebhook):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrMissingTemplateVariable):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrInvitationExpired):
+		return http.StatusGone
+	case errors.Is(err, domain.ErrAttachmentTooLarge), errors.Is(err, domain.ErrBulkTooLarge),
+		errors.Is(err, domain.ErrImportTooLarge):
+		return http.StatusRequestEntityTooLarge
+	}
+	return fallback
+}
+
+// taskQueryError answers a failed task listing or search, pointing at the
+// offending character of a filter expression.
+func taskQueryError(c *gin.Context, err error) {
+	var filterErr *domain.FilterError
+	if errors.As(err, &filterErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "position": filterErr.Position})
+		return
+	}
+	c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+}
+
+// organization controllers
+func (oc *OrganizationController) Create(c *gin.Context){
+	var org domain.Organization
+	if err := c.ShouldBindJSON(&org); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := oc.OrganizationUsecase.Create(c, &org, c.GetString("user_id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Organization created successfully", Data: org})
+}
+
+func (oc *OrganizationController) FetchMine(c *gin.Context){
+	orgs, err := oc.OrganizationUsecase.FetchForUser(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get organizations", Data: orgs})
+}
+
+func (oc *OrganizationController) Switch(c *gin.Context){
+	token, refreshToken, err := oc.UserUsecase.SwitchOrganization(c, c.GetString("user_id"), c.GetString("session_id"), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Switched organization", "token": token, "refresh_token": refreshToken})
+}
+
+func (oc *OrganizationController) FetchMembers(c *gin.Context){
+	members, err := oc.OrganizationUsecase.FetchMembers(c, c.GetString("org_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get members", Data: members})
+}
+
+func (oc *OrganizationController) AddMember(c *gin.Context){
+	var membership domain.Membership
+	if err := c.ShouldBindJSON(&membership); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if membership.Role == domain.OrgRoleOwner && c.GetString("org_role") != domain.OrgRoleOwner {
+		c.JSON(http.StatusForbidden, domain.ErrorResponse{Message: "only owners can add owners"})
+		return
+	}
+
+	membership.Org_id = c.GetString("org_id")
+	if err := oc.OrganizationUsecase.AddMember(c, &membership); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Member added", Data: membership})
+}
+
+func (oc *OrganizationController) UpdateMemberRole(c *gin.Context){
+	var body struct {
+		Role string `json:"role" binding:"required,oneof=OWNER ADMIN MEMBER"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := oc.OrganizationUsecase.UpdateMemberRole(c, c.GetString("org_id"), c.Param("user_id"), body.Role); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Member role updated"})
+}
+
+func (oc *OrganizationController) RemoveMember(c *gin.Context){
+	if err := oc.OrganizationUsecase.RemoveMember(c, c.GetString("org_id"), c.Param("user_id"), c.GetString("org_role")); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Member removed"})
+}
+
+// invitation controllers
+func (ic *InvitationController) Invite(c *gin.Context){
+	var invitation domain.Invitation
+	if err := c.ShouldBindJSON(&invitation); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := ic.InvitationUsecase.Invite(c, &invitation, c.GetString("user_id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Invitation sent", Data: invitation})
+}
+
+func (ic *InvitationController) FetchAll(c *gin.Context){
+	invitations, err := ic.InvitationUsecase.FetchAll(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get invitations", Data: invitations})
+}
+
+func (ic *InvitationController) Resend(c *gin.Context){
+	if err := ic.InvitationUsecase.Resend(c, c.Param("id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Invitation resent"})
+}
+
+func (ic *InvitationController) Revoke(c *gin.Context){
+	if err := ic.InvitationUsecase.Revoke(c, c.Param("id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Invitation revoked"})
+}
+
+func (ic *InvitationController) Accept(c *gin.Context){
+	var acceptance domain.InvitationAcceptance
+	if err := c.ShouldBindJSON(&acceptance); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	user, err := ic.InvitationUsecase.Accept(c, acceptance)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "User registered successfully", Data: user})
+}
+
+// impersonation controllers
+func (ic *ImpersonationController) Impersonate(c *gin.Context){
+	token, err := ic.ImpersonationUsecase.Impersonate(c, c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Impersonation started", "token": token})
+}
+
+func (ic *ImpersonationController) FetchAuditLog(c *gin.Context){
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "100"), 10, 64)
+	filter := domain.AuditFilter{
+		Actor_id: c.Query("actor_id"),
+		Subject_id: c.Query("subject_id"),
+		Limit: limit,
+	}
+
+	entries, err := ic.ImpersonationUsecase.FetchAuditLog(c, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get audit log", Data: entries})
+}
+
+// checklist controllers
+func (u *TaskController) AddChecklistItem(c *gin.Context) {
+	var body struct {
+		domain.ChecklistItem
+		// Position is the zero-based index to insert at; items are appended
+		// when it is missing.
+		Position *int `json:"position" binding:"omitempty,min=0"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	task, err := u.TaskUsecase.AddChecklistItem(c, c.Param("id"), body.ChecklistItem, body.Position)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Checklist item added", Data: task})
+}
+
+func (u *TaskController) UpdateChecklistItem(c *gin.Context) {
+	var update domain.ChecklistItemUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if update.Text != nil && *update.Text == "" {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "text cannot be empty"})
+		return
+	}
+
+	task, err := u.TaskUsecase.UpdateChecklistItem(c, c.Param("id"), c.Param("item_id"), update)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Checklist item updated", Data: task})
+}
+
+func (u *TaskController) ToggleChecklistItem(c *gin.Context) {
+	task, err := u.TaskUsecase.ToggleChecklistItem(c, c.Param("id"), c.Param("item_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Checklist item toggled", Data: task})
+}
+
+func (u *TaskController) ReorderChecklist(c *gin.Context) {
+	var body struct {
+		Item_ids []string `json:"item_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	task, err := u.TaskUsecase.ReorderChecklist(c, c.Param("id"), body.Item_ids)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Checklist reordered", Data: task})
+}
+
+func (u *TaskController) DeleteChecklistItem(c *gin.Context) {
+	task, err := u.TaskUsecase.DeleteChecklistItem(c, c.Param("id"), c.Param("item_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Checklist item deleted", Data: task})
+}
+
+// comment controllers
+func (cc *CommentController) Create(c *gin.Context){
+	var comment domain.Comment
+	if err := c.ShouldBindJSON(&comment); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	comment.Author_id = c.GetString("user_id")
+	if err := cc.CommentUsecase.Create(c, c.Param("id"), &comment); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Comment added", Data: comment})
+}
+
+func (cc *CommentController) FetchByTask(c *gin.Context){
+	page, _ := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+
+	comments, err := cc.CommentUsecase.FetchByTask(c, c.Param("id"), page, limit)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get comments", Data: comments})
+}
+
+func (cc *CommentController) Update(c *gin.Context){
+	var body struct {
+		Body string `json:"body" binding:"required,max=10000"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	comment, err := cc.CommentUsecase.Update(c, c.Param("id"), c.Param("comment_id"), c.GetString("user_id"), body.Body)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Comment updated", Data: comment})
+}
+
+func (cc *CommentController) Delete(c *gin.Context){
+	role := c.GetString("org_role")
+	moderator := role == domain.OrgRoleOwner || role == domain.OrgRoleAdmin
+
+	if err := cc.CommentUsecase.Delete(c, c.Param("id"), c.Param("comment_id"), c.GetString("user_id"), moderator); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Comment deleted"})
+}
+
+// attachment controllers
+
+// inlineContentTypes may be shown in the browser; anything else, HTML in
+// particular, is served as a download.
+var inlineContentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// Upload streams the "file" part of a multipart form straight to storage.
+func (ac *AttachmentController) Upload(c *gin.Context){
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "missing file field"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		attachment, err := ac.AttachmentUsecase.Upload(c, c.Param("id"), part.FileName(), part, c.GetString("user_id"))
+		part.Close()
+		if err != nil {
+			c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Attachment uploaded", Data: attachment})
+		return
+	}
+}
+
+func (ac *AttachmentController) FetchByTask(c *gin.Context){
+	attachments, err := ac.AttachmentUsecase.FetchByTask(c, c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get attachments", Data: attachments})
+}
+
+// Download serves the attachment with support for Range and conditional
+// requests.
+func (ac *AttachmentController) Download(c *gin.Context){
+	attachment, blob, err := ac.AttachmentUsecase.Open(c, c.Param("id"), c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	defer blob.Close()
+
+	disposition := "attachment"
+	if inlineContentTypes[attachment.Content_type] {
+		disposition = "inline"
+	}
+	c.Header("Content-Type", attachment.Content_type)
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("ETag", `"`+attachment.Checksum+`"`)
+	http.ServeContent(c.Writer, c.Request, attachment.Filename, attachment.Created_at, blob)
+}
+
+func (ac *AttachmentController) Delete(c *gin.Context){
+	role := c.GetString("org_role")
+	moderator := role == domain.OrgRoleOwner || role == domain.OrgRoleAdmin
+
+	if err := ac.AttachmentUsecase.Delete(c, c.Param("id"), c.Param("attachment_id"), c.GetString("user_id"), moderator); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Attachment deleted"})
+}
+
+// time tracking controllers
+func (tc *TimeTrackingController) StartTimer(c *gin.Context){
+	var body struct {
+		Note string `json:"note" binding:"max=500"`
+	}
+	// the body is optional
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+	}
+
+	entry, err := tc.TimeTrackingUsecase.StartTimer(c, c.Param("id"), c.GetString("user_id"), body.Note)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Timer started", Data: entry})
+}
+
+func (tc *TimeTrackingController) StopTimer(c *gin.Context){
+	entry, err := tc.TimeTrackingUsecase.StopTimer(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Timer stopped", Data: entry})
+}
+
+func (tc *TimeTrackingController) FetchRunning(c *gin.Context){
+	entry, err := tc.TimeTrackingUsecase.FetchRunning(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Timer running", Data: entry})
+}
+
+func (tc *TimeTrackingController) LogTime(c *gin.Context){
+	var entry domain.TimeEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	entry.User_id = c.GetString("user_id")
+	if err := tc.TimeTrackingUsecase.LogTime(c, c.Param("id"), &entry); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Time logged", Data: entry})
+}
+
+func (tc *TimeTrackingController) FetchByTask(c *gin.Context){
+	entries, err := tc.TimeTrackingUsecase.FetchByTask(c, c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get time entries", Data: entries})
+}
+
+func (tc *TimeTrackingController) DeleteEntry(c *gin.Context){
+	role := c.GetString("org_role")
+	moderator := role == domain.OrgRoleOwner || role == domain.OrgRoleAdmin
+
+	if err := tc.TimeTrackingUsecase.DeleteEntry(c, c.Param("entry_id"), c.GetString("user_id"), moderator); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Time entry deleted"})
+}
+
+// Report totals logged time per user, task or day. Members only see their
+// own time; owners and admins may report on anyone.
+func (tc *TimeTrackingController) Report(c *gin.Context){
+	var query struct {
+		Group_by string `form:"group_by" binding:"omitempty,oneof=user task day"`
+		From     string `form:"from"`
+		To       string `form:"to"`
+		User_id  string `form:"user_id"`
+		Task_id  string `form:"task_id"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	from, err := parseReportTime(query.From)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "invalid from: " + err.Error()})
+		return
+	}
+	to, err := parseReportTime(query.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "invalid to: " + err.Error()})
+		return
+	}
+
+	filter := domain.TimeReportFilter{Group_by: query.Group_by, From: from, To: to, User_id: query.User_id, Task_id: query.Task_id}
+	role := c.GetString("org_role")
+	if role != domain.OrgRoleOwner && role != domain.OrgRoleAdmin {
+		filter.User_id = c.GetString("user_id")
+	}
+
+	report, err := tc.TimeTrackingUsecase.Report(c, filter)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get time report", Data: report})
+}
+
+// parseReportTime accepts RFC 3339 timestamps and plain dates, read as UTC
+// midnight. An empty value gives the zero time.
+func parseReportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// custom field controllers
+func (cc *CustomFieldController) Create(c *gin.Context){
+	var field domain.CustomField
+	if err := c.ShouldBindJSON(&field); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := cc.CustomFieldUsecase.Create(c, &field); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Custom field created", Data: field})
+}
+
+func (cc *CustomFieldController) FetchAll(c *gin.Context){
+	fields, err := cc.CustomFieldUsecase.FetchAll(c)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get custom fields", Data: fields})
+}
+
+func (cc *CustomFieldController) Update(c *gin.Context){
+	var update domain.CustomFieldUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	field, err := cc.CustomFieldUsecase.Update(c, c.Param("id"), update)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Custom field updated", Data: field})
+}
+
+func (cc *CustomFieldController) Delete(c *gin.Context){
+	if err := cc.CustomFieldUsecase.Delete(c, c.Param("id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Custom field deleted"})
+}
+
+// webhook controllers
+func (wc *WebhookController) Create(c *gin.Context){
+	var webhook domain.Webhook
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	webhook.Created_by = c.GetString("user_id")
+	if err := wc.WebhookUsecase.Create(c, &webhook); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Webhook created, store the secret now as it is not shown again", Data: webhook})
+}
+
+func (wc *WebhookController) FetchAll(c *gin.Context){
+	webhooks, err := wc.WebhookUsecase.FetchAll(c)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
 	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get webhooks", Data: webhooks})
 }
-//user controllers
-func (uc *UserController) Signup(c *gin.Context){
-	var user domain.User
 
-	if err := c.BindJSON(&user); err != nil{
+func (wc *WebhookController) FetchByID(c *gin.Context){
+	webhook, err := wc.WebhookUsecase.FetchByID(c, c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get webhook", Data: webhook})
+}
+
+func (wc *WebhookController) Update(c *gin.Context){
+	var update domain.WebhookUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
 		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	result := uc.UserUsecase.Create(c, &user)
-	if result != nil{
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: result.Error()})
+	webhook, err := wc.WebhookUsecase.Update(c, c.Param("id"), update)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, domain.SuccessResponse{
-		Message: "User registered successfully",
-	})
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Webhook updated", Data: webhook})
 }
 
-func (uc *UserController) Login(c *gin.Context){
-	var user domain.User
-	if err := c.BindJSON(&user); err != nil{
+func (wc *WebhookController) Delete(c *gin.Context){
+	if err := wc.WebhookUsecase.Delete(c, c.Param("id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Webhook deleted"})
+}
+
+func (wc *WebhookController) FetchDeliveries(c *gin.Context){
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	deliveries, err := wc.WebhookUsecase.FetchDeliveries(c, c.Param("id"), limit)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get deliveries", Data: deliveries})
+}
+
+func (wc *WebhookController) Redeliver(c *gin.Context){
+	delivery, err := wc.WebhookUsecase.Redeliver(c, c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, domain.SuccessResponse{Success: true, Message: "Redelivery queued", Data: delivery})
+}
+
+// view controllers
+func (vc *ViewController) Create(c *gin.Context){
+	var view domain.View
+	if err := c.ShouldBindJSON(&view); err != nil {
 		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
 		return
 	}
-	token, refreshToken, err := uc.UserUsecase.HandleLogin(c, &user)
-	if err != nil{
-		c.JSON(http.StatusBadRequest, gin.H{"error":err.Error()})
+
+	view.Owner_id = c.GetString("user_id")
+	if err := vc.ViewUsecase.Create(c, &view); err != nil {
+		taskQueryError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "User loged in successfully!", "token":token, "refresh_token":refreshToken})
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "View created", Data: view})
 }
 
-func (uc *UserController) PromoteUser(c *gin.Context){
-	var userID = c.Param("id")
-	err := uc.UserUsecase.Update(c, userID)
+func (vc *ViewController) FetchAll(c *gin.Context){
+	views, err := vc.ViewUsecase.FetchAll(c, c.GetString("user_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get views", Data: views})
+}
 
-	c.JSON(http.StatusOK, domain.SuccessResponse{Message: "User promoted to ADMIN"})
+func (vc *ViewController) FetchByID(c *gin.Context){
+	view, err := vc.ViewUsecase.FetchByID(c, c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get view", Data: view})
+}
+
+func (vc *ViewController) Update(c *gin.Context){
+	var update domain.ViewUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	view, err := vc.ViewUsecase.Update(c, c.Param("id"), c.GetString("user_id"), update)
+	if err != nil {
+		taskQueryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "View updated", Data: view})
+}
+
+func (vc *ViewController) Delete(c *gin.Context){
+	role := c.GetString("org_role")
+	moderator := role == domain.OrgRoleOwner || role == domain.OrgRoleAdmin
+
+	if err := vc.ViewUsecase.Delete(c, c.Param("id"), c.GetString("user_id"), moderator); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "View deleted"})
+}
+
+func (vc *ViewController) SetDefault(c *gin.Context){
+	if err := vc.ViewUsecase.SetDefault(c, c.Param("id"), c.GetString("user_id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Default view set"})
+}
+
+func (vc *ViewController) ClearDefault(c *gin.Context){
+	if err := vc.ViewUsecase.ClearDefault(c, c.GetString("user_id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Default view cleared"})
+}
+
+func (vc *ViewController) FetchTasks(c *gin.Context){
+	result, err := vc.ViewUsecase.FetchTasks(c, c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		taskQueryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get view tasks", Data: result})
+}
+
+// task search controllers
+func (sc *TaskSearchController) Search(c *gin.Context){
+	page, _ := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	request := domain.TaskSearchRequest{
+		Q:      c.Query("q"),
+		Status: c.Query("status"),
+		Label:  c.Query("label"),
+		Filter: c.Query("filter"),
+		Page:   page,
+		Limit:  limit,
+	}
+
+	result, err := sc.TaskSearchUsecase.Search(c, request)
+	if err != nil {
+		taskQueryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to search tasks", Data: result})
+}
+
+// task bulk controllers
+func (bc *TaskBulkController) Execute(c *gin.Context){
+	var request domain.BulkTaskRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	result, err := bc.TaskBulkUsecase.Execute(c, c.GetString("user_id"), request)
+	if err != nil {
+		taskQueryError(c, err)
+		return
+	}
+	if !result.Committed {
+		c.JSON(http.StatusUnprocessableEntity, domain.SuccessResponse{Success: false, Message: "Bulk request rolled back", Data: result})
+		return
+	}
+	message := fmt.Sprintf("%d succeeded, %d failed", result.Succeeded, result.Failed)
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: message, Data: result})
+}
+
+// task import controllers
+// Import takes the file as the request body. The format comes from the
+// format param or the Content-Type, and columns are mapped to task fields
+// with mapping[Column]=field params.
+func (ic *TaskImportController) Import(c *gin.Context){
+	format := c.Query("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		switch mediaType {
+		case "text/csv":
+			format = domain.ImportCSV
+		case "application/json", "application/x-ndjson":
+			format = domain.ImportJSON
+		}
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "dry_run must be true or false"})
+		return
+	}
+	async, err := strconv.ParseBool(c.DefaultQuery("async", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "async must be true or false"})
+		return
+	}
+	// one byte over the limit is enough for the usecase to refuse it
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, domain.MaxImportBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	request := domain.TaskImportRequest{
+		Format:  format,
+		Mapping: c.QueryMap("mapping"),
+		Data:    data,
+		Dry_run: dryRun,
+		Async:   async,
+	}
+	job, err := ic.TaskImportUsecase.Import(c, c.GetString("user_id"), request)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if job.Status == domain.ImportQueued {
+		c.JSON(http.StatusAccepted, domain.SuccessResponse{Success: true, Message: "Import queued", Data: job})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Import finished", Data: job})
+}
+
+func (ic *TaskImportController) FetchJob(c *gin.Context){
+	job, err := ic.TaskImportUsecase.FetchJob(c, c.Param("job_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get import job", Data: job})
+}
+
+// calendar controllers
+func (cc *CalendarController) CreateFeed(c *gin.Context){
+	feed, err := cc.CalendarUsecase.CreateFeed(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Calendar feed created, keep its URL secret", Data: feed})
+}
+
+func (cc *CalendarController) FetchFeed(c *gin.Context){
+	feed, err := cc.CalendarUsecase.FetchFeed(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get calendar feed", Data: feed})
+}
+
+func (cc *CalendarController) RevokeFeed(c *gin.Context){
+	if err := cc.CalendarUsecase.RevokeFeed(c, c.GetString("user_id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Calendar feed revoked"})
+}
+
+// Feed answers calendar clients, which only have the token in the URL to
+// authenticate with.
+func (cc *CalendarController) Feed(c *gin.Context){
+	token, ok := strings.CutSuffix(c.Param("token"), ".ics")
+	if !ok {
+		c.JSON(http.StatusNotFound, domain.ErrorResponse{Message: domain.ErrCalendarFeedNotFound.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-cache, private")
+	writer := &exportWriter{c: c, mediaType: "text/calendar", extension: "ics"}
+	writer.finish(cc.CalendarUsecase.WriteFeed(c, token, c.Query("type"), writer))
+}
+
+// task template controllers
+func (tc *TaskTemplateController) Create(c *gin.Context){
+	var template domain.TaskTemplate
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	template.Created_by = c.GetString("user_id")
+	if err := tc.TaskTemplateUsecase.Create(c, &template); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Task template created", Data: template})
+}
+
+func (tc *TaskTemplateController) FetchAll(c *gin.Context){
+	templates, err := tc.TaskTemplateUsecase.FetchAll(c)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get task templates", Data: templates})
+}
+
+func (tc *TaskTemplateController) FetchByID(c *gin.Context){
+	template, err := tc.TaskTemplateUsecase.FetchByID(c, c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Success to get task template", Data: template})
+}
+
+func (tc *TaskTemplateController) Update(c *gin.Context){
+	var template domain.TaskTemplate
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	updated, err := tc.TaskTemplateUsecase.Update(c, c.Param("id"), template)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Task template updated", Data: updated})
+}
+
+func (tc *TaskTemplateController) Delete(c *gin.Context){
+	if err := tc.TaskTemplateUsecase.Delete(c, c.Param("id")); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Task template deleted"})
+}
+
+// Instantiate creates tasks from a template. The body is optional when the
+// template has no variables.
+func (tc *TaskTemplateController) Instantiate(c *gin.Context){
+	var request domain.TemplateInstantiation
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+	}
+
+	tree, err := tc.TaskTemplateUsecase.Instantiate(c, c.Param("id"), request, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Success: true, Message: "Tasks created from template", Data: tree})
+}
+
+// privacy controllers
+func (pc *PrivacyController) Export(c *gin.Context){
+	userID := c.GetString("user_id")
+	export, err := pc.PrivacyUsecase.Export(c, userID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="personal-data-%s.json"`, userID))
+	c.IndentedJSON(http.StatusOK, export)
+}
+
+func (pc *PrivacyController) EraseMe(c *gin.Context){
+	var body struct {
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := pc.PrivacyUsecase.EraseAccount(c, c.GetString("user_id"), body.Password); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "Account erased"})
+}
+
+func (pc *PrivacyController) Erase(c *gin.Context){
+	userID := c.Param("id")
+	if userID == c.GetString("user_id") {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "use /me/erase to erase your own account"})
+		return
+	}
+
+	if err := pc.PrivacyUsecase.Erase(c, c.GetString("user_id"), userID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{Success: true, Message: "User erased"})
+}
+
+// oidc controllers
+
+// oidcStateCookie binds a login to the browser that started it, so its
+// callback cannot be completed in another one. It lives as long as the
+// provider waits for the callback.
+const (
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/auth/oidc"
+	oidcStateCookieAge  = 10 * 60
+)
+
+func (oc *OIDCController) Login(c *gin.Context){
+	authURL, state, err := oc.Provider.AuthCodeURL(c)
+	if errors.Is(err, domain.ErrTooManyLogins) {
+		c.JSON(http.StatusServiceUnavailable, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	// Lax, as the provider sends the browser back with a top-level GET
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, oidcStateCookieAge, oidcStateCookiePath, "", secureRequest(c), true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+func (oc *OIDCController) Callback(c *gin.Context){
+	if providerErr := c.Query("error"); providerErr != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": providerErr, "error_description": c.Query("error_description")})
+		return
+	}
+
+	state, err := c.Cookie(oidcStateCookie)
+	if err != nil || state == "" || state != c.Query("state") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "login state does not match this browser"})
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, "", -1, oidcStateCookiePath, "", secureRequest(c), true)
+
+	identity, err := oc.Provider.Exchange(c, state, c.Query("code"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, refreshToken, err := oc.UserUsecase.HandleExternalLogin(domain.WithClient(c, clientInfo(c)), identity)
+	if err != nil{
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error":err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User loged in successfully!", "token":token, "refresh_token":refreshToken})
+}
+
+// secureRequest reports whether the client reached us over HTTPS, directly
+// or through a proxy.
+func secureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
 }
 
 // task controllers
@@ -81,10 +1455,11 @@ func (tc *TaskController) Create(c *gin.Context){
 		return
 	}
 
+	task.Created_by = c.GetString("user_id")
 	err = tc.TaskUsecase.Create(c, &task)
 	if err != nil{
 
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
 		return
 	}
 
@@ -94,10 +1469,26 @@ func (tc *TaskController) Create(c *gin.Context){
 	})
 }
 
+// taskQuery reads the filter, sort and cf.<key> params of a task listing.
+func taskQuery(c *gin.Context) domain.TaskQuery {
+	query := domain.TaskQuery{Sort: c.Query("sort"), Filter: c.Query("filter")}
+	for param, values := range c.Request.URL.Query() {
+		key, ok := strings.CutPrefix(param, "cf.")
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if query.Custom_fields == nil {
+			query.Custom_fields = map[string]string{}
+		}
+		query.Custom_fields[key] = values[0]
+	}
+	return query
+}
+
 func (u *TaskController) FetchAll(c *gin.Context) {
-	tasks, err := u.TaskUsecase.FetchAll(c)
+	tasks, err := u.TaskUsecase.FetchAll(c, taskQuery(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		taskQueryError(c, err)
 		return
 	}
 
@@ -108,6 +1499,92 @@ func (u *TaskController) FetchAll(c *gin.Context) {
 	})
 }
 
+// exportFormats lists what GET /tasks/export writes, in the order Accept
+// negotiation prefers them.
+var exportFormats = []struct {
+	format    string
+	mediaType string
+	extension string
+}{
+	{domain.ExportCSV, "text/csv", "csv"},
+	{domain.ExportNDJSON, "application/x-ndjson", "ndjson"},
+	{domain.ExportMarkdown, "text/markdown", "md"},
+	{domain.ExportICS, "text/calendar", "ics"},
+}
+
+// Export streams the tasks GET /tasks would list, in the format named by
+// the format param or else negotiated from the Accept header.
+func (u *TaskController) Export(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		offered := make([]string, len(exportFormats))
+		for i, candidate := range exportFormats {
+			offered[i] = candidate.mediaType
+		}
+		negotiated := c.NegotiateFormat(offered...)
+		for _, candidate := range exportFormats {
+			if candidate.mediaType == negotiated {
+				format = candidate.format
+			}
+		}
+		if format == "" {
+			c.JSON(http.StatusNotAcceptable, domain.ErrorResponse{Message: "tasks can be exported as " + strings.Join(offered, ", ")})
+			return
+		}
+	}
+
+	writer := &exportWriter{c: c}
+	for _, candidate := range exportFormats {
+		if candidate.format == format {
+			writer.mediaType, writer.extension = candidate.mediaType, candidate.extension
+		}
+	}
+	if writer.mediaType == "" {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: fmt.Sprintf("unknown export format %q", format)})
+		return
+	}
+
+	writer.finish(u.TaskUsecase.Export(c, taskQuery(c), format, writer))
+}
+
+// exportWriter holds back the export headers until the first byte, so a
+// failure before then can still be answered with an error.
+type exportWriter struct {
+	c         *gin.Context
+	mediaType string
+	extension string
+	started   bool
+}
+
+func (w *exportWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.c.Header("Content-Type", w.mediaType+"; charset=utf-8")
+	w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, w.extension))
+	w.c.Status(http.StatusOK)
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.start()
+	return w.c.Writer.Write(p)
+}
+
+// finish answers with err when nothing was written yet. Otherwise the
+// status is already sent, so a failure just ends the output early.
+func (w *exportWriter) finish(err error) {
+	if err != nil && !w.started {
+		taskQueryError(w.c, err)
+		return
+	}
+	if err != nil {
+		log.Printf("%s %s failed midway: %v", w.c.Request.Method, w.c.Request.URL.Path, err)
+		return
+	}
+	w.start()
+}
+
 func (u *TaskController) FetchByTaskID(c *gin.Context) {
 	taskID := c.Param("id")
 
@@ -137,7 +1614,7 @@ func (u *TaskController) Update(c *gin.Context) {
 	err = u.TaskUsecase.Update(c, taskID, updatedTask)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), domain.ErrorResponse{Message: err.Error()})
 		return
 	}
 
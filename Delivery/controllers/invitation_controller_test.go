@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type invitationControllerSuite struct {
+	suite.Suite
+	usecase       *mocks.InvitationUsecase
+	testingServer *httptest.Server
+}
+
+func (suite *invitationControllerSuite) SetupTest() {
+	suite.usecase = new(mocks.InvitationUsecase)
+	controller := NewInvitationController(suite.usecase)
+
+	router := gin.Default()
+	router.Use(func(c *gin.Context) { c.Set("user_id", "admin-id") })
+	router.POST("/invitations", controller.Invite)
+	router.POST("/invitations/accept", controller.Accept)
+	suite.testingServer = httptest.NewServer(router)
+}
+
+func (suite *invitationControllerSuite) TearDownTest() {
+	suite.testingServer.Close()
+}
+
+func (suite *invitationControllerSuite) post(path string, body string) *http.Response {
+	response, err := http.Post(fmt.Sprintf("%s%s", suite.testingServer.URL, path), "application/json", bytes.NewBufferString(body))
+	suite.Require().NoError(err)
+	return response
+}
+
+func (suite *invitationControllerSuite) TestInvite_Positive() {
+	suite.usecase.On("Invite", mock.Anything, &domain.Invitation{Email: "new@example.com", User_type: "ADMIN"}, "admin-id").Return(nil)
+
+	response := suite.post("/invitations", `{"email": "new@example.com", "user_type": "ADMIN"}`)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusCreated, response.StatusCode)
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *invitationControllerSuite) TestInvite_UnknownRole() {
+	response := suite.post("/invitations", `{"email": "new@example.com", "user_type": "ROOT"}`)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+	suite.usecase.AssertNotCalled(suite.T(), "Invite", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *invitationControllerSuite) TestAccept_Expired() {
+	suite.usecase.On("Accept", mock.Anything, mock.Anything).Return(nil, domain.ErrInvitationExpired)
+
+	response := suite.post("/invitations/accept", `{"token": "t", "name": "New Person", "username": "newbie", "password": "strongpassword"}`)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusGone, response.StatusCode)
+}
+
+func TestInvitationController(t *testing.T) {
+	suite.Run(t, new(invitationControllerSuite))
+}
@@ -2,10 +2,13 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	domain "task-manger-api_test/Domain"
 	"task-manger-api_test/Domain/mocks"
 	"testing"
@@ -37,6 +40,7 @@ func (suite *taskControllerSuite) SetupSuite() {
 	// create default server using gin, then register all endpoints
 	router := gin.Default()
 	router.GET("/tasks", controller.FetchAll)
+	router.GET("/tasks/export", controller.Export)
 	router.GET("/tasks/:id", controller.FetchByTaskID)
 	router.PUT("/tasks/:id", controller.Update)
 	router.DELETE("/tasks/:id", controller.Delete)
@@ -105,7 +109,7 @@ func (suite *taskControllerSuite) TestGetAllTasks_Positive() {
 	},
 	}
 
-	suite.usecase.On("FetchAll", mock.Anything).Return(&tasks, nil)
+	suite.usecase.On("FetchAll", mock.Anything, domain.TaskQuery{}).Return(&tasks, nil)
 
 	response, err := http.Get(fmt.Sprintf("%s/tasks", suite.testingServer.URL))
 	suite.NoError(err, "no error when calling this endpoint")
@@ -119,6 +123,71 @@ func (suite *taskControllerSuite) TestGetAllTasks_Positive() {
 	suite.usecase.AssertExpectations(suite.T())
 }
 
+func (suite *taskControllerSuite) TestFetchAllTasks_CustomFieldQuery() {
+	query := domain.TaskQuery{Custom_fields: map[string]string{"priority": "high"}, Sort: "-cf.points"}
+	suite.usecase.On("FetchAll", mock.Anything, query).Return(nil, fmt.Errorf("%w: unknown custom field", domain.ErrInvalidTaskQuery))
+
+	response, err := http.Get(fmt.Sprintf("%s/tasks?cf.priority=high&sort=-cf.points", suite.testingServer.URL))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *taskControllerSuite) TestFetchAllTasks_FilterErrorPosition() {
+	query := domain.TaskQuery{Filter: "status = todo and"}
+	suite.usecase.On("FetchAll", mock.Anything, query).Return(nil, &domain.FilterError{Position: 18, Message: "expected a field name"})
+
+	response, err := http.Get(fmt.Sprintf("%s/tasks?filter=%s", suite.testingServer.URL, url.QueryEscape(query.Filter)))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	var body struct {
+		Message  string `json:"message"`
+		Position int    `json:"position"`
+	}
+	json.NewDecoder(response.Body).Decode(&body)
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+	suite.Equal(18, body.Position)
+	suite.Contains(body.Message, "position 18")
+}
+
+func (suite *taskControllerSuite) TestExportTasks_NegotiatesFormat() {
+	suite.usecase.On("Export", mock.Anything, domain.TaskQuery{Filter: "status = todo"}, domain.ExportICS, mock.Anything).Return(func(c context.Context, query domain.TaskQuery, format string, w io.Writer) error {
+		_, err := io.WriteString(w, "BEGIN:VCALENDAR\r\n")
+		return err
+	})
+
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/tasks/export?filter=%s", suite.testingServer.URL, url.QueryEscape("status = todo")), nil)
+	request.Header.Set("Accept", "text/calendar, */*;q=0.1")
+	response, err := http.DefaultClient.Do(request)
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("text/calendar; charset=utf-8", response.Header.Get("Content-Type"))
+	suite.Equal(`attachment; filename="tasks.ics"`, response.Header.Get("Content-Disposition"))
+	suite.Equal("BEGIN:VCALENDAR\r\n", string(body))
+
+	request, _ = http.NewRequest(http.MethodGet, suite.testingServer.URL+"/tasks/export", nil)
+	request.Header.Set("Accept", "image/png")
+	response, err = http.DefaultClient.Do(request)
+	suite.Require().NoError(err)
+	response.Body.Close()
+	suite.Equal(http.StatusNotAcceptable, response.StatusCode)
+}
+
+func (suite *taskControllerSuite) TestExportTasks_ErrorBeforeOutput() {
+	suite.usecase.On("Export", mock.Anything, domain.TaskQuery{Filter: "status ="}, domain.ExportCSV, mock.Anything).Return(&domain.FilterError{Position: 9, Message: "expected a value"})
+
+	response, err := http.Get(fmt.Sprintf("%s/tasks/export?format=csv&filter=%s", suite.testingServer.URL, url.QueryEscape("status =")))
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+	suite.Contains(response.Header.Get("Content-Type"), "application/json")
+}
+
 func (suite *taskControllerSuite) TestGetTaskByID_Positive() {
 	taskID := primitive.NewObjectID()
 	task := domain.Task{
@@ -142,4 +211,30 @@ func (suite *taskControllerSuite) TestGetTaskByID_Positive() {
 
 func TestTaskController(t *testing.T) {
 	suite.Run(t, new(taskControllerSuite))
-}
\ No newline at end of file
+}
+func TestBulkTasks_RolledBackAndInvalid(t *testing.T) {
+	usecase := new(mocks.TaskBulkUsecase)
+	controller := &TaskBulkController{TaskBulkUsecase: usecase}
+	router := gin.Default()
+	router.POST("/tasks/bulk", controller.Execute)
+
+	rolledBack := &domain.BulkTaskResult{Atomic: true, Failed: 1, Results: []domain.BulkTaskItemResult{{Op: domain.BulkDelete, Task_id: "a", Error: "task not found"}}}
+	usecase.On("Execute", mock.Anything, mock.Anything, mock.MatchedBy(func(request domain.BulkTaskRequest) bool {
+		return request.Atomic
+	})).Return(rolledBack, nil)
+
+	body := []byte(`{"atomic": true, "operations": [{"op": "delete", "task_id": "a"}]}`)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/tasks/bulk", bytes.NewReader(body)))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("rolled back request answered %d", recorder.Code)
+	}
+
+	body = []byte(`{"operations": [{"op": "archive", "task_id": "a"}]}`)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/tasks/bulk", bytes.NewReader(body)))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("unknown op answered %d", recorder.Code)
+	}
+	usecase.AssertNumberOfCalls(t, "Execute", 1)
+}
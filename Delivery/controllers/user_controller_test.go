@@ -128,4 +128,164 @@ func (suite *userControllerSuite) TestSignup() {
 }
 func TestUserController(t *testing.T) {
 	suite.Run(t, new(userControllerSuite))
-}
\ No newline at end of file
+}
+type oidcControllerSuite struct {
+	suite.Suite
+	provider *mocks.IdentityProvider
+	usecase *mocks.UserUsecase
+	testingServer *httptest.Server
+}
+
+func (suite *oidcControllerSuite) SetupTest() {
+	suite.provider = new(mocks.IdentityProvider)
+	suite.usecase = new(mocks.UserUsecase)
+	controller := NewOIDCController(suite.provider, suite.usecase)
+
+	router := gin.Default()
+	router.GET("/auth/oidc/login", controller.Login)
+	router.GET("/auth/oidc/callback", controller.Callback)
+	suite.testingServer = httptest.NewServer(router)
+}
+
+func (suite *oidcControllerSuite) TearDownTest() {
+	suite.testingServer.Close()
+}
+
+func (suite *oidcControllerSuite) TestLogin_RedirectsToProvider() {
+	suite.provider.On("AuthCodeURL", mock.Anything).Return("https://idp.example.com/authorize?state=abc", "abc", nil)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	response, err := client.Get(fmt.Sprintf("%s/auth/oidc/login", suite.testingServer.URL))
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusFound, response.StatusCode)
+	suite.Equal("https://idp.example.com/authorize?state=abc", response.Header.Get("Location"))
+	suite.Require().Len(response.Cookies(), 1)
+	cookie := response.Cookies()[0]
+	suite.Equal("oidc_state", cookie.Name)
+	suite.Equal("abc", cookie.Value)
+	suite.True(cookie.HttpOnly)
+	suite.Equal(http.SameSiteLaxMode, cookie.SameSite)
+}
+
+func (suite *oidcControllerSuite) TestLogin_TooManyLogins() {
+	suite.provider.On("AuthCodeURL", mock.Anything).Return("", "", domain.ErrTooManyLogins)
+
+	response, err := http.Get(fmt.Sprintf("%s/auth/oidc/login", suite.testingServer.URL))
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+	suite.Equal(http.StatusServiceUnavailable, response.StatusCode)
+}
+
+// callback completes a login as the browser holding the state cookie.
+func (suite *oidcControllerSuite) callback(query string, cookieState string) *http.Response {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/auth/oidc/callback?%s", suite.testingServer.URL, query), nil)
+	suite.Require().NoError(err)
+	if cookieState != "" {
+		request.AddCookie(&http.Cookie{Name: "oidc_state", Value: cookieState})
+	}
+	response, err := http.DefaultClient.Do(request)
+	suite.Require().NoError(err)
+	return response
+}
+
+func (suite *oidcControllerSuite) TestCallback_IssuesTokens() {
+	identity := &domain.ExternalIdentity{Provider: "stub", Subject: "sub", Email: "jane@example.com", Email_verified: true}
+	suite.provider.On("Exchange", mock.Anything, "abc", "the-code").Return(identity, nil)
+	suite.usecase.On("HandleExternalLogin", mock.Anything, identity).Return("token", "refresh", nil)
+
+	response := suite.callback("state=abc&code=the-code", "abc")
+	defer response.Body.Close()
+
+	responseBody := map[string]string{}
+	json.NewDecoder(response.Body).Decode(&responseBody)
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("token", responseBody["token"])
+	suite.Equal("refresh", responseBody["refresh_token"])
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *oidcControllerSuite) TestCallback_StateMustMatchTheBrowser() {
+	for name, cookieState := range map[string]string{"no cookie": "", "another login": "xyz"} {
+		response := suite.callback("state=abc&code=the-code", cookieState)
+		response.Body.Close()
+		suite.Equal(http.StatusUnauthorized, response.StatusCode, name)
+	}
+	suite.provider.AssertNotCalled(suite.T(), "Exchange", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *oidcControllerSuite) TestCallback_ProviderError() {
+	response, err := http.Get(fmt.Sprintf("%s/auth/oidc/callback?error=access_denied", suite.testingServer.URL))
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusUnauthorized, response.StatusCode)
+	suite.provider.AssertNotCalled(suite.T(), "Exchange", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOIDCController(t *testing.T) {
+	suite.Run(t, new(oidcControllerSuite))
+}
+
+type profileControllerSuite struct {
+	suite.Suite
+	usecase *mocks.UserUsecase
+	testingServer *httptest.Server
+}
+
+func (suite *profileControllerSuite) SetupTest() {
+	suite.usecase = new(mocks.UserUsecase)
+	controller := NewUserController(suite.usecase)
+
+	router := gin.Default()
+	router.Use(func(c *gin.Context) { c.Set("user_id", "admin-id") })
+	router.GET("/me", controller.Me)
+	router.POST("/users/:id/demote", controller.Demote)
+	suite.testingServer = httptest.NewServer(router)
+}
+
+func (suite *profileControllerSuite) TearDownTest() {
+	suite.testingServer.Close()
+}
+
+func (suite *profileControllerSuite) TestMe_OmitsPassword() {
+	hash := "$2a$14$hash"
+	user := &domain.User{User_id: "admin-id", Username: &hash, Password: &hash}
+	suite.usecase.On("FetchByUserID", mock.Anything, "admin-id").Return(user, nil)
+
+	response, err := http.Get(fmt.Sprintf("%s/me", suite.testingServer.URL))
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	responseBody := map[string]map[string]interface{}{}
+	json.NewDecoder(response.Body).Decode(&responseBody)
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("admin-id", responseBody["data"]["user_id"])
+	suite.NotContains(responseBody["data"], "password")
+}
+
+func (suite *profileControllerSuite) TestDemote_Self() {
+	response, err := http.Post(fmt.Sprintf("%s/users/admin-id/demote", suite.testingServer.URL), "application/json", nil)
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+	suite.usecase.AssertNotCalled(suite.T(), "Demote", mock.Anything, mock.Anything)
+}
+
+func (suite *profileControllerSuite) TestDemote_NotFound() {
+	suite.usecase.On("Demote", mock.Anything, "other-id").Return(domain.ErrUserNotFound)
+
+	response, err := http.Post(fmt.Sprintf("%s/users/other-id/demote", suite.testingServer.URL), "application/json", nil)
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusNotFound, response.StatusCode)
+}
+
+func TestProfileController(t *testing.T) {
+	suite.Run(t, new(profileControllerSuite))
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"task-manger-api_test/Delivery/routers"
 	domain "task-manger-api_test/Domain"
+	infrastructure "task-manger-api_test/Infrastructure"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -27,15 +28,46 @@ func main(){
 		port = ":8080"
 	}
 
+	jwtConfig, err := infrastructure.JWTConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	keys, err := infrastructure.InitJWT(jwtConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	stopRotation := make(chan struct{})
+	defer close(stopRotation)
+	keys.StartRotation(stopRotation)
+
+	passwordConfig, err := infrastructure.PasswordConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := infrastructure.InitPasswords(passwordConfig); err != nil {
+		log.Fatal(err)
+	}
+
 	client := DBinstance(mongo_url)
 	db := client.Database(domain.DatabaseName)
 	defer CloseMongoDBConnection(client)
 
+	blobs, err := infrastructure.BlobStoreFromEnv(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	timeout := time.Duration(10) * time.Second
+	startedAt := time.Now()
 
 	gin := gin.Default()
 
-	routers.Setup(timeout, db, gin)
+	routers.Setup(timeout, db, blobs, gin)
+	stopRelay := make(chan struct{})
+	defer close(stopRelay)
+	routers.ResumeWebhookDeliveries(timeout, db, startedAt)
+	routers.FailInterruptedImports(timeout, db, startedAt)
+	routers.StartOutboxRelay(timeout, db, stopRelay)
 
 	gin.Run(port)
 }
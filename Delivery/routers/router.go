@@ -1,6 +1,8 @@
 package routers
 
 import (
+	"context"
+	"log"
 	"task-manger-api_test/Delivery/controllers"
 	domain "task-manger-api_test/Domain"
 	infrastructure "task-manger-api_test/Infrastructure"
@@ -12,47 +14,313 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func Setup(timeout time.Duration, db *mongo.Database, gin *gin.Engine) {
+// sessionCache is shared by every session repository, so a revoked session
+// stops working in AuthMiddleware straight away.
+var sessionCache = infrastructure.NewSessionCache(30 * time.Second)
+
+func newSessionRepository(db *mongo.Database) domain.SessionRepository {
+	return infrastructure.NewCachedSessionRepository(repositories.NewSessionRepository(db, domain.CollectionSession), sessionCache)
+}
+
+// eventBus carries the domain events the outbox relay publishes to the
+// usecases reacting to them.
+var eventBus = infrastructure.NewEventBus()
+
+func newOutboxRepository(db *mongo.Database) domain.OutboxRepository {
+	return repositories.NewOutboxRepository(db, domain.CollectionOutbox)
+}
+
+// ResumeWebhookDeliveries sends the webhook deliveries a restart cut off:
+// those still pending from before startedAt.
+func ResumeWebhookDeliveries(timeout time.Duration, db *mongo.Database, startedAt time.Time) {
+	resumed, err := newWebhookUsecase(timeout, db).ResumePending(context.Background(), startedAt)
+	if err != nil {
+		log.Println("failed to resume webhook deliveries:", err)
+		return
+	}
+	if resumed > 0 {
+		log.Printf("resumed %d webhook deliveries", resumed)
+	}
+}
+
+// FailInterruptedImports fails the queued imports a restart cut off: those
+// still queued or running from before startedAt.
+func FailInterruptedImports(timeout time.Duration, db *mongo.Database, startedAt time.Time) {
+	failed, err := newTaskImportUsecase(timeout, db).FailInterrupted(context.Background(), startedAt)
+	if err != nil {
+		log.Println("failed to fail interrupted imports:", err)
+		return
+	}
+	if failed > 0 {
+		log.Printf("failed %d imports interrupted by a restart", failed)
+	}
+}
+
+// StartOutboxRelay publishes the events usecases store in the outbox until
+// stop is closed. Call it after Setup, which subscribes to them.
+func StartOutboxRelay(timeout time.Duration, db *mongo.Database, stop <-chan struct{}) {
+	usecases.NewOutboxRelay(newOutboxRepository(db), eventBus, timeout).Start(stop)
+}
+
+// webhookSender is shared so deliveries reuse connections.
+var webhookSender = infrastructure.WebhookSenderFromEnv(10 * time.Second)
+
+func newWebhookUsecase(timeout time.Duration, db *mongo.Database) domain.WebhookUsecase {
+	webhookRepo := repositories.NewWebhookRepository(db, domain.CollectionWebhook)
+	deliveryRepo := repositories.NewWebhookDeliveryRepository(db, domain.CollectionWebhookDelivery)
+	return usecases.NewWebhookUsecase(webhookRepo, deliveryRepo, webhookSender, timeout)
+}
+
+func Setup(timeout time.Duration, db *mongo.Database, blobs domain.BlobStore, gin *gin.Engine) {
+	// Lets usecases read the tenant TenantMiddleware puts on the request context
+	gin.ContextWithFallback = true
+	// Webhooks react to the task events the outbox relay publishes
+	usecases.SubscribeWebhooks(eventBus, newWebhookUsecase(timeout, db))
+
 	publicRouter := gin.Group("")
 	// All Public APIs
+	publicRouter.GET("/.well-known/jwks.json", infrastructure.JWKSHandler())
 	PublicUserRouter(timeout, db, publicRouter)
-	PublicTaskRouter(timeout, db, publicRouter)
+	PublicInvitationRouter(timeout, db, publicRouter)
+	OIDCRouter(timeout, db, publicRouter)
+	PublicCalendarRouter(timeout, db, publicRouter)
 
 	protectedRouter := gin.Group("")
 	// Middleware to verify AccessToken
-	protectedRouter.Use(infrastructure.AuthMiddleware())
+	protectedRouter.Use(infrastructure.AuthMiddleware(newSessionRepository(db)))
+	// Middleware to record requests made with an impersonation token
+	protectedRouter.Use(infrastructure.AuditImpersonation(repositories.NewAuditRepository(db, domain.CollectionAudit)))
 	// All Private APIs
-	PrivateTaskRouter(timeout, db, protectedRouter)
-	PromoteRouter(timeout, db, protectedRouter)
-}
+	ProfileRouter(timeout, db, protectedRouter)
+	OrganizationRouter(timeout, db, protectedRouter)
+	PrivacyRouter(timeout, db, protectedRouter)
 
+	tenantRouter := protectedRouter.Group("")
+	// Middleware to resolve the active organization and scope queries to it
+	tenantRouter.Use(infrastructure.TenantMiddleware(repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)))
+	PrivateTaskRouter(timeout, db, blobs, tenantRouter)
+	CommentRouter(timeout, db, tenantRouter)
+	AttachmentRouter(timeout, db, blobs, tenantRouter)
+	TimeTrackingRouter(timeout, db, tenantRouter)
+	TaskSearchRouter(timeout, db, tenantRouter)
+	TaskBulkRouter(timeout, db, blobs, tenantRouter)
+	TaskImportRouter(timeout, db, tenantRouter)
+	CalendarRouter(timeout, db, tenantRouter)
+	WebhookRouter(timeout, db, tenantRouter)
+	ViewRouter(timeout, db, tenantRouter)
+	CustomFieldRouter(timeout, db, tenantRouter)
+	TaskTemplateRouter(timeout, db, tenantRouter)
+	MemberRouter(timeout, db, tenantRouter)
 
-func PublicTaskRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	adminRouter := protectedRouter.Group("")
+	adminRouter.Use(infrastructure.AuthRole("ADMIN"))
+	PromoteRouter(timeout, db, adminRouter)
+	AdminUserRouter(timeout, db, adminRouter)
+	AdminInvitationRouter(timeout, db, adminRouter)
+	ImpersonationRouter(timeout, db, adminRouter)
+	AdminPrivacyRouter(timeout, db, adminRouter)
+}
+
+func PrivateTaskRouter(timeout time.Duration, db *mongo.Database, blobs domain.BlobStore, group *gin.RouterGroup) {
 	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
-	taskUsecase := usecases.NewTaskUsecase(taskRepo, timeout)
+	attachmentRepo := repositories.NewAttachmentRepository(db, domain.CollectionAttachment)
+	customFieldRepo := repositories.NewCustomFieldRepository(db, domain.CollectionCustomField)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	taskUsecase := usecases.NewTaskUsecase(taskRepo, attachmentRepo, blobs, customFieldRepo, orgRepo, newOutboxRepository(db), repositories.NewTransactor(db), timeout)
 	taskController := &controllers.TaskController{
 		TaskUsecase : taskUsecase,
 	}
 
 	group.GET("/tasks", taskController.FetchAll)
+	group.GET("/tasks/export", taskController.Export)
 	group.GET("/tasks/:id", taskController.FetchByTaskID)
+	group.POST("/tasks", taskController.Create)
+	group.PUT("/tasks/:id", taskController.Update)
+	group.DELETE("/tasks/:id", taskController.Delete)
+	group.POST("/tasks/:id/checklist", taskController.AddChecklistItem)
+	group.PUT("/tasks/:id/checklist/order", taskController.ReorderChecklist)
+	group.PATCH("/tasks/:id/checklist/:item_id", taskController.UpdateChecklistItem)
+	group.POST("/tasks/:id/checklist/:item_id/toggle", taskController.ToggleChecklistItem)
+	group.DELETE("/tasks/:id/checklist/:item_id", taskController.DeleteChecklistItem)
 }
 
-func PrivateTaskRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+func CommentRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	commentRepo := repositories.NewCommentRepository(db, domain.CollectionComment)
 	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
-	taskUsecase := usecases.NewTaskUsecase(taskRepo, timeout)
-	taskController := &controllers.TaskController{
-		TaskUsecase : taskUsecase,
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	commentController := &controllers.CommentController{
+		CommentUsecase: usecases.NewCommentUsecase(commentRepo, taskRepo, userRepo, orgRepo, timeout),
 	}
 
-	group.POST("/tasks", taskController.Create)
-	group.PUT("/tasks/:id", taskController.Update)
-	group.DELETE("/tasks/:id", taskController.Delete)
+	group.GET("/tasks/:id/comments", commentController.FetchByTask)
+	group.POST("/tasks/:id/comments", commentController.Create)
+	group.PATCH("/tasks/:id/comments/:comment_id", commentController.Update)
+	group.DELETE("/tasks/:id/comments/:comment_id", commentController.Delete)
+}
+
+func AttachmentRouter(timeout time.Duration, db *mongo.Database, blobs domain.BlobStore, group *gin.RouterGroup) {
+	attachmentRepo := repositories.NewAttachmentRepository(db, domain.CollectionAttachment)
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	attachmentController := &controllers.AttachmentController{
+		AttachmentUsecase: usecases.NewAttachmentUsecase(attachmentRepo, taskRepo, blobs, timeout),
+	}
+
+	group.GET("/tasks/:id/attachments", attachmentController.FetchByTask)
+	group.POST("/tasks/:id/attachments", attachmentController.Upload)
+	group.GET("/tasks/:id/attachments/:attachment_id", attachmentController.Download)
+	group.DELETE("/tasks/:id/attachments/:attachment_id", attachmentController.Delete)
+}
+
+func TimeTrackingRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	timeEntryRepo := repositories.NewTimeEntryRepository(db, domain.CollectionTimeEntry)
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	timeTrackingController := &controllers.TimeTrackingController{
+		TimeTrackingUsecase: usecases.NewTimeTrackingUsecase(timeEntryRepo, taskRepo, timeout),
+	}
+
+	group.POST("/tasks/:id/timer/start", timeTrackingController.StartTimer)
+	group.GET("/timer", timeTrackingController.FetchRunning)
+	group.POST("/timer/stop", timeTrackingController.StopTimer)
+	group.GET("/tasks/:id/time", timeTrackingController.FetchByTask)
+	group.POST("/tasks/:id/time", timeTrackingController.LogTime)
+	group.DELETE("/time/:entry_id", timeTrackingController.DeleteEntry)
+	group.GET("/time/report", timeTrackingController.Report)
+}
+
+func CustomFieldRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	customFieldRepo := repositories.NewCustomFieldRepository(db, domain.CollectionCustomField)
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	customFieldController := &controllers.CustomFieldController{
+		CustomFieldUsecase: usecases.NewCustomFieldUsecase(customFieldRepo, taskRepo, orgRepo, timeout),
+	}
+
+	group.GET("/custom-fields", customFieldController.FetchAll)
+	group.POST("/custom-fields", infrastructure.OrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), customFieldController.Create)
+	group.PATCH("/custom-fields/:id", infrastructure.OrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), customFieldController.Update)
+	group.DELETE("/custom-fields/:id", infrastructure.OrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), customFieldController.Delete)
+}
+
+func TaskSearchRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	commentRepo := repositories.NewCommentRepository(db, domain.CollectionComment)
+	customFieldRepo := repositories.NewCustomFieldRepository(db, domain.CollectionCustomField)
+	searchController := &controllers.TaskSearchController{
+		TaskSearchUsecase: usecases.NewTaskSearchUsecase(taskRepo, commentRepo, customFieldRepo, timeout),
+	}
+
+	group.GET("/tasks/search", searchController.Search)
+}
+
+func TaskBulkRouter(timeout time.Duration, db *mongo.Database, blobs domain.BlobStore, group *gin.RouterGroup) {
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	attachmentRepo := repositories.NewAttachmentRepository(db, domain.CollectionAttachment)
+	customFieldRepo := repositories.NewCustomFieldRepository(db, domain.CollectionCustomField)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	bulkController := &controllers.TaskBulkController{
+		TaskBulkUsecase: usecases.NewTaskBulkUsecase(taskRepo, attachmentRepo, blobs, customFieldRepo, orgRepo, newOutboxRepository(db), repositories.NewTransactor(db), timeout),
+	}
+
+	group.POST("/tasks/bulk", bulkController.Execute)
+}
+
+func newTaskImportUsecase(timeout time.Duration, db *mongo.Database) domain.TaskImportUsecase {
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	importJobRepo := repositories.NewImportJobRepository(db, domain.CollectionImportJob)
+	customFieldRepo := repositories.NewCustomFieldRepository(db, domain.CollectionCustomField)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	return usecases.NewTaskImportUsecase(taskRepo, importJobRepo, customFieldRepo, orgRepo, newOutboxRepository(db), repositories.NewTransactor(db), timeout)
+}
+
+func TaskImportRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	importController := &controllers.TaskImportController{
+		TaskImportUsecase: newTaskImportUsecase(timeout, db),
+	}
+
+	group.POST("/tasks/import", importController.Import)
+	group.GET("/tasks/import/:job_id", importController.FetchJob)
+}
+
+func newCalendarController(timeout time.Duration, db *mongo.Database) *controllers.CalendarController {
+	feedRepo := repositories.NewCalendarFeedRepository(db, domain.CollectionCalendarFeed)
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	return &controllers.CalendarController{
+		CalendarUsecase: usecases.NewCalendarUsecase(feedRepo, taskRepo, userRepo, orgRepo, timeout),
+	}
+}
+
+// PublicCalendarRouter serves feeds to calendar clients, which cannot send
+// access tokens; the secret in the URL stands in for one.
+func PublicCalendarRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	group.GET("/calendar/:token", newCalendarController(timeout, db).Feed)
+}
+
+func CalendarRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	calendarController := newCalendarController(timeout, db)
+
+	group.GET("/calendar/feed", calendarController.FetchFeed)
+	// a feed URL is a long-lived credential, so impersonators may not mint one
+	group.POST("/calendar/feed", infrastructure.BlockImpersonation(), calendarController.CreateFeed)
+	group.DELETE("/calendar/feed", infrastructure.BlockImpersonation(), calendarController.RevokeFeed)
+}
+
+func WebhookRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	webhookController := &controllers.WebhookController{
+		WebhookUsecase: newWebhookUsecase(timeout, db),
+	}
+
+	admins := group.Group("", infrastructure.OrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin))
+	admins.GET("/webhooks", webhookController.FetchAll)
+	admins.POST("/webhooks", webhookController.Create)
+	admins.GET("/webhooks/:id", webhookController.FetchByID)
+	admins.PATCH("/webhooks/:id", webhookController.Update)
+	admins.DELETE("/webhooks/:id", webhookController.Delete)
+	admins.GET("/webhooks/:id/deliveries", webhookController.FetchDeliveries)
+	admins.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", webhookController.Redeliver)
+}
+
+func ViewRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	viewRepo := repositories.NewViewRepository(db, domain.CollectionView, domain.CollectionViewDefault)
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	customFieldRepo := repositories.NewCustomFieldRepository(db, domain.CollectionCustomField)
+	viewController := &controllers.ViewController{
+		ViewUsecase: usecases.NewViewUsecase(viewRepo, taskRepo, customFieldRepo, timeout),
+	}
+
+	group.GET("/views", viewController.FetchAll)
+	group.POST("/views", viewController.Create)
+	group.DELETE("/views/default", viewController.ClearDefault)
+	group.GET("/views/:id", viewController.FetchByID)
+	group.PATCH("/views/:id", viewController.Update)
+	group.DELETE("/views/:id", viewController.Delete)
+	group.PUT("/views/:id/default", viewController.SetDefault)
+	group.GET("/views/:id/tasks", viewController.FetchTasks)
+}
+
+func TaskTemplateRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	templateRepo := repositories.NewTaskTemplateRepository(db, domain.CollectionTaskTemplate)
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	customFieldRepo := repositories.NewCustomFieldRepository(db, domain.CollectionCustomField)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	templateController := &controllers.TaskTemplateController{
+		TaskTemplateUsecase: usecases.NewTaskTemplateUsecase(templateRepo, taskRepo, customFieldRepo, orgRepo, newOutboxRepository(db), repositories.NewTransactor(db), timeout),
+	}
+
+	group.GET("/task-templates", templateController.FetchAll)
+	group.GET("/task-templates/:id", templateController.FetchByID)
+	group.POST("/task-templates", infrastructure.OrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), templateController.Create)
+	group.PUT("/task-templates/:id", infrastructure.OrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), templateController.Update)
+	group.DELETE("/task-templates/:id", infrastructure.OrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), templateController.Delete)
+	group.POST("/tasks/from-template/:id", templateController.Instantiate)
 }
 
 func PublicUserRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
 	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
-	userUsecase := usecases.NewUserUsecase(userRepo, timeout)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	userUsecase := usecases.NewUserUsecase(userRepo, orgRepo, newSessionRepository(db), newOutboxRepository(db), repositories.NewTransactor(db), timeout)
 	userController := &controllers.UserController{
 		UserUsecase: userUsecase,
 	}
@@ -63,10 +331,148 @@ func PublicUserRouter(timeout time.Duration, db *mongo.Database, group *gin.Rout
 
 func PromoteRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
 	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
-	userUsecase := usecases.NewUserUsecase(userRepo, timeout)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	userUsecase := usecases.NewUserUsecase(userRepo, orgRepo, newSessionRepository(db), newOutboxRepository(db), repositories.NewTransactor(db), timeout)
 	userController := &controllers.UserController{
 		UserUsecase: userUsecase,
 	}
 
 	group.PUT("/promote/:id", userController.PromoteUser)
-}
\ No newline at end of file
+}
+
+func ProfileRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	userUsecase := usecases.NewUserUsecase(userRepo, orgRepo, newSessionRepository(db), newOutboxRepository(db), repositories.NewTransactor(db), timeout)
+	userController := &controllers.UserController{
+		UserUsecase: userUsecase,
+	}
+
+	group.GET("/me", userController.Me)
+	group.PATCH("/me", userController.UpdateMe)
+	group.POST("/me/password", infrastructure.BlockImpersonation(), userController.ChangePassword)
+	group.DELETE("/me", infrastructure.BlockImpersonation(), userController.DeleteMe)
+	group.GET("/me/sessions", userController.FetchSessions)
+	group.DELETE("/me/sessions/:id", infrastructure.BlockImpersonation(), userController.RevokeSession)
+}
+
+func AdminUserRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	userUsecase := usecases.NewUserUsecase(userRepo, orgRepo, newSessionRepository(db), newOutboxRepository(db), repositories.NewTransactor(db), timeout)
+	userController := &controllers.UserController{
+		UserUsecase: userUsecase,
+	}
+
+	group.GET("/users", userController.FetchAll)
+	group.GET("/users/:id", userController.FetchByUserID)
+	group.POST("/users/:id/deactivate", userController.Deactivate)
+	group.POST("/users/:id/reactivate", userController.Reactivate)
+	group.POST("/users/:id/demote", userController.Demote)
+	group.DELETE("/users/:id", userController.Delete)
+}
+
+func newInvitationController(timeout time.Duration, db *mongo.Database) *controllers.InvitationController {
+	invitationRepo := repositories.NewInvitationRepository(db, domain.CollectionInvitation)
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	return &controllers.InvitationController{
+		InvitationUsecase: usecases.NewInvitationUsecase(invitationRepo, userRepo, orgRepo, newOutboxRepository(db), repositories.NewTransactor(db), infrastructure.MailerFromEnv(), timeout),
+	}
+}
+
+func PublicInvitationRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	invitationController := newInvitationController(timeout, db)
+
+	group.POST("/invitations/accept", invitationController.Accept)
+}
+
+func AdminInvitationRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	invitationController := newInvitationController(timeout, db)
+
+	group.GET("/invitations", invitationController.FetchAll)
+	group.POST("/invitations", invitationController.Invite)
+	group.POST("/invitations/:id/resend", invitationController.Resend)
+	group.DELETE("/invitations/:id", invitationController.Revoke)
+}
+
+func ImpersonationRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	auditRepo := repositories.NewAuditRepository(db, domain.CollectionAudit)
+	impersonationController := &controllers.ImpersonationController{
+		ImpersonationUsecase: usecases.NewImpersonationUsecase(userRepo, orgRepo, auditRepo, timeout),
+	}
+
+	group.POST("/users/:id/impersonate", infrastructure.BlockImpersonation(), impersonationController.Impersonate)
+	group.GET("/audit", impersonationController.FetchAuditLog)
+}
+
+func newPrivacyController(timeout time.Duration, db *mongo.Database) *controllers.PrivacyController {
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	taskRepo := repositories.NewTaskRepository(db, domain.CollectionTask)
+	commentRepo := repositories.NewCommentRepository(db, domain.CollectionComment)
+	feedRepo := repositories.NewCalendarFeedRepository(db, domain.CollectionCalendarFeed)
+	viewRepo := repositories.NewViewRepository(db, domain.CollectionView, domain.CollectionViewDefault)
+	auditRepo := repositories.NewAuditRepository(db, domain.CollectionAudit)
+	return &controllers.PrivacyController{
+		PrivacyUsecase: usecases.NewPrivacyUsecase(userRepo, orgRepo, newSessionRepository(db), taskRepo, commentRepo, feedRepo, viewRepo, auditRepo, newOutboxRepository(db), repositories.NewTransactor(db), timeout),
+	}
+}
+
+func PrivacyRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	privacyController := newPrivacyController(timeout, db)
+
+	group.GET("/me/export", infrastructure.BlockImpersonation(), privacyController.Export)
+	group.POST("/me/erase", infrastructure.BlockImpersonation(), privacyController.EraseMe)
+}
+
+func AdminPrivacyRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	privacyController := newPrivacyController(timeout, db)
+
+	group.POST("/users/:id/erase", privacyController.Erase)
+}
+
+func OrganizationRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	orgController := &controllers.OrganizationController{
+		OrganizationUsecase: usecases.NewOrganizationUsecase(orgRepo, userRepo, timeout),
+		UserUsecase:         usecases.NewUserUsecase(userRepo, orgRepo, newSessionRepository(db), newOutboxRepository(db), repositories.NewTransactor(db), timeout),
+	}
+
+	group.GET("/orgs", orgController.FetchMine)
+	group.POST("/orgs", orgController.Create)
+	group.POST("/orgs/:id/switch", infrastructure.BlockImpersonation(), orgController.Switch)
+}
+
+func MemberRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	orgController := &controllers.OrganizationController{
+		OrganizationUsecase: usecases.NewOrganizationUsecase(orgRepo, userRepo, timeout),
+	}
+
+	group.GET("/org/members", orgController.FetchMembers)
+	group.POST("/org/members", infrastructure.OrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), orgController.AddMember)
+	group.PATCH("/org/members/:user_id", infrastructure.OrgRole(domain.OrgRoleOwner), orgController.UpdateMemberRole)
+	group.DELETE("/org/members/:user_id", infrastructure.OrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), orgController.RemoveMember)
+}
+
+func OIDCRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	config, ok := infrastructure.OIDCConfigFromEnv()
+	if !ok {
+		return
+	}
+	userRepo := repositories.NewUserRepository(db, domain.CollectionUser)
+	orgRepo := repositories.NewOrganizationRepository(db, domain.CollectionOrganization, domain.CollectionMembership)
+	userUsecase := usecases.NewUserUsecase(userRepo, orgRepo, newSessionRepository(db), newOutboxRepository(db), repositories.NewTransactor(db), timeout)
+	oidcController := &controllers.OIDCController{
+		Provider:    infrastructure.NewOIDCProvider(config),
+		UserUsecase: userUsecase,
+	}
+
+	group.GET("/auth/oidc/login", oidcController.Login)
+	group.GET("/auth/oidc/callback", oidcController.Callback)
+}
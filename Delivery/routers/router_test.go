@@ -0,0 +1,42 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"task-manger-api_test/Domain/mocks"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestCalendarRouter_BlocksFeedTokensWhileImpersonating(t *testing.T) {
+	if _, err := infrastructure.InitJWT(infrastructure.JWTConfig{Issuer: "test-issuer", Audience: "test-audience", Keys: infrastructure.KeyManagerConfig{Algorithm: infrastructure.AlgorithmEdDSA, VerifyFor: time.Hour}}); err != nil {
+		t.Fatal(err)
+	}
+	// the client connects lazily, and blocked requests never reach it
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI("mongodb://localhost:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	CalendarRouter(time.Second, client.Database("test"), router.Group("", infrastructure.AuthMiddleware(new(mocks.SessionRepository))))
+
+	token, err := infrastructure.GenerateImpersonationToken(infrastructure.Actor{Sub: "admin-id"}, "user-id", "user", "user@example.com", "USER", "org-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{http.MethodPost, http.MethodDelete} {
+		req := httptest.NewRequest(method, "/calendar/feed", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusForbidden {
+			t.Errorf("%s /calendar/feed while impersonating = %d, want %d", method, recorder.Code, http.StatusForbidden)
+		}
+	}
+}
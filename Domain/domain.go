@@ -2,6 +2,10 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +16,30 @@ const (
 	DatabaseName = "taskmanager"
 	CollectionTask = "tasks"
 	CollectionUser = "users"
+	CollectionOrganization = "organizations"
+	CollectionMembership = "memberships"
+	CollectionInvitation = "invitations"
+	CollectionAudit = "audit_log"
+	CollectionSession = "sessions"
+	CollectionComment = "comments"
+	CollectionAttachment = "attachments"
+	CollectionTimeEntry = "time_entries"
+	CollectionCustomField = "custom_fields"
+	CollectionTaskTemplate = "task_templates"
+	CollectionView = "views"
+	CollectionViewDefault = "view_defaults"
+	CollectionImportJob = "import_jobs"
+	CollectionCalendarFeed = "calendar_feeds"
+	CollectionWebhook = "webhooks"
+	CollectionWebhookDelivery = "webhook_deliveries"
+	CollectionOutbox = "outbox"
+)
+
+// Organization roles, from most to least privileged.
+const (
+	OrgRoleOwner = "OWNER"
+	OrgRoleAdmin = "ADMIN"
+	OrgRoleMember = "MEMBER"
 )
 
 type Task struct {
@@ -20,6 +48,794 @@ type Task struct {
  Description string    `json:"description"`
  DueDate     time.Time `json:"due_date"`
  Status      string    `json:"status"`
+ Org_id      string    `json:"org_id" bson:"org_id"`
+ Created_by  string    `json:"created_by" bson:"created_by"`
+ Assignee_id string    `json:"assignee_id" bson:"assignee_id"`
+ Labels      []string  `json:"labels" bson:"labels,omitempty" binding:"max=20,dive,min=1,max=40"`
+ // Parent_id is the ID of the task this one is a subtask of.
+ Parent_id   string    `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+ // External_id identifies an imported task in the system it came from,
+ // so importing the same rows again updates it instead of duplicating.
+ External_id string    `json:"external_id,omitempty" bson:"external_id,omitempty"`
+ Checklist   []ChecklistItem `json:"checklist" bson:"checklist,omitempty" binding:"dive"`
+ // Estimates are in minutes; zero means not estimated.
+ Original_estimate  int  `json:"original_estimate" bson:"original_estimate" binding:"min=0"`
+ Remaining_estimate int  `json:"remaining_estimate" bson:"remaining_estimate" binding:"min=0"`
+ // Custom_fields holds values for the organization's custom fields, keyed
+ // by CustomField.Key and checked against their definitions.
+ Custom_fields map[string]interface{} `json:"custom_fields,omitempty" bson:"custom_fields,omitempty"`
+ // Completion is the percentage of checklist items done, left out when
+ // the task has no checklist.
+ Completion  *int      `json:"completion,omitempty" bson:"-"`
+}
+
+// ChecklistItem is one step of a task's checklist. Items are kept in order
+// inside the task document.
+type ChecklistItem struct {
+	ID			primitive.ObjectID	`json:"id" bson:"_id"`
+	Text		string			`json:"text" bson:"text" binding:"required,max=500"`
+	Done		bool			`json:"done" bson:"done"`
+	Done_at		*time.Time		`json:"done_at,omitempty" bson:"done_at,omitempty"`
+}
+
+// Custom field types.
+const (
+	CustomFieldText = "text"
+	CustomFieldNumber = "number"
+	CustomFieldEnum = "enum"
+	CustomFieldDate = "date"
+	CustomFieldUser = "user"
+)
+
+// CustomField defines an extra task attribute for one organization.
+type CustomField struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	// Key names the value in Task.Custom_fields and cannot change.
+	Key				string			`json:"key" bson:"key" binding:"required,min=1,max=40"`
+	Name			string			`json:"name" bson:"name" binding:"required,max=100"`
+	Type			string			`json:"type" bson:"type" binding:"required,oneof=text number enum date user"`
+	Required		bool			`json:"required" bson:"required"`
+	// Options lists the allowed values of an enum field.
+	Options			[]string		`json:"options,omitempty" bson:"options,omitempty"`
+	// Default fills the field on new tasks that leave it out, and on
+	// existing tasks when the field becomes required.
+	Default			interface{}		`json:"default,omitempty" bson:"default,omitempty"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+}
+
+// CustomFieldUpdate holds the definition fields that may change. Nil fields
+// are left untouched; key and type are fixed once created.
+type CustomFieldUpdate struct {
+	Name			*string			`json:"name" binding:"omitempty,max=100"`
+	Required		*bool			`json:"required"`
+	Options			*[]string		`json:"options"`
+	Default			interface{}		`json:"default"`
+}
+
+// TaskQuery is what a client asks GET /tasks for, before validation.
+type TaskQuery struct {
+	// Custom_fields maps custom field keys to the value tasks must have.
+	Custom_fields	map[string]string
+	// Sort names a field to order by, prefixed with "-" for descending:
+	// title, status, due_date or cf.<key>.
+	Sort			string
+	// Filter is a filter expression such as
+	// status in (todo, blocked) and (due < 2026-11-01 or priority = high).
+	Filter			string
+}
+
+// TaskFilter is a checked TaskQuery, ready for TaskRepository.FetchAll.
+type TaskFilter struct {
+	// Custom_fields holds typed values to match exactly.
+	Custom_fields	map[string]interface{}
+	// Sort_by is a document field path; empty keeps insertion order.
+	Sort_by			string
+	Descending		bool
+	// Expression further narrows the tasks when set.
+	Expression		*FilterExpr
+}
+
+// Filter expression node kinds.
+const (
+	FilterAnd = "and"
+	FilterOr = "or"
+	FilterNot = "not"
+	FilterCompare = "compare"
+)
+
+// Filter expression comparison operators.
+const (
+	FilterEq = "="
+	FilterNe = "!="
+	FilterLt = "<"
+	FilterLte = "<="
+	FilterGt = ">"
+	FilterGte = ">="
+	FilterIn = "in"
+	FilterContains = "contains"
+)
+
+// FilterExpr is a checked task filter expression. And, or and not nodes
+// combine Children; compare nodes test the document field at path Field
+// against Values, already converted to the field's type. On list fields a
+// comparison holds when any element satisfies it.
+type FilterExpr struct {
+	Kind			string
+	Children		[]FilterExpr
+	Field			string
+	Operator		string
+	Values			[]interface{}
+}
+
+// FilterError points at the character of a filter expression, counted
+// from 1, where parsing or checking failed.
+type FilterError struct {
+	Position		int
+	Message			string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%v at position %d: %s", ErrInvalidFilter, e.Position, e.Message)
+}
+
+func (e *FilterError) Unwrap() error {
+	return ErrInvalidFilter
+}
+
+// TaskSearchRequest is what a client asks GET /tasks/search for. Q holds
+// words, "quoted phrases" and prefix* terms, all of which must match.
+type TaskSearchRequest struct {
+	Q				string
+	// Status, Label and the Filter expression narrow the hits without
+	// changing the facets.
+	Status			string
+	Label			string
+	Filter			string
+	Page			int64
+	Limit			int64
+}
+
+// TaskSearch is a parsed search, as repositories look for candidates.
+type TaskSearch struct {
+	// Text is a MongoDB $text search string of the words and phrases.
+	Text			string
+	// Prefixes are lowercase word beginnings the text index cannot match.
+	Prefixes		[]string
+	// Task_ids adds tasks found through their comments.
+	Task_ids		[]string
+	Limit			int64
+}
+
+type TaskSearchResult struct {
+	Hits			[]TaskSearchHit	`json:"hits"`
+	Total			int				`json:"total"`
+	Page			int64			`json:"page"`
+	Limit			int64			`json:"limit"`
+	Facets			TaskSearchFacets	`json:"facets"`
+	// Truncated is set when the query found more candidates than are
+	// looked at, so Total and Facets count only some of the matches.
+	Truncated		bool			`json:"truncated"`
+}
+
+type TaskSearchHit struct {
+	Task			Task			`json:"task"`
+	Score			float64			`json:"score"`
+	Highlights		[]SearchHighlight	`json:"highlights"`
+}
+
+// SearchHighlight is an HTML-escaped excerpt of a matching field with the
+// matches wrapped in <mark> tags.
+type SearchHighlight struct {
+	// Field is title, description or comment.
+	Field			string			`json:"field"`
+	Comment_id		string			`json:"comment_id,omitempty"`
+	Snippet			string			`json:"snippet"`
+}
+
+// TaskSearchFacets count every task matching the query by status and label,
+// unless the result is Truncated.
+type TaskSearchFacets struct {
+	Status			map[string]int	`json:"status"`
+	Labels			map[string]int	`json:"labels"`
+}
+
+// View visibilities.
+const (
+	ViewPrivate = "PRIVATE"
+	ViewShared = "SHARED"
+)
+
+// View is a saved task listing: a filter expression, a sort and the
+// columns to show. Private views are only visible to their owner; shared
+// ones to the whole organization.
+type View struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	Owner_id		string			`json:"owner_id" bson:"owner_id"`
+	Name			string			`json:"name" bson:"name" binding:"required,max=100"`
+	Filter			string			`json:"filter" bson:"filter" binding:"max=2000"`
+	Sort			string			`json:"sort" bson:"sort"`
+	Columns			[]string		`json:"columns" bson:"columns" binding:"max=30"`
+	Visibility		string			`json:"visibility" bson:"visibility" binding:"omitempty,oneof=PRIVATE SHARED"`
+	// Default is set when the requesting user picked this view as theirs.
+	Default			bool			`json:"default" bson:"-"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+	Updated_at		time.Time		`json:"updated_at" bson:"updated_at"`
+}
+
+// ViewUpdate holds the view fields to change; nil fields are left alone.
+type ViewUpdate struct {
+	Name			*string			`json:"name" binding:"omitempty,max=100"`
+	Filter			*string			`json:"filter" binding:"omitempty,max=2000"`
+	Sort			*string			`json:"sort"`
+	Columns			*[]string		`json:"columns" binding:"omitempty,max=30"`
+	Visibility		*string			`json:"visibility" binding:"omitempty,oneof=PRIVATE SHARED"`
+}
+
+// ViewTasks is a view together with the tasks it currently lists.
+type ViewTasks struct {
+	View			View			`json:"view"`
+	Tasks			[]Task			`json:"tasks"`
+}
+
+// Bulk task operations.
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// BulkTaskRequest is either a list of operations or a filter expression
+// with one update applied to every task it matches. Atomic requests run in
+// a single transaction, so one failure rolls every change back.
+type BulkTaskRequest struct {
+	Operations		[]BulkTaskOperation	`json:"operations" binding:"dive"`
+	Filter			string			`json:"filter" binding:"max=2000"`
+	Update			*TaskPatch		`json:"update"`
+	Atomic			bool			`json:"atomic"`
+}
+
+// BulkTaskOperation creates Task, or updates or deletes the task Task_id
+// names.
+type BulkTaskOperation struct {
+	Op				string			`json:"op" binding:"required,oneof=create update delete"`
+	Task_id			string			`json:"task_id"`
+	Task			*Task			`json:"task"`
+	Update			*TaskPatch		`json:"update"`
+}
+
+// TaskPatch holds the task fields to change; nil fields are left alone.
+// Custom field values are merged into the task's, and a null value clears
+// one.
+type TaskPatch struct {
+	Title				*string			`json:"title"`
+	Description			*string			`json:"description"`
+	Status				*string			`json:"status"`
+	Due_date			*time.Time		`json:"due_date"`
+	Assignee_id			*string			`json:"assignee_id"`
+	Labels				*[]string		`json:"labels" binding:"omitempty,max=20,dive,min=1,max=40"`
+	Original_estimate	*int			`json:"original_estimate" binding:"omitempty,min=0"`
+	Remaining_estimate	*int			`json:"remaining_estimate" binding:"omitempty,min=0"`
+	Custom_fields		map[string]interface{}	`json:"custom_fields"`
+}
+
+// BulkTaskItemResult is the outcome of one operation, or of the update of
+// one matched task. Index is the operation's position in the request.
+type BulkTaskItemResult struct {
+	Index			int				`json:"index"`
+	Op				string			`json:"op"`
+	Task_id			string			`json:"task_id,omitempty"`
+	Success			bool			`json:"success"`
+	Error			string			`json:"error,omitempty"`
+}
+
+// BulkTaskResult reports every item of a bulk request. Committed is false
+// when an atomic request was rolled back.
+type BulkTaskResult struct {
+	Atomic			bool				`json:"atomic"`
+	Committed		bool				`json:"committed"`
+	Succeeded		int					`json:"succeeded"`
+	Failed			int					`json:"failed"`
+	Results			[]BulkTaskItemResult	`json:"results"`
+}
+
+// Export formats.
+const (
+	ExportCSV = "csv"
+	ExportNDJSON = "ndjson"
+	ExportMarkdown = "markdown"
+	ExportICS = "ics"
+)
+
+// Calendar feed entry kinds.
+const (
+	CalendarTodo = "todo"
+	CalendarEvent = "event"
+)
+
+// CalendarFeed is a user's secret iCalendar subscription to their tasks in
+// one organization. Only a hash of the token is stored, so the feed URL is
+// shown once, when the feed is created; creating another revokes it.
+type CalendarFeed struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	User_id			string			`json:"user_id" bson:"user_id"`
+	Token_hash		string			`json:"-" bson:"token_hash"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+	Last_used_at	*time.Time		`json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	// URL is only set on a newly created feed.
+	URL				string			`json:"url,omitempty" bson:"-"`
+}
+
+// Task events webhooks can subscribe to.
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	EventTaskDeleted = "task.deleted"
+	EventTaskStatusChanged = "task.status_changed"
+)
+
+var TaskEvents = []string{EventTaskCreated, EventTaskUpdated, EventTaskDeleted, EventTaskStatusChanged}
+
+// User events.
+const (
+	EventUserRegistered = "user.registered"
+	EventUserPromoted = "user.promoted"
+	EventUserDemoted = "user.demoted"
+	EventUserDeactivated = "user.deactivated"
+	EventUserReactivated = "user.reactivated"
+	EventUserPasswordChanged = "user.password_changed"
+	EventUserDeleted = "user.deleted"
+	EventUserErased = "user.erased"
+)
+
+// AllEvents subscribes to every event published on an EventBus.
+const AllEvents = "*"
+
+// DomainEvent is a change to an aggregate, such as a task or a user,
+// published once the change is stored.
+type DomainEvent interface {
+	EventName() string
+	// AggregateID names the task or user the event is about.
+	AggregateID() string
+}
+
+// Event is a published DomainEvent. ID is unique per Publish and kept by
+// Deliver, so subscribers that see an event twice can tell.
+type Event struct {
+	ID				string
+	Occurred_at		time.Time
+	Payload			DomainEvent
+}
+
+type TaskCreated struct {
+	Task			Task
+}
+
+type TaskUpdated struct {
+	Task			Task
+}
+
+// TaskStatusChanged follows the TaskUpdated of an update that changed
+// the status.
+type TaskStatusChanged struct {
+	Task			Task
+	Previous_status	string
+}
+
+// TaskDeleted carries the task as it was before the deletion.
+type TaskDeleted struct {
+	Task			Task
+}
+
+func (e TaskCreated) EventName() string {
+	return EventTaskCreated
+}
+
+func (e TaskCreated) AggregateID() string {
+	return e.Task.ID.Hex()
+}
+
+func (e TaskUpdated) EventName() string {
+	return EventTaskUpdated
+}
+
+func (e TaskUpdated) AggregateID() string {
+	return e.Task.ID.Hex()
+}
+
+func (e TaskStatusChanged) EventName() string {
+	return EventTaskStatusChanged
+}
+
+func (e TaskStatusChanged) AggregateID() string {
+	return e.Task.ID.Hex()
+}
+
+func (e TaskDeleted) EventName() string {
+	return EventTaskDeleted
+}
+
+func (e TaskDeleted) AggregateID() string {
+	return e.Task.ID.Hex()
+}
+
+
+// UserRegistered is published for sign ups and for first logins through
+// an identity provider.
+type UserRegistered struct {
+	User_id			string
+	Username		string
+	User_type		string
+}
+
+type UserPromoted struct {
+	User_id			string
+}
+
+type UserDemoted struct {
+	User_id			string
+}
+
+type UserDeactivated struct {
+	User_id			string
+}
+
+type UserReactivated struct {
+	User_id			string
+}
+
+type UserPasswordChanged struct {
+	User_id			string
+}
+
+type UserDeleted struct {
+	User_id			string
+}
+
+// UserErased is published when a user's personal data is erased. The
+// anonymized record is kept, unlike after UserDeleted.
+type UserErased struct {
+	User_id			string
+}
+
+func (e UserRegistered) EventName() string {
+	return EventUserRegistered
+}
+
+func (e UserRegistered) AggregateID() string {
+	return e.User_id
+}
+
+func (e UserPromoted) EventName() string {
+	return EventUserPromoted
+}
+
+func (e UserPromoted) AggregateID() string {
+	return e.User_id
+}
+
+func (e UserDemoted) EventName() string {
+	return EventUserDemoted
+}
+
+func (e UserDemoted) AggregateID() string {
+	return e.User_id
+}
+
+func (e UserDeactivated) EventName() string {
+	return EventUserDeactivated
+}
+
+func (e UserDeactivated) AggregateID() string {
+	return e.User_id
+}
+
+func (e UserReactivated) EventName() string {
+	return EventUserReactivated
+}
+
+func (e UserReactivated) AggregateID() string {
+	return e.User_id
+}
+
+func (e UserPasswordChanged) EventName() string {
+	return EventUserPasswordChanged
+}
+
+func (e UserPasswordChanged) AggregateID() string {
+	return e.User_id
+}
+
+func (e UserDeleted) EventName() string {
+	return EventUserDeleted
+}
+
+func (e UserDeleted) AggregateID() string {
+	return e.User_id
+}
+
+func (e UserErased) EventName() string {
+	return EventUserErased
+}
+
+func (e UserErased) AggregateID() string {
+	return e.User_id
+}
+
+
+// Outbox record statuses.
+const (
+	OutboxPending = "PENDING"
+	OutboxDelivered = "DELIVERED"
+	// OutboxFailed records are kept, undelivered, once the relay gives up.
+	OutboxFailed = "FAILED"
+)
+
+// OutboxRecord is a domain event stored in the transaction of the change
+// that raised it, waiting for the relay to publish it. Event_id is the
+// Event.ID of every publication, so subscribers can drop the duplicates
+// at-least-once delivery brings.
+type OutboxRecord struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Event_id		string			`json:"event_id" bson:"event_id"`
+	Name			string			`json:"name" bson:"name"`
+	Aggregate_id	string			`json:"aggregate_id" bson:"aggregate_id"`
+	// Org_id is the tenant the event was raised in, if any.
+	Org_id			string			`json:"org_id,omitempty" bson:"org_id,omitempty"`
+	// Payload is the event encoded as JSON.
+	Payload			string			`json:"payload" bson:"payload"`
+	Status			string			`json:"status" bson:"status"`
+	Attempts		int				`json:"attempts" bson:"attempts"`
+	Last_error		string			`json:"last_error,omitempty" bson:"last_error,omitempty"`
+	Occurred_at		time.Time		`json:"occurred_at" bson:"occurred_at"`
+	Next_attempt_at	time.Time		`json:"next_attempt_at" bson:"next_attempt_at"`
+	// Locked_until is when a relay's claim on the record runs out.
+	Locked_until	time.Time		`json:"locked_until" bson:"locked_until"`
+	Delivered_at	*time.Time		`json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+}
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending = "PENDING"
+	DeliverySucceeded = "SUCCEEDED"
+	DeliveryFailed = "FAILED"
+)
+
+// Webhook posts the organization's task events to URL, signed with
+// Secret. Secret is only returned when the webhook is created or the
+// secret changed. Failure_count counts deliveries in a row that failed
+// every retry; enough of them disable the webhook.
+type Webhook struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	URL				string			`json:"url" bson:"url" binding:"required,url,max=2000"`
+	Events			[]string		`json:"events" bson:"events" binding:"required,min=1,dive,required"`
+	Secret			string			`json:"secret,omitempty" bson:"secret"`
+	Active			bool			`json:"active" bson:"active"`
+	Failure_count	int				`json:"failure_count" bson:"failure_count"`
+	Disabled_at		*time.Time		`json:"disabled_at,omitempty" bson:"disabled_at,omitempty"`
+	Disabled_reason	string			`json:"disabled_reason,omitempty" bson:"disabled_reason,omitempty"`
+	Created_by		string			`json:"created_by" bson:"created_by"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+	Updated_at		time.Time		`json:"updated_at" bson:"updated_at"`
+}
+
+// WebhookUpdate changes the fields that are set. Setting Active re-enables
+// a disabled webhook and clears its failures.
+type WebhookUpdate struct {
+	URL				*string			`json:"url" binding:"omitempty,url,max=2000"`
+	Events			*[]string		`json:"events" binding:"omitempty,min=1,dive,required"`
+	Secret			*string			`json:"secret"`
+	Active			*bool			`json:"active"`
+}
+
+// WebhookEvent is the JSON body of a delivery. ID stays the same across
+// retries and redeliveries so receivers can drop duplicates.
+type WebhookEvent struct {
+	ID				string			`json:"id"`
+	Type			string			`json:"type"`
+	Org_id			string			`json:"org_id"`
+	Occurred_at		time.Time		`json:"occurred_at"`
+	Task			Task			`json:"task"`
+	// Previous_status is set on task.status_changed.
+	Previous_status	string			`json:"previous_status,omitempty"`
+}
+
+// WebhookAttempt records one POST of a delivery.
+type WebhookAttempt struct {
+	At				time.Time		`json:"at" bson:"at"`
+	Response_code	int				`json:"response_code,omitempty" bson:"response_code,omitempty"`
+	// Response_body is cut to the first kilobyte.
+	Response_body	string			`json:"response_body,omitempty" bson:"response_body,omitempty"`
+	Error			string			`json:"error,omitempty" bson:"error,omitempty"`
+	Duration_ms		int64			`json:"duration_ms" bson:"duration_ms"`
+}
+
+// WebhookDelivery is one event sent to one webhook, with every attempt
+// made. A redelivery is a new delivery of the same payload.
+type WebhookDelivery struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	Webhook_id		string			`json:"webhook_id" bson:"webhook_id"`
+	Event_id		string			`json:"event_id" bson:"event_id"`
+	Event			string			`json:"event" bson:"event"`
+	Payload			string			`json:"payload" bson:"payload"`
+	Status			string			`json:"status" bson:"status"`
+	Attempts		[]WebhookAttempt	`json:"attempts" bson:"attempts"`
+	// Redelivery_of is stored empty for first deliveries, which a unique
+	// index keeps to one per webhook and event.
+	Redelivery_of	string			`json:"redelivery_of,omitempty" bson:"redelivery_of"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+	Completed_at	*time.Time		`json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// WebhookResponse is what a webhook endpoint answered.
+type WebhookResponse struct {
+	Status_code		int
+	Body			string
+}
+
+// Import formats.
+const (
+	ImportCSV = "csv"
+	ImportJSON = "json"
+)
+
+// MaxImportBytes bounds the size of an import file.
+const MaxImportBytes = 5 << 20
+
+// Import job statuses.
+const (
+	ImportQueued = "QUEUED"
+	ImportRunning = "RUNNING"
+	ImportDone = "DONE"
+	ImportFailed = "FAILED"
+)
+
+// TaskImportRequest is a CSV or JSON file of tasks. Mapping renames CSV
+// columns or JSON keys to task fields (title, description, status,
+// due_date, assignee_id, labels, original_estimate, remaining_estimate,
+// external_id or cf.<key>); columns already named after a field need no
+// mapping. A dry run checks every row without writing anything.
+type TaskImportRequest struct {
+	Format			string
+	Mapping			map[string]string
+	Data			[]byte
+	Dry_run			bool
+	// Async queues the import even when it is small enough to run
+	// straight away.
+	Async			bool
+}
+
+// ImportRowError explains why one row was not imported. Row is the line a
+// CSV record starts on, or the position of a JSON object counting from 1.
+type ImportRowError struct {
+	Row				int				`json:"row" bson:"row"`
+	External_id		string			`json:"external_id,omitempty" bson:"external_id,omitempty"`
+	Message			string			`json:"message" bson:"message"`
+}
+
+// ImportJob tracks an import and, for queued ones, its progress. Created
+// and Updated count what a dry run would have done.
+type ImportJob struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	Created_by		string			`json:"created_by" bson:"created_by"`
+	Status			string			`json:"status" bson:"status"`
+	Dry_run			bool			`json:"dry_run" bson:"dry_run"`
+	Total			int				`json:"total" bson:"total"`
+	Processed		int				`json:"processed" bson:"processed"`
+	Created			int				`json:"created" bson:"created"`
+	Updated			int				`json:"updated" bson:"updated"`
+	Failed			int				`json:"failed" bson:"failed"`
+	// Errors lists the first failing rows; Failed counts all of them.
+	Errors			[]ImportRowError	`json:"errors" bson:"errors"`
+	Message			string			`json:"message,omitempty" bson:"message,omitempty"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+	Finished_at		*time.Time		`json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+// TaskTemplate is a saved shape of task that can be instantiated over and
+// over. Titles, descriptions and checklist items may hold {{name}}
+// placeholders, filled in from the variables given when instantiating.
+type TaskTemplate struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	Name			string			`json:"name" bson:"name" binding:"required,max=100"`
+	Task			TemplateTask	`json:"task" bson:"task" binding:"required"`
+	// Variables lists the placeholders the template uses, apart from the
+	// built-in start_date.
+	Variables		[]string		`json:"variables" bson:"variables"`
+	Created_by		string			`json:"created_by" bson:"created_by"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+}
+
+// TemplateTask describes one task of a template and its subtasks.
+type TemplateTask struct {
+	Title			string			`json:"title" bson:"title" binding:"required,max=200"`
+	Description		string			`json:"description" bson:"description"`
+	Status			string			`json:"status" bson:"status"`
+	Labels			[]string		`json:"labels,omitempty" bson:"labels,omitempty"`
+	Checklist		[]string		`json:"checklist,omitempty" bson:"checklist,omitempty"`
+	// Due_in sets the due date relative to the start of the instantiation,
+	// as days ("3d"), weeks ("2w") or a duration ("36h"). Empty leaves the
+	// task without a relative due date.
+	Due_in			string			`json:"due_in,omitempty" bson:"due_in,omitempty"`
+	Custom_fields	map[string]interface{}	`json:"custom_fields,omitempty" bson:"custom_fields,omitempty"`
+	Subtasks		[]TemplateTask	`json:"subtasks,omitempty" bson:"subtasks,omitempty" binding:"dive"`
+}
+
+// TemplateInstantiation is the request to create tasks from a template.
+type TemplateInstantiation struct {
+	Variables		map[string]string	`json:"variables"`
+	// Start anchors relative due dates, as a date or RFC 3339 time;
+	// it defaults to now.
+	Start			string			`json:"start"`
+	Assignee_id		string			`json:"assignee_id"`
+}
+
+// TaskTree is a task together with the subtasks created with it.
+type TaskTree struct {
+	Task
+	Subtasks		[]TaskTree		`json:"subtasks,omitempty"`
+}
+
+// TimeEntry is time a user spent on a task. A running timer is an entry
+// without Ended_at; a user has at most one.
+type TimeEntry struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	Task_id			string			`json:"task_id" bson:"task_id"`
+	User_id			string			`json:"user_id" bson:"user_id"`
+	Started_at		time.Time		`json:"started_at" bson:"started_at"`
+	Ended_at		*time.Time		`json:"ended_at,omitempty" bson:"ended_at,omitempty"`
+	Running			bool			`json:"running" bson:"running"`
+	// Duration_seconds is derived from the start and end. When logging time
+	// by hand it may be given instead of Ended_at.
+	Duration_seconds	int64		`json:"duration_seconds" bson:"-" binding:"min=0"`
+	Note			string			`json:"note" bson:"note" binding:"max=500"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+}
+
+// Time report groupings.
+const (
+	TimeReportByUser = "user"
+	TimeReportByTask = "task"
+	TimeReportByDay = "day"
+)
+
+// TimeReportFilter selects finished entries started in [From, To).
+type TimeReportFilter struct {
+	Group_by		string
+	From			time.Time
+	To				time.Time
+	User_id			string
+	Task_id			string
+}
+
+type TimeReportRow struct {
+	// Key is a user ID, a task ID or a UTC date (2006-01-02) depending on
+	// the grouping.
+	Key				string		`json:"key" bson:"_id"`
+	Seconds			int64		`json:"seconds" bson:"seconds"`
+	Entries			int64		`json:"entries" bson:"entries"`
+}
+
+type TimeReport struct {
+	Group_by		string			`json:"group_by"`
+	From			time.Time		`json:"from"`
+	To				time.Time		`json:"to"`
+	Rows			[]TimeReportRow	`json:"rows"`
+	Total_seconds	int64			`json:"total_seconds"`
+}
+
+// ChecklistItemUpdate holds the item fields to change. Nil fields are left
+// untouched.
+type ChecklistItemUpdate struct {
+	Text		*string		`json:"text" binding:"omitempty,max=500"`
+	Done		*bool		`json:"done"`
 }
 
 type User struct{
@@ -32,42 +848,819 @@ type User struct{
 	Created_at		time.Time		`json:"created_at"`
 	Updated_at		time.Time		`json:"updated_at"`
 	User_id			string			`json:"user_id"`
+	Identities		[]ExternalIdentity	`json:"identities,omitempty" bson:"identities,omitempty"`
+	// Email_verified_at is set once the user has shown they own Email, by
+	// accepting an invitation mailed to it or signing in through a
+	// provider that verified it. Only verified emails are linked to
+	// external identities.
+	Email_verified_at	*time.Time	`json:"email_verified_at,omitempty" bson:"email_verified_at,omitempty"`
+	Deactivated_at	*time.Time		`json:"deactivated_at,omitempty" bson:"deactivated_at,omitempty"`
+	Erased_at		*time.Time		`json:"erased_at,omitempty" bson:"erased_at,omitempty"`
+}
+
+// MarshalJSON leaves out the password hash. Password is still read from
+// request bodies on signup and login.
+func (u User) MarshalJSON() ([]byte, error) {
+	type publicUser User
+	view := publicUser(u)
+	view.Password = nil
+	return json.Marshal(struct {
+		publicUser
+		Password *string `json:"password,omitempty"`
+	}{publicUser: view})
+}
+
+func (u *User) IsActive() bool {
+	return u.Deactivated_at == nil
+}
+
+// ProfileUpdate holds the profile fields a user may change about themselves.
+// Nil fields are left untouched.
+type ProfileUpdate struct {
+	Name		*string		`json:"name" validate:"omitempty,min=2,max=100"`
+	Username	*string		`json:"username" validate:"omitempty,min=2,max=100"`
+	Email		*string		`json:"email" validate:"omitempty,email"`
+}
+
+type PasswordChange struct {
+	Current_password	string	`json:"current_password" binding:"required"`
+	New_password		string	`json:"new_password" binding:"required,min=6"`
+}
+
+type UserFilter struct {
+	Query		string
+	User_type	string
+	Page		int64
+	Limit		int64
+}
+
+type UserPage struct {
+	Users	[]User	`json:"users"`
+	Total	int64	`json:"total"`
+	Page	int64	`json:"page"`
+	Limit	int64	`json:"limit"`
+}
+
+var (
+	ErrUserNotFound = errors.New("USER NOT FOUND")
+	ErrUserDeactivated = errors.New("this account has been deactivated")
+	ErrIncorrectPassword = errors.New("password is incorrect")
+	ErrNoTenant = errors.New("no active organization")
+	ErrNotMember = errors.New("you are not a member of this organization")
+	ErrLastOwner = errors.New("an organization must keep at least one owner")
+	ErrOwnerRequired = errors.New("only owners can remove owners")
+	ErrRegistrationClosed = errors.New("registration is by invitation only")
+	ErrEmailTaken = errors.New("a user with this email already exists")
+	ErrEmailNotVerified = errors.New("a user with this email already exists but has not verified it; sign in with your password instead")
+	ErrTooManyLogins = errors.New("too many logins are in progress, try again later")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationPending = errors.New("an invitation is already pending for this email")
+	ErrInvitationInvalid = errors.New("this invitation is invalid or has already been used")
+	ErrInvitationExpired = errors.New("this invitation has expired")
+	ErrCannotImpersonate = errors.New("this user cannot be impersonated")
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked = errors.New("this session has been revoked")
+	ErrUserErased = errors.New("this account has been erased")
+	ErrTaskNotFound = errors.New("task not found")
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrNotCommentAuthor = errors.New("only the author can change this comment")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentTooLarge = errors.New("attachment exceeds the maximum upload size")
+	ErrNotUploader = errors.New("only the uploader can remove this attachment")
+	ErrBlobNotFound = errors.New("blob not found")
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrChecklistChanged = errors.New("the checklist changed, reload it and try again")
+	ErrInvalidChecklistOrder = errors.New("the new order must list every checklist item exactly once")
+	ErrTimerRunning = errors.New("you already have a timer running")
+	ErrNoRunningTimer = errors.New("you have no timer running")
+	ErrInvalidTimeEntry = errors.New("a time entry needs a start and either an end after it or a duration")
+	ErrInvalidTimeRange = errors.New("the report range must start before it ends")
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	ErrInvalidCustomField = errors.New("invalid custom field")
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+	ErrCustomFieldExists = errors.New("a custom field with this key already exists")
+	ErrCustomFieldInUse = errors.New("tasks still use the values this change would remove")
+	ErrInvalidTaskQuery = errors.New("invalid task query")
+	ErrNotTimeEntryOwner = errors.New("only the user who logged this time can remove it")
+	ErrTaskTemplateNotFound = errors.New("task template not found")
+	ErrInvalidTaskTemplate = errors.New("invalid task template")
+	ErrMissingTemplateVariable = errors.New("missing template variables")
+	ErrInvalidSearch = errors.New("invalid search")
+	ErrInvalidFilter = errors.New("invalid filter")
+	ErrViewNotFound = errors.New("view not found")
+	ErrNotViewOwner = errors.New("only the owner can change this view")
+	ErrInvalidView = errors.New("invalid view")
+	ErrInvalidBulkRequest = errors.New("invalid bulk request")
+	ErrBulkTooLarge = errors.New("too many tasks for one bulk request")
+	ErrInvalidImport = errors.New("invalid import")
+	ErrImportTooLarge = errors.New("the import file is too large")
+	ErrImportJobNotFound = errors.New("import job not found")
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+	ErrInvalidWebhook = errors.New("invalid webhook")
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrWebhookDisabled = errors.New("the webhook is disabled, enable it first")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookDeliveryExists = errors.New("the event already has a delivery to this webhook")
+	ErrOutboxRecordNotFound = errors.New("outbox record not found")
+)
+
+type Organization struct {
+	ID				primitive.ObjectID	`bson:"_id" json:"id"`
+	Name			string			`json:"name" bson:"name" validate:"required,min=2,max=100"`
+	Created_by		string			`json:"created_by" bson:"created_by"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+	// Role is the requesting user's role, filled in when listing their organizations.
+	Role			string			`json:"role,omitempty" bson:"-"`
+}
+
+// Membership grants a user an org-scoped role.
+type Membership struct {
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	User_id			string			`json:"user_id" bson:"user_id" binding:"required"`
+	Role			string			`json:"role" bson:"role" binding:"required,oneof=OWNER ADMIN MEMBER"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+}
+
+// Invitation statuses, derived from the accepted/revoked/expiry timestamps.
+const (
+	InvitationPending = "PENDING"
+	InvitationAccepted = "ACCEPTED"
+	InvitationRevoked = "REVOKED"
+	InvitationExpired = "EXPIRED"
+)
+
+// Invitation lets an admin bring in a user by email with a pre-assigned
+// user type. Only a hash of the token is stored.
+type Invitation struct {
+	ID				primitive.ObjectID	`bson:"_id" json:"id"`
+	Email			string			`json:"email" bson:"email" binding:"required,email"`
+	User_type		string			`json:"user_type" bson:"user_type" binding:"required,oneof=USER ADMIN"`
+	Token_hash		string			`json:"-" bson:"token_hash"`
+	Invited_by		string			`json:"invited_by" bson:"invited_by"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+	Expires_at		time.Time		`json:"expires_at" bson:"expires_at"`
+	Accepted_at		*time.Time		`json:"accepted_at,omitempty" bson:"accepted_at"`
+	Revoked_at		*time.Time		`json:"revoked_at,omitempty" bson:"revoked_at"`
+	Status			string			`json:"status" bson:"-"`
+}
+
+func (i *Invitation) StatusAt(now time.Time) string {
+	switch {
+	case i.Accepted_at != nil:
+		return InvitationAccepted
+	case i.Revoked_at != nil:
+		return InvitationRevoked
+	case !now.Before(i.Expires_at):
+		return InvitationExpired
+	}
+	return InvitationPending
+}
+
+// InvitationAcceptance is the signup form behind an invitation link. The
+// email comes from the invitation itself.
+type InvitationAcceptance struct {
+	Token		string		`json:"token" binding:"required"`
+	Name		string		`json:"name" binding:"required,min=2,max=100"`
+	Username	string		`json:"username" binding:"required,min=2,max=100"`
+	Password	string		`json:"password" binding:"required,min=6"`
+}
+
+// Comment is a Markdown message on a task. Replies point at a top-level
+// comment through Parent_id, so threads are one level deep.
+type Comment struct {
+	ID				primitive.ObjectID	`bson:"_id" json:"id"`
+	Task_id			string			`json:"task_id" bson:"task_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	Parent_id		string			`json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Author_id		string			`json:"author_id" bson:"author_id"`
+	Body			string			`json:"body" bson:"body" binding:"required,max=10000"`
+	// Html is Body rendered and sanitized when comments are read.
+	Html			string			`json:"html" bson:"-"`
+	// Mentions holds the IDs of the users @mentioned in Body.
+	Mentions		[]string		`json:"mentions" bson:"mentions"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+	Edited_at		*time.Time		`json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	Deleted_at		*time.Time		`json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	Replies			[]Comment		`json:"replies,omitempty" bson:"-"`
+}
+
+type CommentPage struct {
+	Comments	[]Comment	`json:"comments"`
+	Total		int64		`json:"total"`
+	Page		int64		`json:"page"`
+	Limit		int64		`json:"limit"`
+}
+
+// Session is one signed-in device. Tokens carry its ID, so revoking the
+// session revokes them.
+type Session struct {
+	ID				primitive.ObjectID	`bson:"_id" json:"id"`
+	User_id			string			`json:"user_id" bson:"user_id"`
+	User_agent		string			`json:"user_agent" bson:"user_agent"`
+	Ip				string			`json:"ip" bson:"ip"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+	Last_seen_at	time.Time		`json:"last_seen_at" bson:"last_seen_at"`
+	Expires_at		time.Time		`json:"expires_at" bson:"expires_at"`
+	Revoked_at		*time.Time		`json:"revoked_at,omitempty" bson:"revoked_at"`
+	// Current marks the session the listing request was made from.
+	Current			bool			`json:"current" bson:"-"`
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.Revoked_at == nil && now.Before(s.Expires_at)
+}
+
+// ClientInfo describes the device a request comes from.
+type ClientInfo struct {
+	User_agent	string
+	Ip			string
+}
+
+type clientContextKey struct{}
+
+func WithClient(ctx context.Context, client ClientInfo) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client)
+}
+
+func ClientFromContext(ctx context.Context) ClientInfo {
+	client, _ := ctx.Value(clientContextKey{}).(ClientInfo)
+	return client
+}
+
+// Audit actions.
+const (
+	AuditImpersonationStart = "impersonation.start"
+	AuditImpersonatedRequest = "impersonation.request"
+	AuditUserErased = "user.erased"
+)
+
+// AuditEntry records something Actor_id did, on behalf of Subject_id when
+// impersonating.
+type AuditEntry struct {
+	ID				primitive.ObjectID	`bson:"_id" json:"id"`
+	Action			string			`json:"action" bson:"action"`
+	Actor_id		string			`json:"actor_id" bson:"actor_id"`
+	Subject_id		string			`json:"subject_id" bson:"subject_id"`
+	Org_id			string			`json:"org_id,omitempty" bson:"org_id,omitempty"`
+	Method			string			`json:"method,omitempty" bson:"method,omitempty"`
+	Path			string			`json:"path,omitempty" bson:"path,omitempty"`
+	Status			int				`json:"status,omitempty" bson:"status,omitempty"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
+}
+
+type AuditFilter struct {
+	Actor_id	string
+	Subject_id	string
+	// Involving matches entries where the user is either actor or subject.
+	Involving	string
+	Limit		int64
+}
+
+// PersonalDataExport is everything stored about one user, for data subject
+// access requests.
+type PersonalDataExport struct {
+	Exported_at		time.Time		`json:"exported_at"`
+	Profile			User			`json:"profile"`
+	Memberships		[]Membership	`json:"memberships"`
+	Sessions		[]Session		`json:"sessions"`
+	Tasks			[]Task			`json:"tasks"`
+	Comments		[]Comment		`json:"comments"`
+	Audit			[]AuditEntry	`json:"audit"`
+}
+
+type tenantContextKey struct{}
+
+// WithTenant scopes ctx to an organization. Repositories refuse to touch
+// tenant data without one.
+func WithTenant(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, orgID)
+}
+
+func TenantFromContext(ctx context.Context) (string, bool) {
+	orgID, ok := ctx.Value(tenantContextKey{}).(string)
+	return orgID, ok && orgID != ""
+}
+
+// ExternalIdentity links a User to an account at an OpenID Connect provider.
+type ExternalIdentity struct {
+	Provider		string		`json:"provider" bson:"provider"`
+	Subject			string		`json:"subject" bson:"subject"`
+	Email			string		`json:"email" bson:"email"`
+	Email_verified	bool		`json:"email_verified" bson:"email_verified"`
+	Name			string		`json:"name" bson:"-"`
+	Username		string		`json:"username" bson:"-"`
+	Linked_at		time.Time	`json:"linked_at" bson:"linked_at"`
+}
+
+// Attachment describes a file attached to a task. The contents live in a
+// BlobStore under Storage_key.
+type Attachment struct {
+	ID				primitive.ObjectID	`json:"id" bson:"_id"`
+	Task_id			string			`json:"task_id" bson:"task_id"`
+	Org_id			string			`json:"org_id" bson:"org_id"`
+	Filename		string			`json:"filename" bson:"filename"`
+	// Content_type is sniffed from the uploaded bytes, not taken from the client.
+	Content_type	string			`json:"content_type" bson:"content_type"`
+	Size			int64			`json:"size" bson:"size"`
+	// Checksum is the hex SHA-256 of the contents.
+	Checksum		string			`json:"checksum" bson:"checksum"`
+	Storage_key		string			`json:"-" bson:"storage_key"`
+	Uploaded_by		string			`json:"uploaded_by" bson:"uploaded_by"`
+	Created_at		time.Time		`json:"created_at" bson:"created_at"`
 }
 
 type Config struct {
     MongoDBURI string
     Port       string
     TimeZone   string
-    SecretKey  string
 	DatabaseName string
 }
 
 type TaskRepository interface {
 	Create(c context.Context, task *Task) error
-	FetchAll(c context.Context) (*[]Task, error)
+	FetchAll(c context.Context, filter TaskFilter) (*[]Task, error)
 	FetchByTaskID(c context.Context, taskID string) (*Task, error)
 	Update(c context.Context, taskID string, updatedTask Task) error
 	Delete(c context.Context, taskID string) error
+	// Stream calls fn with each task matching filter as it is read from
+	// the database, stopping at the first error fn returns.
+	Stream(c context.Context, filter TaskFilter, fn func(Task) error) error
+	// FetchByExternalID returns ErrTaskNotFound when no task of the
+	// organization was imported under externalID.
+	FetchByExternalID(c context.Context, externalID string) (*Task, error)
+	// CountCustomFieldValues counts tasks whose key field holds one of
+	// values, or any value when values is empty.
+	CountCustomFieldValues(c context.Context, key string, values []interface{}) (int64, error)
+	CountMissingCustomField(c context.Context, key string) (int64, error)
+	// BackfillCustomField sets key to value on tasks that have no value.
+	BackfillCustomField(c context.Context, key string, value interface{}) error
+	UnsetCustomField(c context.Context, key string) error
+	// Search returns candidate tasks for a search, best text matches first.
+	Search(c context.Context, search TaskSearch) ([]Task, error)
+	// FetchByUser and Unassign work across all organizations and are only
+	// meant for account-wide operations such as data export and erasure.
+	FetchByUser(c context.Context, userID string) ([]Task, error)
+	Unassign(c context.Context, userID string) error
+	// AddChecklistItem inserts item at position, or appends it when
+	// position is negative.
+	AddChecklistItem(c context.Context, taskID string, item ChecklistItem, position int) error
+	UpdateChecklistItem(c context.Context, taskID string, itemID string, update ChecklistItemUpdate, doneAt time.Time) error
+	DeleteChecklistItem(c context.Context, taskID string, itemID string) error
+	// ReplaceChecklist swaps the checklist for reordered, failing with
+	// ErrChecklistChanged unless it still equals current.
+	ReplaceChecklist(c context.Context, taskID string, current []ChecklistItem, reordered []ChecklistItem) error
 }
 
 type UserRepository interface {
 	Create(c context.Context, user *User) error
 	FindByUsername(c context.Context, usrname string) (User, error)
 	Update(c context.Context, userID string) error
+	FindByEmail(c context.Context, email string) (User, error)
+	FindByIdentity(c context.Context, provider string, subject string) (User, error)
+	AddIdentity(c context.Context, userID string, identity ExternalIdentity) error
+	CreateExternal(c context.Context, user *User) error
+	FetchByUserID(c context.Context, userID string) (User, error)
+	FetchAll(c context.Context, filter UserFilter) ([]User, int64, error)
+	UpdateProfile(c context.Context, userID string, update ProfileUpdate) error
+	UpdatePassword(c context.Context, userID string, passwordHash string) error
+	SetUserType(c context.Context, userID string, userType string) error
+	SetDeactivated(c context.Context, userID string, deactivatedAt *time.Time) error
+	Delete(c context.Context, userID string) error
+	Anonymize(c context.Context, userID string, erasedAt time.Time) error
+}
+
+type OrganizationRepository interface {
+	Create(c context.Context, org *Organization) error
+	FetchByID(c context.Context, orgID string) (Organization, error)
+	FetchByIDs(c context.Context, orgIDs []string) ([]Organization, error)
+	AddMember(c context.Context, membership *Membership) error
+	FetchMembership(c context.Context, orgID string, userID string) (Membership, error)
+	FetchMembers(c context.Context, orgID string) ([]Membership, error)
+	FetchMemberships(c context.Context, userID string) ([]Membership, error)
+	UpdateMemberRole(c context.Context, orgID string, userID string, role string) error
+	RemoveMember(c context.Context, orgID string, userID string) error
+	RemoveUser(c context.Context, userID string) error
+	CountOwners(c context.Context, orgID string) (int64, error)
+}
+
+type InvitationRepository interface {
+	Create(c context.Context, invitation *Invitation) error
+	FetchByID(c context.Context, invitationID string) (Invitation, error)
+	FetchByTokenHash(c context.Context, tokenHash string) (Invitation, error)
+	FetchPendingByEmail(c context.Context, email string) (Invitation, error)
+	FetchAll(c context.Context) ([]Invitation, error)
+	Renew(c context.Context, invitationID string, tokenHash string, expiresAt time.Time) error
+	MarkAccepted(c context.Context, invitationID string, acceptedAt time.Time) error
+	Revoke(c context.Context, invitationID string, revokedAt time.Time) error
+}
+
+type AuditRepository interface {
+	Create(c context.Context, entry *AuditEntry) error
+	FetchAll(c context.Context, filter AuditFilter) ([]AuditEntry, error)
+}
+
+type CommentRepository interface {
+	Create(c context.Context, comment *Comment) error
+	FetchByID(c context.Context, commentID string) (Comment, error)
+	FetchThreads(c context.Context, taskID string, page int64, limit int64) ([]Comment, int64, error)
+	FetchReplies(c context.Context, parentIDs []string) ([]Comment, error)
+	Update(c context.Context, commentID string, body string, mentions []string, editedAt time.Time) error
+	Delete(c context.Context, commentID string, deletedAt time.Time) error
+	// FetchByAuthor works across all organizations, for data export.
+	FetchByAuthor(c context.Context, userID string) ([]Comment, error)
+	// Search returns comments that may match a search.
+	Search(c context.Context, search TaskSearch) ([]Comment, error)
+}
+
+type AttachmentRepository interface {
+	Create(c context.Context, attachment *Attachment) error
+	FetchByID(c context.Context, attachmentID string) (Attachment, error)
+	FetchByTask(c context.Context, taskID string) ([]Attachment, error)
+	Delete(c context.Context, attachmentID string) error
+}
+
+// Transactor runs fn inside a database transaction. Repository calls made
+// with the context fn receives take part in it, and an error from fn rolls
+// them all back. fn may run more than once when the transaction is retried.
+type Transactor interface {
+	WithTransaction(c context.Context, fn func(ctx context.Context) error) error
+}
+
+// BlobStore keeps attachment contents. Keys are chosen by the caller and
+// opaque to the store.
+type BlobStore interface {
+	Put(c context.Context, key string, r io.Reader) error
+	// Open returns ErrBlobNotFound when nothing is stored under key. The
+	// returned reader is seekable so downloads can serve byte ranges.
+	Open(c context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete succeeds when nothing is stored under key.
+	Delete(c context.Context, key string) error
+}
+
+type CustomFieldRepository interface {
+	Create(c context.Context, field *CustomField) error
+	FetchAll(c context.Context) ([]CustomField, error)
+	FetchByID(c context.Context, fieldID string) (CustomField, error)
+	Update(c context.Context, field CustomField) error
+	Delete(c context.Context, fieldID string) error
+}
+
+type ImportJobRepository interface {
+	Create(c context.Context, job *ImportJob) error
+	FetchByID(c context.Context, jobID string) (ImportJob, error)
+	Update(c context.Context, job ImportJob) error
+	// FailUnfinished marks the jobs of every organization created before
+	// cutoff that are still queued or running as failed at finishedAt,
+	// returning how many.
+	FailUnfinished(c context.Context, cutoff time.Time, message string, finishedAt time.Time) (int64, error)
+}
+
+type CalendarFeedRepository interface {
+	// Replace stores feed as the user's only feed in the organization.
+	Replace(c context.Context, feed *CalendarFeed) error
+	FetchByUser(c context.Context, userID string) (CalendarFeed, error)
+	// FetchByTokenHash looks across organizations, as feed requests carry
+	// no tenant.
+	FetchByTokenHash(c context.Context, tokenHash string) (CalendarFeed, error)
+	Delete(c context.Context, userID string) error
+	Touch(c context.Context, feedID string, at time.Time) error
+	// DeleteAllByUser works across all organizations and is only meant for
+	// erasure.
+	DeleteAllByUser(c context.Context, userID string) error
+}
+
+type WebhookRepository interface {
+	Create(c context.Context, webhook *Webhook) error
+	FetchAll(c context.Context) ([]Webhook, error)
+	FetchByID(c context.Context, webhookID string) (Webhook, error)
+	// FetchSubscribed lists the active webhooks subscribed to event.
+	FetchSubscribed(c context.Context, event string) ([]Webhook, error)
+	Update(c context.Context, webhook Webhook) error
+	Delete(c context.Context, webhookID string) error
+	// RecordFailure counts a failed delivery, disabling the webhook once
+	// disableAfter deliveries in a row have failed, and returns the
+	// webhook as it is now.
+	RecordFailure(c context.Context, webhookID string, disableAfter int, at time.Time) (Webhook, error)
+	ResetFailures(c context.Context, webhookID string) error
+}
+
+type WebhookDeliveryRepository interface {
+	// Create returns ErrWebhookDeliveryExists for a second first delivery
+	// of an event to a webhook.
+	Create(c context.Context, delivery *WebhookDelivery) error
+	FetchByID(c context.Context, deliveryID string) (WebhookDelivery, error)
+	// FetchByWebhook lists the webhook's latest deliveries, newest first.
+	FetchByWebhook(c context.Context, webhookID string, limit int64) ([]WebhookDelivery, error)
+	Update(c context.Context, delivery WebhookDelivery) error
+	// DeleteByWebhook removes the delivery log of a deleted webhook.
+	DeleteByWebhook(c context.Context, webhookID string) error
+	// FetchByEvent finds the first delivery of the event to the webhook,
+	// leaving out redeliveries.
+	FetchByEvent(c context.Context, webhookID string, eventID string) (WebhookDelivery, error)
+	// FetchPending lists the deliveries of every organization created
+	// before cutoff that are still pending, oldest first.
+	FetchPending(c context.Context, cutoff time.Time) ([]WebhookDelivery, error)
+}
+
+// OutboxRepository is not tenant scoped: the relay works through the
+// events of every organization.
+type OutboxRepository interface {
+	// Append must be given the context of the transaction making the
+	// change the record is about.
+	Append(c context.Context, record *OutboxRecord) error
+	// ClaimNext takes the oldest pending record due at now that has no
+	// earlier pending record for its aggregate, counting an attempt and
+	// locking it for lease so other relays pass it over. It returns nil
+	// when nothing is due.
+	ClaimNext(c context.Context, now time.Time, lease time.Duration) (*OutboxRecord, error)
+	MarkDelivered(c context.Context, recordID string, at time.Time) error
+	// Reschedule releases a record whose attempt failed until next.
+	Reschedule(c context.Context, recordID string, lastError string, next time.Time) error
+	MarkFailed(c context.Context, recordID string, lastError string) error
+	// PurgeDelivered deletes the records delivered before cutoff.
+	PurgeDelivered(c context.Context, cutoff time.Time) (int64, error)
+}
+
+type ViewRepository interface {
+	Create(c context.Context, view *View) error
+	FetchByID(c context.Context, viewID string) (View, error)
+	// FetchVisible returns the user's own views and the shared ones.
+	FetchVisible(c context.Context, userID string) ([]View, error)
+	Update(c context.Context, view View) error
+	// Delete also clears the view as anyone's default.
+	Delete(c context.Context, viewID string) error
+	SetDefault(c context.Context, userID string, viewID string) error
+	// FetchDefault returns the ID of the user's default view, or "".
+	FetchDefault(c context.Context, userID string) (string, error)
+	ClearDefault(c context.Context, userID string) error
+	// DeletePrivateByOwner removes the user's private views and default
+	// view picks in every organization, leaving shared views to their
+	// organization. It is only meant for erasure.
+	DeletePrivateByOwner(c context.Context, userID string) error
+}
+
+type TaskTemplateRepository interface {
+	Create(c context.Context, template *TaskTemplate) error
+	FetchAll(c context.Context) ([]TaskTemplate, error)
+	FetchByID(c context.Context, templateID string) (TaskTemplate, error)
+	Update(c context.Context, template TaskTemplate) error
+	Delete(c context.Context, templateID string) error
+}
+
+type TimeEntryRepository interface {
+	Create(c context.Context, entry *TimeEntry) error
+	// StartTimer stores a running entry, failing with ErrTimerRunning when
+	// the user already has one in any organization.
+	StartTimer(c context.Context, entry *TimeEntry) error
+	// StopTimer ends the user's running entry at endedAt, or longest after
+	// it started if that is sooner.
+	StopTimer(c context.Context, userID string, endedAt time.Time, longest time.Duration) (TimeEntry, error)
+	FetchRunning(c context.Context, userID string) (TimeEntry, error)
+	FetchByID(c context.Context, entryID string) (TimeEntry, error)
+	FetchByTask(c context.Context, taskID string) ([]TimeEntry, error)
+	Delete(c context.Context, entryID string) error
+	Report(c context.Context, filter TimeReportFilter) ([]TimeReportRow, error)
+}
+
+type SessionRepository interface {
+	Create(c context.Context, session *Session) error
+	FetchByID(c context.Context, sessionID string) (Session, error)
+	FetchActiveByUser(c context.Context, userID string) ([]Session, error)
+	Touch(c context.Context, sessionID string, lastSeenAt time.Time) error
+	Revoke(c context.Context, userID string, sessionID string, revokedAt time.Time) error
+	RevokeAll(c context.Context, userID string, revokedAt time.Time) error
 }
 
 type TaskUsecase interface {
 	Create(c context.Context, task *Task) error
-	FetchAll(c context.Context) (*[]Task, error)
+	FetchAll(c context.Context, query TaskQuery) (*[]Task, error)
 	FetchByTaskID(c context.Context, taskID string) (*Task, error)
 	Update(c context.Context, taskID string, updatedTask Task) error
 	Delete(c context.Context, taskID string) error
+	// Export writes the tasks matching query to w in format as they are
+	// read. Nothing is written when the query or format is invalid.
+	Export(c context.Context, query TaskQuery, format string, w io.Writer) error
+	AddChecklistItem(c context.Context, taskID string, item ChecklistItem, position *int) (*Task, error)
+	UpdateChecklistItem(c context.Context, taskID string, itemID string, update ChecklistItemUpdate) (*Task, error)
+	ToggleChecklistItem(c context.Context, taskID string, itemID string) (*Task, error)
+	ReorderChecklist(c context.Context, taskID string, itemIDs []string) (*Task, error)
+	DeleteChecklistItem(c context.Context, taskID string, itemID string) (*Task, error)
 }
 
 type UserUsecase interface {
 	Create(c context.Context, user *User) error
 	HandleLogin(c context.Context, username *User) (string, string, error)
+	HandleExternalLogin(c context.Context, identity *ExternalIdentity) (string, string, error)
 	Update(c context.Context, userID string) error
+	FetchByUserID(c context.Context, userID string) (*User, error)
+	FetchAll(c context.Context, filter UserFilter) (*UserPage, error)
+	UpdateProfile(c context.Context, userID string, update ProfileUpdate) (*User, error)
+	ChangePassword(c context.Context, userID string, change PasswordChange) error
+	DeleteAccount(c context.Context, userID string, password string) error
+	SetActive(c context.Context, userID string, active bool) error
+	Demote(c context.Context, userID string) error
+	Delete(c context.Context, userID string) error
+	SwitchOrganization(c context.Context, userID string, sessionID string, orgID string) (string, string, error)
+	FetchSessions(c context.Context, userID string, currentSessionID string) (*[]Session, error)
+	RevokeSession(c context.Context, userID string, sessionID string) error
+}
+
+type OrganizationUsecase interface {
+	Create(c context.Context, org *Organization, ownerID string) error
+	FetchForUser(c context.Context, userID string) (*[]Organization, error)
+	FetchMembers(c context.Context, orgID string) (*[]Membership, error)
+	AddMember(c context.Context, membership *Membership) error
+	UpdateMemberRole(c context.Context, orgID string, userID string, role string) error
+	RemoveMember(c context.Context, orgID string, userID string, actorRole string) error
+}
+
+type InvitationUsecase interface {
+	Invite(c context.Context, invitation *Invitation, invitedBy string) error
+	FetchAll(c context.Context) (*[]Invitation, error)
+	Resend(c context.Context, invitationID string) error
+	Revoke(c context.Context, invitationID string) error
+	Accept(c context.Context, acceptance InvitationAcceptance) (*User, error)
+}
+
+type ImpersonationUsecase interface {
+	Impersonate(c context.Context, actorID string, userID string) (string, error)
+	FetchAuditLog(c context.Context, filter AuditFilter) (*[]AuditEntry, error)
+}
+
+type CommentUsecase interface {
+	Create(c context.Context, taskID string, comment *Comment) error
+	FetchByTask(c context.Context, taskID string, page int64, limit int64) (*CommentPage, error)
+	Update(c context.Context, taskID string, commentID string, authorID string, body string) (*Comment, error)
+	Delete(c context.Context, taskID string, commentID string, actorID string, moderator bool) error
+}
+
+type AttachmentUsecase interface {
+	Upload(c context.Context, taskID string, filename string, r io.Reader, uploadedBy string) (*Attachment, error)
+	FetchByTask(c context.Context, taskID string) (*[]Attachment, error)
+	Open(c context.Context, taskID string, attachmentID string) (*Attachment, io.ReadSeekCloser, error)
+	Delete(c context.Context, taskID string, attachmentID string, actorID string, moderator bool) error
+}
+
+type CustomFieldUsecase interface {
+	Create(c context.Context, field *CustomField) error
+	FetchAll(c context.Context) (*[]CustomField, error)
+	Update(c context.Context, fieldID string, update CustomFieldUpdate) (*CustomField, error)
+	Delete(c context.Context, fieldID string) error
+}
+
+type ViewUsecase interface {
+	Create(c context.Context, view *View) error
+	FetchAll(c context.Context, userID string) (*[]View, error)
+	FetchByID(c context.Context, viewID string, userID string) (*View, error)
+	Update(c context.Context, viewID string, userID string, update ViewUpdate) (*View, error)
+	// Delete is open to the owner, and to moderators for shared views.
+	Delete(c context.Context, viewID string, userID string, moderator bool) error
+	SetDefault(c context.Context, viewID string, userID string) error
+	ClearDefault(c context.Context, userID string) error
+	// FetchTasks runs the view's filter and sort.
+	FetchTasks(c context.Context, viewID string, userID string) (*ViewTasks, error)
+}
+
+type TaskSearchUsecase interface {
+	Search(c context.Context, request TaskSearchRequest) (*TaskSearchResult, error)
+}
+
+type TaskBulkUsecase interface {
+	// Execute runs the request's operations, or its update on every task
+	// matching its filter, reporting how each one went.
+	Execute(c context.Context, userID string, request BulkTaskRequest) (*BulkTaskResult, error)
+}
+
+type TaskImportUsecase interface {
+	// Import runs small imports straight away. Larger ones, and any asked
+	// to run asynchronously, are queued; FetchJob reports their progress.
+	Import(c context.Context, userID string, request TaskImportRequest) (*ImportJob, error)
+	FetchJob(c context.Context, jobID string) (*ImportJob, error)
+	// FailInterrupted fails the imports of every organization a restart cut
+	// off, those queued or running since before startedAt, returning how
+	// many.
+	FailInterrupted(c context.Context, startedAt time.Time) (int64, error)
+}
+
+type CalendarUsecase interface {
+	CreateFeed(c context.Context, userID string) (*CalendarFeed, error)
+	FetchFeed(c context.Context, userID string) (*CalendarFeed, error)
+	RevokeFeed(c context.Context, userID string) error
+	// WriteFeed writes the tasks of the feed's owner as VTODOs, or as
+	// all-day VEVENTs for kind CalendarEvent. Unknown and revoked tokens,
+	// and tokens of users who lost access, give ErrCalendarFeedNotFound.
+	WriteFeed(c context.Context, token string, kind string, w io.Writer) error
+}
+
+type WebhookUsecase interface {
+	WebhookDispatcher
+	Create(c context.Context, webhook *Webhook) error
+	FetchAll(c context.Context) (*[]Webhook, error)
+	FetchByID(c context.Context, webhookID string) (*Webhook, error)
+	Update(c context.Context, webhookID string, update WebhookUpdate) (*Webhook, error)
+	Delete(c context.Context, webhookID string) error
+	FetchDeliveries(c context.Context, webhookID string, limit int64) (*[]WebhookDelivery, error)
+	// Redeliver sends a past delivery's payload again as a new delivery.
+	Redeliver(c context.Context, webhookID string, deliveryID string) (*WebhookDelivery, error)
+	// ResumePending sends the deliveries of every organization left pending
+	// before startedAt, returning how many it resumed.
+	ResumePending(c context.Context, startedAt time.Time) (int, error)
+}
+
+// WebhookDispatcher sends an event to the webhooks subscribed to it in the
+// background. Dispatch returns once the deliveries are recorded; its error
+// means some could not be, and the event should be dispatched again.
+// Failed sends are logged and retried, never returned.
+type WebhookDispatcher interface {
+	Dispatch(c context.Context, event WebhookEvent) error
+}
+
+type TaskTemplateUsecase interface {
+	Create(c context.Context, template *TaskTemplate) error
+	FetchAll(c context.Context) (*[]TaskTemplate, error)
+	FetchByID(c context.Context, templateID string) (*TaskTemplate, error)
+	Update(c context.Context, templateID string, template TaskTemplate) (*TaskTemplate, error)
+	Delete(c context.Context, templateID string) error
+	// Instantiate creates the template's task and subtasks, failing with
+	// ErrMissingTemplateVariable before creating anything when a
+	// placeholder has no value.
+	Instantiate(c context.Context, templateID string, request TemplateInstantiation, createdBy string) (*TaskTree, error)
+}
+
+type TimeTrackingUsecase interface {
+	StartTimer(c context.Context, taskID string, userID string, note string) (*TimeEntry, error)
+	StopTimer(c context.Context, userID string) (*TimeEntry, error)
+	FetchRunning(c context.Context, userID string) (*TimeEntry, error)
+	LogTime(c context.Context, taskID string, entry *TimeEntry) error
+	FetchByTask(c context.Context, taskID string) (*[]TimeEntry, error)
+	DeleteEntry(c context.Context, entryID string, actorID string, moderator bool) error
+	Report(c context.Context, filter TimeReportFilter) (*TimeReport, error)
+}
+
+type PrivacyUsecase interface {
+	Export(c context.Context, userID string) (*PersonalDataExport, error)
+	EraseAccount(c context.Context, userID string, password string) error
+	Erase(c context.Context, actorID string, userID string) error
+}
+
+// Mailer delivers transactional email such as invitations.
+type Mailer interface {
+	Send(c context.Context, to string, subject string, body string) error
+}
+
+// EventHandler reacts to a published event. Its error is logged; it does
+// not reach the publisher, though Deliver reports it.
+type EventHandler func(c context.Context, event Event) error
+
+// EventBus delivers domain events to the handlers subscribed to their
+// name, or to AllEvents.
+type EventBus interface {
+	// Publish runs the synchronous handlers before returning and queues
+	// the event for the asynchronous ones. A failing or panicking handler
+	// does not affect the others or the publisher.
+	Publish(c context.Context, event DomainEvent)
+	// Deliver hands subscribers an event published before, keeping its ID
+	// and time. Unlike Publish it reports the synchronous handlers'
+	// failures, so the caller can deliver it again.
+	Deliver(c context.Context, event Event) error
+	// Subscribe runs handler in the publisher's goroutine.
+	Subscribe(name string, handler EventHandler)
+	// SubscribeAsync runs handler in the background, with the publisher's
+	// context values but not its deadline. Events of one aggregate reach
+	// it one at a time, in the order they were published.
+	SubscribeAsync(name string, handler EventHandler)
+}
+
+// OutboxRelay publishes the events stored in the outbox on the EventBus,
+// at least once each.
+type OutboxRelay interface {
+	// Relay publishes the records that are due and returns how many it
+	// handled.
+	Relay(c context.Context) (int, error)
+	// Purge deletes delivered records older than the retention period.
+	Purge(c context.Context) (int64, error)
+	// Start relays and purges in the background until stop is closed.
+	Start(stop <-chan struct{})
+}
+
+// WebhookSender POSTs a webhook payload. Any answer counts as a response;
+// only failures to get one are errors.
+type WebhookSender interface {
+	Send(c context.Context, url string, headers map[string]string, body []byte) (WebhookResponse, error)
+	// Check returns ErrInvalidWebhook for URLs deliveries may not be sent
+	// to, such as those of internal hosts.
+	Check(c context.Context, url string) error
+}
+
+// IdentityProvider runs the authorization code flow against an external
+// OpenID Connect provider.
+type IdentityProvider interface {
+	Name() string
+	// AuthCodeURL starts a login, returning the provider URL to redirect to
+	// and the state the callback must come back with. It fails with
+	// ErrTooManyLogins while too many logins are in progress.
+	AuthCodeURL(c context.Context) (authURL string, state string, err error)
+	Exchange(c context.Context, state string, code string) (*ExternalIdentity, error)
 }
 
 type TaskController interface{
@@ -76,12 +1669,145 @@ type TaskController interface{
 	FetchByTaskID(c *gin.Context)
 	Update(c *gin.Context)
 	Delete(c *gin.Context)
+	Export(c *gin.Context)
+	AddChecklistItem(c *gin.Context)
+	UpdateChecklistItem(c *gin.Context)
+	ToggleChecklistItem(c *gin.Context)
+	ReorderChecklist(c *gin.Context)
+	DeleteChecklistItem(c *gin.Context)
 }
 
 type UserController interface{
 	Signup(c *gin.Context)
 	Login(c *gin.Context)
 	PromoteUser(c *gin.Context)
+	Me(c *gin.Context)
+	UpdateMe(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	DeleteMe(c *gin.Context)
+	FetchAll(c *gin.Context)
+	FetchByUserID(c *gin.Context)
+	Deactivate(c *gin.Context)
+	Reactivate(c *gin.Context)
+	Demote(c *gin.Context)
+	Delete(c *gin.Context)
+	FetchSessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
+}
+
+type OrganizationController interface{
+	Create(c *gin.Context)
+	FetchMine(c *gin.Context)
+	Switch(c *gin.Context)
+	FetchMembers(c *gin.Context)
+	AddMember(c *gin.Context)
+	UpdateMemberRole(c *gin.Context)
+	RemoveMember(c *gin.Context)
+}
+
+type InvitationController interface{
+	Invite(c *gin.Context)
+	FetchAll(c *gin.Context)
+	Resend(c *gin.Context)
+	Revoke(c *gin.Context)
+	Accept(c *gin.Context)
+}
+
+type ImpersonationController interface{
+	Impersonate(c *gin.Context)
+	FetchAuditLog(c *gin.Context)
+}
+
+type CommentController interface{
+	Create(c *gin.Context)
+	FetchByTask(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
+type AttachmentController interface{
+	Upload(c *gin.Context)
+	FetchByTask(c *gin.Context)
+	Download(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
+type CustomFieldController interface{
+	Create(c *gin.Context)
+	FetchAll(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
+type ViewController interface{
+	Create(c *gin.Context)
+	FetchAll(c *gin.Context)
+	FetchByID(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	SetDefault(c *gin.Context)
+	ClearDefault(c *gin.Context)
+	FetchTasks(c *gin.Context)
+}
+
+type TaskSearchController interface{
+	Search(c *gin.Context)
+}
+
+type TaskBulkController interface{
+	Execute(c *gin.Context)
+}
+
+type TaskImportController interface{
+	Import(c *gin.Context)
+	FetchJob(c *gin.Context)
+}
+
+type CalendarController interface{
+	CreateFeed(c *gin.Context)
+	FetchFeed(c *gin.Context)
+	RevokeFeed(c *gin.Context)
+	Feed(c *gin.Context)
+}
+
+type WebhookController interface{
+	Create(c *gin.Context)
+	FetchAll(c *gin.Context)
+	FetchByID(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	FetchDeliveries(c *gin.Context)
+	Redeliver(c *gin.Context)
+}
+
+type TaskTemplateController interface{
+	Create(c *gin.Context)
+	FetchAll(c *gin.Context)
+	FetchByID(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	Instantiate(c *gin.Context)
+}
+
+type TimeTrackingController interface{
+	StartTimer(c *gin.Context)
+	StopTimer(c *gin.Context)
+	FetchRunning(c *gin.Context)
+	LogTime(c *gin.Context)
+	FetchByTask(c *gin.Context)
+	DeleteEntry(c *gin.Context)
+	Report(c *gin.Context)
+}
+
+type PrivacyController interface{
+	Export(c *gin.Context)
+	EraseMe(c *gin.Context)
+	Erase(c *gin.Context)
+}
+
+type OIDCController interface{
+	Login(c *gin.Context)
+	Callback(c *gin.Context)
 }
 type ErrorResponse struct {
 	Message string `json:"message"`
@@ -0,0 +1,48 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AttachmentController is an autogenerated mock type for the AttachmentController type
+type AttachmentController struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: c
+func (_m *AttachmentController) Delete(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Download provides a mock function with given fields: c
+func (_m *AttachmentController) Download(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchByTask provides a mock function with given fields: c
+func (_m *AttachmentController) FetchByTask(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Upload provides a mock function with given fields: c
+func (_m *AttachmentController) Upload(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewAttachmentController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAttachmentController creates a new instance of AttachmentController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAttachmentController(t mockConstructorTestingTNewAttachmentController) *AttachmentController {
+	mock := &AttachmentController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AttachmentRepository is an autogenerated mock type for the AttachmentRepository type
+type AttachmentRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, attachment
+func (_m *AttachmentRepository) Create(c context.Context, attachment *domain.Attachment) error {
+	ret := _m.Called(c, attachment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Attachment) error); ok {
+		r0 = rf(c, attachment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, attachmentID
+func (_m *AttachmentRepository) Delete(c context.Context, attachmentID string) error {
+	ret := _m.Called(c, attachmentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, attachmentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByID provides a mock function with given fields: c, attachmentID
+func (_m *AttachmentRepository) FetchByID(c context.Context, attachmentID string) (domain.Attachment, error) {
+	ret := _m.Called(c, attachmentID)
+
+	var r0 domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Attachment, error)); ok {
+		return rf(c, attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Attachment); ok {
+		r0 = rf(c, attachmentID)
+	} else {
+		r0 = ret.Get(0).(domain.Attachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, attachmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByTask provides a mock function with given fields: c, taskID
+func (_m *AttachmentRepository) FetchByTask(c context.Context, taskID string) ([]domain.Attachment, error) {
+	ret := _m.Called(c, taskID)
+
+	var r0 []domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Attachment, error)); ok {
+		return rf(c, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Attachment); ok {
+		r0 = rf(c, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAttachmentRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAttachmentRepository creates a new instance of AttachmentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAttachmentRepository(t mockConstructorTestingTNewAttachmentRepository) *AttachmentRepository {
+	mock := &AttachmentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
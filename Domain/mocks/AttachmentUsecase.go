@@ -0,0 +1,132 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AttachmentUsecase is an autogenerated mock type for the AttachmentUsecase type
+type AttachmentUsecase struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: c, taskID, attachmentID, actorID, moderator
+func (_m *AttachmentUsecase) Delete(c context.Context, taskID string, attachmentID string, actorID string, moderator bool) error {
+	ret := _m.Called(c, taskID, attachmentID, actorID, moderator)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) error); ok {
+		r0 = rf(c, taskID, attachmentID, actorID, moderator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByTask provides a mock function with given fields: c, taskID
+func (_m *AttachmentUsecase) FetchByTask(c context.Context, taskID string) (*[]domain.Attachment, error) {
+	ret := _m.Called(c, taskID)
+
+	var r0 *[]domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*[]domain.Attachment, error)); ok {
+		return rf(c, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *[]domain.Attachment); ok {
+		r0 = rf(c, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Open provides a mock function with given fields: c, taskID, attachmentID
+func (_m *AttachmentUsecase) Open(c context.Context, taskID string, attachmentID string) (*domain.Attachment, io.ReadSeekCloser, error) {
+	ret := _m.Called(c, taskID, attachmentID)
+
+	var r0 *domain.Attachment
+	var r1 io.ReadSeekCloser
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.Attachment, io.ReadSeekCloser, error)); ok {
+		return rf(c, taskID, attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Attachment); ok {
+		r0 = rf(c, taskID, attachmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) io.ReadSeekCloser); ok {
+		r1 = rf(c, taskID, attachmentID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.ReadSeekCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(c, taskID, attachmentID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Upload provides a mock function with given fields: c, taskID, filename, r, uploadedBy
+func (_m *AttachmentUsecase) Upload(c context.Context, taskID string, filename string, r io.Reader, uploadedBy string) (*domain.Attachment, error) {
+	ret := _m.Called(c, taskID, filename, r, uploadedBy)
+
+	var r0 *domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, io.Reader, string) (*domain.Attachment, error)); ok {
+		return rf(c, taskID, filename, r, uploadedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, io.Reader, string) *domain.Attachment); ok {
+		r0 = rf(c, taskID, filename, r, uploadedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, io.Reader, string) error); ok {
+		r1 = rf(c, taskID, filename, r, uploadedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAttachmentUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAttachmentUsecase creates a new instance of AttachmentUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAttachmentUsecase(t mockConstructorTestingTNewAttachmentUsecase) *AttachmentUsecase {
+	mock := &AttachmentUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
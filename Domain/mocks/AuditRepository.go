@@ -0,0 +1,70 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditRepository type
+type AuditRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, entry
+func (_m *AuditRepository) Create(c context.Context, entry *domain.AuditEntry) error {
+	ret := _m.Called(c, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuditEntry) error); ok {
+		r0 = rf(c, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c, filter
+func (_m *AuditRepository) FetchAll(c context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	ret := _m.Called(c, filter)
+
+	var r0 []domain.AuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) ([]domain.AuditEntry, error)); ok {
+		return rf(c, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) []domain.AuditEntry); ok {
+		r0 = rf(c, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.AuditFilter) error); ok {
+		r1 = rf(c, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAuditRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuditRepository(t mockConstructorTestingTNewAuditRepository) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
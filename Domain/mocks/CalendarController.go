@@ -0,0 +1,48 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CalendarController is an autogenerated mock type for the CalendarController type
+type CalendarController struct {
+	mock.Mock
+}
+
+// CreateFeed provides a mock function with given fields: c
+func (_m *CalendarController) CreateFeed(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Feed provides a mock function with given fields: c
+func (_m *CalendarController) Feed(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchFeed provides a mock function with given fields: c
+func (_m *CalendarController) FetchFeed(c *gin.Context) {
+	_m.Called(c)
+}
+
+// RevokeFeed provides a mock function with given fields: c
+func (_m *CalendarController) RevokeFeed(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewCalendarController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCalendarController creates a new instance of CalendarController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCalendarController(t mockConstructorTestingTNewCalendarController) *CalendarController {
+	mock := &CalendarController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,136 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// CalendarFeedRepository is an autogenerated mock type for the CalendarFeedRepository type
+type CalendarFeedRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: c, userID
+func (_m *CalendarFeedRepository) Delete(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAllByUser provides a mock function with given fields: c, userID
+func (_m *CalendarFeedRepository) DeleteAllByUser(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByTokenHash provides a mock function with given fields: c, tokenHash
+func (_m *CalendarFeedRepository) FetchByTokenHash(c context.Context, tokenHash string) (domain.CalendarFeed, error) {
+	ret := _m.Called(c, tokenHash)
+
+	var r0 domain.CalendarFeed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.CalendarFeed, error)); ok {
+		return rf(c, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.CalendarFeed); ok {
+		r0 = rf(c, tokenHash)
+	} else {
+		r0 = ret.Get(0).(domain.CalendarFeed)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByUser provides a mock function with given fields: c, userID
+func (_m *CalendarFeedRepository) FetchByUser(c context.Context, userID string) (domain.CalendarFeed, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 domain.CalendarFeed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.CalendarFeed, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.CalendarFeed); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Get(0).(domain.CalendarFeed)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Replace provides a mock function with given fields: c, feed
+func (_m *CalendarFeedRepository) Replace(c context.Context, feed *domain.CalendarFeed) error {
+	ret := _m.Called(c, feed)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.CalendarFeed) error); ok {
+		r0 = rf(c, feed)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Touch provides a mock function with given fields: c, feedID, at
+func (_m *CalendarFeedRepository) Touch(c context.Context, feedID string, at time.Time) error {
+	ret := _m.Called(c, feedID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, feedID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewCalendarFeedRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCalendarFeedRepository creates a new instance of CalendarFeedRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCalendarFeedRepository(t mockConstructorTestingTNewCalendarFeedRepository) *CalendarFeedRepository {
+	mock := &CalendarFeedRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,111 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CalendarUsecase is an autogenerated mock type for the CalendarUsecase type
+type CalendarUsecase struct {
+	mock.Mock
+}
+
+// CreateFeed provides a mock function with given fields: c, userID
+func (_m *CalendarUsecase) CreateFeed(c context.Context, userID string) (*domain.CalendarFeed, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *domain.CalendarFeed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.CalendarFeed, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.CalendarFeed); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.CalendarFeed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchFeed provides a mock function with given fields: c, userID
+func (_m *CalendarUsecase) FetchFeed(c context.Context, userID string) (*domain.CalendarFeed, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *domain.CalendarFeed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.CalendarFeed, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.CalendarFeed); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.CalendarFeed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeFeed provides a mock function with given fields: c, userID
+func (_m *CalendarUsecase) RevokeFeed(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WriteFeed provides a mock function with given fields: c, token, kind, w
+func (_m *CalendarUsecase) WriteFeed(c context.Context, token string, kind string, w io.Writer) error {
+	ret := _m.Called(c, token, kind, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, io.Writer) error); ok {
+		r0 = rf(c, token, kind, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewCalendarUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCalendarUsecase creates a new instance of CalendarUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCalendarUsecase(t mockConstructorTestingTNewCalendarUsecase) *CalendarUsecase {
+	mock := &CalendarUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,48 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CommentController is an autogenerated mock type for the CommentController type
+type CommentController struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c
+func (_m *CommentController) Create(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Delete provides a mock function with given fields: c
+func (_m *CommentController) Delete(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchByTask provides a mock function with given fields: c
+func (_m *CommentController) FetchByTask(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Update provides a mock function with given fields: c
+func (_m *CommentController) Update(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewCommentController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCommentController creates a new instance of CommentController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCommentController(t mockConstructorTestingTNewCommentController) *CommentController {
+	mock := &CommentController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
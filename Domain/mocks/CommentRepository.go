@@ -0,0 +1,209 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// CommentRepository is an autogenerated mock type for the CommentRepository type
+type CommentRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, comment
+func (_m *CommentRepository) Create(c context.Context, comment *domain.Comment) error {
+	ret := _m.Called(c, comment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Comment) error); ok {
+		r0 = rf(c, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, commentID, deletedAt
+func (_m *CommentRepository) Delete(c context.Context, commentID string, deletedAt time.Time) error {
+	ret := _m.Called(c, commentID, deletedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, commentID, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByAuthor provides a mock function with given fields: c, userID
+func (_m *CommentRepository) FetchByAuthor(c context.Context, userID string) ([]domain.Comment, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 []domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Comment, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Comment); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, commentID
+func (_m *CommentRepository) FetchByID(c context.Context, commentID string) (domain.Comment, error) {
+	ret := _m.Called(c, commentID)
+
+	var r0 domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Comment, error)); ok {
+		return rf(c, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Comment); ok {
+		r0 = rf(c, commentID)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchReplies provides a mock function with given fields: c, parentIDs
+func (_m *CommentRepository) FetchReplies(c context.Context, parentIDs []string) ([]domain.Comment, error) {
+	ret := _m.Called(c, parentIDs)
+
+	var r0 []domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Comment, error)); ok {
+		return rf(c, parentIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Comment); ok {
+		r0 = rf(c, parentIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(c, parentIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchThreads provides a mock function with given fields: c, taskID, page, limit
+func (_m *CommentRepository) FetchThreads(c context.Context, taskID string, page int64, limit int64) ([]domain.Comment, int64, error) {
+	ret := _m.Called(c, taskID, page, limit)
+
+	var r0 []domain.Comment
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) ([]domain.Comment, int64, error)); ok {
+		return rf(c, taskID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) []domain.Comment); ok {
+		r0 = rf(c, taskID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) int64); ok {
+		r1 = rf(c, taskID, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, int64) error); ok {
+		r2 = rf(c, taskID, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Search provides a mock function with given fields: c, search
+func (_m *CommentRepository) Search(c context.Context, search domain.TaskSearch) ([]domain.Comment, error) {
+	ret := _m.Called(c, search)
+
+	var r0 []domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskSearch) ([]domain.Comment, error)); ok {
+		return rf(c, search)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskSearch) []domain.Comment); ok {
+		r0 = rf(c, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskSearch) error); ok {
+		r1 = rf(c, search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: c, commentID, body, mentions, editedAt
+func (_m *CommentRepository) Update(c context.Context, commentID string, body string, mentions []string, editedAt time.Time) error {
+	ret := _m.Called(c, commentID, body, mentions, editedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, time.Time) error); ok {
+		r0 = rf(c, commentID, body, mentions, editedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewCommentRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCommentRepository creates a new instance of CommentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCommentRepository(t mockConstructorTestingTNewCommentRepository) *CommentRepository {
+	mock := &CommentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,110 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CommentUsecase is an autogenerated mock type for the CommentUsecase type
+type CommentUsecase struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, taskID, comment
+func (_m *CommentUsecase) Create(c context.Context, taskID string, comment *domain.Comment) error {
+	ret := _m.Called(c, taskID, comment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Comment) error); ok {
+		r0 = rf(c, taskID, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, taskID, commentID, actorID, moderator
+func (_m *CommentUsecase) Delete(c context.Context, taskID string, commentID string, actorID string, moderator bool) error {
+	ret := _m.Called(c, taskID, commentID, actorID, moderator)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) error); ok {
+		r0 = rf(c, taskID, commentID, actorID, moderator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByTask provides a mock function with given fields: c, taskID, page, limit
+func (_m *CommentUsecase) FetchByTask(c context.Context, taskID string, page int64, limit int64) (*domain.CommentPage, error) {
+	ret := _m.Called(c, taskID, page, limit)
+
+	var r0 *domain.CommentPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) (*domain.CommentPage, error)); ok {
+		return rf(c, taskID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) *domain.CommentPage); ok {
+		r0 = rf(c, taskID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.CommentPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) error); ok {
+		r1 = rf(c, taskID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: c, taskID, commentID, authorID, body
+func (_m *CommentUsecase) Update(c context.Context, taskID string, commentID string, authorID string, body string) (*domain.Comment, error) {
+	ret := _m.Called(c, taskID, commentID, authorID, body)
+
+	var r0 *domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (*domain.Comment, error)); ok {
+		return rf(c, taskID, commentID, authorID, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) *domain.Comment); ok {
+		r0 = rf(c, taskID, commentID, authorID, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(c, taskID, commentID, authorID, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewCommentUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCommentUsecase creates a new instance of CommentUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCommentUsecase(t mockConstructorTestingTNewCommentUsecase) *CommentUsecase {
+	mock := &CommentUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,48 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CustomFieldController is an autogenerated mock type for the CustomFieldController type
+type CustomFieldController struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c
+func (_m *CustomFieldController) Create(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Delete provides a mock function with given fields: c
+func (_m *CustomFieldController) Delete(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *CustomFieldController) FetchAll(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Update provides a mock function with given fields: c
+func (_m *CustomFieldController) Update(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewCustomFieldController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCustomFieldController creates a new instance of CustomFieldController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCustomFieldController(t mockConstructorTestingTNewCustomFieldController) *CustomFieldController {
+	mock := &CustomFieldController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
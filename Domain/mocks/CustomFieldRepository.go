@@ -0,0 +1,122 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CustomFieldRepository is an autogenerated mock type for the CustomFieldRepository type
+type CustomFieldRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, field
+func (_m *CustomFieldRepository) Create(c context.Context, field *domain.CustomField) error {
+	ret := _m.Called(c, field)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.CustomField) error); ok {
+		r0 = rf(c, field)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, fieldID
+func (_m *CustomFieldRepository) Delete(c context.Context, fieldID string) error {
+	ret := _m.Called(c, fieldID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, fieldID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *CustomFieldRepository) FetchAll(c context.Context) ([]domain.CustomField, error) {
+	ret := _m.Called(c)
+
+	var r0 []domain.CustomField
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.CustomField, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.CustomField); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.CustomField)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, fieldID
+func (_m *CustomFieldRepository) FetchByID(c context.Context, fieldID string) (domain.CustomField, error) {
+	ret := _m.Called(c, fieldID)
+
+	var r0 domain.CustomField
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.CustomField, error)); ok {
+		return rf(c, fieldID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.CustomField); ok {
+		r0 = rf(c, fieldID)
+	} else {
+		r0 = ret.Get(0).(domain.CustomField)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, fieldID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: c, field
+func (_m *CustomFieldRepository) Update(c context.Context, field domain.CustomField) error {
+	ret := _m.Called(c, field)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CustomField) error); ok {
+		r0 = rf(c, field)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewCustomFieldRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCustomFieldRepository creates a new instance of CustomFieldRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCustomFieldRepository(t mockConstructorTestingTNewCustomFieldRepository) *CustomFieldRepository {
+	mock := &CustomFieldRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,53 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// DomainEvent is an autogenerated mock type for the DomainEvent type
+type DomainEvent struct {
+	mock.Mock
+}
+
+// AggregateID provides a mock function with given fields:
+func (_m *DomainEvent) AggregateID() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// EventName provides a mock function with given fields:
+func (_m *DomainEvent) EventName() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewDomainEvent interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewDomainEvent creates a new instance of DomainEvent. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewDomainEvent(t mockConstructorTestingTNewDomainEvent) *DomainEvent {
+	mock := &DomainEvent{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventBus is an autogenerated mock type for the EventBus type
+type EventBus struct {
+	mock.Mock
+}
+
+// Deliver provides a mock function with given fields: c, event
+func (_m *EventBus) Deliver(c context.Context, event domain.Event) error {
+	ret := _m.Called(c, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Event) error); ok {
+		r0 = rf(c, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Publish provides a mock function with given fields: c, event
+func (_m *EventBus) Publish(c context.Context, event domain.DomainEvent) {
+	_m.Called(c, event)
+}
+
+// Subscribe provides a mock function with given fields: name, handler
+func (_m *EventBus) Subscribe(name string, handler domain.EventHandler) {
+	_m.Called(name, handler)
+}
+
+// SubscribeAsync provides a mock function with given fields: name, handler
+func (_m *EventBus) SubscribeAsync(name string, handler domain.EventHandler) {
+	_m.Called(name, handler)
+}
+
+type mockConstructorTestingTNewEventBus interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEventBus creates a new instance of EventBus. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEventBus(t mockConstructorTestingTNewEventBus) *EventBus {
+	mock := &EventBus{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
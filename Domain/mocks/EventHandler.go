@@ -0,0 +1,44 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventHandler is an autogenerated mock type for the EventHandler type
+type EventHandler struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: c, event
+func (_m *EventHandler) Execute(c context.Context, event domain.Event) error {
+	ret := _m.Called(c, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Event) error); ok {
+		r0 = rf(c, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewEventHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEventHandler creates a new instance of EventHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEventHandler(t mockConstructorTestingTNewEventHandler) *EventHandler {
+	mock := &EventHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
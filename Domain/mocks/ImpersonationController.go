@@ -0,0 +1,38 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ImpersonationController is an autogenerated mock type for the ImpersonationController type
+type ImpersonationController struct {
+	mock.Mock
+}
+
+// FetchAuditLog provides a mock function with given fields: c
+func (_m *ImpersonationController) FetchAuditLog(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Impersonate provides a mock function with given fields: c
+func (_m *ImpersonationController) Impersonate(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewImpersonationController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewImpersonationController creates a new instance of ImpersonationController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewImpersonationController(t mockConstructorTestingTNewImpersonationController) *ImpersonationController {
+	mock := &ImpersonationController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
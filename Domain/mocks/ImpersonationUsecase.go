@@ -0,0 +1,80 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ImpersonationUsecase is an autogenerated mock type for the ImpersonationUsecase type
+type ImpersonationUsecase struct {
+	mock.Mock
+}
+
+// FetchAuditLog provides a mock function with given fields: c, filter
+func (_m *ImpersonationUsecase) FetchAuditLog(c context.Context, filter domain.AuditFilter) (*[]domain.AuditEntry, error) {
+	ret := _m.Called(c, filter)
+
+	var r0 *[]domain.AuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) (*[]domain.AuditEntry, error)); ok {
+		return rf(c, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) *[]domain.AuditEntry); ok {
+		r0 = rf(c, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.AuditFilter) error); ok {
+		r1 = rf(c, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Impersonate provides a mock function with given fields: c, actorID, userID
+func (_m *ImpersonationUsecase) Impersonate(c context.Context, actorID string, userID string) (string, error) {
+	ret := _m.Called(c, actorID, userID)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(c, actorID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(c, actorID, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, actorID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewImpersonationUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewImpersonationUsecase creates a new instance of ImpersonationUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewImpersonationUsecase(t mockConstructorTestingTNewImpersonationUsecase) *ImpersonationUsecase {
+	mock := &ImpersonationUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ImportJobRepository is an autogenerated mock type for the ImportJobRepository type
+type ImportJobRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, job
+func (_m *ImportJobRepository) Create(c context.Context, job *domain.ImportJob) error {
+	ret := _m.Called(c, job)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ImportJob) error); ok {
+		r0 = rf(c, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FailUnfinished provides a mock function with given fields: c, cutoff, message, finishedAt
+func (_m *ImportJobRepository) FailUnfinished(c context.Context, cutoff time.Time, message string, finishedAt time.Time) (int64, error) {
+	ret := _m.Called(c, cutoff, message, finishedAt)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, time.Time) (int64, error)); ok {
+		return rf(c, cutoff, message, finishedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, time.Time) int64); ok {
+		r0 = rf(c, cutoff, message, finishedAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string, time.Time) error); ok {
+		r1 = rf(c, cutoff, message, finishedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, jobID
+func (_m *ImportJobRepository) FetchByID(c context.Context, jobID string) (domain.ImportJob, error) {
+	ret := _m.Called(c, jobID)
+
+	var r0 domain.ImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.ImportJob, error)); ok {
+		return rf(c, jobID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.ImportJob); ok {
+		r0 = rf(c, jobID)
+	} else {
+		r0 = ret.Get(0).(domain.ImportJob)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: c, job
+func (_m *ImportJobRepository) Update(c context.Context, job domain.ImportJob) error {
+	ret := _m.Called(c, job)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ImportJob) error); ok {
+		r0 = rf(c, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewImportJobRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewImportJobRepository creates a new instance of ImportJobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewImportJobRepository(t mockConstructorTestingTNewImportJobRepository) *ImportJobRepository {
+	mock := &ImportJobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,53 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// InvitationController is an autogenerated mock type for the InvitationController type
+type InvitationController struct {
+	mock.Mock
+}
+
+// Accept provides a mock function with given fields: c
+func (_m *InvitationController) Accept(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *InvitationController) FetchAll(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Invite provides a mock function with given fields: c
+func (_m *InvitationController) Invite(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Resend provides a mock function with given fields: c
+func (_m *InvitationController) Resend(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Revoke provides a mock function with given fields: c
+func (_m *InvitationController) Revoke(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewInvitationController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewInvitationController creates a new instance of InvitationController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewInvitationController(t mockConstructorTestingTNewInvitationController) *InvitationController {
+	mock := &InvitationController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
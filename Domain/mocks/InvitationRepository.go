@@ -0,0 +1,186 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// InvitationRepository is an autogenerated mock type for the InvitationRepository type
+type InvitationRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, invitation
+func (_m *InvitationRepository) Create(c context.Context, invitation *domain.Invitation) error {
+	ret := _m.Called(c, invitation)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Invitation) error); ok {
+		r0 = rf(c, invitation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *InvitationRepository) FetchAll(c context.Context) ([]domain.Invitation, error) {
+	ret := _m.Called(c)
+
+	var r0 []domain.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Invitation, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Invitation); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, invitationID
+func (_m *InvitationRepository) FetchByID(c context.Context, invitationID string) (domain.Invitation, error) {
+	ret := _m.Called(c, invitationID)
+
+	var r0 domain.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Invitation, error)); ok {
+		return rf(c, invitationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Invitation); ok {
+		r0 = rf(c, invitationID)
+	} else {
+		r0 = ret.Get(0).(domain.Invitation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, invitationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByTokenHash provides a mock function with given fields: c, tokenHash
+func (_m *InvitationRepository) FetchByTokenHash(c context.Context, tokenHash string) (domain.Invitation, error) {
+	ret := _m.Called(c, tokenHash)
+
+	var r0 domain.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Invitation, error)); ok {
+		return rf(c, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Invitation); ok {
+		r0 = rf(c, tokenHash)
+	} else {
+		r0 = ret.Get(0).(domain.Invitation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchPendingByEmail provides a mock function with given fields: c, email
+func (_m *InvitationRepository) FetchPendingByEmail(c context.Context, email string) (domain.Invitation, error) {
+	ret := _m.Called(c, email)
+
+	var r0 domain.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Invitation, error)); ok {
+		return rf(c, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Invitation); ok {
+		r0 = rf(c, email)
+	} else {
+		r0 = ret.Get(0).(domain.Invitation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkAccepted provides a mock function with given fields: c, invitationID, acceptedAt
+func (_m *InvitationRepository) MarkAccepted(c context.Context, invitationID string, acceptedAt time.Time) error {
+	ret := _m.Called(c, invitationID, acceptedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, invitationID, acceptedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Renew provides a mock function with given fields: c, invitationID, tokenHash, expiresAt
+func (_m *InvitationRepository) Renew(c context.Context, invitationID string, tokenHash string, expiresAt time.Time) error {
+	ret := _m.Called(c, invitationID, tokenHash, expiresAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(c, invitationID, tokenHash, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Revoke provides a mock function with given fields: c, invitationID, revokedAt
+func (_m *InvitationRepository) Revoke(c context.Context, invitationID string, revokedAt time.Time) error {
+	ret := _m.Called(c, invitationID, revokedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, invitationID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewInvitationRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewInvitationRepository creates a new instance of InvitationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewInvitationRepository(t mockConstructorTestingTNewInvitationRepository) *InvitationRepository {
+	mock := &InvitationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,124 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// InvitationUsecase is an autogenerated mock type for the InvitationUsecase type
+type InvitationUsecase struct {
+	mock.Mock
+}
+
+// Accept provides a mock function with given fields: c, acceptance
+func (_m *InvitationUsecase) Accept(c context.Context, acceptance domain.InvitationAcceptance) (*domain.User, error) {
+	ret := _m.Called(c, acceptance)
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.InvitationAcceptance) (*domain.User, error)); ok {
+		return rf(c, acceptance)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.InvitationAcceptance) *domain.User); ok {
+		r0 = rf(c, acceptance)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.InvitationAcceptance) error); ok {
+		r1 = rf(c, acceptance)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *InvitationUsecase) FetchAll(c context.Context) (*[]domain.Invitation, error) {
+	ret := _m.Called(c)
+
+	var r0 *[]domain.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*[]domain.Invitation, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *[]domain.Invitation); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Invite provides a mock function with given fields: c, invitation, invitedBy
+func (_m *InvitationUsecase) Invite(c context.Context, invitation *domain.Invitation, invitedBy string) error {
+	ret := _m.Called(c, invitation, invitedBy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Invitation, string) error); ok {
+		r0 = rf(c, invitation, invitedBy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resend provides a mock function with given fields: c, invitationID
+func (_m *InvitationUsecase) Resend(c context.Context, invitationID string) error {
+	ret := _m.Called(c, invitationID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, invitationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Revoke provides a mock function with given fields: c, invitationID
+func (_m *InvitationUsecase) Revoke(c context.Context, invitationID string) error {
+	ret := _m.Called(c, invitationID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, invitationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewInvitationUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewInvitationUsecase creates a new instance of InvitationUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewInvitationUsecase(t mockConstructorTestingTNewInvitationUsecase) *InvitationUsecase {
+	mock := &InvitationUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,38 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCController is an autogenerated mock type for the OIDCController type
+type OIDCController struct {
+	mock.Mock
+}
+
+// Callback provides a mock function with given fields: c
+func (_m *OIDCController) Callback(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Login provides a mock function with given fields: c
+func (_m *OIDCController) Login(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewOIDCController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOIDCController creates a new instance of OIDCController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOIDCController(t mockConstructorTestingTNewOIDCController) *OIDCController {
+	mock := &OIDCController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
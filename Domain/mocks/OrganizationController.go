@@ -0,0 +1,63 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrganizationController is an autogenerated mock type for the OrganizationController type
+type OrganizationController struct {
+	mock.Mock
+}
+
+// AddMember provides a mock function with given fields: c
+func (_m *OrganizationController) AddMember(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Create provides a mock function with given fields: c
+func (_m *OrganizationController) Create(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchMembers provides a mock function with given fields: c
+func (_m *OrganizationController) FetchMembers(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchMine provides a mock function with given fields: c
+func (_m *OrganizationController) FetchMine(c *gin.Context) {
+	_m.Called(c)
+}
+
+// RemoveMember provides a mock function with given fields: c
+func (_m *OrganizationController) RemoveMember(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Switch provides a mock function with given fields: c
+func (_m *OrganizationController) Switch(c *gin.Context) {
+	_m.Called(c)
+}
+
+// UpdateMemberRole provides a mock function with given fields: c
+func (_m *OrganizationController) UpdateMemberRole(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewOrganizationController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOrganizationController creates a new instance of OrganizationController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOrganizationController(t mockConstructorTestingTNewOrganizationController) *OrganizationController {
+	mock := &OrganizationController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,250 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrganizationRepository is an autogenerated mock type for the OrganizationRepository type
+type OrganizationRepository struct {
+	mock.Mock
+}
+
+// AddMember provides a mock function with given fields: c, membership
+func (_m *OrganizationRepository) AddMember(c context.Context, membership *domain.Membership) error {
+	ret := _m.Called(c, membership)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Membership) error); ok {
+		r0 = rf(c, membership)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountOwners provides a mock function with given fields: c, orgID
+func (_m *OrganizationRepository) CountOwners(c context.Context, orgID string) (int64, error) {
+	ret := _m.Called(c, orgID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(c, orgID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(c, orgID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: c, org
+func (_m *OrganizationRepository) Create(c context.Context, org *domain.Organization) error {
+	ret := _m.Called(c, org)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Organization) error); ok {
+		r0 = rf(c, org)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByID provides a mock function with given fields: c, orgID
+func (_m *OrganizationRepository) FetchByID(c context.Context, orgID string) (domain.Organization, error) {
+	ret := _m.Called(c, orgID)
+
+	var r0 domain.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Organization, error)); ok {
+		return rf(c, orgID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Organization); ok {
+		r0 = rf(c, orgID)
+	} else {
+		r0 = ret.Get(0).(domain.Organization)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByIDs provides a mock function with given fields: c, orgIDs
+func (_m *OrganizationRepository) FetchByIDs(c context.Context, orgIDs []string) ([]domain.Organization, error) {
+	ret := _m.Called(c, orgIDs)
+
+	var r0 []domain.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Organization, error)); ok {
+		return rf(c, orgIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Organization); ok {
+		r0 = rf(c, orgIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Organization)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(c, orgIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchMembers provides a mock function with given fields: c, orgID
+func (_m *OrganizationRepository) FetchMembers(c context.Context, orgID string) ([]domain.Membership, error) {
+	ret := _m.Called(c, orgID)
+
+	var r0 []domain.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Membership, error)); ok {
+		return rf(c, orgID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Membership); ok {
+		r0 = rf(c, orgID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchMembership provides a mock function with given fields: c, orgID, userID
+func (_m *OrganizationRepository) FetchMembership(c context.Context, orgID string, userID string) (domain.Membership, error) {
+	ret := _m.Called(c, orgID, userID)
+
+	var r0 domain.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Membership, error)); ok {
+		return rf(c, orgID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Membership); ok {
+		r0 = rf(c, orgID, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Membership)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, orgID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchMemberships provides a mock function with given fields: c, userID
+func (_m *OrganizationRepository) FetchMemberships(c context.Context, userID string) ([]domain.Membership, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 []domain.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Membership, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Membership); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: c, orgID, userID
+func (_m *OrganizationRepository) RemoveMember(c context.Context, orgID string, userID string) error {
+	ret := _m.Called(c, orgID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, orgID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveUser provides a mock function with given fields: c, userID
+func (_m *OrganizationRepository) RemoveUser(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateMemberRole provides a mock function with given fields: c, orgID, userID, role
+func (_m *OrganizationRepository) UpdateMemberRole(c context.Context, orgID string, userID string, role string) error {
+	ret := _m.Called(c, orgID, userID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(c, orgID, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewOrganizationRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOrganizationRepository creates a new instance of OrganizationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOrganizationRepository(t mockConstructorTestingTNewOrganizationRepository) *OrganizationRepository {
+	mock := &OrganizationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
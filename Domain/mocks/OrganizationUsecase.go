@@ -0,0 +1,138 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrganizationUsecase is an autogenerated mock type for the OrganizationUsecase type
+type OrganizationUsecase struct {
+	mock.Mock
+}
+
+// AddMember provides a mock function with given fields: c, membership
+func (_m *OrganizationUsecase) AddMember(c context.Context, membership *domain.Membership) error {
+	ret := _m.Called(c, membership)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Membership) error); ok {
+		r0 = rf(c, membership)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: c, org, ownerID
+func (_m *OrganizationUsecase) Create(c context.Context, org *domain.Organization, ownerID string) error {
+	ret := _m.Called(c, org, ownerID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Organization, string) error); ok {
+		r0 = rf(c, org, ownerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchForUser provides a mock function with given fields: c, userID
+func (_m *OrganizationUsecase) FetchForUser(c context.Context, userID string) (*[]domain.Organization, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *[]domain.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*[]domain.Organization, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *[]domain.Organization); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Organization)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchMembers provides a mock function with given fields: c, orgID
+func (_m *OrganizationUsecase) FetchMembers(c context.Context, orgID string) (*[]domain.Membership, error) {
+	ret := _m.Called(c, orgID)
+
+	var r0 *[]domain.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*[]domain.Membership, error)); ok {
+		return rf(c, orgID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *[]domain.Membership); ok {
+		r0 = rf(c, orgID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: c, orgID, userID, actorRole
+func (_m *OrganizationUsecase) RemoveMember(c context.Context, orgID string, userID string, actorRole string) error {
+	ret := _m.Called(c, orgID, userID, actorRole)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(c, orgID, userID, actorRole)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateMemberRole provides a mock function with given fields: c, orgID, userID, role
+func (_m *OrganizationUsecase) UpdateMemberRole(c context.Context, orgID string, userID string, role string) error {
+	ret := _m.Called(c, orgID, userID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(c, orgID, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewOrganizationUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOrganizationUsecase creates a new instance of OrganizationUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOrganizationUsecase(t mockConstructorTestingTNewOrganizationUsecase) *OrganizationUsecase {
+	mock := &OrganizationUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
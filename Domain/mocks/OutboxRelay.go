@@ -0,0 +1,82 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OutboxRelay is an autogenerated mock type for the OutboxRelay type
+type OutboxRelay struct {
+	mock.Mock
+}
+
+// Purge provides a mock function with given fields: c
+func (_m *OutboxRelay) Purge(c context.Context) (int64, error) {
+	ret := _m.Called(c)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Relay provides a mock function with given fields: c
+func (_m *OutboxRelay) Relay(c context.Context) (int, error) {
+	ret := _m.Called(c)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Start provides a mock function with given fields: stop
+func (_m *OutboxRelay) Start(stop <-chan struct{}) {
+	_m.Called(stop)
+}
+
+type mockConstructorTestingTNewOutboxRelay interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOutboxRelay creates a new instance of OutboxRelay. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOutboxRelay(t mockConstructorTestingTNewOutboxRelay) *OutboxRelay {
+	mock := &OutboxRelay{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
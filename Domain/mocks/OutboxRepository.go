@@ -0,0 +1,138 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRepository is an autogenerated mock type for the OutboxRepository type
+type OutboxRepository struct {
+	mock.Mock
+}
+
+// Append provides a mock function with given fields: c, record
+func (_m *OutboxRepository) Append(c context.Context, record *domain.OutboxRecord) error {
+	ret := _m.Called(c, record)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OutboxRecord) error); ok {
+		r0 = rf(c, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimNext provides a mock function with given fields: c, now, lease
+func (_m *OutboxRepository) ClaimNext(c context.Context, now time.Time, lease time.Duration) (*domain.OutboxRecord, error) {
+	ret := _m.Called(c, now, lease)
+
+	var r0 *domain.OutboxRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration) (*domain.OutboxRecord, error)); ok {
+		return rf(c, now, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration) *domain.OutboxRecord); ok {
+		r0 = rf(c, now, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OutboxRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Duration) error); ok {
+		r1 = rf(c, now, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkDelivered provides a mock function with given fields: c, recordID, at
+func (_m *OutboxRepository) MarkDelivered(c context.Context, recordID string, at time.Time) error {
+	ret := _m.Called(c, recordID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, recordID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkFailed provides a mock function with given fields: c, recordID, lastError
+func (_m *OutboxRepository) MarkFailed(c context.Context, recordID string, lastError string) error {
+	ret := _m.Called(c, recordID, lastError)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, recordID, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeDelivered provides a mock function with given fields: c, cutoff
+func (_m *OutboxRepository) PurgeDelivered(c context.Context, cutoff time.Time) (int64, error) {
+	ret := _m.Called(c, cutoff)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(c, cutoff)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(c, cutoff)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(c, cutoff)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reschedule provides a mock function with given fields: c, recordID, lastError, next
+func (_m *OutboxRepository) Reschedule(c context.Context, recordID string, lastError string, next time.Time) error {
+	ret := _m.Called(c, recordID, lastError, next)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(c, recordID, lastError, next)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewOutboxRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOutboxRepository(t mockConstructorTestingTNewOutboxRepository) *OutboxRepository {
+	mock := &OutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,43 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PrivacyController is an autogenerated mock type for the PrivacyController type
+type PrivacyController struct {
+	mock.Mock
+}
+
+// Erase provides a mock function with given fields: c
+func (_m *PrivacyController) Erase(c *gin.Context) {
+	_m.Called(c)
+}
+
+// EraseMe provides a mock function with given fields: c
+func (_m *PrivacyController) EraseMe(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Export provides a mock function with given fields: c
+func (_m *PrivacyController) Export(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewPrivacyController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPrivacyController creates a new instance of PrivacyController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPrivacyController(t mockConstructorTestingTNewPrivacyController) *PrivacyController {
+	mock := &PrivacyController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PrivacyUsecase is an autogenerated mock type for the PrivacyUsecase type
+type PrivacyUsecase struct {
+	mock.Mock
+}
+
+// Erase provides a mock function with given fields: c, actorID, userID
+func (_m *PrivacyUsecase) Erase(c context.Context, actorID string, userID string) error {
+	ret := _m.Called(c, actorID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, actorID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EraseAccount provides a mock function with given fields: c, userID, password
+func (_m *PrivacyUsecase) EraseAccount(c context.Context, userID string, password string) error {
+	ret := _m.Called(c, userID, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Export provides a mock function with given fields: c, userID
+func (_m *PrivacyUsecase) Export(c context.Context, userID string) (*domain.PersonalDataExport, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *domain.PersonalDataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.PersonalDataExport, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.PersonalDataExport); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PersonalDataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewPrivacyUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPrivacyUsecase creates a new instance of PrivacyUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPrivacyUsecase(t mockConstructorTestingTNewPrivacyUsecase) *PrivacyUsecase {
+	mock := &PrivacyUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,138 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SessionRepository is an autogenerated mock type for the SessionRepository type
+type SessionRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, session
+func (_m *SessionRepository) Create(c context.Context, session *domain.Session) error {
+	ret := _m.Called(c, session)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Session) error); ok {
+		r0 = rf(c, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchActiveByUser provides a mock function with given fields: c, userID
+func (_m *SessionRepository) FetchActiveByUser(c context.Context, userID string) ([]domain.Session, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 []domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Session, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Session); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, sessionID
+func (_m *SessionRepository) FetchByID(c context.Context, sessionID string) (domain.Session, error) {
+	ret := _m.Called(c, sessionID)
+
+	var r0 domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Session, error)); ok {
+		return rf(c, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Session); ok {
+		r0 = rf(c, sessionID)
+	} else {
+		r0 = ret.Get(0).(domain.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: c, userID, sessionID, revokedAt
+func (_m *SessionRepository) Revoke(c context.Context, userID string, sessionID string, revokedAt time.Time) error {
+	ret := _m.Called(c, userID, sessionID, revokedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(c, userID, sessionID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAll provides a mock function with given fields: c, userID, revokedAt
+func (_m *SessionRepository) RevokeAll(c context.Context, userID string, revokedAt time.Time) error {
+	ret := _m.Called(c, userID, revokedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, userID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Touch provides a mock function with given fields: c, sessionID, lastSeenAt
+func (_m *SessionRepository) Touch(c context.Context, sessionID string, lastSeenAt time.Time) error {
+	ret := _m.Called(c, sessionID, lastSeenAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, sessionID, lastSeenAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSessionRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSessionRepository creates a new instance of SessionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSessionRepository(t mockConstructorTestingTNewSessionRepository) *SessionRepository {
+	mock := &SessionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
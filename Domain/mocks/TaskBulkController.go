@@ -0,0 +1,33 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskBulkController is an autogenerated mock type for the TaskBulkController type
+type TaskBulkController struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: c
+func (_m *TaskBulkController) Execute(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewTaskBulkController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTaskBulkController creates a new instance of TaskBulkController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTaskBulkController(t mockConstructorTestingTNewTaskBulkController) *TaskBulkController {
+	mock := &TaskBulkController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,56 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskBulkUsecase is an autogenerated mock type for the TaskBulkUsecase type
+type TaskBulkUsecase struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: c, userID, request
+func (_m *TaskBulkUsecase) Execute(c context.Context, userID string, request domain.BulkTaskRequest) (*domain.BulkTaskResult, error) {
+	ret := _m.Called(c, userID, request)
+
+	var r0 *domain.BulkTaskResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.BulkTaskRequest) (*domain.BulkTaskResult, error)); ok {
+		return rf(c, userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.BulkTaskRequest) *domain.BulkTaskResult); ok {
+		r0 = rf(c, userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.BulkTaskResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.BulkTaskRequest) error); ok {
+		r1 = rf(c, userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTaskBulkUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTaskBulkUsecase creates a new instance of TaskBulkUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTaskBulkUsecase(t mockConstructorTestingTNewTaskBulkUsecase) *TaskBulkUsecase {
+	mock := &TaskBulkUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -12,6 +12,11 @@ type TaskController struct {
 	mock.Mock
 }
 
+// AddChecklistItem provides a mock function with given fields: c
+func (_m *TaskController) AddChecklistItem(c *gin.Context) {
+	_m.Called(c)
+}
+
 // Create provides a mock function with given fields: c
 func (_m *TaskController) Create(c *gin.Context) {
 	_m.Called(c)
@@ -22,6 +27,16 @@ func (_m *TaskController) Delete(c *gin.Context) {
 	_m.Called(c)
 }
 
+// DeleteChecklistItem provides a mock function with given fields: c
+func (_m *TaskController) DeleteChecklistItem(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Export provides a mock function with given fields: c
+func (_m *TaskController) Export(c *gin.Context) {
+	_m.Called(c)
+}
+
 // FetchAll provides a mock function with given fields: c
 func (_m *TaskController) FetchAll(c *gin.Context) {
 	_m.Called(c)
@@ -32,11 +47,26 @@ func (_m *TaskController) FetchByTaskID(c *gin.Context) {
 	_m.Called(c)
 }
 
+// ReorderChecklist provides a mock function with given fields: c
+func (_m *TaskController) ReorderChecklist(c *gin.Context) {
+	_m.Called(c)
+}
+
+// ToggleChecklistItem provides a mock function with given fields: c
+func (_m *TaskController) ToggleChecklistItem(c *gin.Context) {
+	_m.Called(c)
+}
+
 // Update provides a mock function with given fields: c
 func (_m *TaskController) Update(c *gin.Context) {
 	_m.Called(c)
 }
 
+// UpdateChecklistItem provides a mock function with given fields: c
+func (_m *TaskController) UpdateChecklistItem(c *gin.Context) {
+	_m.Called(c)
+}
+
 type mockConstructorTestingTNewTaskController interface {
 	mock.TestingT
 	Cleanup(func())
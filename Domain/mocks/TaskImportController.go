@@ -0,0 +1,38 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskImportController is an autogenerated mock type for the TaskImportController type
+type TaskImportController struct {
+	mock.Mock
+}
+
+// FetchJob provides a mock function with given fields: c
+func (_m *TaskImportController) FetchJob(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Import provides a mock function with given fields: c
+func (_m *TaskImportController) Import(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewTaskImportController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTaskImportController creates a new instance of TaskImportController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTaskImportController(t mockConstructorTestingTNewTaskImportController) *TaskImportController {
+	mock := &TaskImportController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TaskImportUsecase is an autogenerated mock type for the TaskImportUsecase type
+type TaskImportUsecase struct {
+	mock.Mock
+}
+
+// FailInterrupted provides a mock function with given fields: c, startedAt
+func (_m *TaskImportUsecase) FailInterrupted(c context.Context, startedAt time.Time) (int64, error) {
+	ret := _m.Called(c, startedAt)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(c, startedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(c, startedAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(c, startedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchJob provides a mock function with given fields: c, jobID
+func (_m *TaskImportUsecase) FetchJob(c context.Context, jobID string) (*domain.ImportJob, error) {
+	ret := _m.Called(c, jobID)
+
+	var r0 *domain.ImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.ImportJob, error)); ok {
+		return rf(c, jobID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ImportJob); ok {
+		r0 = rf(c, jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ImportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: c, userID, request
+func (_m *TaskImportUsecase) Import(c context.Context, userID string, request domain.TaskImportRequest) (*domain.ImportJob, error) {
+	ret := _m.Called(c, userID, request)
+
+	var r0 *domain.ImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TaskImportRequest) (*domain.ImportJob, error)); ok {
+		return rf(c, userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TaskImportRequest) *domain.ImportJob); ok {
+		r0 = rf(c, userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ImportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.TaskImportRequest) error); ok {
+		r1 = rf(c, userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTaskImportUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTaskImportUsecase creates a new instance of TaskImportUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTaskImportUsecase(t mockConstructorTestingTNewTaskImportUsecase) *TaskImportUsecase {
+	mock := &TaskImportUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 	domain "task-manger-api_test/Domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TaskRepository is an autogenerated mock type for the TaskRepository type
@@ -14,6 +16,82 @@ type TaskRepository struct {
 	mock.Mock
 }
 
+// AddChecklistItem provides a mock function with given fields: c, taskID, item, position
+func (_m *TaskRepository) AddChecklistItem(c context.Context, taskID string, item domain.ChecklistItem, position int) error {
+	ret := _m.Called(c, taskID, item, position)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ChecklistItem, int) error); ok {
+		r0 = rf(c, taskID, item, position)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BackfillCustomField provides a mock function with given fields: c, key, value
+func (_m *TaskRepository) BackfillCustomField(c context.Context, key string, value interface{}) error {
+	ret := _m.Called(c, key, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(c, key, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountCustomFieldValues provides a mock function with given fields: c, key, values
+func (_m *TaskRepository) CountCustomFieldValues(c context.Context, key string, values []interface{}) (int64, error) {
+	ret := _m.Called(c, key, values)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []interface{}) (int64, error)); ok {
+		return rf(c, key, values)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []interface{}) int64); ok {
+		r0 = rf(c, key, values)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []interface{}) error); ok {
+		r1 = rf(c, key, values)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountMissingCustomField provides a mock function with given fields: c, key
+func (_m *TaskRepository) CountMissingCustomField(c context.Context, key string) (int64, error) {
+	ret := _m.Called(c, key)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(c, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(c, key)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: c, task
 func (_m *TaskRepository) Create(c context.Context, task *domain.Task) error {
 	ret := _m.Called(c, task)
@@ -42,25 +120,65 @@ func (_m *TaskRepository) Delete(c context.Context, taskID string) error {
 	return r0
 }
 
-// FetchAll provides a mock function with given fields: c
-func (_m *TaskRepository) FetchAll(c context.Context) (*[]domain.Task, error) {
-	ret := _m.Called(c)
+// DeleteChecklistItem provides a mock function with given fields: c, taskID, itemID
+func (_m *TaskRepository) DeleteChecklistItem(c context.Context, taskID string, itemID string) error {
+	ret := _m.Called(c, taskID, itemID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, taskID, itemID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c, filter
+func (_m *TaskRepository) FetchAll(c context.Context, filter domain.TaskFilter) (*[]domain.Task, error) {
+	ret := _m.Called(c, filter)
 
 	var r0 *[]domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*[]domain.Task, error)); ok {
-		return rf(c)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskFilter) (*[]domain.Task, error)); ok {
+		return rf(c, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) *[]domain.Task); ok {
-		r0 = rf(c)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskFilter) *[]domain.Task); ok {
+		r0 = rf(c, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(c)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskFilter) error); ok {
+		r1 = rf(c, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByExternalID provides a mock function with given fields: c, externalID
+func (_m *TaskRepository) FetchByExternalID(c context.Context, externalID string) (*domain.Task, error) {
+	ret := _m.Called(c, externalID)
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Task, error)); ok {
+		return rf(c, externalID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Task); ok {
+		r0 = rf(c, externalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, externalID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -94,6 +212,114 @@ func (_m *TaskRepository) FetchByTaskID(c context.Context, taskID string) (*doma
 	return r0, r1
 }
 
+// FetchByUser provides a mock function with given fields: c, userID
+func (_m *TaskRepository) FetchByUser(c context.Context, userID string) ([]domain.Task, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 []domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Task, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Task); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceChecklist provides a mock function with given fields: c, taskID, current, reordered
+func (_m *TaskRepository) ReplaceChecklist(c context.Context, taskID string, current []domain.ChecklistItem, reordered []domain.ChecklistItem) error {
+	ret := _m.Called(c, taskID, current, reordered)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.ChecklistItem, []domain.ChecklistItem) error); ok {
+		r0 = rf(c, taskID, current, reordered)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: c, search
+func (_m *TaskRepository) Search(c context.Context, search domain.TaskSearch) ([]domain.Task, error) {
+	ret := _m.Called(c, search)
+
+	var r0 []domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskSearch) ([]domain.Task, error)); ok {
+		return rf(c, search)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskSearch) []domain.Task); ok {
+		r0 = rf(c, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskSearch) error); ok {
+		r1 = rf(c, search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stream provides a mock function with given fields: c, filter, fn
+func (_m *TaskRepository) Stream(c context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+	ret := _m.Called(c, filter, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskFilter, func(domain.Task) error) error); ok {
+		r0 = rf(c, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unassign provides a mock function with given fields: c, userID
+func (_m *TaskRepository) Unassign(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnsetCustomField provides a mock function with given fields: c, key
+func (_m *TaskRepository) UnsetCustomField(c context.Context, key string) error {
+	ret := _m.Called(c, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: c, taskID, updatedTask
 func (_m *TaskRepository) Update(c context.Context, taskID string, updatedTask domain.Task) error {
 	ret := _m.Called(c, taskID, updatedTask)
@@ -108,6 +334,20 @@ func (_m *TaskRepository) Update(c context.Context, taskID string, updatedTask d
 	return r0
 }
 
+// UpdateChecklistItem provides a mock function with given fields: c, taskID, itemID, update, doneAt
+func (_m *TaskRepository) UpdateChecklistItem(c context.Context, taskID string, itemID string, update domain.ChecklistItemUpdate, doneAt time.Time) error {
+	ret := _m.Called(c, taskID, itemID, update, doneAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ChecklistItemUpdate, time.Time) error); ok {
+		r0 = rf(c, taskID, itemID, update, doneAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewTaskRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,33 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskSearchController is an autogenerated mock type for the TaskSearchController type
+type TaskSearchController struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: c
+func (_m *TaskSearchController) Search(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewTaskSearchController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTaskSearchController creates a new instance of TaskSearchController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTaskSearchController(t mockConstructorTestingTNewTaskSearchController) *TaskSearchController {
+	mock := &TaskSearchController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
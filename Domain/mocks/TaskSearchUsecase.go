@@ -0,0 +1,56 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskSearchUsecase is an autogenerated mock type for the TaskSearchUsecase type
+type TaskSearchUsecase struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: c, request
+func (_m *TaskSearchUsecase) Search(c context.Context, request domain.TaskSearchRequest) (*domain.TaskSearchResult, error) {
+	ret := _m.Called(c, request)
+
+	var r0 *domain.TaskSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskSearchRequest) (*domain.TaskSearchResult, error)); ok {
+		return rf(c, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskSearchRequest) *domain.TaskSearchResult); ok {
+		r0 = rf(c, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskSearchRequest) error); ok {
+		r1 = rf(c, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTaskSearchUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTaskSearchUsecase creates a new instance of TaskSearchUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTaskSearchUsecase(t mockConstructorTestingTNewTaskSearchUsecase) *TaskSearchUsecase {
+	mock := &TaskSearchUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskTemplateController is an autogenerated mock type for the TaskTemplateController type
+type TaskTemplateController struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c
+func (_m *TaskTemplateController) Create(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Delete provides a mock function with given fields: c
+func (_m *TaskTemplateController) Delete(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *TaskTemplateController) FetchAll(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchByID provides a mock function with given fields: c
+func (_m *TaskTemplateController) FetchByID(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Instantiate provides a mock function with given fields: c
+func (_m *TaskTemplateController) Instantiate(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Update provides a mock function with given fields: c
+func (_m *TaskTemplateController) Update(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewTaskTemplateController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTaskTemplateController creates a new instance of TaskTemplateController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTaskTemplateController(t mockConstructorTestingTNewTaskTemplateController) *TaskTemplateController {
+	mock := &TaskTemplateController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
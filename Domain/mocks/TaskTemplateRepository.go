@@ -0,0 +1,122 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskTemplateRepository is an autogenerated mock type for the TaskTemplateRepository type
+type TaskTemplateRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, template
+func (_m *TaskTemplateRepository) Create(c context.Context, template *domain.TaskTemplate) error {
+	ret := _m.Called(c, template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TaskTemplate) error); ok {
+		r0 = rf(c, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, templateID
+func (_m *TaskTemplateRepository) Delete(c context.Context, templateID string) error {
+	ret := _m.Called(c, templateID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, templateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *TaskTemplateRepository) FetchAll(c context.Context) ([]domain.TaskTemplate, error) {
+	ret := _m.Called(c)
+
+	var r0 []domain.TaskTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.TaskTemplate, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.TaskTemplate); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TaskTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, templateID
+func (_m *TaskTemplateRepository) FetchByID(c context.Context, templateID string) (domain.TaskTemplate, error) {
+	ret := _m.Called(c, templateID)
+
+	var r0 domain.TaskTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TaskTemplate, error)); ok {
+		return rf(c, templateID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TaskTemplate); ok {
+		r0 = rf(c, templateID)
+	} else {
+		r0 = ret.Get(0).(domain.TaskTemplate)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, templateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: c, template
+func (_m *TaskTemplateRepository) Update(c context.Context, template domain.TaskTemplate) error {
+	ret := _m.Called(c, template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskTemplate) error); ok {
+		r0 = rf(c, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTaskTemplateRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTaskTemplateRepository creates a new instance of TaskTemplateRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTaskTemplateRepository(t mockConstructorTestingTNewTaskTemplateRepository) *TaskTemplateRepository {
+	mock := &TaskTemplateRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
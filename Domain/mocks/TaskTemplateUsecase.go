@@ -0,0 +1,162 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskTemplateUsecase is an autogenerated mock type for the TaskTemplateUsecase type
+type TaskTemplateUsecase struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, template
+func (_m *TaskTemplateUsecase) Create(c context.Context, template *domain.TaskTemplate) error {
+	ret := _m.Called(c, template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TaskTemplate) error); ok {
+		r0 = rf(c, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, templateID
+func (_m *TaskTemplateUsecase) Delete(c context.Context, templateID string) error {
+	ret := _m.Called(c, templateID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, templateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *TaskTemplateUsecase) FetchAll(c context.Context) (*[]domain.TaskTemplate, error) {
+	ret := _m.Called(c)
+
+	var r0 *[]domain.TaskTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*[]domain.TaskTemplate, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *[]domain.TaskTemplate); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.TaskTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, templateID
+func (_m *TaskTemplateUsecase) FetchByID(c context.Context, templateID string) (*domain.TaskTemplate, error) {
+	ret := _m.Called(c, templateID)
+
+	var r0 *domain.TaskTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TaskTemplate, error)); ok {
+		return rf(c, templateID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TaskTemplate); ok {
+		r0 = rf(c, templateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, templateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Instantiate provides a mock function with given fields: c, templateID, request, createdBy
+func (_m *TaskTemplateUsecase) Instantiate(c context.Context, templateID string, request domain.TemplateInstantiation, createdBy string) (*domain.TaskTree, error) {
+	ret := _m.Called(c, templateID, request, createdBy)
+
+	var r0 *domain.TaskTree
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TemplateInstantiation, string) (*domain.TaskTree, error)); ok {
+		return rf(c, templateID, request, createdBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TemplateInstantiation, string) *domain.TaskTree); ok {
+		r0 = rf(c, templateID, request, createdBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskTree)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.TemplateInstantiation, string) error); ok {
+		r1 = rf(c, templateID, request, createdBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: c, templateID, template
+func (_m *TaskTemplateUsecase) Update(c context.Context, templateID string, template domain.TaskTemplate) (*domain.TaskTemplate, error) {
+	ret := _m.Called(c, templateID, template)
+
+	var r0 *domain.TaskTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TaskTemplate) (*domain.TaskTemplate, error)); ok {
+		return rf(c, templateID, template)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TaskTemplate) *domain.TaskTemplate); ok {
+		r0 = rf(c, templateID, template)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.TaskTemplate) error); ok {
+		r1 = rf(c, templateID, template)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTaskTemplateUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTaskTemplateUsecase creates a new instance of TaskTemplateUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTaskTemplateUsecase(t mockConstructorTestingTNewTaskTemplateUsecase) *TaskTemplateUsecase {
+	mock := &TaskTemplateUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
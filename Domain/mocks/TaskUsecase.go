@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	domain "task-manger-api_test/Domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -14,6 +15,32 @@ type TaskUsecase struct {
 	mock.Mock
 }
 
+// AddChecklistItem provides a mock function with given fields: c, taskID, item, position
+func (_m *TaskUsecase) AddChecklistItem(c context.Context, taskID string, item domain.ChecklistItem, position *int) (*domain.Task, error) {
+	ret := _m.Called(c, taskID, item, position)
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ChecklistItem, *int) (*domain.Task, error)); ok {
+		return rf(c, taskID, item, position)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ChecklistItem, *int) *domain.Task); ok {
+		r0 = rf(c, taskID, item, position)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ChecklistItem, *int) error); ok {
+		r1 = rf(c, taskID, item, position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: c, task
 func (_m *TaskUsecase) Create(c context.Context, task *domain.Task) error {
 	ret := _m.Called(c, task)
@@ -42,25 +69,65 @@ func (_m *TaskUsecase) Delete(c context.Context, taskID string) error {
 	return r0
 }
 
-// FetchAll provides a mock function with given fields: c
-func (_m *TaskUsecase) FetchAll(c context.Context) (*[]domain.Task, error) {
-	ret := _m.Called(c)
+// DeleteChecklistItem provides a mock function with given fields: c, taskID, itemID
+func (_m *TaskUsecase) DeleteChecklistItem(c context.Context, taskID string, itemID string) (*domain.Task, error) {
+	ret := _m.Called(c, taskID, itemID)
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.Task, error)); ok {
+		return rf(c, taskID, itemID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Task); ok {
+		r0 = rf(c, taskID, itemID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, taskID, itemID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Export provides a mock function with given fields: c, query, format, w
+func (_m *TaskUsecase) Export(c context.Context, query domain.TaskQuery, format string, w io.Writer) error {
+	ret := _m.Called(c, query, format, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery, string, io.Writer) error); ok {
+		r0 = rf(c, query, format, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c, query
+func (_m *TaskUsecase) FetchAll(c context.Context, query domain.TaskQuery) (*[]domain.Task, error) {
+	ret := _m.Called(c, query)
 
 	var r0 *[]domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*[]domain.Task, error)); ok {
-		return rf(c)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery) (*[]domain.Task, error)); ok {
+		return rf(c, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) *[]domain.Task); ok {
-		r0 = rf(c)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery) *[]domain.Task); ok {
+		r0 = rf(c, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(c)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskQuery) error); ok {
+		r1 = rf(c, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -94,6 +161,58 @@ func (_m *TaskUsecase) FetchByTaskID(c context.Context, taskID string) (*domain.
 	return r0, r1
 }
 
+// ReorderChecklist provides a mock function with given fields: c, taskID, itemIDs
+func (_m *TaskUsecase) ReorderChecklist(c context.Context, taskID string, itemIDs []string) (*domain.Task, error) {
+	ret := _m.Called(c, taskID, itemIDs)
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (*domain.Task, error)); ok {
+		return rf(c, taskID, itemIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *domain.Task); ok {
+		r0 = rf(c, taskID, itemIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(c, taskID, itemIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ToggleChecklistItem provides a mock function with given fields: c, taskID, itemID
+func (_m *TaskUsecase) ToggleChecklistItem(c context.Context, taskID string, itemID string) (*domain.Task, error) {
+	ret := _m.Called(c, taskID, itemID)
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.Task, error)); ok {
+		return rf(c, taskID, itemID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Task); ok {
+		r0 = rf(c, taskID, itemID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, taskID, itemID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: c, taskID, updatedTask
 func (_m *TaskUsecase) Update(c context.Context, taskID string, updatedTask domain.Task) error {
 	ret := _m.Called(c, taskID, updatedTask)
@@ -108,6 +227,32 @@ func (_m *TaskUsecase) Update(c context.Context, taskID string, updatedTask doma
 	return r0
 }
 
+// UpdateChecklistItem provides a mock function with given fields: c, taskID, itemID, update
+func (_m *TaskUsecase) UpdateChecklistItem(c context.Context, taskID string, itemID string, update domain.ChecklistItemUpdate) (*domain.Task, error) {
+	ret := _m.Called(c, taskID, itemID, update)
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ChecklistItemUpdate) (*domain.Task, error)); ok {
+		return rf(c, taskID, itemID, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ChecklistItemUpdate) *domain.Task); ok {
+		r0 = rf(c, taskID, itemID, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.ChecklistItemUpdate) error); ok {
+		r1 = rf(c, taskID, itemID, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewTaskUsecase interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,198 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TimeEntryRepository is an autogenerated mock type for the TimeEntryRepository type
+type TimeEntryRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, entry
+func (_m *TimeEntryRepository) Create(c context.Context, entry *domain.TimeEntry) error {
+	ret := _m.Called(c, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TimeEntry) error); ok {
+		r0 = rf(c, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, entryID
+func (_m *TimeEntryRepository) Delete(c context.Context, entryID string) error {
+	ret := _m.Called(c, entryID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, entryID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByID provides a mock function with given fields: c, entryID
+func (_m *TimeEntryRepository) FetchByID(c context.Context, entryID string) (domain.TimeEntry, error) {
+	ret := _m.Called(c, entryID)
+
+	var r0 domain.TimeEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TimeEntry, error)); ok {
+		return rf(c, entryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TimeEntry); ok {
+		r0 = rf(c, entryID)
+	} else {
+		r0 = ret.Get(0).(domain.TimeEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, entryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByTask provides a mock function with given fields: c, taskID
+func (_m *TimeEntryRepository) FetchByTask(c context.Context, taskID string) ([]domain.TimeEntry, error) {
+	ret := _m.Called(c, taskID)
+
+	var r0 []domain.TimeEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.TimeEntry, error)); ok {
+		return rf(c, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.TimeEntry); ok {
+		r0 = rf(c, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TimeEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchRunning provides a mock function with given fields: c, userID
+func (_m *TimeEntryRepository) FetchRunning(c context.Context, userID string) (domain.TimeEntry, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 domain.TimeEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TimeEntry, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TimeEntry); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Get(0).(domain.TimeEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Report provides a mock function with given fields: c, filter
+func (_m *TimeEntryRepository) Report(c context.Context, filter domain.TimeReportFilter) ([]domain.TimeReportRow, error) {
+	ret := _m.Called(c, filter)
+
+	var r0 []domain.TimeReportRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeReportFilter) ([]domain.TimeReportRow, error)); ok {
+		return rf(c, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeReportFilter) []domain.TimeReportRow); ok {
+		r0 = rf(c, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TimeReportRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TimeReportFilter) error); ok {
+		r1 = rf(c, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartTimer provides a mock function with given fields: c, entry
+func (_m *TimeEntryRepository) StartTimer(c context.Context, entry *domain.TimeEntry) error {
+	ret := _m.Called(c, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TimeEntry) error); ok {
+		r0 = rf(c, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StopTimer provides a mock function with given fields: c, userID, endedAt, longest
+func (_m *TimeEntryRepository) StopTimer(c context.Context, userID string, endedAt time.Time, longest time.Duration) (domain.TimeEntry, error) {
+	ret := _m.Called(c, userID, endedAt, longest)
+
+	var r0 domain.TimeEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) (domain.TimeEntry, error)); ok {
+		return rf(c, userID, endedAt, longest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) domain.TimeEntry); ok {
+		r0 = rf(c, userID, endedAt, longest)
+	} else {
+		r0 = ret.Get(0).(domain.TimeEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration) error); ok {
+		r1 = rf(c, userID, endedAt, longest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTimeEntryRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTimeEntryRepository creates a new instance of TimeEntryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTimeEntryRepository(t mockConstructorTestingTNewTimeEntryRepository) *TimeEntryRepository {
+	mock := &TimeEntryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
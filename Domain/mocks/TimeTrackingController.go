@@ -0,0 +1,63 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TimeTrackingController is an autogenerated mock type for the TimeTrackingController type
+type TimeTrackingController struct {
+	mock.Mock
+}
+
+// DeleteEntry provides a mock function with given fields: c
+func (_m *TimeTrackingController) DeleteEntry(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchByTask provides a mock function with given fields: c
+func (_m *TimeTrackingController) FetchByTask(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchRunning provides a mock function with given fields: c
+func (_m *TimeTrackingController) FetchRunning(c *gin.Context) {
+	_m.Called(c)
+}
+
+// LogTime provides a mock function with given fields: c
+func (_m *TimeTrackingController) LogTime(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Report provides a mock function with given fields: c
+func (_m *TimeTrackingController) Report(c *gin.Context) {
+	_m.Called(c)
+}
+
+// StartTimer provides a mock function with given fields: c
+func (_m *TimeTrackingController) StartTimer(c *gin.Context) {
+	_m.Called(c)
+}
+
+// StopTimer provides a mock function with given fields: c
+func (_m *TimeTrackingController) StopTimer(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewTimeTrackingController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTimeTrackingController creates a new instance of TimeTrackingController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTimeTrackingController(t mockConstructorTestingTNewTimeTrackingController) *TimeTrackingController {
+	mock := &TimeTrackingController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,188 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TimeTrackingUsecase is an autogenerated mock type for the TimeTrackingUsecase type
+type TimeTrackingUsecase struct {
+	mock.Mock
+}
+
+// DeleteEntry provides a mock function with given fields: c, entryID, actorID, moderator
+func (_m *TimeTrackingUsecase) DeleteEntry(c context.Context, entryID string, actorID string, moderator bool) error {
+	ret := _m.Called(c, entryID, actorID, moderator)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) error); ok {
+		r0 = rf(c, entryID, actorID, moderator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByTask provides a mock function with given fields: c, taskID
+func (_m *TimeTrackingUsecase) FetchByTask(c context.Context, taskID string) (*[]domain.TimeEntry, error) {
+	ret := _m.Called(c, taskID)
+
+	var r0 *[]domain.TimeEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*[]domain.TimeEntry, error)); ok {
+		return rf(c, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *[]domain.TimeEntry); ok {
+		r0 = rf(c, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.TimeEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchRunning provides a mock function with given fields: c, userID
+func (_m *TimeTrackingUsecase) FetchRunning(c context.Context, userID string) (*domain.TimeEntry, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *domain.TimeEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TimeEntry, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TimeEntry); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TimeEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LogTime provides a mock function with given fields: c, taskID, entry
+func (_m *TimeTrackingUsecase) LogTime(c context.Context, taskID string, entry *domain.TimeEntry) error {
+	ret := _m.Called(c, taskID, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TimeEntry) error); ok {
+		r0 = rf(c, taskID, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Report provides a mock function with given fields: c, filter
+func (_m *TimeTrackingUsecase) Report(c context.Context, filter domain.TimeReportFilter) (*domain.TimeReport, error) {
+	ret := _m.Called(c, filter)
+
+	var r0 *domain.TimeReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeReportFilter) (*domain.TimeReport, error)); ok {
+		return rf(c, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeReportFilter) *domain.TimeReport); ok {
+		r0 = rf(c, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TimeReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TimeReportFilter) error); ok {
+		r1 = rf(c, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartTimer provides a mock function with given fields: c, taskID, userID, note
+func (_m *TimeTrackingUsecase) StartTimer(c context.Context, taskID string, userID string, note string) (*domain.TimeEntry, error) {
+	ret := _m.Called(c, taskID, userID, note)
+
+	var r0 *domain.TimeEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.TimeEntry, error)); ok {
+		return rf(c, taskID, userID, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.TimeEntry); ok {
+		r0 = rf(c, taskID, userID, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TimeEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(c, taskID, userID, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StopTimer provides a mock function with given fields: c, userID
+func (_m *TimeTrackingUsecase) StopTimer(c context.Context, userID string) (*domain.TimeEntry, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *domain.TimeEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TimeEntry, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TimeEntry); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TimeEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTimeTrackingUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTimeTrackingUsecase creates a new instance of TimeTrackingUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTimeTrackingUsecase(t mockConstructorTestingTNewTimeTrackingUsecase) *TimeTrackingUsecase {
+	mock := &TimeTrackingUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -12,21 +12,81 @@ type UserController struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: c
+func (_m *UserController) ChangePassword(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Deactivate provides a mock function with given fields: c
+func (_m *UserController) Deactivate(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Delete provides a mock function with given fields: c
+func (_m *UserController) Delete(c *gin.Context) {
+	_m.Called(c)
+}
+
+// DeleteMe provides a mock function with given fields: c
+func (_m *UserController) DeleteMe(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Demote provides a mock function with given fields: c
+func (_m *UserController) Demote(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *UserController) FetchAll(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchByUserID provides a mock function with given fields: c
+func (_m *UserController) FetchByUserID(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchSessions provides a mock function with given fields: c
+func (_m *UserController) FetchSessions(c *gin.Context) {
+	_m.Called(c)
+}
+
 // Login provides a mock function with given fields: c
 func (_m *UserController) Login(c *gin.Context) {
 	_m.Called(c)
 }
 
+// Me provides a mock function with given fields: c
+func (_m *UserController) Me(c *gin.Context) {
+	_m.Called(c)
+}
+
 // PromoteUser provides a mock function with given fields: c
 func (_m *UserController) PromoteUser(c *gin.Context) {
 	_m.Called(c)
 }
 
+// Reactivate provides a mock function with given fields: c
+func (_m *UserController) Reactivate(c *gin.Context) {
+	_m.Called(c)
+}
+
+// RevokeSession provides a mock function with given fields: c
+func (_m *UserController) RevokeSession(c *gin.Context) {
+	_m.Called(c)
+}
+
 // Signup provides a mock function with given fields: c
 func (_m *UserController) Signup(c *gin.Context) {
 	_m.Called(c)
 }
 
+// UpdateMe provides a mock function with given fields: c
+func (_m *UserController) UpdateMe(c *gin.Context) {
+	_m.Called(c)
+}
+
 type mockConstructorTestingTNewUserController interface {
 	mock.TestingT
 	Cleanup(func())
@@ -7,6 +7,8 @@ import (
 	domain "task-manger-api_test/Domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -14,6 +16,34 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// AddIdentity provides a mock function with given fields: c, userID, identity
+func (_m *UserRepository) AddIdentity(c context.Context, userID string, identity domain.ExternalIdentity) error {
+	ret := _m.Called(c, userID, identity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ExternalIdentity) error); ok {
+		r0 = rf(c, userID, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Anonymize provides a mock function with given fields: c, userID, erasedAt
+func (_m *UserRepository) Anonymize(c context.Context, userID string, erasedAt time.Time) error {
+	ret := _m.Called(c, userID, erasedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, userID, erasedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: c, user
 func (_m *UserRepository) Create(c context.Context, user *domain.User) error {
 	ret := _m.Called(c, user)
@@ -28,6 +58,139 @@ func (_m *UserRepository) Create(c context.Context, user *domain.User) error {
 	return r0
 }
 
+// CreateExternal provides a mock function with given fields: c, user
+func (_m *UserRepository) CreateExternal(c context.Context, user *domain.User) error {
+	ret := _m.Called(c, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = rf(c, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, userID
+func (_m *UserRepository) Delete(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c, filter
+func (_m *UserRepository) FetchAll(c context.Context, filter domain.UserFilter) ([]domain.User, int64, error) {
+	ret := _m.Called(c, filter)
+
+	var r0 []domain.User
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserFilter) ([]domain.User, int64, error)); ok {
+		return rf(c, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserFilter) []domain.User); ok {
+		r0 = rf(c, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.UserFilter) int64); ok {
+		r1 = rf(c, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, domain.UserFilter) error); ok {
+		r2 = rf(c, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchByUserID provides a mock function with given fields: c, userID
+func (_m *UserRepository) FetchByUserID(c context.Context, userID string) (domain.User, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByEmail provides a mock function with given fields: c, email
+func (_m *UserRepository) FindByEmail(c context.Context, email string) (domain.User, error) {
+	ret := _m.Called(c, email)
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(c, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(c, email)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByIdentity provides a mock function with given fields: c, provider, subject
+func (_m *UserRepository) FindByIdentity(c context.Context, provider string, subject string) (domain.User, error) {
+	ret := _m.Called(c, provider, subject)
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.User, error)); ok {
+		return rf(c, provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.User); ok {
+		r0 = rf(c, provider, subject)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByUsername provides a mock function with given fields: c, usrname
 func (_m *UserRepository) FindByUsername(c context.Context, usrname string) (domain.User, error) {
 	ret := _m.Called(c, usrname)
@@ -52,6 +215,34 @@ func (_m *UserRepository) FindByUsername(c context.Context, usrname string) (dom
 	return r0, r1
 }
 
+// SetDeactivated provides a mock function with given fields: c, userID, deactivatedAt
+func (_m *UserRepository) SetDeactivated(c context.Context, userID string, deactivatedAt *time.Time) error {
+	ret := _m.Called(c, userID, deactivatedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) error); ok {
+		r0 = rf(c, userID, deactivatedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserType provides a mock function with given fields: c, userID, userType
+func (_m *UserRepository) SetUserType(c context.Context, userID string, userType string) error {
+	ret := _m.Called(c, userID, userType)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userID, userType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: c, userID
 func (_m *UserRepository) Update(c context.Context, userID string) error {
 	ret := _m.Called(c, userID)
@@ -66,6 +257,34 @@ func (_m *UserRepository) Update(c context.Context, userID string) error {
 	return r0
 }
 
+// UpdatePassword provides a mock function with given fields: c, userID, passwordHash
+func (_m *UserRepository) UpdatePassword(c context.Context, userID string, passwordHash string) error {
+	ret := _m.Called(c, userID, passwordHash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userID, passwordHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProfile provides a mock function with given fields: c, userID, update
+func (_m *UserRepository) UpdateProfile(c context.Context, userID string, update domain.ProfileUpdate) error {
+	ret := _m.Called(c, userID, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProfileUpdate) error); ok {
+		r0 = rf(c, userID, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewUserRepository interface {
 	mock.TestingT
 	Cleanup(func())
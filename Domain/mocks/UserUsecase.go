@@ -14,6 +14,20 @@ type UserUsecase struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: c, userID, change
+func (_m *UserUsecase) ChangePassword(c context.Context, userID string, change domain.PasswordChange) error {
+	ret := _m.Called(c, userID, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.PasswordChange) error); ok {
+		r0 = rf(c, userID, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: c, user
 func (_m *UserUsecase) Create(c context.Context, user *domain.User) error {
 	ret := _m.Called(c, user)
@@ -28,6 +42,157 @@ func (_m *UserUsecase) Create(c context.Context, user *domain.User) error {
 	return r0
 }
 
+// Delete provides a mock function with given fields: c, userID
+func (_m *UserUsecase) Delete(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAccount provides a mock function with given fields: c, userID, password
+func (_m *UserUsecase) DeleteAccount(c context.Context, userID string, password string) error {
+	ret := _m.Called(c, userID, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Demote provides a mock function with given fields: c, userID
+func (_m *UserUsecase) Demote(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c, filter
+func (_m *UserUsecase) FetchAll(c context.Context, filter domain.UserFilter) (*domain.UserPage, error) {
+	ret := _m.Called(c, filter)
+
+	var r0 *domain.UserPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserFilter) (*domain.UserPage, error)); ok {
+		return rf(c, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserFilter) *domain.UserPage); ok {
+		r0 = rf(c, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.UserFilter) error); ok {
+		r1 = rf(c, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByUserID provides a mock function with given fields: c, userID
+func (_m *UserUsecase) FetchByUserID(c context.Context, userID string) (*domain.User, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchSessions provides a mock function with given fields: c, userID, currentSessionID
+func (_m *UserUsecase) FetchSessions(c context.Context, userID string, currentSessionID string) (*[]domain.Session, error) {
+	ret := _m.Called(c, userID, currentSessionID)
+
+	var r0 *[]domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*[]domain.Session, error)); ok {
+		return rf(c, userID, currentSessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *[]domain.Session); ok {
+		r0 = rf(c, userID, currentSessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, userID, currentSessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HandleExternalLogin provides a mock function with given fields: c, identity
+func (_m *UserUsecase) HandleExternalLogin(c context.Context, identity *domain.ExternalIdentity) (string, string, error) {
+	ret := _m.Called(c, identity)
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ExternalIdentity) (string, string, error)); ok {
+		return rf(c, identity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ExternalIdentity) string); ok {
+		r0 = rf(c, identity)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ExternalIdentity) string); ok {
+		r1 = rf(c, identity)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.ExternalIdentity) error); ok {
+		r2 = rf(c, identity)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // HandleLogin provides a mock function with given fields: c, username
 func (_m *UserUsecase) HandleLogin(c context.Context, username *domain.User) (string, string, error) {
 	ret := _m.Called(c, username)
@@ -59,6 +224,65 @@ func (_m *UserUsecase) HandleLogin(c context.Context, username *domain.User) (st
 	return r0, r1, r2
 }
 
+// RevokeSession provides a mock function with given fields: c, userID, sessionID
+func (_m *UserUsecase) RevokeSession(c context.Context, userID string, sessionID string) error {
+	ret := _m.Called(c, userID, sessionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetActive provides a mock function with given fields: c, userID, active
+func (_m *UserUsecase) SetActive(c context.Context, userID string, active bool) error {
+	ret := _m.Called(c, userID, active)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(c, userID, active)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SwitchOrganization provides a mock function with given fields: c, userID, sessionID, orgID
+func (_m *UserUsecase) SwitchOrganization(c context.Context, userID string, sessionID string, orgID string) (string, string, error) {
+	ret := _m.Called(c, userID, sessionID, orgID)
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, string, error)); ok {
+		return rf(c, userID, sessionID, orgID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(c, userID, sessionID, orgID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) string); ok {
+		r1 = rf(c, userID, sessionID, orgID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string) error); ok {
+		r2 = rf(c, userID, sessionID, orgID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Update provides a mock function with given fields: c, userID
 func (_m *UserUsecase) Update(c context.Context, userID string) error {
 	ret := _m.Called(c, userID)
@@ -73,6 +297,32 @@ func (_m *UserUsecase) Update(c context.Context, userID string) error {
 	return r0
 }
 
+// UpdateProfile provides a mock function with given fields: c, userID, update
+func (_m *UserUsecase) UpdateProfile(c context.Context, userID string, update domain.ProfileUpdate) (*domain.User, error) {
+	ret := _m.Called(c, userID, update)
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProfileUpdate) (*domain.User, error)); ok {
+		return rf(c, userID, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProfileUpdate) *domain.User); ok {
+		r0 = rf(c, userID, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ProfileUpdate) error); ok {
+		r1 = rf(c, userID, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewUserUsecase interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,68 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ViewController is an autogenerated mock type for the ViewController type
+type ViewController struct {
+	mock.Mock
+}
+
+// ClearDefault provides a mock function with given fields: c
+func (_m *ViewController) ClearDefault(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Create provides a mock function with given fields: c
+func (_m *ViewController) Create(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Delete provides a mock function with given fields: c
+func (_m *ViewController) Delete(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *ViewController) FetchAll(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchByID provides a mock function with given fields: c
+func (_m *ViewController) FetchByID(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchTasks provides a mock function with given fields: c
+func (_m *ViewController) FetchTasks(c *gin.Context) {
+	_m.Called(c)
+}
+
+// SetDefault provides a mock function with given fields: c
+func (_m *ViewController) SetDefault(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Update provides a mock function with given fields: c
+func (_m *ViewController) Update(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewViewController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewViewController creates a new instance of ViewController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewViewController(t mockConstructorTestingTNewViewController) *ViewController {
+	mock := &ViewController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
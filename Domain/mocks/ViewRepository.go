@@ -0,0 +1,188 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ViewRepository is an autogenerated mock type for the ViewRepository type
+type ViewRepository struct {
+	mock.Mock
+}
+
+// ClearDefault provides a mock function with given fields: c, userID
+func (_m *ViewRepository) ClearDefault(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: c, view
+func (_m *ViewRepository) Create(c context.Context, view *domain.View) error {
+	ret := _m.Called(c, view)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.View) error); ok {
+		r0 = rf(c, view)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, viewID
+func (_m *ViewRepository) Delete(c context.Context, viewID string) error {
+	ret := _m.Called(c, viewID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, viewID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePrivateByOwner provides a mock function with given fields: c, userID
+func (_m *ViewRepository) DeletePrivateByOwner(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByID provides a mock function with given fields: c, viewID
+func (_m *ViewRepository) FetchByID(c context.Context, viewID string) (domain.View, error) {
+	ret := _m.Called(c, viewID)
+
+	var r0 domain.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.View, error)); ok {
+		return rf(c, viewID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.View); ok {
+		r0 = rf(c, viewID)
+	} else {
+		r0 = ret.Get(0).(domain.View)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, viewID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchDefault provides a mock function with given fields: c, userID
+func (_m *ViewRepository) FetchDefault(c context.Context, userID string) (string, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchVisible provides a mock function with given fields: c, userID
+func (_m *ViewRepository) FetchVisible(c context.Context, userID string) ([]domain.View, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 []domain.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.View, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.View); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.View)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetDefault provides a mock function with given fields: c, userID, viewID
+func (_m *ViewRepository) SetDefault(c context.Context, userID string, viewID string) error {
+	ret := _m.Called(c, userID, viewID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userID, viewID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: c, view
+func (_m *ViewRepository) Update(c context.Context, view domain.View) error {
+	ret := _m.Called(c, view)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.View) error); ok {
+		r0 = rf(c, view)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewViewRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewViewRepository creates a new instance of ViewRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewViewRepository(t mockConstructorTestingTNewViewRepository) *ViewRepository {
+	mock := &ViewRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
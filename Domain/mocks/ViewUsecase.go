@@ -0,0 +1,190 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ViewUsecase is an autogenerated mock type for the ViewUsecase type
+type ViewUsecase struct {
+	mock.Mock
+}
+
+// ClearDefault provides a mock function with given fields: c, userID
+func (_m *ViewUsecase) ClearDefault(c context.Context, userID string) error {
+	ret := _m.Called(c, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: c, view
+func (_m *ViewUsecase) Create(c context.Context, view *domain.View) error {
+	ret := _m.Called(c, view)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.View) error); ok {
+		r0 = rf(c, view)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, viewID, userID, moderator
+func (_m *ViewUsecase) Delete(c context.Context, viewID string, userID string, moderator bool) error {
+	ret := _m.Called(c, viewID, userID, moderator)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) error); ok {
+		r0 = rf(c, viewID, userID, moderator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c, userID
+func (_m *ViewUsecase) FetchAll(c context.Context, userID string) (*[]domain.View, error) {
+	ret := _m.Called(c, userID)
+
+	var r0 *[]domain.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*[]domain.View, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *[]domain.View); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.View)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, viewID, userID
+func (_m *ViewUsecase) FetchByID(c context.Context, viewID string, userID string) (*domain.View, error) {
+	ret := _m.Called(c, viewID, userID)
+
+	var r0 *domain.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.View, error)); ok {
+		return rf(c, viewID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.View); ok {
+		r0 = rf(c, viewID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.View)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, viewID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchTasks provides a mock function with given fields: c, viewID, userID
+func (_m *ViewUsecase) FetchTasks(c context.Context, viewID string, userID string) (*domain.ViewTasks, error) {
+	ret := _m.Called(c, viewID, userID)
+
+	var r0 *domain.ViewTasks
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.ViewTasks, error)); ok {
+		return rf(c, viewID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.ViewTasks); ok {
+		r0 = rf(c, viewID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ViewTasks)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, viewID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetDefault provides a mock function with given fields: c, viewID, userID
+func (_m *ViewUsecase) SetDefault(c context.Context, viewID string, userID string) error {
+	ret := _m.Called(c, viewID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, viewID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: c, viewID, userID, update
+func (_m *ViewUsecase) Update(c context.Context, viewID string, userID string, update domain.ViewUpdate) (*domain.View, error) {
+	ret := _m.Called(c, viewID, userID, update)
+
+	var r0 *domain.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ViewUpdate) (*domain.View, error)); ok {
+		return rf(c, viewID, userID, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ViewUpdate) *domain.View); ok {
+		r0 = rf(c, viewID, userID, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.View)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.ViewUpdate) error); ok {
+		r1 = rf(c, viewID, userID, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewViewUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewViewUsecase creates a new instance of ViewUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewViewUsecase(t mockConstructorTestingTNewViewUsecase) *ViewUsecase {
+	mock := &ViewUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
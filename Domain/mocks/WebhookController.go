@@ -0,0 +1,63 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookController is an autogenerated mock type for the WebhookController type
+type WebhookController struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c
+func (_m *WebhookController) Create(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Delete provides a mock function with given fields: c
+func (_m *WebhookController) Delete(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *WebhookController) FetchAll(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchByID provides a mock function with given fields: c
+func (_m *WebhookController) FetchByID(c *gin.Context) {
+	_m.Called(c)
+}
+
+// FetchDeliveries provides a mock function with given fields: c
+func (_m *WebhookController) FetchDeliveries(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Redeliver provides a mock function with given fields: c
+func (_m *WebhookController) Redeliver(c *gin.Context) {
+	_m.Called(c)
+}
+
+// Update provides a mock function with given fields: c
+func (_m *WebhookController) Update(c *gin.Context) {
+	_m.Called(c)
+}
+
+type mockConstructorTestingTNewWebhookController interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebhookController creates a new instance of WebhookController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookController(t mockConstructorTestingTNewWebhookController) *WebhookController {
+	mock := &WebhookController{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
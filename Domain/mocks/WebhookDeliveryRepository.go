@@ -0,0 +1,174 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhookDeliveryRepository is an autogenerated mock type for the WebhookDeliveryRepository type
+type WebhookDeliveryRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, delivery
+func (_m *WebhookDeliveryRepository) Create(c context.Context, delivery *domain.WebhookDelivery) error {
+	ret := _m.Called(c, delivery)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.WebhookDelivery) error); ok {
+		r0 = rf(c, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByWebhook provides a mock function with given fields: c, webhookID
+func (_m *WebhookDeliveryRepository) DeleteByWebhook(c context.Context, webhookID string) error {
+	ret := _m.Called(c, webhookID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, webhookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchByEvent provides a mock function with given fields: c, webhookID, eventID
+func (_m *WebhookDeliveryRepository) FetchByEvent(c context.Context, webhookID string, eventID string) (domain.WebhookDelivery, error) {
+	ret := _m.Called(c, webhookID, eventID)
+
+	var r0 domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.WebhookDelivery, error)); ok {
+		return rf(c, webhookID, eventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.WebhookDelivery); ok {
+		r0 = rf(c, webhookID, eventID)
+	} else {
+		r0 = ret.Get(0).(domain.WebhookDelivery)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, webhookID, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, deliveryID
+func (_m *WebhookDeliveryRepository) FetchByID(c context.Context, deliveryID string) (domain.WebhookDelivery, error) {
+	ret := _m.Called(c, deliveryID)
+
+	var r0 domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.WebhookDelivery, error)); ok {
+		return rf(c, deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.WebhookDelivery); ok {
+		r0 = rf(c, deliveryID)
+	} else {
+		r0 = ret.Get(0).(domain.WebhookDelivery)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByWebhook provides a mock function with given fields: c, webhookID, limit
+func (_m *WebhookDeliveryRepository) FetchByWebhook(c context.Context, webhookID string, limit int64) ([]domain.WebhookDelivery, error) {
+	ret := _m.Called(c, webhookID, limit)
+
+	var r0 []domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]domain.WebhookDelivery, error)); ok {
+		return rf(c, webhookID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.WebhookDelivery); ok {
+		r0 = rf(c, webhookID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(c, webhookID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchPending provides a mock function with given fields: c, cutoff
+func (_m *WebhookDeliveryRepository) FetchPending(c context.Context, cutoff time.Time) ([]domain.WebhookDelivery, error) {
+	ret := _m.Called(c, cutoff)
+
+	var r0 []domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.WebhookDelivery, error)); ok {
+		return rf(c, cutoff)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.WebhookDelivery); ok {
+		r0 = rf(c, cutoff)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(c, cutoff)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: c, delivery
+func (_m *WebhookDeliveryRepository) Update(c context.Context, delivery domain.WebhookDelivery) error {
+	ret := _m.Called(c, delivery)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WebhookDelivery) error); ok {
+		r0 = rf(c, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewWebhookDeliveryRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebhookDeliveryRepository creates a new instance of WebhookDeliveryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookDeliveryRepository(t mockConstructorTestingTNewWebhookDeliveryRepository) *WebhookDeliveryRepository {
+	mock := &WebhookDeliveryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
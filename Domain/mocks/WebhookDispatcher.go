@@ -0,0 +1,44 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookDispatcher is an autogenerated mock type for the WebhookDispatcher type
+type WebhookDispatcher struct {
+	mock.Mock
+}
+
+// Dispatch provides a mock function with given fields: c, event
+func (_m *WebhookDispatcher) Dispatch(c context.Context, event domain.WebhookEvent) error {
+	ret := _m.Called(c, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WebhookEvent) error); ok {
+		r0 = rf(c, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewWebhookDispatcher interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebhookDispatcher creates a new instance of WebhookDispatcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookDispatcher(t mockConstructorTestingTNewWebhookDispatcher) *WebhookDispatcher {
+	mock := &WebhookDispatcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,188 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhookRepository is an autogenerated mock type for the WebhookRepository type
+type WebhookRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, webhook
+func (_m *WebhookRepository) Create(c context.Context, webhook *domain.Webhook) error {
+	ret := _m.Called(c, webhook)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook) error); ok {
+		r0 = rf(c, webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, webhookID
+func (_m *WebhookRepository) Delete(c context.Context, webhookID string) error {
+	ret := _m.Called(c, webhookID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, webhookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *WebhookRepository) FetchAll(c context.Context) ([]domain.Webhook, error) {
+	ret := _m.Called(c)
+
+	var r0 []domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Webhook, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Webhook); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, webhookID
+func (_m *WebhookRepository) FetchByID(c context.Context, webhookID string) (domain.Webhook, error) {
+	ret := _m.Called(c, webhookID)
+
+	var r0 domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Webhook, error)); ok {
+		return rf(c, webhookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Webhook); ok {
+		r0 = rf(c, webhookID)
+	} else {
+		r0 = ret.Get(0).(domain.Webhook)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, webhookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchSubscribed provides a mock function with given fields: c, event
+func (_m *WebhookRepository) FetchSubscribed(c context.Context, event string) ([]domain.Webhook, error) {
+	ret := _m.Called(c, event)
+
+	var r0 []domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Webhook, error)); ok {
+		return rf(c, event)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Webhook); ok {
+		r0 = rf(c, event)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordFailure provides a mock function with given fields: c, webhookID, disableAfter, at
+func (_m *WebhookRepository) RecordFailure(c context.Context, webhookID string, disableAfter int, at time.Time) (domain.Webhook, error) {
+	ret := _m.Called(c, webhookID, disableAfter, at)
+
+	var r0 domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Time) (domain.Webhook, error)); ok {
+		return rf(c, webhookID, disableAfter, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Time) domain.Webhook); ok {
+		r0 = rf(c, webhookID, disableAfter, at)
+	} else {
+		r0 = ret.Get(0).(domain.Webhook)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, time.Time) error); ok {
+		r1 = rf(c, webhookID, disableAfter, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetFailures provides a mock function with given fields: c, webhookID
+func (_m *WebhookRepository) ResetFailures(c context.Context, webhookID string) error {
+	ret := _m.Called(c, webhookID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, webhookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: c, webhook
+func (_m *WebhookRepository) Update(c context.Context, webhook domain.Webhook) error {
+	ret := _m.Called(c, webhook)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Webhook) error); ok {
+		r0 = rf(c, webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewWebhookRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookRepository(t mockConstructorTestingTNewWebhookRepository) *WebhookRepository {
+	mock := &WebhookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
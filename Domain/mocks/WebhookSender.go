@@ -0,0 +1,68 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookSender is an autogenerated mock type for the WebhookSender type
+type WebhookSender struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: c, url
+func (_m *WebhookSender) Check(c context.Context, url string) error {
+	ret := _m.Called(c, url)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, url)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Send provides a mock function with given fields: c, url, headers, body
+func (_m *WebhookSender) Send(c context.Context, url string, headers map[string]string, body []byte) (domain.WebhookResponse, error) {
+	ret := _m.Called(c, url, headers, body)
+
+	var r0 domain.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, []byte) (domain.WebhookResponse, error)); ok {
+		return rf(c, url, headers, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, []byte) domain.WebhookResponse); ok {
+		r0 = rf(c, url, headers, body)
+	} else {
+		r0 = ret.Get(0).(domain.WebhookResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]string, []byte) error); ok {
+		r1 = rf(c, url, headers, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWebhookSender interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebhookSender creates a new instance of WebhookSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookSender(t mockConstructorTestingTNewWebhookSender) *WebhookSender {
+	mock := &WebhookSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,228 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task-manger-api_test/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhookUsecase is an autogenerated mock type for the WebhookUsecase type
+type WebhookUsecase struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, webhook
+func (_m *WebhookUsecase) Create(c context.Context, webhook *domain.Webhook) error {
+	ret := _m.Called(c, webhook)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook) error); ok {
+		r0 = rf(c, webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, webhookID
+func (_m *WebhookUsecase) Delete(c context.Context, webhookID string) error {
+	ret := _m.Called(c, webhookID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, webhookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Dispatch provides a mock function with given fields: c, event
+func (_m *WebhookUsecase) Dispatch(c context.Context, event domain.WebhookEvent) error {
+	ret := _m.Called(c, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WebhookEvent) error); ok {
+		r0 = rf(c, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAll provides a mock function with given fields: c
+func (_m *WebhookUsecase) FetchAll(c context.Context) (*[]domain.Webhook, error) {
+	ret := _m.Called(c)
+
+	var r0 *[]domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*[]domain.Webhook, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *[]domain.Webhook); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: c, webhookID
+func (_m *WebhookUsecase) FetchByID(c context.Context, webhookID string) (*domain.Webhook, error) {
+	ret := _m.Called(c, webhookID)
+
+	var r0 *domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Webhook, error)); ok {
+		return rf(c, webhookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Webhook); ok {
+		r0 = rf(c, webhookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, webhookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchDeliveries provides a mock function with given fields: c, webhookID, limit
+func (_m *WebhookUsecase) FetchDeliveries(c context.Context, webhookID string, limit int64) (*[]domain.WebhookDelivery, error) {
+	ret := _m.Called(c, webhookID, limit)
+
+	var r0 *[]domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*[]domain.WebhookDelivery, error)); ok {
+		return rf(c, webhookID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *[]domain.WebhookDelivery); ok {
+		r0 = rf(c, webhookID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(c, webhookID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Redeliver provides a mock function with given fields: c, webhookID, deliveryID
+func (_m *WebhookUsecase) Redeliver(c context.Context, webhookID string, deliveryID string) (*domain.WebhookDelivery, error) {
+	ret := _m.Called(c, webhookID, deliveryID)
+
+	var r0 *domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.WebhookDelivery, error)); ok {
+		return rf(c, webhookID, deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.WebhookDelivery); ok {
+		r0 = rf(c, webhookID, deliveryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, webhookID, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResumePending provides a mock function with given fields: c, startedAt
+func (_m *WebhookUsecase) ResumePending(c context.Context, startedAt time.Time) (int, error) {
+	ret := _m.Called(c, startedAt)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(c, startedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(c, startedAt)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(c, startedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: c, webhookID, update
+func (_m *WebhookUsecase) Update(c context.Context, webhookID string, update domain.WebhookUpdate) (*domain.Webhook, error) {
+	ret := _m.Called(c, webhookID, update)
+
+	var r0 *domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.WebhookUpdate) (*domain.Webhook, error)); ok {
+		return rf(c, webhookID, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.WebhookUpdate) *domain.Webhook); ok {
+		r0 = rf(c, webhookID, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.WebhookUpdate) error); ok {
+		r1 = rf(c, webhookID, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWebhookUsecase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebhookUsecase creates a new instance of WebhookUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookUsecase(t mockConstructorTestingTNewWebhookUsecase) *WebhookUsecase {
+	mock := &WebhookUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,17 +1,26 @@
 package infrastructure
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
-	"os"
 	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-var SECRET_KEY string = os.Getenv("SECRET_KEY")
+// sessionTouchInterval limits how often a session's last-seen time is
+// written back.
+const sessionTouchInterval = time.Minute
 
-func AuthMiddleware() gin.HandlerFunc{
+// AuthMiddleware verifies the access token and, for tokens tied to a session,
+// rejects sessions that were revoked or expired. Pass a
+// CachedSessionRepository to keep the check off the database.
+func AuthMiddleware(sessions domain.SessionRepository) gin.HandlerFunc{
 
     return func(c *gin.Context){
       authHeader := c.GetHeader("Authorization")
@@ -39,10 +48,133 @@ func AuthMiddleware() gin.HandlerFunc{
 	  c.Set("username", claims.Username)
 	  c.Set("user_id",claims.User_id)
 	  c.Set("user_type", claims.User_type)
+	  c.Set("org_id", claims.Org_id)
+	  if claims.Session_id != "" {
+	    if !checkSession(c, sessions, claims) {
+	      return
+	    }
+	    c.Set("session_id", claims.Session_id)
+	  }
+	  if claims.Act != nil {
+	    c.Set("impersonator_id", claims.Act.Sub)
+	    c.Header("X-Impersonated-By", claims.Act.Sub)
+	  }
 	  c.Next()
     }
   }
 
+func checkSession(c *gin.Context, sessions domain.SessionRepository, claims *UserClaim) bool {
+  session, err := sessions.FetchByID(c, claims.Session_id)
+  if err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+    c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    c.Abort()
+    return false
+  }
+  now := time.Now()
+  if err != nil || session.User_id != claims.User_id || !session.IsActive(now) {
+    c.JSON(http.StatusUnauthorized, gin.H{"error": domain.ErrSessionRevoked.Error()})
+    c.Abort()
+    return false
+  }
+
+  if now.Sub(session.Last_seen_at) >= sessionTouchInterval {
+    if err := sessions.Touch(c, claims.Session_id, now); err != nil {
+      log.Printf("updating last seen for session %s failed: %v", claims.Session_id, err)
+    }
+  }
+  return true
+}
+
+// AuditImpersonation records every request made with an impersonation token.
+// It must run after AuthMiddleware.
+func AuditImpersonation(audit domain.AuditRepository) gin.HandlerFunc{
+  return func(c *gin.Context){
+    c.Next()
+
+    actorID := c.GetString("impersonator_id")
+    if actorID == "" {
+      return
+    }
+    entry := &domain.AuditEntry{
+      Action: domain.AuditImpersonatedRequest,
+      Actor_id: actorID,
+      Subject_id: c.GetString("user_id"),
+      Org_id: c.GetString("org_id"),
+      Method: c.Request.Method,
+      Path: c.Request.URL.Path,
+      Status: c.Writer.Status(),
+    }
+    ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+    defer cancel()
+    if err := audit.Create(ctx, entry); err != nil {
+      log.Printf("recording impersonated request %s %s by %s failed: %v", entry.Method, entry.Path, actorID, err)
+    }
+  }
+}
+
+// BlockImpersonation refuses sensitive actions, such as changing a password
+// or minting new tokens, when the request comes from an impersonation token.
+func BlockImpersonation() gin.HandlerFunc{
+  return func(c *gin.Context){
+    if c.GetString("impersonator_id") != "" {
+      c.JSON(http.StatusForbidden, gin.H{"error": "this action is not allowed while impersonating"})
+      c.Abort()
+      return
+    }
+    c.Next()
+  }
+}
+
+// TenantMiddleware resolves the active organization from the X-Org-ID header,
+// falling back to the one in the token, checks the caller belongs to it and
+// scopes the request context to it.
+func TenantMiddleware(organizations domain.OrganizationRepository) gin.HandlerFunc{
+  return func(c *gin.Context){
+    orgID := c.GetHeader("X-Org-ID")
+    if orgID == "" {
+      orgID = c.GetString("org_id")
+    }
+    if orgID == "" {
+      c.JSON(http.StatusForbidden, gin.H{"error": domain.ErrNoTenant.Error()})
+      c.Abort()
+      return
+    }
+
+    membership, err := organizations.FetchMembership(c, orgID, c.GetString("user_id"))
+    if errors.Is(err, domain.ErrNotMember) {
+      c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+      c.Abort()
+      return
+    }
+    if err != nil {
+      c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+      c.Abort()
+      return
+    }
+
+    c.Set("org_id", membership.Org_id)
+    c.Set("org_role", membership.Role)
+    c.Request = c.Request.WithContext(domain.WithTenant(c.Request.Context(), membership.Org_id))
+    c.Next()
+  }
+}
+
+// OrgRole allows the request through only if the caller's role in the active
+// organization is one of roles. It must run after TenantMiddleware.
+func OrgRole(roles ...string) gin.HandlerFunc{
+  return func(c *gin.Context){
+    role := c.GetString("org_role")
+    for _, allowed := range roles {
+      if role == allowed {
+        c.Next()
+        return
+      }
+    }
+    c.JSON(http.StatusForbidden, gin.H{"error": "your role in this organization does not allow this"})
+    c.Abort()
+  }
+}
+
 func AuthRole(reqRole string) gin.HandlerFunc{
   return func(c *gin.Context){
     userType := c.GetString("user_type")
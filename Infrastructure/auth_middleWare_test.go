@@ -0,0 +1,245 @@
+package infrastructure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type tenantMiddlewareSuite struct {
+	suite.Suite
+	organizations *mocks.OrganizationRepository
+	router        *gin.Engine
+	seenTenant    string
+}
+
+func (suite *tenantMiddlewareSuite) SetupTest() {
+	suite.organizations = new(mocks.OrganizationRepository)
+	suite.seenTenant = ""
+
+	suite.router = gin.New()
+	suite.router.ContextWithFallback = true
+	suite.router.Use(func(c *gin.Context) {
+		c.Set("user_id", "u1")
+		c.Set("org_id", "token-org")
+	})
+	suite.router.Use(TenantMiddleware(suite.organizations))
+	suite.router.GET("/tasks", OrgRole(domain.OrgRoleOwner, domain.OrgRoleMember), func(c *gin.Context) {
+		// usecases derive their context from the gin context
+		ctx, cancel := context.WithTimeout(c, time.Second)
+		defer cancel()
+		suite.seenTenant, _ = domain.TenantFromContext(ctx)
+		c.Status(http.StatusOK)
+	})
+}
+
+func (suite *tenantMiddlewareSuite) request(header string) int {
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	if header != "" {
+		req.Header.Set("X-Org-ID", header)
+	}
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func (suite *tenantMiddlewareSuite) TestTokenOrganization() {
+	suite.organizations.On("FetchMembership", mock.Anything, "token-org", "u1").Return(domain.Membership{Org_id: "token-org", Role: domain.OrgRoleMember}, nil)
+
+	suite.Equal(http.StatusOK, suite.request(""))
+	suite.Equal("token-org", suite.seenTenant)
+}
+
+func (suite *tenantMiddlewareSuite) TestHeaderOverridesToken() {
+	suite.organizations.On("FetchMembership", mock.Anything, "header-org", "u1").Return(domain.Membership{Org_id: "header-org", Role: domain.OrgRoleOwner}, nil)
+
+	suite.Equal(http.StatusOK, suite.request("header-org"))
+	suite.Equal("header-org", suite.seenTenant)
+}
+
+func (suite *tenantMiddlewareSuite) TestNotAMember() {
+	suite.organizations.On("FetchMembership", mock.Anything, "other-org", "u1").Return(domain.Membership{}, domain.ErrNotMember)
+
+	suite.Equal(http.StatusForbidden, suite.request("other-org"))
+	suite.Empty(suite.seenTenant)
+}
+
+func (suite *tenantMiddlewareSuite) TestRoleNotAllowed() {
+	suite.organizations.On("FetchMembership", mock.Anything, "token-org", "u1").Return(domain.Membership{Org_id: "token-org", Role: domain.OrgRoleAdmin}, nil)
+
+	suite.Equal(http.StatusForbidden, suite.request(""))
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	suite.Run(t, new(tenantMiddlewareSuite))
+}
+
+type impersonationMiddlewareSuite struct {
+	suite.Suite
+	audit  *mocks.AuditRepository
+	router *gin.Engine
+}
+
+func (suite *impersonationMiddlewareSuite) SetupSuite() {
+	_, err := InitJWT(JWTConfig{Issuer: "test-issuer", Audience: "test-audience", Keys: KeyManagerConfig{Algorithm: AlgorithmEdDSA, VerifyFor: time.Hour}})
+	suite.Require().NoError(err)
+}
+
+func (suite *impersonationMiddlewareSuite) SetupTest() {
+	suite.audit = new(mocks.AuditRepository)
+
+	suite.router = gin.New()
+	suite.router.Use(AuthMiddleware(new(mocks.SessionRepository)), AuditImpersonation(suite.audit))
+	suite.router.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+	suite.router.POST("/me/password", BlockImpersonation(), func(c *gin.Context) { c.Status(http.StatusOK) })
+}
+
+func (suite *impersonationMiddlewareSuite) request(method string, path string, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (suite *impersonationMiddlewareSuite) impersonationToken() string {
+	token, err := GenerateImpersonationToken(Actor{Sub: "admin-id"}, "user-id", "user", "user@example.com", "USER", "org-id")
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *impersonationMiddlewareSuite) TestImpersonatedRequestIsAudited() {
+	suite.audit.On("Create", mock.Anything, mock.MatchedBy(func(entry *domain.AuditEntry) bool {
+		return entry.Action == domain.AuditImpersonatedRequest && entry.Actor_id == "admin-id" && entry.Subject_id == "user-id" &&
+			entry.Method == http.MethodGet && entry.Path == "/me" && entry.Status == http.StatusOK
+	})).Return(nil).Once()
+
+	recorder := suite.request(http.MethodGet, "/me", suite.impersonationToken())
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal("admin-id", recorder.Header().Get("X-Impersonated-By"))
+	suite.audit.AssertExpectations(suite.T())
+}
+
+func (suite *impersonationMiddlewareSuite) TestSensitiveActionBlocked() {
+	suite.audit.On("Create", mock.Anything, mock.MatchedBy(func(entry *domain.AuditEntry) bool {
+		return entry.Status == http.StatusForbidden
+	})).Return(nil).Once()
+
+	recorder := suite.request(http.MethodPost, "/me/password", suite.impersonationToken())
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	suite.audit.AssertExpectations(suite.T())
+}
+
+func (suite *impersonationMiddlewareSuite) TestRegularTokenNotAudited() {
+	token, _, err := GenerateJWTToken("user-id", "user", "user@example.com", "USER", "org-id", "")
+	suite.Require().NoError(err)
+
+	recorder := suite.request(http.MethodPost, "/me/password", token)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Empty(recorder.Header().Get("X-Impersonated-By"))
+	suite.audit.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func TestImpersonationMiddleware(t *testing.T) {
+	suite.Run(t, new(impersonationMiddlewareSuite))
+}
+
+type sessionMiddlewareSuite struct {
+	suite.Suite
+	repository *mocks.SessionRepository
+	sessions   *CachedSessionRepository
+	router     *gin.Engine
+	session    domain.Session
+}
+
+func (suite *sessionMiddlewareSuite) SetupSuite() {
+	_, err := InitJWT(JWTConfig{Issuer: "test-issuer", Audience: "test-audience", Keys: KeyManagerConfig{Algorithm: AlgorithmEdDSA, VerifyFor: time.Hour}})
+	suite.Require().NoError(err)
+}
+
+func (suite *sessionMiddlewareSuite) SetupTest() {
+	suite.repository = new(mocks.SessionRepository)
+	suite.sessions = NewCachedSessionRepository(suite.repository, NewSessionCache(time.Minute))
+	suite.session = domain.Session{
+		ID:           primitive.NewObjectID(),
+		User_id:      "user-id",
+		Last_seen_at: time.Now(),
+		Expires_at:   time.Now().Add(time.Hour),
+	}
+
+	suite.router = gin.New()
+	suite.router.Use(AuthMiddleware(suite.sessions))
+	suite.router.GET("/me", func(c *gin.Context) { c.String(http.StatusOK, c.GetString("session_id")) })
+}
+
+func (suite *sessionMiddlewareSuite) request(sessionID string) *httptest.ResponseRecorder {
+	token, _, err := GenerateJWTToken("user-id", "user", "user@example.com", "USER", "org-id", sessionID)
+	suite.Require().NoError(err)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (suite *sessionMiddlewareSuite) TestActiveSessionIsCached() {
+	suite.repository.On("FetchByID", mock.Anything, suite.session.ID.Hex()).Return(suite.session, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		recorder := suite.request(suite.session.ID.Hex())
+		suite.Equal(http.StatusOK, recorder.Code)
+		suite.Equal(suite.session.ID.Hex(), recorder.Body.String())
+	}
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *sessionMiddlewareSuite) TestRevokedThroughCacheAppliesAtOnce() {
+	suite.repository.On("FetchByID", mock.Anything, suite.session.ID.Hex()).Return(suite.session, nil).Once()
+	suite.Equal(http.StatusOK, suite.request(suite.session.ID.Hex()).Code)
+
+	revoked := suite.session
+	now := time.Now()
+	revoked.Revoked_at = &now
+	suite.repository.On("Revoke", mock.Anything, "user-id", suite.session.ID.Hex(), mock.Anything).Return(nil)
+	suite.repository.On("FetchByID", mock.Anything, suite.session.ID.Hex()).Return(revoked, nil).Once()
+	suite.Require().NoError(suite.sessions.Revoke(context.TODO(), "user-id", suite.session.ID.Hex(), now))
+
+	suite.Equal(http.StatusUnauthorized, suite.request(suite.session.ID.Hex()).Code)
+}
+
+func (suite *sessionMiddlewareSuite) TestUnknownSession() {
+	suite.repository.On("FetchByID", mock.Anything, "missing").Return(domain.Session{}, domain.ErrSessionNotFound)
+
+	suite.Equal(http.StatusUnauthorized, suite.request("missing").Code)
+}
+
+func (suite *sessionMiddlewareSuite) TestSessionOfAnotherUser() {
+	suite.session.User_id = "someone-else"
+	suite.repository.On("FetchByID", mock.Anything, suite.session.ID.Hex()).Return(suite.session, nil)
+
+	suite.Equal(http.StatusUnauthorized, suite.request(suite.session.ID.Hex()).Code)
+}
+
+func (suite *sessionMiddlewareSuite) TestStaleLastSeenIsTouched() {
+	suite.session.Last_seen_at = time.Now().Add(-time.Hour)
+	suite.repository.On("FetchByID", mock.Anything, suite.session.ID.Hex()).Return(suite.session, nil).Once()
+	suite.repository.On("Touch", mock.Anything, suite.session.ID.Hex(), mock.Anything).Return(nil).Once()
+
+	suite.Equal(http.StatusOK, suite.request(suite.session.ID.Hex()).Code)
+	suite.Equal(http.StatusOK, suite.request(suite.session.ID.Hex()).Code)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func TestSessionMiddleware(t *testing.T) {
+	suite.Run(t, new(sessionMiddlewareSuite))
+}
@@ -0,0 +1,179 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	domain "task-manger-api_test/Domain"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BlobStoreFromEnv picks the attachment store named by BLOB_STORE:
+//   - "local" (default) keeps files under BLOB_DIR, default ./data/blobs
+//   - "gridfs" keeps them in the application database
+//   - "s3" talks to any S3-compatible service, see S3ConfigFromEnv
+func BlobStoreFromEnv(db *mongo.Database) (domain.BlobStore, error) {
+	switch kind := os.Getenv("BLOB_STORE"); kind {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = filepath.Join("data", "blobs")
+		}
+		return NewLocalBlobStore(dir)
+	case "gridfs":
+		return NewGridFSBlobStore(db, "blobs"), nil
+	case "s3":
+		config, err := S3ConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewS3BlobStore(config), nil
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q", kind)
+	}
+}
+
+// LocalBlobStore keeps each blob as a file below a root directory.
+type LocalBlobStore struct {
+	root string
+}
+
+func NewLocalBlobStore(root string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalBlobStore{root: root}, nil
+}
+
+// Put writes to a temporary file first, so a failed or oversized upload never
+// leaves a partial blob behind.
+func (s *LocalBlobStore) Put(c context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, contextReader{ctx: c, r: r}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalBlobStore) Open(c context.Context, key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, domain.ErrBlobNotFound
+	}
+	return file, err
+}
+
+func (s *LocalBlobStore) Delete(c context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps a key to a file, refusing keys that would escape the root.
+func (s *LocalBlobStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || clean == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
+
+// contextReader stops a copy once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// rangeReader makes a store that can only stream from an offset seekable.
+// Each seek that moves the offset reopens the stream there.
+type rangeReader struct {
+	size   int64
+	offset int64
+	open   func(offset int64) (io.ReadCloser, error)
+	body   io.ReadCloser
+}
+
+func (rr *rangeReader) Read(p []byte) (int, error) {
+	if rr.offset >= rr.size {
+		return 0, io.EOF
+	}
+	if rr.body == nil {
+		body, err := rr.open(rr.offset)
+		if err != nil {
+			return 0, err
+		}
+		rr.body = body
+	}
+	n, err := rr.body.Read(p)
+	rr.offset += int64(n)
+	return n, err
+}
+
+func (rr *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	var next int64
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = rr.offset + offset
+	case io.SeekEnd:
+		next = rr.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if next < 0 {
+		return 0, errors.New("negative position")
+	}
+	if next != rr.offset && rr.body != nil {
+		rr.body.Close()
+		rr.body = nil
+	}
+	rr.offset = next
+	return next, nil
+}
+
+func (rr *rangeReader) Close() error {
+	if rr.body == nil {
+		return nil
+	}
+	err := rr.body.Close()
+	rr.body = nil
+	return err
+}
@@ -0,0 +1,185 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"runtime/debug"
+	"sync"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// eventShards is how many events an asynchronous subscriber handles at
+	// once, each shard owning a share of the aggregates.
+	eventShards = 8
+	// eventQueue bounds the events waiting in a shard. A full shard makes
+	// Publish wait rather than drop events.
+	eventQueue = 256
+)
+
+// EventBus is an in-process domain.EventBus. Events still queued for
+// asynchronous handlers are lost if the process stops.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]domain.EventHandler
+	async    map[string][]*asyncSubscriber
+	closed   bool
+	// done is closed by Close, releasing publishers waiting on a full
+	// shard.
+	done    chan struct{}
+	workers sync.WaitGroup
+	now     func() time.Time
+}
+
+type queuedEvent struct {
+	ctx   context.Context
+	event domain.Event
+}
+
+type asyncSubscriber struct {
+	handler domain.EventHandler
+	shards  []chan queuedEvent
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		handlers: make(map[string][]domain.EventHandler),
+		async:    make(map[string][]*asyncSubscriber),
+		done:     make(chan struct{}),
+		now:      time.Now,
+	}
+}
+
+func (b *EventBus) Subscribe(name string, handler domain.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// SubscribeAsync gives the handler its own shards, so a slow subscriber
+// holds back only itself. The handler must not publish an event that lands
+// in its own shard while that shard is full: it would wait for itself
+// until the bus closes.
+func (b *EventBus) SubscribeAsync(name string, handler domain.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subscriber := &asyncSubscriber{handler: handler, shards: make([]chan queuedEvent, eventShards)}
+	for i := range subscriber.shards {
+		shard := make(chan queuedEvent, eventQueue)
+		subscriber.shards[i] = shard
+		b.workers.Add(1)
+		go func() {
+			defer b.workers.Done()
+			for {
+				select {
+				case queued := <-shard:
+					handle(queued.ctx, queued.event.Payload.EventName(), subscriber.handler, queued.event)
+				case <-b.done:
+					// handle what was queued before the bus closed
+					for {
+						select {
+						case queued := <-shard:
+							handle(queued.ctx, queued.event.Payload.EventName(), subscriber.handler, queued.event)
+						default:
+							return
+						}
+					}
+				}
+			}
+		}()
+	}
+	b.async[name] = append(b.async[name], subscriber)
+}
+
+func (b *EventBus) Publish(c context.Context, payload domain.DomainEvent) {
+	// failures are logged as they happen
+	b.Deliver(c, domain.Event{ID: primitive.NewObjectID().Hex(), Occurred_at: b.now(), Payload: payload})
+}
+
+func (b *EventBus) Deliver(c context.Context, event domain.Event) error {
+	payload := event.Payload
+	name := payload.EventName()
+
+	// handlers run without the lock held, so they may publish in turn
+	b.mu.RLock()
+	var handlers []domain.EventHandler
+	handlers = append(handlers, b.handlers[name]...)
+	handlers = append(handlers, b.handlers[domain.AllEvents]...)
+	b.mu.RUnlock()
+	var errs []error
+	for _, handler := range handlers {
+		if err := handle(c, name, handler, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// the shards are picked under the lock but fed without it, so a full
+	// shard does not hold up subscribing, closing or other publishers
+	b.mu.RLock()
+	closed := b.closed
+	var targets []chan queuedEvent
+	shard := shardOf(payload.AggregateID())
+	for _, key := range []string{name, domain.AllEvents} {
+		for _, subscriber := range b.async[key] {
+			targets = append(targets, subscriber.shards[shard])
+		}
+	}
+	b.mu.RUnlock()
+	if closed {
+		log.Printf("dropped event %s %s published after the bus closed", name, event.ID)
+		return errors.Join(errs...)
+	}
+
+	// the request may end before the handlers run, but its values, such
+	// as the tenant, still apply
+	queued := queuedEvent{ctx: context.WithoutCancel(c), event: event}
+	for _, target := range targets {
+		select {
+		case target <- queued:
+		case <-b.done:
+			log.Printf("dropped event %s %s: the bus closed while its queue was full", name, event.ID)
+			return errors.Join(errs...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops accepting events, releases publishers waiting on a full
+// shard and waits for the queued events to be handled.
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.done)
+	}
+	b.mu.Unlock()
+	b.workers.Wait()
+}
+
+func shardOf(aggregateID string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(aggregateID))
+	return int(hash.Sum32() % eventShards)
+}
+
+// handle runs one handler, logging its error or panic and returning it
+// as an error rather than letting it reach the other handlers.
+func handle(c context.Context, name string, handler domain.EventHandler, event domain.Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("%s handler panicked on event %s: %v\n%s", name, event.ID, recovered, debug.Stack())
+			err = fmt.Errorf("%s handler panicked: %v", name, recovered)
+		}
+	}()
+	if err := handler(c, event); err != nil {
+		log.Printf("%s handler failed on event %s: %v", name, event.ID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"sync"
+	domain "task-manger-api_test/Domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus_SyncHandlersRunBeforePublishReturns(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+
+	var names []string
+	bus.Subscribe(domain.EventTaskCreated, func(c context.Context, event domain.Event) error {
+		names = append(names, "created")
+		assert.NotEmpty(t, event.ID)
+		assert.False(t, event.Occurred_at.IsZero())
+		return nil
+	})
+	bus.Subscribe(domain.AllEvents, func(c context.Context, event domain.Event) error {
+		names = append(names, event.Payload.EventName())
+		return nil
+	})
+
+	bus.Publish(context.Background(), domain.TaskCreated{Task: domain.Task{Title: "one"}})
+	bus.Publish(context.Background(), domain.UserPromoted{User_id: "u1"})
+	assert.Equal(t, []string{"created", domain.EventTaskCreated, domain.EventUserPromoted}, names)
+}
+
+func TestEventBus_IsolatesFailingHandlers(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+
+	calls := 0
+	bus.Subscribe(domain.EventUserDeleted, func(c context.Context, event domain.Event) error {
+		panic("boom")
+	})
+	bus.Subscribe(domain.EventUserDeleted, func(c context.Context, event domain.Event) error {
+		return errors.New("failed")
+	})
+	bus.Subscribe(domain.EventUserDeleted, func(c context.Context, event domain.Event) error {
+		calls++
+		return nil
+	})
+
+	assert.NotPanics(t, func() {
+		bus.Publish(context.Background(), domain.UserDeleted{User_id: "u1"})
+	})
+	assert.Equal(t, 1, calls)
+}
+
+func TestEventBus_AsyncKeepsOrderPerAggregate(t *testing.T) {
+	bus := NewEventBus()
+
+	var mu sync.Mutex
+	seen := map[string][]string{}
+	bus.SubscribeAsync(domain.AllEvents, func(c context.Context, event domain.Event) error {
+		payload := event.Payload.(domain.UserPromoted)
+		mu.Lock()
+		defer mu.Unlock()
+		seen[payload.AggregateID()] = append(seen[payload.AggregateID()], event.ID)
+		return nil
+	})
+	bus.SubscribeAsync(domain.EventUserPromoted, func(c context.Context, event domain.Event) error {
+		panic("boom")
+	})
+
+	ctx, cancel := context.WithCancel(domain.WithTenant(context.Background(), "org-1"))
+	var sent []string
+	bus.Subscribe(domain.EventUserPromoted, func(c context.Context, event domain.Event) error {
+		if event.Payload.AggregateID() == "u1" {
+			sent = append(sent, event.ID)
+		}
+		return nil
+	})
+	bus.SubscribeAsync(domain.EventUserPromoted, func(c context.Context, event domain.Event) error {
+		assert.NoError(t, c.Err(), "the publisher's cancellation does not reach async handlers")
+		org, ok := domain.TenantFromContext(c)
+		assert.True(t, ok)
+		assert.Equal(t, "org-1", org)
+		return nil
+	})
+	for i := 0; i < 100; i++ {
+		bus.Publish(ctx, domain.UserPromoted{User_id: "u1"})
+		bus.Publish(ctx, domain.UserPromoted{User_id: "u2"})
+	}
+	cancel()
+	bus.Close()
+
+	require.Len(t, seen["u1"], 100)
+	assert.Len(t, seen["u2"], 100)
+	assert.Equal(t, sent, seen["u1"])
+
+	// published after Close, so dropped rather than queued
+	bus.Publish(context.Background(), domain.UserPromoted{User_id: "u1"})
+	assert.Len(t, seen["u1"], 100)
+}
+
+func TestEventBus_FullShardHoldsUpOnlyItsPublisher(t *testing.T) {
+	bus := NewEventBus()
+
+	release := make(chan struct{})
+	bus.SubscribeAsync(domain.EventUserPromoted, func(c context.Context, event domain.Event) error {
+		<-release
+		return nil
+	})
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		// the first is taken by the handler, the rest fill the shard and one waits
+		for i := 0; i < eventQueue+2; i++ {
+			bus.Publish(context.Background(), domain.UserPromoted{User_id: "u1"})
+		}
+	}()
+
+	subscribed := make(chan struct{})
+	go func() {
+		defer close(subscribed)
+		bus.Subscribe(domain.EventUserDemoted, func(c context.Context, event domain.Event) error { return nil })
+		bus.Publish(context.Background(), domain.UserDemoted{User_id: "u1"})
+	}()
+	select {
+	case <-subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a full shard blocked subscribing and publishing elsewhere")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		bus.Close()
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not release the waiting publisher")
+	}
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return")
+	}
+}
+
+func TestEventBus_DeliverKeepsTheEventAndReportsFailures(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+
+	event := domain.Event{ID: "e1", Occurred_at: time.Unix(1700000000, 0), Payload: domain.UserPromoted{User_id: "u1"}}
+	var received []domain.Event
+	bus.Subscribe(domain.EventUserPromoted, func(c context.Context, event domain.Event) error {
+		received = append(received, event)
+		return nil
+	})
+	require.NoError(t, bus.Deliver(context.Background(), event))
+	assert.Equal(t, []domain.Event{event}, received)
+
+	bus.Subscribe(domain.EventUserPromoted, func(c context.Context, event domain.Event) error {
+		return errors.New("failed")
+	})
+	bus.Subscribe(domain.AllEvents, func(c context.Context, event domain.Event) error {
+		panic("boom")
+	})
+	err := bus.Deliver(context.Background(), event)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed")
+	assert.Contains(t, err.Error(), "panicked: boom")
+	assert.Len(t, received, 2, "a failing handler does not stop the others")
+}
@@ -0,0 +1,123 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"io"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFSBlobStore keeps blobs in a GridFS bucket, using the key as filename.
+// When a key is written twice Open serves the newest copy.
+type GridFSBlobStore struct {
+	database *mongo.Database
+	name     string
+}
+
+func NewGridFSBlobStore(db *mongo.Database, bucketName string) *GridFSBlobStore {
+	return &GridFSBlobStore{database: db, name: bucketName}
+}
+
+// bucket opens a bucket for one operation. Buckets carry their deadlines as
+// state, so they are not shared between requests.
+func (s *GridFSBlobStore) bucket(c context.Context) (*gridfs.Bucket, error) {
+	bucket, err := gridfs.NewBucket(s.database, options.GridFSBucket().SetName(s.name))
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := c.Deadline(); ok {
+		bucket.SetReadDeadline(deadline)
+		bucket.SetWriteDeadline(deadline)
+	}
+	return bucket, nil
+}
+
+func (s *GridFSBlobStore) Put(c context.Context, key string, r io.Reader) error {
+	bucket, err := s.bucket(c)
+	if err != nil {
+		return err
+	}
+	stream, err := bucket.OpenUploadStream(key)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(stream, contextReader{ctx: c, r: r}); err != nil {
+		stream.Abort()
+		return err
+	}
+	return stream.Close()
+}
+
+func (s *GridFSBlobStore) Open(c context.Context, key string) (io.ReadSeekCloser, error) {
+	file, err := s.find(c, key)
+	if err != nil {
+		return nil, err
+	}
+	return &rangeReader{
+		size: file.Length,
+		// downloads outlive the request timeout of the handler that opened
+		// them, so each stream gets its own deadline
+		open: func(offset int64) (io.ReadCloser, error) {
+			bucket, err := gridfs.NewBucket(s.database, options.GridFSBucket().SetName(s.name))
+			if err != nil {
+				return nil, err
+			}
+			bucket.SetReadDeadline(time.Now().Add(time.Hour))
+			stream, err := bucket.OpenDownloadStream(file.ID)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := stream.Skip(offset); err != nil {
+				stream.Close()
+				return nil, err
+			}
+			return stream, nil
+		},
+	}, nil
+}
+
+func (s *GridFSBlobStore) Delete(c context.Context, key string) error {
+	bucket, err := s.bucket(c)
+	if err != nil {
+		return err
+	}
+	cur, err := bucket.FindContext(c, bson.M{"filename": key})
+	if err != nil {
+		return err
+	}
+	var files []gridfs.File
+	if err := cur.All(c, &files); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := bucket.DeleteContext(c, file.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GridFSBlobStore) find(c context.Context, key string) (gridfs.File, error) {
+	bucket, err := s.bucket(c)
+	if err != nil {
+		return gridfs.File{}, err
+	}
+	cur, err := bucket.FindContext(c, bson.M{"filename": key}, options.GridFSFind().SetSort(bson.M{"uploadDate": -1}).SetLimit(1))
+	if err != nil {
+		return gridfs.File{}, err
+	}
+	var files []gridfs.File
+	if err := cur.All(c, &files); err != nil {
+		return gridfs.File{}, err
+	}
+	if len(files) == 0 {
+		return gridfs.File{}, domain.ErrBlobNotFound
+	}
+	return files[0], nil
+}
@@ -0,0 +1,380 @@
+package infrastructure
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	rsaKeyBits = 2048
+)
+
+// SigningKey is a private key identified by its kid. A key stops being used
+// for signing once it is rotated out, but keeps verifying until RetiresAt.
+type SigningKey struct {
+	Kid       string
+	Algorithm string
+	Private   crypto.Signer
+	CreatedAt time.Time
+	RetiresAt time.Time
+}
+
+func (k *SigningKey) Public() crypto.PublicKey {
+	return k.Private.Public()
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+type KeyManagerConfig struct {
+	Algorithm        string
+	RotationInterval time.Duration
+	// VerifyFor is how long a rotated-out key keeps verifying. It must be at
+	// least the lifetime of the longest-lived token signed with it.
+	VerifyFor time.Duration
+	// KeysDir, when set, persists keys as <kid>.pem so they survive restarts.
+	// Keys are only read at startup and each instance rotates on its own, so
+	// instances must not share a directory.
+	KeysDir string
+}
+
+type KeyManager struct {
+	mu     sync.RWMutex
+	config KeyManagerConfig
+	keys   []*SigningKey
+	now    func() time.Time
+}
+
+func NewKeyManager(config KeyManagerConfig) (*KeyManager, error) {
+	if config.Algorithm == "" {
+		config.Algorithm = AlgorithmRS256
+	}
+	if config.Algorithm != AlgorithmRS256 && config.Algorithm != AlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", config.Algorithm)
+	}
+
+	km := &KeyManager{config: config, now: time.Now}
+	if config.KeysDir != "" {
+		if err := km.load(); err != nil {
+			return nil, err
+		}
+	}
+	if km.Current() == nil {
+		if err := km.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return km, nil
+}
+
+// Current returns the key new tokens are signed with.
+func (km *KeyManager) Current() *SigningKey {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+
+	if len(km.keys) == 0 {
+		return nil
+	}
+	current := km.keys[len(km.keys)-1]
+	if !current.RetiresAt.IsZero() {
+		return nil
+	}
+	return current
+}
+
+// Lookup returns the key with the given kid if it may still verify tokens.
+func (km *KeyManager) Lookup(kid string) (*SigningKey, bool) {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+
+	now := km.now()
+	for _, key := range km.keys {
+		if key.Kid != kid {
+			continue
+		}
+		if !key.RetiresAt.IsZero() && now.After(key.RetiresAt) {
+			return nil, false
+		}
+		return key, true
+	}
+	return nil, false
+}
+
+// Rotate generates a new signing key and schedules the previous one for
+// retirement. Keys past their retirement are dropped.
+func (km *KeyManager) Rotate() error {
+	key, err := generateSigningKey(km.config.Algorithm, km.now())
+	if err != nil {
+		return err
+	}
+	if km.config.KeysDir != "" {
+		if err := writeSigningKey(km.config.KeysDir, key); err != nil {
+			return err
+		}
+	}
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	for _, old := range km.keys {
+		if old.RetiresAt.IsZero() {
+			old.RetiresAt = key.CreatedAt.Add(km.config.VerifyFor)
+		}
+	}
+	km.keys = append(km.keys, key)
+	km.prune()
+	return nil
+}
+
+// StartRotation rotates the signing key every RotationInterval until stop is
+// closed. It is a no-op when no interval is configured.
+func (km *KeyManager) StartRotation(stop <-chan struct{}) {
+	if km.config.RotationInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(km.config.RotationInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := km.Rotate(); err != nil {
+					log.Printf("jwt key rotation failed: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// JWKS returns the public half of every key that may still verify tokens.
+func (km *KeyManager) JWKS() JSONWebKeySet {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	now := km.now()
+	for _, key := range km.keys {
+		if !key.RetiresAt.IsZero() && now.After(key.RetiresAt) {
+			continue
+		}
+		set.Keys = append(set.Keys, newJSONWebKey(key))
+	}
+	return set
+}
+
+// prune must be called with mu held.
+func (km *KeyManager) prune() {
+	now := km.now()
+	kept := km.keys[:0]
+	for _, key := range km.keys {
+		if !key.RetiresAt.IsZero() && now.After(key.RetiresAt) {
+			if km.config.KeysDir != "" {
+				os.Remove(filepath.Join(km.config.KeysDir, key.Kid+".pem"))
+			}
+			continue
+		}
+		kept = append(kept, key)
+	}
+	km.keys = kept
+}
+
+// load reads persisted keys, treating all but the newest as rotated out at the
+// time their successor was created.
+func (km *KeyManager) load() error {
+	if err := os.MkdirAll(km.config.KeysDir, 0o700); err != nil {
+		return err
+	}
+	files, err := filepath.Glob(filepath.Join(km.config.KeysDir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	var keys []*SigningKey
+	for _, file := range files {
+		key, err := readSigningKey(file)
+		if err != nil {
+			return err
+		}
+		if key.Algorithm == km.config.Algorithm {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+
+	for i := 0; i < len(keys)-1; i++ {
+		keys[i].RetiresAt = keys[i+1].CreatedAt.Add(km.config.VerifyFor)
+	}
+	if len(keys) > 0 {
+		last := keys[len(keys)-1]
+		if km.config.RotationInterval > 0 && km.now().Sub(last.CreatedAt) > km.config.RotationInterval {
+			last.RetiresAt = km.now().Add(km.config.VerifyFor)
+		}
+	}
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	km.keys = keys
+	km.prune()
+	return nil
+}
+
+func generateSigningKey(algorithm string, now time.Time) (*SigningKey, error) {
+	var private crypto.Signer
+	var err error
+	switch algorithm {
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kidBytes := make([]byte, 8)
+	if _, err := rand.Read(kidBytes); err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		Kid:       hex.EncodeToString(kidBytes),
+		Algorithm: algorithm,
+		Private:   private,
+		CreatedAt: now,
+	}, nil
+}
+
+func writeSigningKey(dir string, key *SigningKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.Private)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, key.Kid+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return err
+	}
+	return os.Chtimes(path, key.CreatedAt, key.CreatedAt)
+}
+
+func readSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block found", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	key := &SigningKey{
+		Kid:       strings.TrimSuffix(filepath.Base(path), ".pem"),
+		CreatedAt: info.ModTime(),
+	}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Private = AlgorithmRS256, private
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Private = AlgorithmEdDSA, private
+	default:
+		return nil, fmt.Errorf("%s: unsupported key type %T", path, parsed)
+	}
+	return key, nil
+}
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+func newJSONWebKey(key *SigningKey) JSONWebKey {
+	jwk := JSONWebKey{Kid: key.Kid, Use: "sig", Alg: key.Algorithm}
+	switch public := key.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// PublicKey decodes the verification key described by a JWK.
+func (jwk JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
@@ -2,27 +2,152 @@ package infrastructure
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	AccessTokenTTL  = 24 * time.Hour
+	RefreshTokenTTL = 168 * time.Hour
+	// ImpersonationTokenTTL is kept short: support sessions should not
+	// outlive the ticket that started them.
+	ImpersonationTokenTTL = 15 * time.Minute
+)
 
 type UserClaim struct{
-	User_id			string			
+	User_id			string
 	Username		string
 	Email			string
 	User_type		string
-	jwt.StandardClaims
+	Org_id			string			`json:",omitempty"`
+	Session_id		string			`json:"sid,omitempty"`
+	// Act is set on impersonation tokens and names the admin really
+	// behind the request (RFC 8693 actor claim).
+	Act				*Actor			`json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type Actor struct {
+	Sub      string `json:"sub"`
+	Username string `json:"username,omitempty"`
+}
+
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	Keys     KeyManagerConfig
+}
+
+var (
+	jwtMu     sync.RWMutex
+	jwtConfig JWTConfig
+	jwtKeys   *KeyManager
+)
+
+// JWTConfigFromEnv reads JWT_ALGORITHM, JWT_ISSUER, JWT_AUDIENCE,
+// JWT_KEY_ROTATION and JWT_KEYS_DIR, falling back to defaults.
+func JWTConfigFromEnv() (JWTConfig, error) {
+	config := JWTConfig{
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+		Keys: KeyManagerConfig{
+			Algorithm:        os.Getenv("JWT_ALGORITHM"),
+			RotationInterval: 30 * 24 * time.Hour,
+			VerifyFor:        RefreshTokenTTL,
+			KeysDir:          os.Getenv("JWT_KEYS_DIR"),
+		},
+	}
+	if config.Issuer == "" {
+		config.Issuer = "task-manager-api"
+	}
+	if config.Audience == "" {
+		config.Audience = "task-manager-api"
+	}
+	if rotation := os.Getenv("JWT_KEY_ROTATION"); rotation != "" {
+		interval, err := time.ParseDuration(rotation)
+		if err != nil {
+			return JWTConfig{}, fmt.Errorf("invalid JWT_KEY_ROTATION: %w", err)
+		}
+		config.Keys.RotationInterval = interval
+	}
+	return config, nil
+}
+
+// InitJWT replaces the signing keys and claim expectations used by
+// GenerateJWTToken and ValidateToken.
+func InitJWT(config JWTConfig) (*KeyManager, error) {
+	keys, err := NewKeyManager(config.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtMu.Lock()
+	defer jwtMu.Unlock()
+	jwtConfig = config
+	jwtKeys = keys
+	return keys, nil
+}
+
+// currentJWT falls back to JWTConfigFromEnv when InitJWT was never called.
+// The keys are created under the write lock so concurrent first requests
+// agree on one signing key.
+func currentJWT() (JWTConfig, *KeyManager, error) {
+	jwtMu.RLock()
+	config, keys := jwtConfig, jwtKeys
+	jwtMu.RUnlock()
+	if keys != nil {
+		return config, keys, nil
+	}
+
+	jwtMu.Lock()
+	defer jwtMu.Unlock()
+	if jwtKeys != nil {
+		return jwtConfig, jwtKeys, nil
+	}
+	config, err := JWTConfigFromEnv()
+	if err != nil {
+		return JWTConfig{}, nil, err
+	}
+	keys, err = NewKeyManager(config.Keys)
+	if err != nil {
+		return JWTConfig{}, nil, err
+	}
+	jwtConfig = config
+	jwtKeys = keys
+	return config, keys, nil
 }
 
 func ValidateToken(signedToken string) (claims *UserClaim, err error){
+	config, keys, err := currentJWT()
+	if err != nil {
+		return nil, err
+	}
+
 	token, msg := jwt.ParseWithClaims(
-		signedToken, 
-		&UserClaim{}, 
+		signedToken,
+		&UserClaim{},
 		func(t *jwt.Token) (interface{}, error) {
-			return []byte(SECRET_KEY), nil
+			kid, _ := t.Header["kid"].(string)
+			key, ok := keys.Lookup(kid)
+			if !ok {
+				return nil, errors.New("unknown signing key")
+			}
+			if t.Method.Alg() != key.Algorithm {
+				return nil, errors.New("unexpected signing method")
+			}
+			return key.Public(), nil
 		},
+		jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}),
+		jwt.WithIssuer(config.Issuer),
+		jwt.WithAudience(config.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
 	)
 
 	if msg != nil || !token.Valid{
@@ -35,40 +160,98 @@ func ValidateToken(signedToken string) (claims *UserClaim, err error){
 		err = errors.New("the token is invalid")
 		return
 	}
-
-	if claims.ExpiresAt < time.Now().Local().Unix(){
-		err = errors.New("token is expired")
-		return
-	}
 	return claims, err
 }
 
-func GenerateJWTToken(user_id string, username string, email string, user_type string) (signedToken, signedRefreshToken string, err error){
+func GenerateJWTToken(user_id string, username string, email string, user_type string, org_id string, session_id string) (signedToken, signedRefreshToken string, err error){
+	config, keys, err := currentJWT()
+	if err != nil {
+		return "", "", err
+	}
+	key := keys.Current()
+	if key == nil {
+		return "", "", errors.New("no signing key available")
+	}
+
+	now := time.Now()
 	claims := &UserClaim{
 		User_id: user_id,
 		Username: username,
 		Email: email,
 		User_type: user_type,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Local().Add(time.Hour * time.Duration(24)).Unix(),
-		},
+		Org_id: org_id,
+		Session_id: session_id,
+		RegisteredClaims: registeredClaims(config, user_id, now, AccessTokenTTL),
 	}
 
 	refreshClaims := &UserClaim{
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Local().Add(time.Hour * time.Duration(168)).Unix(),
-		},
+		Session_id: session_id,
+		RegisteredClaims: registeredClaims(config, user_id, now, RefreshTokenTTL),
 	}
 
-	signedToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(SECRET_KEY))
+	signedToken, err = signClaims(key, claims)
     if err != nil {
         return "", "", err
     }
 
-    signedRefreshToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(SECRET_KEY))
+    signedRefreshToken, err = signClaims(key, refreshClaims)
     if err != nil {
         return "", "", err
     }
 
     return
 }
+
+// GenerateImpersonationToken signs a short-lived access token for user_id on
+// behalf of actor. No refresh token is issued.
+func GenerateImpersonationToken(actor Actor, user_id string, username string, email string, user_type string, org_id string) (string, error) {
+	config, keys, err := currentJWT()
+	if err != nil {
+		return "", err
+	}
+	key := keys.Current()
+	if key == nil {
+		return "", errors.New("no signing key available")
+	}
+
+	claims := &UserClaim{
+		User_id: user_id,
+		Username: username,
+		Email: email,
+		User_type: user_type,
+		Org_id: org_id,
+		Act: &actor,
+		RegisteredClaims: registeredClaims(config, user_id, time.Now(), ImpersonationTokenTTL),
+	}
+	return signClaims(key, claims)
+}
+
+func registeredClaims(config JWTConfig, subject string, now time.Time, ttl time.Duration) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Issuer:    config.Issuer,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{config.Audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+}
+
+func signClaims(key *SigningKey, claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.Kid
+	return token.SignedString(key.Private)
+}
+
+// JWKSHandler serves the public keys other services need to verify our tokens.
+func JWKSHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, keys, err := currentJWT()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, keys.JWKS())
+	}
+}
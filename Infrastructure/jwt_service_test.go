@@ -1,38 +1,225 @@
 package infrastructure
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	domain "task-manger-api_test/Domain"
+	"sync"
+	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type validateTokenTestSuite struct {
 	suite.Suite
+	keys *KeyManager
 	validRefreshToken string
 	validToken   string
 	expiredToken string
 }
 
-func (suite *validateTokenTestSuite) SetupSuite(){
+func (suite *validateTokenTestSuite) SetupTest(){
 	var err error
-	
+
+	suite.keys, err = InitJWT(JWTConfig{
+		Issuer: "test-issuer",
+		Audience: "test-audience",
+		Keys: KeyManagerConfig{Algorithm: AlgorithmRS256, VerifyFor: RefreshTokenTTL},
+	})
+	suite.Require().NoError(err)
+
 	user := domain.User{
 		User_id: primitive.NewObjectID().Hex(),
 		Username: ptr("new user"),
 		Email: ptr("email@example.com"),
 		User_type: "ADMIN",
 	}
-	
-	suite.validToken, suite.validRefreshToken, err = GenerateJWTToken(user.User_id, *user.Username, *user.Email, user.User_type)
+
+	suite.validToken, suite.validRefreshToken, err = GenerateJWTToken(user.User_id, *user.Username, *user.Email, user.User_type, primitive.NewObjectID().Hex(), "")
+	suite.Require().NoError(err)
+
+	expired := &UserClaim{
+		User_id: user.User_id,
+		RegisteredClaims: registeredClaims(jwtConfig, user.User_id, time.Now().Add(-48*time.Hour), time.Hour),
+	}
+	suite.expiredToken, err = signClaims(suite.keys.Current(), expired)
+	suite.Require().NoError(err)
 }
 
 func (suite *validateTokenTestSuite)  TestValidateToken_Valid() {
 	claims, err := ValidateToken(suite.validToken)
 	suite.NoError(err, "Expected no error with valid token")
 	suite.NotNil(claims, "Expected claims to be non-nil with valid token")
+	suite.Equal("test-issuer", claims.Issuer)
+	suite.Equal(claims.User_id, claims.Subject)
+}
+
+func (suite *validateTokenTestSuite) TestValidateToken_Expired() {
+	_, err := ValidateToken(suite.expiredToken)
+	suite.ErrorIs(err, jwt.ErrTokenExpired)
+}
+
+func (suite *validateTokenTestSuite) TestValidateToken_WrongAudience() {
+	claims := &UserClaim{RegisteredClaims: registeredClaims(JWTConfig{Issuer: "test-issuer", Audience: "other-service"}, "id", time.Now(), time.Hour)}
+	token, err := signClaims(suite.keys.Current(), claims)
+	suite.Require().NoError(err)
+
+	_, err = ValidateToken(token)
+	suite.ErrorIs(err, jwt.ErrTokenInvalidAudience)
+}
+
+func (suite *validateTokenTestSuite) TestValidateToken_NotYetValid() {
+	claims := &UserClaim{RegisteredClaims: registeredClaims(jwtConfig, "id", time.Now(), time.Hour)}
+	claims.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	token, err := signClaims(suite.keys.Current(), claims)
+	suite.Require().NoError(err)
+
+	_, err = ValidateToken(token)
+	suite.ErrorIs(err, jwt.ErrTokenNotValidYet)
+}
+
+func (suite *validateTokenTestSuite) TestValidateToken_RejectsHS256() {
+	claims := &UserClaim{RegisteredClaims: registeredClaims(jwtConfig, "id", time.Now(), time.Hour)}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = suite.keys.Current().Kid
+	signed, err := token.SignedString([]byte("secret"))
+	suite.Require().NoError(err)
+
+	_, err = ValidateToken(signed)
+	suite.Error(err)
+}
+
+func (suite *validateTokenTestSuite) TestValidateToken_AfterRotation() {
+	oldKid := suite.keys.Current().Kid
+	suite.Require().NoError(suite.keys.Rotate())
+	suite.NotEqual(oldKid, suite.keys.Current().Kid)
+
+	_, err := ValidateToken(suite.validToken)
+	suite.NoError(err, "tokens signed with a rotated-out key keep verifying")
+	_, err = ValidateToken(suite.validRefreshToken)
+	suite.NoError(err)
+
+	suite.keys.now = func() time.Time { return time.Now().Add(RefreshTokenTTL + time.Hour) }
+	_, ok := suite.keys.Lookup(oldKid)
+	suite.False(ok, "retired keys stop verifying")
+}
+
+func (suite *validateTokenTestSuite) TestJWKSHandler() {
+	suite.Require().NoError(suite.keys.Rotate())
+
+	router := gin.New()
+	router.GET("/.well-known/jwks.json", JWKSHandler())
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	var set JSONWebKeySet
+	suite.Require().NoError(json.NewDecoder(recorder.Body).Decode(&set))
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Len(set.Keys, 2)
+
+	for _, jwk := range set.Keys {
+		suite.Equal("RSA", jwk.Kty)
+		public, err := jwk.PublicKey()
+		suite.NoError(err)
+		key, ok := suite.keys.Lookup(jwk.Kid)
+		suite.True(ok)
+		suite.Equal(key.Public(), public)
+	}
+}
+
+func (suite *validateTokenTestSuite) TestImpersonationToken() {
+	token, err := GenerateImpersonationToken(Actor{Sub: "admin-id", Username: "admin"}, "user-id", "user", "user@example.com", "USER", "org-id")
+	suite.Require().NoError(err)
+
+	claims, err := ValidateToken(token)
+	suite.Require().NoError(err)
+	suite.Equal("user-id", claims.User_id)
+	suite.Require().NotNil(claims.Act)
+	suite.Equal("admin-id", claims.Act.Sub)
+	suite.WithinDuration(time.Now().Add(ImpersonationTokenTTL), claims.ExpiresAt.Time, time.Minute)
+
+	claims, err = ValidateToken(suite.validToken)
+	suite.Require().NoError(err)
+	suite.Nil(claims.Act, "regular tokens carry no actor")
+}
+
+func TestValidateToken(t *testing.T) {
+	suite.Run(t, new(validateTokenTestSuite))
+}
+
+func TestKeyManager_EdDSAPersisted(t *testing.T) {
+	dir := t.TempDir()
+	config := KeyManagerConfig{Algorithm: AlgorithmEdDSA, VerifyFor: time.Hour, KeysDir: dir}
+
+	keys, err := NewKeyManager(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := &UserClaim{RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}}
+	signed, err := signClaims(keys.Current(), claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewKeyManager(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Current().Kid != keys.Current().Kid {
+		t.Fatalf("expected persisted key %s, got %s", keys.Current().Kid, reloaded.Current().Kid)
+	}
+
+	jwk := reloaded.JWKS().Keys[0]
+	public, err := jwk.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return public, nil })
+	if err != nil {
+		t.Fatalf("token did not verify against the published key: %v", err)
+	}
 }
 
 func ptr(s string) *string {
 	return &s
-}
\ No newline at end of file
+}
+
+func TestCurrentJWT_ConcurrentFirstUseSharesOneKey(t *testing.T) {
+	jwtMu.Lock()
+	previousConfig, previousKeys := jwtConfig, jwtKeys
+	jwtConfig, jwtKeys = JWTConfig{}, nil
+	jwtMu.Unlock()
+	t.Cleanup(func() {
+		jwtMu.Lock()
+		jwtConfig, jwtKeys = previousConfig, previousKeys
+		jwtMu.Unlock()
+	})
+	t.Setenv("JWT_ALGORITHM", AlgorithmEdDSA)
+	t.Setenv("JWT_KEYS_DIR", "")
+
+	var wg sync.WaitGroup
+	kids := make([]string, 8)
+	for i := range kids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, keys, err := currentJWT()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			kids[i] = keys.Current().Kid
+		}(i)
+	}
+	wg.Wait()
+	for _, kid := range kids {
+		if kid != kids[0] {
+			t.Fatalf("concurrent first use created more than one key: %v", kids)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	domain "task-manger-api_test/Domain"
+)
+
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// SMTPConfigFromEnv reads SMTP_ADDR, SMTP_FROM, SMTP_USERNAME and
+// SMTP_PASSWORD. ok is false when no SMTP server is configured.
+func SMTPConfigFromEnv() (config SMTPConfig, ok bool) {
+	config = SMTPConfig{
+		Addr:     os.Getenv("SMTP_ADDR"),
+		From:     os.Getenv("SMTP_FROM"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+	}
+	return config, config.Addr != "" && config.From != ""
+}
+
+// MailerFromEnv sends through SMTP when configured, and otherwise logs
+// messages so invitation links are still reachable in development.
+func MailerFromEnv() domain.Mailer {
+	if config, ok := SMTPConfigFromEnv(); ok {
+		return NewSMTPMailer(config)
+	}
+	return LogMailer{}
+}
+
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config}
+}
+
+func (m *SMTPMailer) Send(c context.Context, to string, subject string, body string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("invalid mail header")
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		host, _, err := net.SplitHostPort(m.config.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, host)
+	}
+
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		m.config.From, to, subject, body)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.config.Addr, auth, m.config.From, []string{to}, []byte(message))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-c.Done():
+		return c.Err()
+	}
+}
+
+type LogMailer struct{}
+
+func (LogMailer) Send(c context.Context, to string, subject string, body string) error {
+	log.Printf("mail to %s: %s\n%s", to, subject, body)
+	return nil
+}
@@ -0,0 +1,55 @@
+package infrastructure
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	// ugcPolicy allows the formatting Markdown produces and strips scripts,
+	// event handlers and javascript: links.
+	ugcPolicy      = newUGCPolicy()
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_.-]{2,100})`)
+)
+
+func newUGCPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	// GFM task list items render as disabled checkboxes
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	return policy
+}
+
+// RenderMarkdown turns user-written Markdown into HTML that is safe to embed.
+func RenderMarkdown(source string) string {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return ugcPolicy.Sanitize(source)
+	}
+	return ugcPolicy.Sanitize(buf.String())
+}
+
+// ExtractMentions returns the distinct usernames @mentioned in text, in the
+// order they first appear.
+func ExtractMentions(text string) []string {
+	seen := map[string]bool{}
+	usernames := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		username := match[1]
+		// a trailing dot ends the sentence, not the username
+		for len(username) > 0 && username[len(username)-1] == '.' {
+			username = username[:len(username)-1]
+		}
+		if len(username) < 2 || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
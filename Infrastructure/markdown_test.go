@@ -0,0 +1,20 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown_Sanitizes(t *testing.T) {
+	html := RenderMarkdown("# Title\n\n[link](javascript:alert(1)) <img src=x onerror=alert(1)>\n\n- [x] done")
+	assert.Contains(t, html, "<h1")
+	assert.NotContains(t, html, "javascript:")
+	assert.NotContains(t, html, "onerror")
+	assert.Contains(t, html, "checkbox")
+}
+
+func TestExtractMentions(t *testing.T) {
+	mentions := ExtractMentions("@alice, thanks! cc @bob. and @alice again; mail me at carol@example.com")
+	assert.Equal(t, []string{"alice", "bob"}, mentions)
+}
@@ -0,0 +1,341 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	domain "task-manger-api_test/Domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	oidcLoginTTL = 10 * time.Minute
+	// maxPendingOIDCLogins bounds the logins waiting for their callback, so
+	// anonymous login requests cannot grow memory without limit.
+	maxPendingOIDCLogins = 10000
+)
+
+type OIDCConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	HTTPClient   *http.Client
+}
+
+// OIDCConfigFromEnv reads OIDC_PROVIDER_NAME, OIDC_ISSUER, OIDC_CLIENT_ID,
+// OIDC_CLIENT_SECRET and OIDC_REDIRECT_URL. ok is false when no issuer is set.
+func OIDCConfigFromEnv() (config OIDCConfig, ok bool) {
+	config = OIDCConfig{
+		Name:         os.Getenv("OIDC_PROVIDER_NAME"),
+		Issuer:       os.Getenv("OIDC_ISSUER"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+	}
+	if config.Name == "" {
+		config.Name = "oidc"
+	}
+	return config, config.Issuer != ""
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcPendingLogin struct {
+	verifier  string
+	nonce     string
+	expiresAt time.Time
+}
+
+type oidcIDTokenClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Nonce             string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// OIDCProvider implements the authorization code flow with PKCE. Provider
+// metadata and signing keys are fetched lazily on first use.
+type OIDCProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]JSONWebKey
+	pending   map[string]oidcPendingLogin
+	now       func() time.Time
+}
+
+func NewOIDCProvider(config OIDCConfig) domain.IdentityProvider {
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &OIDCProvider{
+		config:  config,
+		client:  client,
+		pending: map[string]oidcPendingLogin{},
+		now:     time.Now,
+	}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL starts a login and returns the provider URL to redirect to,
+// along with its state.
+func (p *OIDCProvider) AuthCodeURL(c context.Context) (string, string, error) {
+	discovery, err := p.discover(c)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	p.mu.Lock()
+	p.expirePending()
+	if len(p.pending) >= maxPendingOIDCLogins {
+		p.mu.Unlock()
+		return "", "", domain.ErrTooManyLogins
+	}
+	p.pending[state] = oidcPendingLogin{verifier: verifier, nonce: nonce, expiresAt: p.now().Add(oidcLoginTTL)}
+	p.mu.Unlock()
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {PKCEChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), state, nil
+}
+
+// Exchange completes a login started by AuthCodeURL and returns the verified
+// identity from the ID token.
+func (p *OIDCProvider) Exchange(c context.Context, state string, code string) (*domain.ExternalIdentity, error) {
+	p.mu.Lock()
+	login, ok := p.pending[state]
+	delete(p.pending, state)
+	p.mu.Unlock()
+	if !ok || p.now().After(login.expiresAt) {
+		return nil, errors.New("unknown or expired login state")
+	}
+
+	discovery, err := p.discover(c)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {login.verifier},
+	}
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(c, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tokenResponse struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.doJSON(req, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("token exchange failed: %w", err)
+	}
+	if tokenResponse.IDToken == "" {
+		return nil, errors.New("token response did not include an id_token")
+	}
+
+	claims, err := p.verifyIDToken(c, discovery, tokenResponse.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Nonce != login.nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+
+	return &domain.ExternalIdentity{
+		Provider:       p.config.Name,
+		Subject:        claims.Subject,
+		Email:          claims.Email,
+		Email_verified: claims.EmailVerified,
+		Name:           claims.Name,
+		Username:       claims.PreferredUsername,
+	}, nil
+}
+
+func (p *OIDCProvider) verifyIDToken(c context.Context, discovery *oidcDiscovery, idToken string) (*oidcIDTokenClaims, error) {
+	claims := &oidcIDTokenClaims{}
+	_, err := jwt.ParseWithClaims(
+		idToken,
+		claims,
+		func(t *jwt.Token) (interface{}, error) {
+			kid, _ := t.Header["kid"].(string)
+			jwk, err := p.signingKey(c, discovery, kid)
+			if err != nil {
+				return nil, err
+			}
+			return jwk.PublicKey()
+		},
+		jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id_token: missing subject")
+	}
+	return claims, nil
+}
+
+// signingKey looks kid up in the cached JWKS, refetching once on a miss so
+// provider key rotation is picked up.
+func (p *OIDCProvider) signingKey(c context.Context, discovery *oidcDiscovery, kid string) (JSONWebKey, error) {
+	p.mu.Lock()
+	jwk, ok := p.keys[kid]
+	p.mu.Unlock()
+	if ok {
+		return jwk, nil
+	}
+
+	req, err := http.NewRequestWithContext(c, http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return JSONWebKey{}, err
+	}
+	var set JSONWebKeySet
+	if err := p.doJSON(req, &set); err != nil {
+		return JSONWebKey{}, fmt.Errorf("fetching provider keys failed: %w", err)
+	}
+
+	keys := make(map[string]JSONWebKey, len(set.Keys))
+	for _, key := range set.Keys {
+		keys[key.Kid] = key
+	}
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	if jwk, ok = keys[kid]; !ok {
+		return JSONWebKey{}, fmt.Errorf("unknown provider signing key %q", kid)
+	}
+	return jwk, nil
+}
+
+func (p *OIDCProvider) discover(c context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	discovery := p.discovery
+	p.mu.Unlock()
+	if discovery != nil {
+		return discovery, nil
+	}
+
+	endpoint := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(c, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	discovery = &oidcDiscovery{}
+	if err := p.doJSON(req, discovery); err != nil {
+		return nil, fmt.Errorf("provider discovery failed: %w", err)
+	}
+	if discovery.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("provider issuer %q does not match %q", discovery.Issuer, p.config.Issuer)
+	}
+
+	p.mu.Lock()
+	p.discovery = discovery
+	p.mu.Unlock()
+	return discovery, nil
+}
+
+func (p *OIDCProvider) doJSON(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", req.URL.Path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// expirePending must be called with mu held.
+func (p *OIDCProvider) expirePending() {
+	now := p.now()
+	for state, login := range p.pending {
+		if now.After(login.expiresAt) {
+			delete(p.pending, state)
+		}
+	}
+}
+
+// PKCEChallenge derives the S256 code challenge for a PKCE verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
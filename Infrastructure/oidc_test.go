@@ -0,0 +1,202 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	domain "task-manger-api_test/Domain"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+// stubProvider is a local stand-in for an OpenID Connect provider.
+type stubProvider struct {
+	server   *httptest.Server
+	keys     *KeyManager
+	codes    map[string]stubAuthorization
+	subject  string
+	email    string
+	audience string
+}
+
+type stubAuthorization struct {
+	challenge string
+	nonce     string
+}
+
+func newStubProvider(keys *KeyManager) *stubProvider {
+	stub := &stubProvider{keys: keys, codes: map[string]stubAuthorization{}, subject: "provider-user-1", email: "jane@example.com"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                stub.server.URL,
+			AuthorizationEndpoint: stub.server.URL + "/authorize",
+			TokenEndpoint:         stub.server.URL + "/token",
+			JWKSURI:               stub.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(stub.keys.JWKS())
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		auth, ok := stub.codes[r.Form.Get("code")]
+		if !ok || PKCEChallenge(r.Form.Get("code_verifier")) != auth.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		audience := r.Form.Get("client_id")
+		if stub.audience != "" {
+			audience = stub.audience
+		}
+		now := time.Now()
+		idToken, _ := signClaims(stub.keys.Current(), &oidcIDTokenClaims{
+			Email:         stub.email,
+			EmailVerified: true,
+			Name:          "Jane Doe",
+			Nonce:         auth.nonce,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    stub.server.URL,
+				Subject:   stub.subject,
+				Audience:  jwt.ClaimStrings{audience},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			},
+		})
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idToken, "token_type": "Bearer"})
+	})
+	stub.server = httptest.NewServer(mux)
+	return stub
+}
+
+// authorize plays the user consenting at the provider and returns the state
+// and code the provider would redirect back with.
+func (stub *stubProvider) authorize(authURL string) (state string, code string) {
+	parsed, _ := url.Parse(authURL)
+	query := parsed.Query()
+	code = "code-" + query.Get("state")[:8]
+	stub.codes[code] = stubAuthorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	return query.Get("state"), code
+}
+
+type oidcProviderSuite struct {
+	suite.Suite
+	stub     *stubProvider
+	provider *OIDCProvider
+}
+
+func (suite *oidcProviderSuite) SetupTest() {
+	keys, err := NewKeyManager(KeyManagerConfig{Algorithm: AlgorithmRS256, VerifyFor: time.Hour})
+	suite.Require().NoError(err)
+	suite.stub = newStubProvider(keys)
+	suite.provider = NewOIDCProvider(OIDCConfig{
+		Name:        "stub",
+		Issuer:      suite.stub.server.URL,
+		ClientID:    "task-manager",
+		RedirectURL: "http://localhost:8080/auth/oidc/callback",
+	}).(*OIDCProvider)
+}
+
+func (suite *oidcProviderSuite) TearDownTest() {
+	suite.stub.server.Close()
+}
+
+func (suite *oidcProviderSuite) TestAuthCodeURL_UsesPKCE() {
+	authURL, _, err := suite.provider.AuthCodeURL(context.TODO())
+	suite.Require().NoError(err)
+
+	parsed, err := url.Parse(authURL)
+	suite.Require().NoError(err)
+	suite.Equal(suite.stub.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	suite.Equal("S256", parsed.Query().Get("code_challenge_method"))
+	suite.NotEmpty(parsed.Query().Get("code_challenge"))
+	suite.NotEmpty(parsed.Query().Get("nonce"))
+}
+
+func (suite *oidcProviderSuite) TestAuthCodeURL_BoundsPendingLogins() {
+	authURL, state, err := suite.provider.AuthCodeURL(context.TODO())
+	suite.Require().NoError(err)
+	parsed, err := url.Parse(authURL)
+	suite.Require().NoError(err)
+	suite.Equal(state, parsed.Query().Get("state"))
+
+	for i := len(suite.provider.pending); i < maxPendingOIDCLogins; i++ {
+		suite.provider.pending[fmt.Sprint(i)] = oidcPendingLogin{expiresAt: time.Now().Add(oidcLoginTTL)}
+	}
+	_, _, err = suite.provider.AuthCodeURL(context.TODO())
+	suite.ErrorIs(err, domain.ErrTooManyLogins)
+
+	suite.provider.now = func() time.Time { return time.Now().Add(oidcLoginTTL + time.Minute) }
+	_, _, err = suite.provider.AuthCodeURL(context.TODO())
+	suite.NoError(err, "expired logins make room")
+}
+
+func (suite *oidcProviderSuite) TestExchange_Positive() {
+	authURL, _, err := suite.provider.AuthCodeURL(context.TODO())
+	suite.Require().NoError(err)
+	state, code := suite.stub.authorize(authURL)
+
+	identity, err := suite.provider.Exchange(context.TODO(), state, code)
+	suite.Require().NoError(err)
+	suite.Equal("stub", identity.Provider)
+	suite.Equal("provider-user-1", identity.Subject)
+	suite.Equal("jane@example.com", identity.Email)
+	suite.True(identity.Email_verified)
+}
+
+func (suite *oidcProviderSuite) TestExchange_StateIsSingleUse() {
+	authURL, _, err := suite.provider.AuthCodeURL(context.TODO())
+	suite.Require().NoError(err)
+	state, code := suite.stub.authorize(authURL)
+
+	_, err = suite.provider.Exchange(context.TODO(), state, code)
+	suite.Require().NoError(err)
+	_, err = suite.provider.Exchange(context.TODO(), state, code)
+	suite.EqualError(err, "unknown or expired login state")
+}
+
+func (suite *oidcProviderSuite) TestExchange_ExpiredState() {
+	authURL, _, err := suite.provider.AuthCodeURL(context.TODO())
+	suite.Require().NoError(err)
+	state, code := suite.stub.authorize(authURL)
+
+	suite.provider.now = func() time.Time { return time.Now().Add(oidcLoginTTL + time.Minute) }
+	_, err = suite.provider.Exchange(context.TODO(), state, code)
+	suite.EqualError(err, "unknown or expired login state")
+}
+
+func (suite *oidcProviderSuite) TestExchange_ProviderKeyRotation() {
+	authURL, _, err := suite.provider.AuthCodeURL(context.TODO())
+	suite.Require().NoError(err)
+	state, code := suite.stub.authorize(authURL)
+	_, err = suite.provider.Exchange(context.TODO(), state, code)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.stub.keys.Rotate())
+
+	authURL, _, err = suite.provider.AuthCodeURL(context.TODO())
+	suite.Require().NoError(err)
+	state, code = suite.stub.authorize(authURL)
+	_, err = suite.provider.Exchange(context.TODO(), state, code)
+	suite.NoError(err, "an unknown kid triggers a JWKS refetch")
+}
+
+func (suite *oidcProviderSuite) TestExchange_WrongAudience() {
+	suite.stub.audience = "someone-else"
+	authURL, _, err := suite.provider.AuthCodeURL(context.TODO())
+	suite.Require().NoError(err)
+	state, code := suite.stub.authorize(authURL)
+
+	_, err = suite.provider.Exchange(context.TODO(), state, code)
+	suite.ErrorIs(err, jwt.ErrTokenInvalidAudience)
+}
+
+func TestOIDCProvider(t *testing.T) {
+	suite.Run(t, new(oidcProviderSuite))
+}
@@ -0,0 +1,78 @@
+package infrastructure
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+type PasswordPolicyConfig struct {
+	MinLength int
+	MaxLength int
+	// BreachedListPath points at a local file of known-breached passwords,
+	// one per line, either in plain text or as SHA-1 hex digests (the
+	// "HASH:count" format of downloaded breach corpora is accepted).
+	BreachedListPath string
+}
+
+type PasswordPolicy struct {
+	config   PasswordPolicyConfig
+	breached map[[sha1.Size]byte]struct{}
+}
+
+func NewPasswordPolicy(config PasswordPolicyConfig) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{config: config, breached: map[[sha1.Size]byte]struct{}{}}
+	if config.BreachedListPath == "" {
+		return policy, nil
+	}
+
+	file, err := os.Open(config.BreachedListPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening breached password list: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		policy.breached[breachedListEntry(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading breached password list: %w", err)
+	}
+	return policy, nil
+}
+
+// breachedListEntry treats a line as a SHA-1 digest when it looks like one
+// and hashes it as a plain-text password otherwise.
+func breachedListEntry(line string) [sha1.Size]byte {
+	digest, _, _ := strings.Cut(line, ":")
+	if len(digest) == 2*sha1.Size {
+		var sum [sha1.Size]byte
+		if _, err := hex.Decode(sum[:], []byte(digest)); err == nil {
+			return sum
+		}
+	}
+	return sha1.Sum([]byte(line))
+}
+
+func (pp *PasswordPolicy) Check(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < pp.config.MinLength {
+		return fmt.Errorf("password must be at least %d characters", pp.config.MinLength)
+	}
+	if pp.config.MaxLength > 0 && length > pp.config.MaxLength {
+		return fmt.Errorf("password must be at most %d characters", pp.config.MaxLength)
+	}
+	if _, found := pp.breached[sha1.Sum([]byte(password))]; found {
+		return fmt.Errorf("this password has appeared in a data breach; please choose another")
+	}
+	return nil
+}
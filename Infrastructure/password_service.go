@@ -1,28 +1,318 @@
 package infrastructure
 
 import (
-	"log"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var ErrPasswordTooLong = errors.New("password is longer than 72 bytes, which bcrypt cannot hash")
+
+// PasswordHasher hashes passwords into self-describing strings that carry
+// the algorithm and cost parameters used.
+type PasswordHasher interface {
+	Algorithm() string
+	Hash(password string) (string, error)
+	// Verify reports whether password matches encoded, and whether encoded
+	// was produced with weaker parameters than the hasher's own.
+	Verify(password string, encoded string) (match bool, outdated bool, err error)
+	Recognizes(encoded string) bool
+}
+
+type Argon2idHasher struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+func (h Argon2idHasher) Algorithm() string {
+	return AlgorithmArgon2id
+}
+
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h Argon2idHasher) Verify(password string, encoded string) (bool, bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, false, err
+	}
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return false, false, nil
+	}
+
+	outdated := params.Memory < h.Memory || params.Iterations < h.Iterations ||
+		params.Parallelism < h.Parallelism || uint32(len(key)) < h.KeyLength || uint32(len(salt)) < h.SaltLength
+	return true, outdated, nil
+}
+
+func (h Argon2idHasher) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func decodeArgon2id(encoded string) (params Argon2idHasher, salt []byte, key []byte, err error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, errors.New("malformed argon2id hash")
+	}
 
-func HashPassword(password string) string{
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	if err!=nil{
-		log.Panic(err)
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, errors.New("malformed argon2id hash")
 	}
-	return string(bytes)
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, errors.New("malformed argon2id hash")
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, errors.New("malformed argon2id hash")
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return params, nil, nil, errors.New("malformed argon2id hash")
+	}
+	return params, salt, key, nil
+}
+
+type BcryptHasher struct {
+	Cost int
 }
 
-func VerifyPassword(userPassword string, providedPassword string)(bool, string){
-	err := bcrypt.CompareHashAndPassword([]byte(providedPassword), []byte(userPassword))
-	check := true
-	msg := ""
+func (h BcryptHasher) Algorithm() string {
+	return AlgorithmBcrypt
+}
+
+// Hash refuses passwords over 72 bytes instead of silently truncating them.
+func (h BcryptHasher) Hash(password string) (string, error) {
+	if len(password) > 72 {
+		return "", ErrPasswordTooLong
+	}
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
 
-	if err!= nil {
-		msg = "password is incorrect"
-		check=false
+func (h BcryptHasher) Verify(password string, encoded string) (bool, bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, false, nil
 	}
-	return check, msg
-}
\ No newline at end of file
+	if err != nil {
+		return false, false, err
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return false, false, err
+	}
+	return true, cost < h.Cost, nil
+}
+
+func (h BcryptHasher) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// PasswordService hashes with the preferred hasher and verifies hashes made
+// by any known one, flagging those that should be rehashed.
+type PasswordService struct {
+	preferred PasswordHasher
+	hashers   []PasswordHasher
+}
+
+func NewPasswordService(preferred PasswordHasher, others ...PasswordHasher) *PasswordService {
+	return &PasswordService{preferred: preferred, hashers: append([]PasswordHasher{preferred}, others...)}
+}
+
+func (ps *PasswordService) Hash(password string) (string, error) {
+	return ps.preferred.Hash(password)
+}
+
+// Verify reports whether password matches encoded and whether encoded should
+// be replaced by a fresh hash from the preferred hasher.
+func (ps *PasswordService) Verify(password string, encoded string) (match bool, rehash bool, err error) {
+	for _, hasher := range ps.hashers {
+		if !hasher.Recognizes(encoded) {
+			continue
+		}
+		match, outdated, err := hasher.Verify(password, encoded)
+		if err != nil || !match {
+			return false, false, err
+		}
+		return true, outdated || hasher.Algorithm() != ps.preferred.Algorithm(), nil
+	}
+	return false, false, errors.New("unrecognized password hash format")
+}
+
+type PasswordConfig struct {
+	Algorithm string
+	Argon2id  Argon2idHasher
+	Bcrypt    BcryptHasher
+	Policy    PasswordPolicyConfig
+}
+
+func DefaultPasswordConfig() PasswordConfig {
+	return PasswordConfig{
+		Algorithm: AlgorithmArgon2id,
+		Argon2id:  Argon2idHasher{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+		Bcrypt:    BcryptHasher{Cost: 14},
+		Policy:    PasswordPolicyConfig{MinLength: 8, MaxLength: 128},
+	}
+}
+
+// PasswordConfigFromEnv reads PASSWORD_HASH_ALGORITHM, ARGON2_MEMORY_KIB,
+// ARGON2_ITERATIONS, ARGON2_PARALLELISM, BCRYPT_COST, PASSWORD_MIN_LENGTH,
+// PASSWORD_MAX_LENGTH and PASSWORD_BREACHED_LIST on top of the defaults.
+// Numbers outside the range their setting accepts are refused rather than
+// clamped, so a typo stops startup instead of weakening every hash.
+func PasswordConfigFromEnv() (PasswordConfig, error) {
+	config := DefaultPasswordConfig()
+	if algorithm := os.Getenv("PASSWORD_HASH_ALGORITHM"); algorithm != "" {
+		config.Algorithm = algorithm
+	}
+	config.Policy.BreachedListPath = os.Getenv("PASSWORD_BREACHED_LIST")
+
+	numbers := []struct {
+		env      string
+		min, max uint64
+		set      func(uint64)
+	}{
+		// argon2 needs at least 8 KiB per lane; 4 GiB is plenty
+		{"ARGON2_MEMORY_KIB", 8, 4 * 1024 * 1024, func(v uint64) { config.Argon2id.Memory = uint32(v) }},
+		{"ARGON2_ITERATIONS", 1, 100, func(v uint64) { config.Argon2id.Iterations = uint32(v) }},
+		{"ARGON2_PARALLELISM", 1, 255, func(v uint64) { config.Argon2id.Parallelism = uint8(v) }},
+		{"BCRYPT_COST", uint64(bcrypt.MinCost), uint64(bcrypt.MaxCost), func(v uint64) { config.Bcrypt.Cost = int(v) }},
+		{"PASSWORD_MIN_LENGTH", 1, 1024, func(v uint64) { config.Policy.MinLength = int(v) }},
+		{"PASSWORD_MAX_LENGTH", 1, 1024, func(v uint64) { config.Policy.MaxLength = int(v) }},
+	}
+	for _, number := range numbers {
+		raw := os.Getenv(number.env)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return PasswordConfig{}, fmt.Errorf("invalid %s: %w", number.env, err)
+		}
+		if value < number.min || value > number.max {
+			return PasswordConfig{}, fmt.Errorf("invalid %s: %d is outside %d to %d", number.env, value, number.min, number.max)
+		}
+		number.set(value)
+	}
+	if config.Argon2id.Memory < 8*uint32(config.Argon2id.Parallelism) {
+		return PasswordConfig{}, fmt.Errorf("invalid ARGON2_MEMORY_KIB: %d parallel lanes need at least %d KiB", config.Argon2id.Parallelism, 8*uint32(config.Argon2id.Parallelism))
+	}
+	if config.Policy.MaxLength < config.Policy.MinLength {
+		return PasswordConfig{}, fmt.Errorf("invalid PASSWORD_MAX_LENGTH: %d is below PASSWORD_MIN_LENGTH %d", config.Policy.MaxLength, config.Policy.MinLength)
+	}
+	return config, nil
+}
+
+var (
+	passwordMu      sync.RWMutex
+	passwordService *PasswordService
+	passwordPolicy  *PasswordPolicy
+)
+
+// InitPasswords replaces the hasher and policy used by HashPassword,
+// VerifyPassword and CheckPasswordPolicy.
+func InitPasswords(config PasswordConfig) error {
+	var service *PasswordService
+	switch config.Algorithm {
+	case AlgorithmArgon2id:
+		service = NewPasswordService(config.Argon2id, config.Bcrypt)
+	case AlgorithmBcrypt:
+		service = NewPasswordService(config.Bcrypt, config.Argon2id)
+	default:
+		return fmt.Errorf("unsupported password hash algorithm %q", config.Algorithm)
+	}
+	policy, err := NewPasswordPolicy(config.Policy)
+	if err != nil {
+		return err
+	}
+
+	passwordMu.Lock()
+	defer passwordMu.Unlock()
+	passwordService = service
+	passwordPolicy = policy
+	return nil
+}
+
+func currentPasswords() (*PasswordService, *PasswordPolicy, error) {
+	passwordMu.RLock()
+	service, policy := passwordService, passwordPolicy
+	passwordMu.RUnlock()
+	if service != nil {
+		return service, policy, nil
+	}
+
+	config, err := PasswordConfigFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := InitPasswords(config); err != nil {
+		return nil, nil, err
+	}
+	return currentPasswords()
+}
+
+func HashPassword(password string) (string, error) {
+	service, _, err := currentPasswords()
+	if err != nil {
+		return "", err
+	}
+	return service.Hash(password)
+}
+
+// VerifyPassword checks userPassword against the stored hash. rehash is true
+// when the stored hash should be upgraded to the current algorithm or cost.
+func VerifyPassword(userPassword string, providedPassword string) (check bool, rehash bool, msg string) {
+	service, _, err := currentPasswords()
+	if err != nil {
+		return false, false, err.Error()
+	}
+
+	match, rehash, err := service.Verify(userPassword, providedPassword)
+	if err != nil || !match {
+		return false, false, "password is incorrect"
+	}
+	return true, rehash, ""
+}
+
+// CheckPasswordPolicy reports why password may not be used, if anything.
+func CheckPasswordPolicy(password string) error {
+	_, policy, err := currentPasswords()
+	if err != nil {
+		return err
+	}
+	return policy.Check(password)
+}
@@ -0,0 +1,151 @@
+package infrastructure
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type passwordServiceSuite struct {
+	suite.Suite
+	argon  Argon2idHasher
+	bcrypt BcryptHasher
+}
+
+func (suite *passwordServiceSuite) SetupTest() {
+	suite.argon = Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	suite.bcrypt = BcryptHasher{Cost: 4}
+}
+
+func (suite *passwordServiceSuite) TestArgon2id_EncodesParameters() {
+	hash, err := suite.argon.Hash("correct horse battery staple")
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	match, outdated, err := suite.argon.Verify("correct horse battery staple", hash)
+	suite.NoError(err)
+	suite.True(match)
+	suite.False(outdated)
+
+	match, _, err = suite.argon.Verify("wrong", hash)
+	suite.NoError(err)
+	suite.False(match)
+}
+
+func (suite *passwordServiceSuite) TestArgon2id_OutdatedParameters() {
+	hash, err := suite.argon.Hash("correct horse battery staple")
+	suite.Require().NoError(err)
+
+	stronger := suite.argon
+	stronger.Iterations = 2
+	match, outdated, err := stronger.Verify("correct horse battery staple", hash)
+	suite.NoError(err)
+	suite.True(match)
+	suite.True(outdated)
+}
+
+func (suite *passwordServiceSuite) TestBcrypt_RejectsLongPasswords() {
+	_, err := suite.bcrypt.Hash(strings.Repeat("a", 73))
+	suite.ErrorIs(err, ErrPasswordTooLong)
+
+	service := NewPasswordService(suite.argon, suite.bcrypt)
+	hash, err := service.Hash(strings.Repeat("a", 100))
+	suite.NoError(err, "argon2id has no length limit")
+	match, _, err := service.Verify(strings.Repeat("a", 100), hash)
+	suite.NoError(err)
+	suite.True(match)
+	match, _, _ = service.Verify(strings.Repeat("a", 99)+"b", hash)
+	suite.False(match, "the full password is significant")
+}
+
+func (suite *passwordServiceSuite) TestService_FlagsOtherAlgorithmsForRehash() {
+	legacy, err := suite.bcrypt.Hash("correct horse battery staple")
+	suite.Require().NoError(err)
+
+	service := NewPasswordService(suite.argon, suite.bcrypt)
+	match, rehash, err := service.Verify("correct horse battery staple", legacy)
+	suite.NoError(err)
+	suite.True(match)
+	suite.True(rehash)
+
+	_, _, err = service.Verify("anything", "plaintext")
+	suite.Error(err)
+}
+
+func (suite *passwordServiceSuite) TestService_BcryptCostUpgrade() {
+	legacy, err := suite.bcrypt.Hash("correct horse battery staple")
+	suite.Require().NoError(err)
+
+	service := NewPasswordService(BcryptHasher{Cost: 5})
+	match, rehash, err := service.Verify("correct horse battery staple", legacy)
+	suite.NoError(err)
+	suite.True(match)
+	suite.True(rehash)
+}
+
+func TestPasswordService(t *testing.T) {
+	suite.Run(t, new(passwordServiceSuite))
+}
+
+func TestPasswordConfigFromEnv_RejectsOutOfRangeNumbers(t *testing.T) {
+	for env, value := range map[string]string{
+		"ARGON2_ITERATIONS":   "0",
+		"ARGON2_PARALLELISM":  "256",
+		"ARGON2_MEMORY_KIB":   "4",
+		"BCRYPT_COST":         "40",
+		"PASSWORD_MAX_LENGTH": "4",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, value)
+			if _, err := PasswordConfigFromEnv(); err == nil || !strings.Contains(err.Error(), env) {
+				t.Errorf("PasswordConfigFromEnv() with %s=%s: error = %v, want one naming %s", env, value, err, env)
+			}
+		})
+	}
+
+	t.Setenv("ARGON2_PARALLELISM", "255")
+	t.Setenv("ARGON2_MEMORY_KIB", "4096")
+	config, err := PasswordConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Argon2id.Parallelism != 255 {
+		t.Errorf("Parallelism = %d, want 255", config.Argon2id.Parallelism)
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	sum := sha1.Sum([]byte("Summer2024!"))
+	list := "password123\n" + strings.ToUpper(hex.EncodeToString(sum[:])) + ":4211\n"
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(list), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := NewPasswordPolicy(PasswordPolicyConfig{MinLength: 8, MaxLength: 64, BreachedListPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		password string
+		wantErr  bool
+	}{
+		{"short", true},
+		{strings.Repeat("x", 65), true},
+		{"password123", true},
+		{"Summer2024!", true},
+		{"a perfectly fine passphrase", false},
+	}
+	for _, tt := range tests {
+		err := policy.Check(tt.password)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Check(%q) error = %v, wantErr %v", tt.password, err, tt.wantErr)
+		}
+	}
+}
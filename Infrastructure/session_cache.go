@@ -0,0 +1,124 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	domain "task-manger-api_test/Domain"
+	"time"
+)
+
+// SessionCache keeps recently checked sessions in memory so AuthMiddleware
+// does not query Mongo on every request. Revocations made through a
+// CachedSessionRepository sharing the cache apply at once; revocations made
+// elsewhere apply once the entry expires.
+type SessionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]sessionCacheEntry
+	now     func() time.Time
+}
+
+type sessionCacheEntry struct {
+	session  domain.Session
+	cachedAt time.Time
+}
+
+func NewSessionCache(ttl time.Duration) *SessionCache {
+	return &SessionCache{
+		ttl:     ttl,
+		entries: make(map[string]sessionCacheEntry),
+		now:     time.Now,
+	}
+}
+
+func (sc *SessionCache) get(sessionID string) (domain.Session, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	entry, ok := sc.entries[sessionID]
+	if !ok {
+		return domain.Session{}, false
+	}
+	if sc.now().Sub(entry.cachedAt) >= sc.ttl {
+		delete(sc.entries, sessionID)
+		return domain.Session{}, false
+	}
+	return entry.session, true
+}
+
+func (sc *SessionCache) put(session domain.Session) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	now := sc.now()
+	for id, entry := range sc.entries {
+		if now.Sub(entry.cachedAt) >= sc.ttl {
+			delete(sc.entries, id)
+		}
+	}
+	sc.entries[session.ID.Hex()] = sessionCacheEntry{session: session, cachedAt: now}
+}
+
+func (sc *SessionCache) touch(sessionID string, lastSeenAt time.Time) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if entry, ok := sc.entries[sessionID]; ok {
+		entry.session.Last_seen_at = lastSeenAt
+		sc.entries[sessionID] = entry
+	}
+}
+
+func (sc *SessionCache) invalidate(match func(domain.Session) bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	for id, entry := range sc.entries {
+		if match(entry.session) {
+			delete(sc.entries, id)
+		}
+	}
+}
+
+type CachedSessionRepository struct {
+	domain.SessionRepository
+	cache *SessionCache
+}
+
+// NewCachedSessionRepository serves FetchByID from cache and keeps the cache
+// in step with writes made through it.
+func NewCachedSessionRepository(repository domain.SessionRepository, cache *SessionCache) *CachedSessionRepository {
+	return &CachedSessionRepository{SessionRepository: repository, cache: cache}
+}
+
+func (cr *CachedSessionRepository) FetchByID(c context.Context, sessionID string) (domain.Session, error) {
+	if session, ok := cr.cache.get(sessionID); ok {
+		return session, nil
+	}
+	session, err := cr.SessionRepository.FetchByID(c, sessionID)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	cr.cache.put(session)
+	return session, nil
+}
+
+func (cr *CachedSessionRepository) Touch(c context.Context, sessionID string, lastSeenAt time.Time) error {
+	if err := cr.SessionRepository.Touch(c, sessionID, lastSeenAt); err != nil {
+		return err
+	}
+	cr.cache.touch(sessionID, lastSeenAt)
+	return nil
+}
+
+func (cr *CachedSessionRepository) Revoke(c context.Context, userID string, sessionID string, revokedAt time.Time) error {
+	err := cr.SessionRepository.Revoke(c, userID, sessionID, revokedAt)
+	cr.cache.invalidate(func(session domain.Session) bool { return session.ID.Hex() == sessionID })
+	return err
+}
+
+func (cr *CachedSessionRepository) RevokeAll(c context.Context, userID string, revokedAt time.Time) error {
+	err := cr.SessionRepository.RevokeAll(c, userID, revokedAt)
+	cr.cache.invalidate(func(session domain.Session) bool { return session.User_id == userID })
+	return err
+}
@@ -0,0 +1,23 @@
+package infrastructure
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// NewOpaqueToken returns a random URL-safe token for links sent to users,
+// along with the hash to store in its place.
+func NewOpaqueToken() (token string, hash string, err error) {
+	token, err = randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashOpaqueToken(token), nil
+}
+
+// HashOpaqueToken hashes a token from NewOpaqueToken for lookup. The tokens
+// carry 256 bits of entropy, so a plain SHA-256 is enough.
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,171 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	domain "task-manger-api_test/Domain"
+	"time"
+)
+
+// maxWebhookResponse is how much of a response body is kept for the
+// delivery log.
+const maxWebhookResponse = 1 << 10
+
+// SignWebhook returns the X-Webhook-Signature value for body sent at
+// timestamp: "sha256=" and the hex HMAC-SHA256, keyed with secret, of the
+// Unix timestamp, a dot and the body. Signing the timestamp lets receivers
+// reject replayed deliveries.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks a signature from SignWebhook in constant time.
+func VerifyWebhook(secret string, timestamp time.Time, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhook(secret, timestamp, body)), []byte(signature))
+}
+
+type HTTPWebhookSender struct {
+	client *http.Client
+	// allowPrivate lets webhooks reach loopback, private and other
+	// internal addresses, for trying receivers out locally.
+	allowPrivate bool
+}
+
+// errBlockedAddress is returned for connections to addresses webhooks may
+// not reach.
+var errBlockedAddress = errors.New("the webhook target resolves to a private or reserved address")
+
+// reservedNetworks are the ranges blockedAddress adds to what net.IP
+// already classifies: "this network", shared address space (some cloud
+// metadata services live there), IETF protocol assignments, benchmarking,
+// the reserved 240/4 block and NAT64, which can reach any IPv4 address.
+var reservedNetworks = parseNetworks("0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "240.0.0.0/4", "64:ff9b::/96", "64:ff9b:1::/48")
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// blockedAddress reports whether ip is loopback, private, link-local (which
+// covers 169.254.169.254), multicast, unspecified or reserved.
+func blockedAddress(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewHTTPWebhookSender gives each POST timeout to answer. Redirects are not
+// followed and proxies are not used, so a delivery only ever reaches the
+// URL it was configured with. Unless allowPrivate is set, the address of
+// every connection is checked as it is dialed, after DNS resolution, so a
+// host that resolves to an internal address later on is still refused.
+func NewHTTPWebhookSender(timeout time.Duration, allowPrivate bool) *HTTPWebhookSender {
+	sender := &HTTPWebhookSender{allowPrivate: allowPrivate}
+	dialer := &net.Dialer{Timeout: timeout, Control: sender.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	sender.client = &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return sender
+}
+
+// WebhookSenderFromEnv reads WEBHOOK_ALLOW_PRIVATE_TARGETS, off by default.
+func WebhookSenderFromEnv(timeout time.Duration) *HTTPWebhookSender {
+	allowPrivate, _ := strconv.ParseBool(os.Getenv("WEBHOOK_ALLOW_PRIVATE_TARGETS"))
+	return NewHTTPWebhookSender(timeout, allowPrivate)
+}
+
+func (s *HTTPWebhookSender) control(network string, address string, _ syscall.RawConn) error {
+	if s.allowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || blockedAddress(ip) {
+		return fmt.Errorf("%w: %s", errBlockedAddress, host)
+	}
+	return nil
+}
+
+// Check resolves the URL's host and refuses it when any of its addresses
+// is one deliveries may not reach.
+func (s *HTTPWebhookSender) Check(c context.Context, rawURL string) error {
+	if s.allowPrivate {
+		return nil
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: the URL does not parse", domain.ErrInvalidWebhook)
+	}
+	host := target.Hostname()
+	addresses, err := net.DefaultResolver.LookupIPAddr(c, host)
+	if err != nil || len(addresses) == 0 {
+		return fmt.Errorf("%w: %s does not resolve", domain.ErrInvalidWebhook, host)
+	}
+	for _, address := range addresses {
+		if blockedAddress(address.IP) {
+			return fmt.Errorf("%w: %s resolves to a private or reserved address", domain.ErrInvalidWebhook, host)
+		}
+	}
+	return nil
+}
+
+func (s *HTTPWebhookSender) Send(c context.Context, url string, headers map[string]string, body []byte) (domain.WebhookResponse, error) {
+	req, err := http.NewRequestWithContext(c, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return domain.WebhookResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-manager-webhooks/1.0")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return domain.WebhookResponse{}, err
+	}
+	defer resp.Body.Close()
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponse))
+	// drain a little more so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return domain.WebhookResponse{Status_code: resp.StatusCode, Body: string(excerpt)}, nil
+}
@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWebhook(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	signature := SignWebhook("s3cret", at, []byte(`{"id":"1"}`))
+	assert.True(t, strings.HasPrefix(signature, "sha256="))
+	assert.Len(t, signature, len("sha256=")+64)
+	assert.True(t, VerifyWebhook("s3cret", at, []byte(`{"id":"1"}`), signature))
+	assert.False(t, VerifyWebhook("other", at, []byte(`{"id":"1"}`), signature))
+	assert.False(t, VerifyWebhook("s3cret", at.Add(time.Second), []byte(`{"id":"1"}`), signature))
+	assert.False(t, VerifyWebhook("s3cret", at, []byte(`{"id":"2"}`), signature))
+}
+
+func TestHTTPWebhookSender(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seconds, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" ||
+			!VerifyWebhook("s3cret", time.Unix(seconds, 0), body, r.Header.Get("X-Webhook-Signature")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(strings.Repeat("x", 2*maxWebhookResponse)))
+	}))
+	defer server.Close()
+
+	sender := NewHTTPWebhookSender(time.Second, true)
+	at := time.Now()
+	body := []byte(`{"type":"task.created"}`)
+	resp, err := sender.Send(context.Background(), server.URL, map[string]string{
+		"X-Webhook-Timestamp": strconv.FormatInt(at.Unix(), 10),
+		"X-Webhook-Signature": SignWebhook("s3cret", at, body),
+	}, body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.Status_code)
+	assert.Len(t, resp.Body, maxWebhookResponse)
+
+	resp, err = sender.Send(context.Background(), server.URL, nil, body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.Status_code)
+}
+
+func TestHTTPWebhookSender_DoesNotFollowRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the redirect was followed")
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer server.Close()
+
+	resp, err := NewHTTPWebhookSender(time.Second, true).Send(context.Background(), server.URL, nil, []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTemporaryRedirect, resp.Status_code)
+}
+
+func TestHTTPWebhookSender_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a loopback address was reached")
+	}))
+	defer server.Close()
+
+	sender := NewHTTPWebhookSender(time.Second, false)
+	_, err := sender.Send(context.Background(), server.URL, nil, []byte(`{}`))
+	assert.ErrorIs(t, err, errBlockedAddress)
+
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://10.1.2.3/hook",
+		"http://192.168.0.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://100.100.100.200/",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		assert.ErrorIs(t, sender.Check(context.Background(), target), domain.ErrInvalidWebhook, target)
+	}
+	assert.NoError(t, sender.Check(context.Background(), "https://93.184.216.34/hook"))
+	assert.NoError(t, NewHTTPWebhookSender(time.Second, true).Check(context.Background(), server.URL))
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type attachmentRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewAttachmentRepository(db *mongo.Database, collection string) domain.AttachmentRepository {
+	return &attachmentRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (ar *attachmentRepository) Create(c context.Context, attachment *domain.Attachment) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	if attachment.ID.IsZero() {
+		attachment.ID = primitive.NewObjectID()
+	}
+	attachment.Org_id = orgID
+	attachment.Created_at = time.Now()
+	_, err := ar.database.Collection(ar.collection).InsertOne(c, attachment)
+	return err
+}
+
+func (ar *attachmentRepository) FetchByID(c context.Context, attachmentID string) (domain.Attachment, error) {
+	filter, err := ar.byID(c, attachmentID)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	var attachment domain.Attachment
+	err = ar.database.Collection(ar.collection).FindOne(c, filter).Decode(&attachment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Attachment{}, domain.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+func (ar *attachmentRepository) FetchByTask(c context.Context, taskID string) ([]domain.Attachment, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := ar.database.Collection(ar.collection).Find(c, bson.M{"org_id": orgID, "task_id": taskID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	attachments := []domain.Attachment{}
+	if err := cur.All(c, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (ar *attachmentRepository) Delete(c context.Context, attachmentID string) error {
+	filter, err := ar.byID(c, attachmentID)
+	if err != nil {
+		return err
+	}
+	result, err := ar.database.Collection(ar.collection).DeleteOne(c, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrAttachmentNotFound
+	}
+	return nil
+}
+
+func (ar *attachmentRepository) byID(c context.Context, attachmentID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(attachmentID)
+	if err != nil {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
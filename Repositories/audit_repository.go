@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auditRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewAuditRepository(db *mongo.Database, collection string) domain.AuditRepository {
+	return &auditRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (ar *auditRepository) Create(c context.Context, entry *domain.AuditEntry) error {
+	entry.ID = primitive.NewObjectID()
+	entry.Created_at = time.Now()
+	_, err := ar.database.Collection(ar.collection).InsertOne(c, entry)
+	return err
+}
+
+func (ar *auditRepository) FetchAll(c context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	query := bson.M{}
+	if filter.Actor_id != "" {
+		query["actor_id"] = filter.Actor_id
+	}
+	if filter.Subject_id != "" {
+		query["subject_id"] = filter.Subject_id
+	}
+	if filter.Involving != "" {
+		query["$or"] = bson.A{bson.M{"actor_id": filter.Involving}, bson.M{"subject_id": filter.Involving}}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	cur, err := ar.database.Collection(ar.collection).Find(c, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	entries := []domain.AuditEntry{}
+	if err := cur.All(c, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type calendarFeedRepository struct {
+	database   *mongo.Database
+	collection string
+	indexMu    sync.Mutex
+	indexed    bool
+}
+
+func NewCalendarFeedRepository(db *mongo.Database, collection string) domain.CalendarFeedRepository {
+	return &calendarFeedRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (cr *calendarFeedRepository) Replace(c context.Context, feed *domain.CalendarFeed) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	if err := cr.ensureIndex(c); err != nil {
+		return err
+	}
+	collection := cr.database.Collection(cr.collection)
+	if _, err := collection.DeleteMany(c, bson.M{"org_id": orgID, "user_id": feed.User_id}); err != nil {
+		return err
+	}
+	feed.ID = primitive.NewObjectID()
+	feed.Org_id = orgID
+	feed.Created_at = time.Now()
+	_, err := collection.InsertOne(c, feed)
+	return err
+}
+
+func (cr *calendarFeedRepository) FetchByUser(c context.Context, userID string) (domain.CalendarFeed, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.CalendarFeed{}, domain.ErrNoTenant
+	}
+	return cr.fetchOne(c, bson.M{"org_id": orgID, "user_id": userID})
+}
+
+func (cr *calendarFeedRepository) FetchByTokenHash(c context.Context, tokenHash string) (domain.CalendarFeed, error) {
+	if err := cr.ensureIndex(c); err != nil {
+		return domain.CalendarFeed{}, err
+	}
+	return cr.fetchOne(c, bson.M{"token_hash": tokenHash})
+}
+
+func (cr *calendarFeedRepository) Delete(c context.Context, userID string) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	result, err := cr.database.Collection(cr.collection).DeleteMany(c, bson.M{"org_id": orgID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+func (cr *calendarFeedRepository) DeleteAllByUser(c context.Context, userID string) error {
+	_, err := cr.database.Collection(cr.collection).DeleteMany(c, bson.M{"user_id": userID})
+	return err
+}
+
+func (cr *calendarFeedRepository) Touch(c context.Context, feedID string, at time.Time) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(feedID)
+	if err != nil {
+		return domain.ErrCalendarFeedNotFound
+	}
+	_, err = cr.database.Collection(cr.collection).UpdateOne(c, bson.M{"_id": objID, "org_id": orgID}, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
+
+func (cr *calendarFeedRepository) fetchOne(c context.Context, filter bson.M) (domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	err := cr.database.Collection(cr.collection).FindOne(c, filter).Decode(&feed)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+	return feed, nil
+}
+
+// ensureIndex makes token lookups cheap.
+func (cr *calendarFeedRepository) ensureIndex(c context.Context) error {
+	cr.indexMu.Lock()
+	defer cr.indexMu.Unlock()
+	if cr.indexed {
+		return nil
+	}
+	_, err := cr.database.Collection(cr.collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys:    bson.D{{Key: "token_hash", Value: 1}},
+		Options: options.Index().SetName("calendar_feed_token").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	cr.indexed = true
+	return nil
+}
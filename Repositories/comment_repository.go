@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type commentRepository struct {
+	database   *mongo.Database
+	collection string
+	indexMu    sync.Mutex
+	indexed    bool
+}
+
+func NewCommentRepository(db *mongo.Database, collection string) domain.CommentRepository {
+	return &commentRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (cr *commentRepository) Create(c context.Context, comment *domain.Comment) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	comment.ID = primitive.NewObjectID()
+	comment.Org_id = orgID
+	comment.Created_at = time.Now()
+	_, err := cr.database.Collection(cr.collection).InsertOne(c, comment)
+	return err
+}
+
+func (cr *commentRepository) FetchByID(c context.Context, commentID string) (domain.Comment, error) {
+	filter, err := cr.byID(c, commentID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	var comment domain.Comment
+	err = cr.database.Collection(cr.collection).FindOne(c, filter).Decode(&comment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Comment{}, domain.ErrCommentNotFound
+	}
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return comment, nil
+}
+
+// FetchThreads returns a page of top-level comments on a task, oldest first.
+func (cr *commentRepository) FetchThreads(c context.Context, taskID string, page int64, limit int64) ([]domain.Comment, int64, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, 0, domain.ErrNoTenant
+	}
+	filter := bson.M{"org_id": orgID, "task_id": taskID, "parent_id": bson.M{"$exists": false}}
+	collection := cr.database.Collection(cr.collection)
+
+	total, err := collection.CountDocuments(c, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip((page - 1) * limit).
+		SetLimit(limit)
+	comments, err := cr.find(c, filter, opts)
+	return comments, total, err
+}
+
+func (cr *commentRepository) FetchReplies(c context.Context, parentIDs []string) ([]domain.Comment, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	filter := bson.M{"org_id": orgID, "parent_id": bson.M{"$in": parentIDs}}
+	return cr.find(c, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+}
+
+func (cr *commentRepository) FetchByAuthor(c context.Context, userID string) ([]domain.Comment, error) {
+	return cr.find(c, bson.M{"author_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+}
+
+func (cr *commentRepository) find(c context.Context, filter bson.M, opts *options.FindOptions) ([]domain.Comment, error) {
+	cur, err := cr.database.Collection(cr.collection).Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	comments := []domain.Comment{}
+	if err := cur.All(c, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Search looks up comments through the text index and, for prefixes, by
+// regular expression, leaving deleted comments out.
+func (cr *commentRepository) Search(c context.Context, search domain.TaskSearch) ([]domain.Comment, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	comments := []domain.Comment{}
+
+	if search.Text != "" {
+		if err := cr.ensureTextIndex(c); err != nil {
+			return nil, err
+		}
+		filter := bson.M{"org_id": orgID, "deleted_at": bson.M{"$exists": false}, "$text": bson.M{"$search": search.Text}}
+		opts := options.Find().SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).SetLimit(search.Limit)
+		found, err := cr.find(c, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, found...)
+	}
+	if len(search.Prefixes) > 0 {
+		or := bson.A{}
+		for _, prefix := range search.Prefixes {
+			or = append(or, bson.M{"body": primitive.Regex{Pattern: `(^|\W)` + regexp.QuoteMeta(prefix), Options: "i"}})
+		}
+		filter := bson.M{"org_id": orgID, "deleted_at": bson.M{"$exists": false}, "$or": or}
+		found, err := cr.find(c, filter, options.Find().SetLimit(search.Limit))
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[primitive.ObjectID]bool, len(comments))
+		for _, comment := range comments {
+			seen[comment.ID] = true
+		}
+		for _, comment := range found {
+			if !seen[comment.ID] {
+				comments = append(comments, comment)
+			}
+		}
+	}
+	return comments, nil
+}
+
+func (cr *commentRepository) ensureTextIndex(c context.Context) error {
+	cr.indexMu.Lock()
+	defer cr.indexMu.Unlock()
+	if cr.indexed {
+		return nil
+	}
+	_, err := cr.database.Collection(cr.collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys:    bson.D{{Key: "body", Value: "text"}},
+		Options: options.Index().SetName("comment_text"),
+	})
+	if err != nil {
+		return err
+	}
+	cr.indexed = true
+	return nil
+}
+
+func (cr *commentRepository) Update(c context.Context, commentID string, body string, mentions []string, editedAt time.Time) error {
+	return cr.updateOne(c, commentID, bson.M{"$set": bson.M{"body": body, "mentions": mentions, "edited_at": editedAt}})
+}
+
+// Delete blanks the comment but keeps it, so replies stay in their thread.
+func (cr *commentRepository) Delete(c context.Context, commentID string, deletedAt time.Time) error {
+	return cr.updateOne(c, commentID, bson.M{"$set": bson.M{"body": "", "mentions": []string{}, "deleted_at": deletedAt}})
+}
+
+func (cr *commentRepository) updateOne(c context.Context, commentID string, update bson.M) error {
+	filter, err := cr.byID(c, commentID)
+	if err != nil {
+		return err
+	}
+	filter["deleted_at"] = bson.M{"$exists": false}
+
+	result, err := cr.database.Collection(cr.collection).UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrCommentNotFound
+	}
+	return nil
+}
+
+func (cr *commentRepository) byID(c context.Context, commentID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return nil, domain.ErrCommentNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
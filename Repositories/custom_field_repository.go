@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type customFieldRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewCustomFieldRepository(db *mongo.Database, collection string) domain.CustomFieldRepository {
+	return &customFieldRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (cr *customFieldRepository) Create(c context.Context, field *domain.CustomField) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	collection := cr.database.Collection(cr.collection)
+	err := collection.FindOne(c, bson.M{"org_id": orgID, "key": field.Key}).Err()
+	if err == nil {
+		return domain.ErrCustomFieldExists
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	field.ID = primitive.NewObjectID()
+	field.Org_id = orgID
+	field.Created_at = time.Now()
+	_, err = collection.InsertOne(c, field)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrCustomFieldExists
+	}
+	return err
+}
+
+func (cr *customFieldRepository) FetchAll(c context.Context) ([]domain.CustomField, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := cr.database.Collection(cr.collection).Find(c, bson.M{"org_id": orgID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	fields := []domain.CustomField{}
+	if err := cur.All(c, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (cr *customFieldRepository) FetchByID(c context.Context, fieldID string) (domain.CustomField, error) {
+	filter, err := cr.byID(c, fieldID)
+	if err != nil {
+		return domain.CustomField{}, err
+	}
+
+	var field domain.CustomField
+	err = cr.database.Collection(cr.collection).FindOne(c, filter).Decode(&field)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.CustomField{}, domain.ErrCustomFieldNotFound
+	}
+	if err != nil {
+		return domain.CustomField{}, err
+	}
+	return field, nil
+}
+
+// Update saves the mutable parts of field; its key and type never change.
+func (cr *customFieldRepository) Update(c context.Context, field domain.CustomField) error {
+	filter, err := cr.byID(c, field.ID.Hex())
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{
+		"name":     field.Name,
+		"required": field.Required,
+		"options":  field.Options,
+		"default":  field.Default,
+	}}
+	result, err := cr.database.Collection(cr.collection).UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrCustomFieldNotFound
+	}
+	return nil
+}
+
+func (cr *customFieldRepository) Delete(c context.Context, fieldID string) error {
+	filter, err := cr.byID(c, fieldID)
+	if err != nil {
+		return err
+	}
+	result, err := cr.database.Collection(cr.collection).DeleteOne(c, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrCustomFieldNotFound
+	}
+	return nil
+}
+
+func (cr *customFieldRepository) byID(c context.Context, fieldID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(fieldID)
+	if err != nil {
+		return nil, domain.ErrCustomFieldNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type importJobRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewImportJobRepository(db *mongo.Database, collection string) domain.ImportJobRepository {
+	return &importJobRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (ir *importJobRepository) Create(c context.Context, job *domain.ImportJob) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	job.ID = primitive.NewObjectID()
+	job.Org_id = orgID
+	job.Created_at = time.Now()
+	_, err := ir.database.Collection(ir.collection).InsertOne(c, job)
+	return err
+}
+
+func (ir *importJobRepository) FetchByID(c context.Context, jobID string) (domain.ImportJob, error) {
+	filter, err := ir.byID(c, jobID)
+	if err != nil {
+		return domain.ImportJob{}, err
+	}
+
+	var job domain.ImportJob
+	err = ir.database.Collection(ir.collection).FindOne(c, filter).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ImportJob{}, domain.ErrImportJobNotFound
+	}
+	if err != nil {
+		return domain.ImportJob{}, err
+	}
+	return job, nil
+}
+
+// Update records the job's progress and outcome.
+func (ir *importJobRepository) Update(c context.Context, job domain.ImportJob) error {
+	filter, err := ir.byID(c, job.ID.Hex())
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{
+		"status":      job.Status,
+		"total":       job.Total,
+		"processed":   job.Processed,
+		"created":     job.Created,
+		"updated":     job.Updated,
+		"failed":      job.Failed,
+		"errors":      job.Errors,
+		"message":     job.Message,
+		"finished_at": job.Finished_at,
+	}}
+	result, err := ir.database.Collection(ir.collection).UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrImportJobNotFound
+	}
+	return nil
+}
+
+// FailUnfinished is not tenant scoped: it serves the sweep that runs after
+// a restart.
+func (ir *importJobRepository) FailUnfinished(c context.Context, cutoff time.Time, message string, finishedAt time.Time) (int64, error) {
+	filter := bson.M{
+		"status":     bson.M{"$in": bson.A{domain.ImportQueued, domain.ImportRunning}},
+		"created_at": bson.M{"$lt": cutoff},
+	}
+	update := bson.M{"$set": bson.M{"status": domain.ImportFailed, "message": message, "finished_at": finishedAt}}
+	result, err := ir.database.Collection(ir.collection).UpdateMany(c, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (ir *importJobRepository) byID(c context.Context, jobID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(jobID)
+	if err != nil {
+		return nil, domain.ErrImportJobNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
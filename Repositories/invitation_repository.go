@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type invitationRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewInvitationRepository(db *mongo.Database, collection string) domain.InvitationRepository {
+	return &invitationRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (ir *invitationRepository) Create(c context.Context, invitation *domain.Invitation) error {
+	invitation.ID = primitive.NewObjectID()
+	invitation.Created_at = time.Now()
+	_, err := ir.database.Collection(ir.collection).InsertOne(c, invitation)
+	return err
+}
+
+func (ir *invitationRepository) FetchByID(c context.Context, invitationID string) (domain.Invitation, error) {
+	objID, err := primitive.ObjectIDFromHex(invitationID)
+	if err != nil {
+		return domain.Invitation{}, domain.ErrInvitationNotFound
+	}
+	return ir.findOne(c, bson.M{"_id": objID}, domain.ErrInvitationNotFound)
+}
+
+func (ir *invitationRepository) FetchByTokenHash(c context.Context, tokenHash string) (domain.Invitation, error) {
+	return ir.findOne(c, bson.M{"token_hash": tokenHash}, domain.ErrInvitationInvalid)
+}
+
+func (ir *invitationRepository) FetchPendingByEmail(c context.Context, email string) (domain.Invitation, error) {
+	return ir.findOne(c, pendingFilter(bson.M{"email": email}), domain.ErrInvitationNotFound)
+}
+
+func (ir *invitationRepository) findOne(c context.Context, filter bson.M, notFound error) (domain.Invitation, error) {
+	var invitation domain.Invitation
+	err := ir.database.Collection(ir.collection).FindOne(c, filter).Decode(&invitation)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Invitation{}, notFound
+	}
+	if err != nil {
+		return domain.Invitation{}, err
+	}
+	return invitation, nil
+}
+
+func (ir *invitationRepository) FetchAll(c context.Context) ([]domain.Invitation, error) {
+	cur, err := ir.database.Collection(ir.collection).Find(c, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	invitations := []domain.Invitation{}
+	if err := cur.All(c, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (ir *invitationRepository) Renew(c context.Context, invitationID string, tokenHash string, expiresAt time.Time) error {
+	return ir.updateOpen(c, invitationID, bson.M{"token_hash": tokenHash, "expires_at": expiresAt})
+}
+
+func (ir *invitationRepository) MarkAccepted(c context.Context, invitationID string, acceptedAt time.Time) error {
+	return ir.updateOpen(c, invitationID, bson.M{"accepted_at": acceptedAt})
+}
+
+func (ir *invitationRepository) Revoke(c context.Context, invitationID string, revokedAt time.Time) error {
+	return ir.updateOpen(c, invitationID, bson.M{"revoked_at": revokedAt})
+}
+
+// updateOpen only touches invitations that were neither accepted nor
+// revoked, so a token cannot be used twice.
+func (ir *invitationRepository) updateOpen(c context.Context, invitationID string, set bson.M) error {
+	objID, err := primitive.ObjectIDFromHex(invitationID)
+	if err != nil {
+		return domain.ErrInvitationNotFound
+	}
+	result, err := ir.database.Collection(ir.collection).UpdateOne(c,
+		bson.M{"_id": objID, "accepted_at": nil, "revoked_at": nil},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrInvitationInvalid
+	}
+	return nil
+}
+
+func pendingFilter(filter bson.M) bson.M {
+	filter["accepted_at"] = nil
+	filter["revoked_at"] = nil
+	filter["expires_at"] = bson.M{"$gt": time.Now()}
+	return filter
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type organizationRepository struct {
+	database             *mongo.Database
+	collection           string
+	membershipCollection string
+}
+
+func NewOrganizationRepository(db *mongo.Database, collection string, membershipCollection string) domain.OrganizationRepository {
+	return &organizationRepository{
+		database:             db,
+		collection:           collection,
+		membershipCollection: membershipCollection,
+	}
+}
+
+func (or *organizationRepository) Create(c context.Context, org *domain.Organization) error {
+	if validationErr := validate.Struct(org); validationErr != nil {
+		return validationErr
+	}
+
+	org.ID = primitive.NewObjectID()
+	org.Created_at = time.Now()
+	_, err := or.database.Collection(or.collection).InsertOne(c, org)
+	return err
+}
+
+func (or *organizationRepository) FetchByID(c context.Context, orgID string) (domain.Organization, error) {
+	var org domain.Organization
+	objID, err := primitive.ObjectIDFromHex(orgID)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+
+	err = or.database.Collection(or.collection).FindOne(c, bson.M{"_id": objID}).Decode(&org)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+	return org, nil
+}
+
+func (or *organizationRepository) FetchByIDs(c context.Context, orgIDs []string) ([]domain.Organization, error) {
+	objIDs := make([]primitive.ObjectID, 0, len(orgIDs))
+	for _, orgID := range orgIDs {
+		objID, err := primitive.ObjectIDFromHex(orgID)
+		if err != nil {
+			return nil, err
+		}
+		objIDs = append(objIDs, objID)
+	}
+
+	cur, err := or.database.Collection(or.collection).Find(c, bson.M{"_id": bson.M{"$in": objIDs}}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	orgs := []domain.Organization{}
+	if err := cur.All(c, &orgs); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (or *organizationRepository) AddMember(c context.Context, membership *domain.Membership) error {
+	memberships := or.database.Collection(or.membershipCollection)
+
+	count, err := memberships.CountDocuments(c, bson.M{"org_id": membership.Org_id, "user_id": membership.User_id})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("this user is already a member of the organization")
+	}
+
+	membership.Created_at = time.Now()
+	_, err = memberships.InsertOne(c, membership)
+	return err
+}
+
+func (or *organizationRepository) FetchMembership(c context.Context, orgID string, userID string) (domain.Membership, error) {
+	var membership domain.Membership
+	err := or.database.Collection(or.membershipCollection).FindOne(c, bson.M{"org_id": orgID, "user_id": userID}).Decode(&membership)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Membership{}, domain.ErrNotMember
+	}
+	if err != nil {
+		return domain.Membership{}, err
+	}
+	return membership, nil
+}
+
+func (or *organizationRepository) FetchMembers(c context.Context, orgID string) ([]domain.Membership, error) {
+	return or.findMemberships(c, bson.M{"org_id": orgID})
+}
+
+func (or *organizationRepository) FetchMemberships(c context.Context, userID string) ([]domain.Membership, error) {
+	return or.findMemberships(c, bson.M{"user_id": userID})
+}
+
+func (or *organizationRepository) findMemberships(c context.Context, filter bson.M) ([]domain.Membership, error) {
+	cur, err := or.database.Collection(or.membershipCollection).Find(c, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	memberships := []domain.Membership{}
+	if err := cur.All(c, &memberships); err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+func (or *organizationRepository) UpdateMemberRole(c context.Context, orgID string, userID string, role string) error {
+	result, err := or.database.Collection(or.membershipCollection).UpdateOne(c,
+		bson.M{"org_id": orgID, "user_id": userID},
+		bson.M{"$set": bson.M{"role": role}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotMember
+	}
+	return nil
+}
+
+func (or *organizationRepository) RemoveMember(c context.Context, orgID string, userID string) error {
+	result, err := or.database.Collection(or.membershipCollection).DeleteOne(c, bson.M{"org_id": orgID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotMember
+	}
+	return nil
+}
+
+func (or *organizationRepository) RemoveUser(c context.Context, userID string) error {
+	_, err := or.database.Collection(or.membershipCollection).DeleteMany(c, bson.M{"user_id": userID})
+	return err
+}
+
+func (or *organizationRepository) CountOwners(c context.Context, orgID string) (int64, error) {
+	return or.database.Collection(or.membershipCollection).CountDocuments(c, bson.M{"org_id": orgID, "role": domain.OrgRoleOwner})
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type outboxRepository struct {
+	database   *mongo.Database
+	collection string
+	indexMu    sync.Mutex
+	indexed    bool
+}
+
+func NewOutboxRepository(db *mongo.Database, collection string) domain.OutboxRepository {
+	return &outboxRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// Append leaves the indexes to the relay: they cannot be created inside the
+// transaction Append runs in.
+func (or *outboxRepository) Append(c context.Context, record *domain.OutboxRecord) error {
+	record.ID = primitive.NewObjectID()
+	_, err := or.database.Collection(or.collection).InsertOne(c, record)
+	return err
+}
+
+// outboxClaimCandidates is how many due records ClaimNext tries before
+// looking again, in case other relays claim them first.
+const outboxClaimCandidates = 10
+
+// ClaimNext takes records in the order they were appended, one aggregate at
+// a time: a record is only due once every earlier pending record of its
+// aggregate is done, so a record backing off holds back the ones after it.
+// A relay that stops before finishing one leaves it locked until the lease
+// runs out, when it is claimed again.
+func (or *outboxRepository) ClaimNext(c context.Context, now time.Time, lease time.Duration) (*domain.OutboxRecord, error) {
+	if err := or.ensureIndexes(c); err != nil {
+		return nil, err
+	}
+	collection := or.database.Collection(or.collection)
+	for {
+		candidates, err := or.heads(c, now)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) == 0 {
+			return nil, nil
+		}
+		for _, candidate := range candidates {
+			filter := bson.M{
+				"_id":             candidate.ID,
+				"status":          domain.OutboxPending,
+				"next_attempt_at": bson.M{"$lte": now},
+				"locked_until":    bson.M{"$lte": now},
+			}
+			update := bson.M{
+				"$set": bson.M{"locked_until": now.Add(lease)},
+				"$inc": bson.M{"attempts": 1},
+			}
+			var record domain.OutboxRecord
+			err := collection.FindOneAndUpdate(c, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&record)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				// another relay claimed it first
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &record, nil
+		}
+	}
+}
+
+// heads finds the oldest pending record of each aggregate, keeping those
+// that are due and not leased, oldest first.
+func (or *outboxRepository) heads(c context.Context, now time.Time) ([]domain.OutboxRecord, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": domain.OutboxPending}}},
+		{{Key: "$sort", Value: bson.D{{Key: "aggregate_id", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$aggregate_id", "head": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$head"}}},
+		{{Key: "$match", Value: bson.M{
+			"next_attempt_at": bson.M{"$lte": now},
+			"locked_until":    bson.M{"$lte": now},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: outboxClaimCandidates}},
+	}
+	cur, err := or.database.Collection(or.collection).Aggregate(c, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	records := []domain.OutboxRecord{}
+	if err := cur.All(c, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (or *outboxRepository) MarkDelivered(c context.Context, recordID string, at time.Time) error {
+	return or.set(c, recordID, bson.M{"status": domain.OutboxDelivered, "delivered_at": at})
+}
+
+func (or *outboxRepository) Reschedule(c context.Context, recordID string, lastError string, next time.Time) error {
+	return or.set(c, recordID, bson.M{"last_error": lastError, "next_attempt_at": next, "locked_until": time.Time{}})
+}
+
+func (or *outboxRepository) MarkFailed(c context.Context, recordID string, lastError string) error {
+	return or.set(c, recordID, bson.M{"status": domain.OutboxFailed, "last_error": lastError})
+}
+
+func (or *outboxRepository) PurgeDelivered(c context.Context, cutoff time.Time) (int64, error) {
+	if err := or.ensureIndexes(c); err != nil {
+		return 0, err
+	}
+	result, err := or.database.Collection(or.collection).DeleteMany(c, bson.M{
+		"status":       domain.OutboxDelivered,
+		"delivered_at": bson.M{"$lt": cutoff},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (or *outboxRepository) set(c context.Context, recordID string, fields bson.M) error {
+	objID, err := primitive.ObjectIDFromHex(recordID)
+	if err != nil {
+		return domain.ErrOutboxRecordNotFound
+	}
+	result, err := or.database.Collection(or.collection).UpdateOne(c, bson.M{"_id": objID}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrOutboxRecordNotFound
+	}
+	return nil
+}
+
+// ensureIndexes keeps event IDs unique, so an event cannot be appended
+// twice, and makes finding the head of each aggregate and purgeable records
+// cheap.
+func (or *outboxRepository) ensureIndexes(c context.Context) error {
+	or.indexMu.Lock()
+	defer or.indexMu.Unlock()
+	if or.indexed {
+		return nil
+	}
+	_, err := or.database.Collection(or.collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "event_id", Value: 1}},
+			Options: options.Index().SetName("outbox_event_id").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "aggregate_id", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("outbox_aggregate"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "delivered_at", Value: 1}},
+			Options: options.Index().SetName("outbox_delivered"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	or.indexed = true
+	return nil
+}
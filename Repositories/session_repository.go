@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type sessionRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewSessionRepository(db *mongo.Database, collection string) domain.SessionRepository {
+	return &sessionRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (sr *sessionRepository) Create(c context.Context, session *domain.Session) error {
+	session.ID = primitive.NewObjectID()
+	_, err := sr.database.Collection(sr.collection).InsertOne(c, session)
+	return err
+}
+
+func (sr *sessionRepository) FetchByID(c context.Context, sessionID string) (domain.Session, error) {
+	var session domain.Session
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return domain.Session{}, domain.ErrSessionNotFound
+	}
+
+	err = sr.database.Collection(sr.collection).FindOne(c, bson.M{"_id": objID}).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Session{}, domain.ErrSessionNotFound
+	}
+	if err != nil {
+		return domain.Session{}, err
+	}
+	return session, nil
+}
+
+func (sr *sessionRepository) FetchActiveByUser(c context.Context, userID string) ([]domain.Session, error) {
+	filter := bson.M{"user_id": userID, "revoked_at": nil, "expires_at": bson.M{"$gt": time.Now()}}
+	cur, err := sr.database.Collection(sr.collection).Find(c, filter, options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	sessions := []domain.Session{}
+	if err := cur.All(c, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (sr *sessionRepository) Touch(c context.Context, sessionID string, lastSeenAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return domain.ErrSessionNotFound
+	}
+	_, err = sr.database.Collection(sr.collection).UpdateOne(c, bson.M{"_id": objID}, bson.M{"$set": bson.M{"last_seen_at": lastSeenAt}})
+	return err
+}
+
+func (sr *sessionRepository) Revoke(c context.Context, userID string, sessionID string, revokedAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return domain.ErrSessionNotFound
+	}
+	result, err := sr.database.Collection(sr.collection).UpdateOne(c,
+		bson.M{"_id": objID, "user_id": userID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": revokedAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (sr *sessionRepository) RevokeAll(c context.Context, userID string, revokedAt time.Time) error {
+	_, err := sr.database.Collection(sr.collection).UpdateMany(c,
+		bson.M{"user_id": userID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": revokedAt}},
+	)
+	return err
+}
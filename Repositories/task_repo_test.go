@@ -5,8 +5,11 @@ import (
 	domain "task-manger-api_test/Domain"
 	"task-manger-api_test/config"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -15,6 +18,8 @@ type taskRepositorySuite struct{
 	suite.Suite
 	repository domain.TaskRepository
 	client *mongo.Client
+	// ctx is scoped to a tenant, as requests are by TenantMiddleware
+	ctx context.Context
 	// cleanupExecutor utils.TruncateTableExecutor
 }
 
@@ -26,6 +31,7 @@ func (suite *taskRepositorySuite) SetupSuite(){
 
 	suite.client = client
 	suite.repository = repository
+	suite.ctx = domain.WithTenant(context.TODO(), primitive.NewObjectID().Hex())
 }
 
 func (suite *taskRepositorySuite) TearDownTest() {
@@ -40,26 +46,26 @@ func (suite *taskRepositorySuite) TestCreateTask_Positive(){
 		Status: "Pending",
 	}
 
-	err := suite.repository.Create(context.TODO(), &task)
+	err := suite.repository.Create(suite.ctx, &task)
 	suite.NoError(err, "no error when create task with valid input")
 }
 
 func (suite *taskRepositorySuite) TestCreateTask_NilPointer_Negative(){
-	err := suite.repository.Create(context.TODO(), nil)
+	err := suite.repository.Create(suite.ctx, nil)
 	suite.Error(err, "create error with nil input returns error")
 
 }
 
 func (suite *taskRepositorySuite) TestCreateTask_EmptyFields_Positive(){
 	var task domain.Task
-	err := suite.repository.Create(context.TODO(), &task)
+	err := suite.repository.Create(suite.ctx, &task)
 	suite.NoError(err, "no error when create task with empty fields")
 }
 
 
 // FetchAll test
 func (suite *taskRepositorySuite) TestGetAllTasks_EmptySlice_Positive() {
-	tasks, err := suite.repository.FetchAll(context.TODO())
+	tasks, err := suite.repository.FetchAll(suite.ctx, domain.TaskFilter{})
 	suite.NoError(err, "no error when get all tasks when the table is empty")
 	suite.Equal(len(*tasks), 0, "length of tasks should be 0, since it is empty slice")
 	suite.Equal(tasks, &[]domain.Task{}, "tasks is an empty slice")
@@ -73,14 +79,14 @@ func (suite *taskRepositorySuite) TestGetAllTasks_FilledRecords_Positive() {
 	}
 
 	// inserting 3 tasks to be queried later
-	err := suite.repository.Create(context.TODO(), &task)
+	err := suite.repository.Create(suite.ctx, &task)
 	suite.NoError(err, "no error when create task with valid input")
-	err = suite.repository.Create(context.TODO(), &task)
+	err = suite.repository.Create(suite.ctx, &task)
 	suite.NoError(err, "no error when create task with valid input")
-	err = suite.repository.Create(context.TODO(), &task)
+	err = suite.repository.Create(suite.ctx, &task)
 	suite.NoError(err, "no error when create task with valid input")
 
-	tasks, err := suite.repository.FetchAll(context.TODO())
+	tasks, err := suite.repository.FetchAll(suite.ctx, domain.TaskFilter{})
 	suite.NoError(err, "no error when get all tasks when the table is empty")
 	suite.Equal(len(*tasks), 3, "insert 3 records before get all data, so it should contain three tasks")
 }
@@ -89,7 +95,7 @@ func (suite *taskRepositorySuite) TestGetAllTasks_FilledRecords_Positive() {
 func (suite *taskRepositorySuite) TestGetTaskByID_NotFound_Negative(){
 	id := primitive.NewObjectID().Hex()
 
-	_, err := suite.repository.FetchByTaskID(context.TODO(), id)
+	_, err := suite.repository.FetchByTaskID(suite.ctx, id)
 	suite.Error(err, "error db not found")
 	suite.Equal(err.Error(), "mongo: no documents in result")
 }
@@ -102,10 +108,10 @@ func (suite *taskRepositorySuite) TestGetTaskByID_Exists_Positive(){
 		Status: "Pending",
 	}
 
-	err := suite.repository.Create(context.TODO(), &task)
+	err := suite.repository.Create(suite.ctx, &task)
 	suite.NoError(err, "no error when create task with valid input")
 
-	result, err := suite.repository.FetchByTaskID(context.TODO(), task.ID.Hex())
+	result, err := suite.repository.FetchByTaskID(suite.ctx, task.ID.Hex())
 	suite.NoError(err, "no error because task is found")
 	suite.Equal(task.Title, (result).Title, "should be equal between result and task")
 	suite.Equal(task.Description, (result).Description, "should be equal between result and task")
@@ -123,7 +129,7 @@ func (suite *taskRepositorySuite) TestUpdate_Positive(){
 		Status: "Pending",
 	}
 
-	err = suite.repository.Create(context.TODO(), &task)
+	err = suite.repository.Create(suite.ctx, &task)
 	suite.NoError(err, "no error when create task with valid input")
 
 	updatedTask := domain.Task{
@@ -131,22 +137,24 @@ func (suite *taskRepositorySuite) TestUpdate_Positive(){
 		Title: "updated title",
 		Description: "updated description",
 		Status: "updated status",
+		DueDate: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
 	}
 
-	err = suite.repository.Update(context.TODO(), taskID.Hex(), updatedTask)
+	err = suite.repository.Update(suite.ctx, taskID.Hex(), updatedTask)
 	suite.NoError(err, "no error when updating task with valid input")
 
-	result, err := suite.repository.FetchByTaskID(context.TODO(), taskID.Hex())
+	result, err := suite.repository.FetchByTaskID(suite.ctx, taskID.Hex())
 	suite.NoError(err, "no error because task is found")
 	suite.Equal(updatedTask.Title, (result).Title, "should be equal between result and updatedTask")
 	suite.Equal(updatedTask.Description, (result).Description, "should be equal between result and updatedTask")
 	suite.Equal(updatedTask.Status, (result).Status, "should be equal between result and updatedTask")
+	suite.True(updatedTask.DueDate.Equal(result.DueDate), "the due date is updated")
 }
 
 func (suite *taskRepositorySuite) TestUpdate_InvalidID() {
 	invalidID := "invalidID"
 	updatedTask := domain.Task{}
-	err := suite.repository.Update(context.TODO(), invalidID, updatedTask)
+	err := suite.repository.Update(suite.ctx, invalidID, updatedTask)
 	suite.Error(err)
 	suite.Equal(err.Error(), "the provided hex string is not a valid ObjectID")
 }
@@ -158,7 +166,7 @@ func (suite *taskRepositorySuite) TestUpdate_TaskNotFound() {
 		Description: "Updated Description",
 		Status:      "Completed",
 	}
-	err := suite.repository.Update(context.TODO(), nonExistentID, updatedTask)
+	err := suite.repository.Update(suite.ctx, nonExistentID, updatedTask)
 	suite.EqualError(err, "TASK NOT FOUND")
 }
 
@@ -173,17 +181,171 @@ func (suite *taskRepositorySuite) TestDelete_Positive(){
 		Status: "Pending",
 	}
 
-	err = suite.repository.Create(context.TODO(), &task)
+	err = suite.repository.Create(suite.ctx, &task)
 	suite.NoError(err, "no error when create task with valid input")
 
-	err = suite.repository.Delete(context.TODO(), taskID.Hex())
+	err = suite.repository.Delete(suite.ctx, taskID.Hex())
 	suite.NoError(err)
 
-	_, err = suite.repository.FetchByTaskID(context.TODO(), taskID.Hex())
+	_, err = suite.repository.FetchByTaskID(suite.ctx, taskID.Hex())
 	suite.Error(err, "error db not found")
 	suite.Equal(err.Error(), "mongo: no documents in result")
 	}
 
+// Tenant isolation
+func (suite *taskRepositorySuite) TestOtherTenant_CannotSeeTask(){
+	taskID := primitive.NewObjectID()
+	task := domain.Task{
+		ID: taskID,
+		Title: "new title",
+		Org_id: "spoofed",
+	}
+
+	err := suite.repository.Create(suite.ctx, &task)
+	suite.NoError(err, "no error when create task with valid input")
+	suite.NotEqual("spoofed", task.Org_id, "the tenant comes from the context, not the input")
+
+	otherTenant := domain.WithTenant(context.TODO(), primitive.NewObjectID().Hex())
+	_, err = suite.repository.FetchByTaskID(otherTenant, taskID.Hex())
+	suite.Error(err, "tasks of another tenant are not found")
+	err = suite.repository.Update(otherTenant, taskID.Hex(), domain.Task{Title: "hijacked"})
+	suite.EqualError(err, "TASK NOT FOUND")
+	err = suite.repository.Delete(otherTenant, taskID.Hex())
+	suite.EqualError(err, "task not found")
+}
+
+func (suite *taskRepositorySuite) TestNoTenant_Negative(){
+	_, err := suite.repository.FetchAll(context.TODO(), domain.TaskFilter{})
+	suite.ErrorIs(err, domain.ErrNoTenant)
+}
+
+// Account-wide queries
+func (suite *taskRepositorySuite) TestFetchByUser_AcrossTenants(){
+	userID := primitive.NewObjectID().Hex()
+	otherTenant := domain.WithTenant(context.TODO(), primitive.NewObjectID().Hex())
+
+	suite.NoError(suite.repository.Create(suite.ctx, &domain.Task{ID: primitive.NewObjectID(), Title: "mine", Created_by: userID}))
+	suite.NoError(suite.repository.Create(otherTenant, &domain.Task{ID: primitive.NewObjectID(), Title: "assigned", Assignee_id: userID}))
+	suite.NoError(suite.repository.Create(otherTenant, &domain.Task{ID: primitive.NewObjectID(), Title: "someone else's"}))
+
+	tasks, err := suite.repository.FetchByUser(context.TODO(), userID)
+	suite.NoError(err)
+	suite.Len(tasks, 2)
+
+	suite.NoError(suite.repository.Unassign(context.TODO(), userID))
+	tasks, err = suite.repository.FetchByUser(context.TODO(), userID)
+	suite.NoError(err)
+	suite.Len(tasks, 1, "only the task they created still refers to them")
+}
+
+// Checklists
+func (suite *taskRepositorySuite) TestChecklist_AddReorderToggle(){
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "with steps"}
+	suite.NoError(suite.repository.Create(suite.ctx, &task))
+	taskID := task.ID.Hex()
+
+	second := domain.ChecklistItem{ID: primitive.NewObjectID(), Text: "second"}
+	first := domain.ChecklistItem{ID: primitive.NewObjectID(), Text: "first"}
+	suite.NoError(suite.repository.AddChecklistItem(suite.ctx, taskID, second, -1))
+	suite.NoError(suite.repository.AddChecklistItem(suite.ctx, taskID, first, 0))
+
+	stored, err := suite.repository.FetchByTaskID(suite.ctx, taskID)
+	suite.Require().NoError(err)
+	suite.Require().Len(stored.Checklist, 2)
+	suite.Equal("first", stored.Checklist[0].Text)
+
+	done := true
+	suite.NoError(suite.repository.UpdateChecklistItem(suite.ctx, taskID, second.ID.Hex(), domain.ChecklistItemUpdate{Done: &done}, time.Now()))
+	suite.ErrorIs(suite.repository.ReplaceChecklist(suite.ctx, taskID, stored.Checklist, []domain.ChecklistItem{second, first}), domain.ErrChecklistChanged,
+		"the toggle happened after stored was read")
+
+	suite.NoError(suite.repository.DeleteChecklistItem(suite.ctx, taskID, first.ID.Hex()))
+	suite.ErrorIs(suite.repository.DeleteChecklistItem(suite.ctx, taskID, first.ID.Hex()), domain.ErrChecklistItemNotFound)
+}
+
+// Custom fields
+func (suite *taskRepositorySuite) TestCustomFields_FilterSortBackfill(){
+	ctx := domain.WithTenant(context.TODO(), primitive.NewObjectID().Hex())
+	suite.NoError(suite.repository.Create(ctx, &domain.Task{ID: primitive.NewObjectID(), Title: "small", Custom_fields: map[string]interface{}{"points": float64(1)}}))
+	suite.NoError(suite.repository.Create(ctx, &domain.Task{ID: primitive.NewObjectID(), Title: "large", Custom_fields: map[string]interface{}{"points": float64(8)}}))
+	suite.NoError(suite.repository.Create(ctx, &domain.Task{ID: primitive.NewObjectID(), Title: "unsized"}))
+
+	tasks, err := suite.repository.FetchAll(ctx, domain.TaskFilter{Custom_fields: map[string]interface{}{"points": float64(8)}})
+	suite.Require().NoError(err)
+	suite.Require().Len(*tasks, 1)
+	suite.Equal("large", (*tasks)[0].Title)
+
+	tasks, err = suite.repository.FetchAll(ctx, domain.TaskFilter{Sort_by: "custom_fields.points", Descending: true})
+	suite.Require().NoError(err)
+	suite.Require().Len(*tasks, 3)
+	suite.Equal("large", (*tasks)[0].Title)
+
+	missing, err := suite.repository.CountMissingCustomField(ctx, "points")
+	suite.NoError(err)
+	suite.Equal(int64(1), missing)
+	suite.NoError(suite.repository.BackfillCustomField(ctx, "points", float64(3)))
+	inUse, err := suite.repository.CountCustomFieldValues(ctx, "points", []interface{}{float64(3), float64(8)})
+	suite.NoError(err)
+	suite.Equal(int64(2), inUse)
+
+	suite.NoError(suite.repository.UnsetCustomField(ctx, "points"))
+	missing, err = suite.repository.CountMissingCustomField(ctx, "points")
+	suite.NoError(err)
+	suite.Equal(int64(3), missing)
+}
+
+// Search
+func (suite *taskRepositorySuite) TestSearch_TextAndPrefix(){
+	ctx := domain.WithTenant(context.TODO(), primitive.NewObjectID().Hex())
+	deploy := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy the gateway"}
+	docs := domain.Task{ID: primitive.NewObjectID(), Title: "Write documentation"}
+	suite.NoError(suite.repository.Create(ctx, &deploy))
+	suite.NoError(suite.repository.Create(ctx, &docs))
+	suite.NoError(suite.repository.Create(suite.ctx, &domain.Task{ID: primitive.NewObjectID(), Title: "Deploy elsewhere"}))
+
+	tasks, err := suite.repository.Search(ctx, domain.TaskSearch{Text: "deploying", Limit: 10})
+	suite.Require().NoError(err)
+	suite.Require().Len(tasks, 1, "stemmed, and only in this organization")
+	suite.Equal(deploy.ID, tasks[0].ID)
+
+	tasks, err = suite.repository.Search(ctx, domain.TaskSearch{Text: "deploy", Prefixes: []string{"docu"}, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Len(tasks, 2)
+}
+
+func (suite *taskRepositorySuite) TestFetchByExternalID_UniquePerTenant(){
+	other := domain.WithTenant(context.TODO(), primitive.NewObjectID().Hex())
+	imported := domain.Task{ID: primitive.NewObjectID(), Title: "Imported", External_id: "A-1"}
+	suite.NoError(suite.repository.Create(suite.ctx, &imported))
+	suite.NoError(suite.repository.Create(suite.ctx, &domain.Task{ID: primitive.NewObjectID(), Title: "Local"}))
+	suite.NoError(suite.repository.Create(other, &domain.Task{ID: primitive.NewObjectID(), Title: "Elsewhere", External_id: "A-1"}))
+
+	task, err := suite.repository.FetchByExternalID(suite.ctx, "A-1")
+	suite.Require().NoError(err)
+	suite.Equal(imported.ID, task.ID)
+	_, err = suite.repository.FetchByExternalID(suite.ctx, "A-2")
+	suite.ErrorIs(err, domain.ErrTaskNotFound)
+	suite.Error(suite.repository.Create(suite.ctx, &domain.Task{ID: primitive.NewObjectID(), Title: "Twice", External_id: "A-1"}))
+}
+
+func TestCompileFilter(t *testing.T) {
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	expr := domain.FilterExpr{Kind: domain.FilterAnd, Children: []domain.FilterExpr{
+		{Kind: domain.FilterCompare, Field: "status", Operator: domain.FilterIn, Values: []interface{}{"todo", "blocked"}},
+		{Kind: domain.FilterNot, Children: []domain.FilterExpr{
+			{Kind: domain.FilterCompare, Field: "duedate", Operator: domain.FilterGte, Values: []interface{}{due}},
+		}},
+		{Kind: domain.FilterCompare, Field: "title", Operator: domain.FilterContains, Values: []interface{}{"a.b"}},
+	}}
+
+	want := bson.M{"$and": bson.A{
+		bson.M{"status": bson.M{"$in": []interface{}{"todo", "blocked"}}},
+		bson.M{"$nor": bson.A{bson.M{"duedate": bson.M{"$gte": due}}}},
+		bson.M{"title": primitive.Regex{Pattern: `a\.b`, Options: "i"}},
+	}}
+	assert.Equal(t, want, compileFilter(expr))
+}
+
 func TestTaskRepository(t *testing.T) {
 	suite.Run(t, new(taskRepositorySuite))
 }
\ No newline at end of file
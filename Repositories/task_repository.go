@@ -3,17 +3,24 @@ package repositories
 import (
 	"context"
 	"errors"
+	"regexp"
+	"sync"
 	domain "task-manger-api_test/Domain"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type taskRepository struct {
 	database   *mongo.Database
 	collection string
+	indexMu    sync.Mutex
+	indexed    bool
+	// externalIndexed is guarded by indexMu as well.
+	externalIndexed bool
 }
 
 func NewTaskRepository(db *mongo.Database, collection string) domain.TaskRepository {
@@ -28,7 +35,14 @@ func (tr *taskRepository) Create(c context.Context, task *domain.Task) error {
 	if task == nil{
 		return errors.New("task cannot be nil")
 	}
-	task.DueDate = time.Now()
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	task.Org_id = orgID
+	if task.DueDate.IsZero() {
+		task.DueDate = time.Now()
+	}
 
 	taskCollection := tr.database.Collection(tr.collection)
 	_, err := taskCollection.InsertOne(c, task)
@@ -36,32 +50,57 @@ func (tr *taskRepository) Create(c context.Context, task *domain.Task) error {
 	return err
 }
 
-func (tr *taskRepository) FetchAll(c context.Context) (*[]domain.Task, error) {
+func (tr *taskRepository) FetchAll(c context.Context, taskFilter domain.TaskFilter) (*[]domain.Task, error) {
 	var tasks []domain.Task
-	taskCollection := tr.database.Collection(tr.collection)
-
-	cur, err := taskCollection.Find(c, bson.D{{}})
+	err := tr.Stream(c, taskFilter, func(task domain.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
 	if err != nil {
 		return &[]domain.Task{}, err
 	}
+	return &tasks, nil
+}
 
-	for cur.Next(c) {
-		var task *domain.Task
+func (tr *taskRepository) Stream(c context.Context, taskFilter domain.TaskFilter, fn func(domain.Task) error) error {
+	taskCollection := tr.database.Collection(tr.collection)
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
 
-		val := cur.Decode(&task)
-		if val != nil {
-			return &[]domain.Task{}, val
+	filter := bson.M{"org_id": orgID}
+	for key, value := range taskFilter.Custom_fields {
+		filter["custom_fields."+key] = value
+	}
+	if taskFilter.Expression != nil {
+		filter["$and"] = bson.A{compileFilter(*taskFilter.Expression)}
+	}
+	opts := options.Find()
+	if taskFilter.Sort_by != "" {
+		direction := 1
+		if taskFilter.Descending {
+			direction = -1
 		}
-
-		tasks = append(tasks, *task)
+		opts.SetSort(bson.D{{Key: taskFilter.Sort_by, Value: direction}, {Key: "_id", Value: 1}})
 	}
 
-	if err := cur.Err(); err != nil {
-		return &[]domain.Task{}, err
+	cur, err := taskCollection.Find(c, filter, opts)
+	if err != nil {
+		return err
 	}
+	defer cur.Close(c)
 
-	cur.Close(c)
-	return &tasks, nil
+	for cur.Next(c) {
+		var task domain.Task
+		if err := cur.Decode(&task); err != nil {
+			return err
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
 }
 
 func (tr *taskRepository) FetchByTaskID(c context.Context, taskID string) (*domain.Task, error) {
@@ -72,8 +111,12 @@ func (tr *taskRepository) FetchByTaskID(c context.Context, taskID string) (*doma
 	if err != nil {
         return &domain.Task{}, err
     }
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return &domain.Task{}, domain.ErrNoTenant
+	}
 
-	filter := bson.D{{Key: "_id", Value: objID}}
+	filter := bson.D{{Key: "_id", Value: objID}, {Key: "org_id", Value: orgID}}
 	result := taskCollection.FindOne(c, filter).Decode(&task)
 	if result != nil {
 		return &domain.Task{}, result
@@ -87,15 +130,28 @@ func (tr *taskRepository) Update(c context.Context, taskID string, updatedTask d
     if err != nil {
         return err
     }
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
 
-	filter := bson.D{{Key: "_id", Value: objID}}
+	filter := bson.D{{Key: "_id", Value: objID}, {Key: "org_id", Value: orgID}}
 	update := bson.D{
 		{Key: "$set", Value: bson.D{
 			{Key: "title", Value: updatedTask.Title},
 			{Key: "description", Value: updatedTask.Description},
-			{Key: "due_date", Value: updatedTask.DueDate},
+			{Key: "duedate", Value: updatedTask.DueDate},
 			{Key: "status", Value: updatedTask.Status},
+			{Key: "assignee_id", Value: updatedTask.Assignee_id},
+			{Key: "labels", Value: updatedTask.Labels},
+			{Key: "original_estimate", Value: updatedTask.Original_estimate},
+			{Key: "remaining_estimate", Value: updatedTask.Remaining_estimate},
 		}},
+		// earlier updates wrote the due date under a key nothing reads
+		{Key: "$unset", Value: bson.D{{Key: "due_date", Value: ""}}},
+	}
+	if updatedTask.Custom_fields != nil {
+		update[0].Value = append(update[0].Value.(bson.D), bson.E{Key: "custom_fields", Value: updatedTask.Custom_fields})
 	}
 	updateResult, result := taskCollection.UpdateOne(c, filter, update)
 	if result != nil {
@@ -113,8 +169,12 @@ func (tr *taskRepository) Delete(c context.Context, taskID string) error {
     if err != nil {
         return errors.New("INVALID ID")
     }
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
 
-    result, err := taskCollection.DeleteOne(context.TODO(), bson.D{{Key: "_id", Value: objID}})
+    result, err := taskCollection.DeleteOne(c, bson.D{{Key: "_id", Value: objID}, {Key: "org_id", Value: orgID}})
     if err != nil {
         return err 
     }
@@ -125,3 +185,320 @@ func (tr *taskRepository) Delete(c context.Context, taskID string) error {
 
     return nil
 }
+
+func (tr *taskRepository) FetchByExternalID(c context.Context, externalID string) (*domain.Task, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	if err := tr.ensureExternalIDIndex(c); err != nil {
+		return nil, err
+	}
+
+	var task domain.Task
+	err := tr.database.Collection(tr.collection).FindOne(c, bson.M{"org_id": orgID, "external_id": externalID}).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ensureExternalIDIndex keeps external IDs unique within an organization,
+// so concurrent imports of the same rows cannot both create a task.
+func (tr *taskRepository) ensureExternalIDIndex(c context.Context) error {
+	tr.indexMu.Lock()
+	defer tr.indexMu.Unlock()
+	if tr.externalIndexed {
+		return nil
+	}
+	_, err := tr.database.Collection(tr.collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "external_id", Value: 1}},
+		Options: options.Index().SetName("task_external_id").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"external_id": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+	tr.externalIndexed = true
+	return nil
+}
+
+func (tr *taskRepository) FetchByUser(c context.Context, userID string) ([]domain.Task, error) {
+	filter := bson.M{"$or": bson.A{bson.M{"created_by": userID}, bson.M{"assignee_id": userID}}}
+	cur, err := tr.database.Collection(tr.collection).Find(c, filter)
+	if err != nil {
+		return nil, err
+	}
+	tasks := []domain.Task{}
+	if err := cur.All(c, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (tr *taskRepository) Unassign(c context.Context, userID string) error {
+	_, err := tr.database.Collection(tr.collection).UpdateMany(c, bson.M{"assignee_id": userID}, bson.M{"$set": bson.M{"assignee_id": ""}})
+	return err
+}
+
+func (tr *taskRepository) AddChecklistItem(c context.Context, taskID string, item domain.ChecklistItem, position int) error {
+	filter, err := tr.byID(c, taskID)
+	if err != nil {
+		return err
+	}
+	push := bson.M{"$each": bson.A{item}}
+	if position >= 0 {
+		push["$position"] = position
+	}
+	result, err := tr.database.Collection(tr.collection).UpdateOne(c, filter, bson.M{"$push": bson.M{"checklist": push}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (tr *taskRepository) UpdateChecklistItem(c context.Context, taskID string, itemID string, update domain.ChecklistItemUpdate, doneAt time.Time) error {
+	filter, err := tr.byChecklistItem(c, taskID, itemID)
+	if err != nil {
+		return err
+	}
+	set := bson.M{}
+	changes := bson.M{"$set": set}
+	if update.Text != nil {
+		set["checklist.$.text"] = *update.Text
+	}
+	if update.Done != nil {
+		set["checklist.$.done"] = *update.Done
+		if *update.Done {
+			set["checklist.$.done_at"] = doneAt
+		} else {
+			changes["$unset"] = bson.M{"checklist.$.done_at": ""}
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+
+	result, err := tr.database.Collection(tr.collection).UpdateOne(c, filter, changes)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrChecklistItemNotFound
+	}
+	return nil
+}
+
+func (tr *taskRepository) DeleteChecklistItem(c context.Context, taskID string, itemID string) error {
+	filter, err := tr.byChecklistItem(c, taskID, itemID)
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$pull": bson.M{"checklist": bson.M{"_id": filter["checklist._id"]}}}
+	result, err := tr.database.Collection(tr.collection).UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrChecklistItemNotFound
+	}
+	return nil
+}
+
+func (tr *taskRepository) ReplaceChecklist(c context.Context, taskID string, current []domain.ChecklistItem, reordered []domain.ChecklistItem) error {
+	filter, err := tr.byID(c, taskID)
+	if err != nil {
+		return err
+	}
+	filter["checklist"] = current
+	result, err := tr.database.Collection(tr.collection).UpdateOne(c, filter, bson.M{"$set": bson.M{"checklist": reordered}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrChecklistChanged
+	}
+	return nil
+}
+
+func (tr *taskRepository) CountCustomFieldValues(c context.Context, key string, values []interface{}) (int64, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return 0, domain.ErrNoTenant
+	}
+	filter := bson.M{"org_id": orgID, "custom_fields." + key: bson.M{"$in": values}}
+	return tr.database.Collection(tr.collection).CountDocuments(c, filter)
+}
+
+func (tr *taskRepository) CountMissingCustomField(c context.Context, key string) (int64, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return 0, domain.ErrNoTenant
+	}
+	filter := bson.M{"org_id": orgID, "custom_fields." + key: bson.M{"$exists": false}}
+	return tr.database.Collection(tr.collection).CountDocuments(c, filter)
+}
+
+func (tr *taskRepository) BackfillCustomField(c context.Context, key string, value interface{}) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	filter := bson.M{"org_id": orgID, "custom_fields." + key: bson.M{"$exists": false}}
+	_, err := tr.database.Collection(tr.collection).UpdateMany(c, filter, bson.M{"$set": bson.M{"custom_fields." + key: value}})
+	return err
+}
+
+func (tr *taskRepository) UnsetCustomField(c context.Context, key string) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	filter := bson.M{"org_id": orgID, "custom_fields." + key: bson.M{"$exists": true}}
+	_, err := tr.database.Collection(tr.collection).UpdateMany(c, filter, bson.M{"$unset": bson.M{"custom_fields." + key: ""}})
+	return err
+}
+
+// Search runs the text index query and the prefix and comment lookups
+// separately, as $text cannot be combined with them under $or, and merges
+// the results.
+func (tr *taskRepository) Search(c context.Context, search domain.TaskSearch) ([]domain.Task, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	collection := tr.database.Collection(tr.collection)
+	tasks := []domain.Task{}
+
+	if search.Text != "" {
+		if err := tr.ensureTextIndex(c); err != nil {
+			return nil, err
+		}
+		filter := bson.M{"org_id": orgID, "$text": bson.M{"$search": search.Text}}
+		opts := options.Find().SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).SetLimit(search.Limit)
+		cur, err := collection.Find(c, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := cur.All(c, &tasks); err != nil {
+			return nil, err
+		}
+	}
+
+	or := bson.A{}
+	for _, prefix := range search.Prefixes {
+		pattern := primitive.Regex{Pattern: `(^|\W)` + regexp.QuoteMeta(prefix), Options: "i"}
+		or = append(or, bson.M{"title": pattern}, bson.M{"description": pattern})
+	}
+	if len(search.Task_ids) > 0 {
+		ids := bson.A{}
+		for _, taskID := range search.Task_ids {
+			if objID, err := primitive.ObjectIDFromHex(taskID); err == nil {
+				ids = append(ids, objID)
+			}
+		}
+		or = append(or, bson.M{"_id": bson.M{"$in": ids}})
+	}
+	if len(or) == 0 {
+		return tasks, nil
+	}
+	cur, err := collection.Find(c, bson.M{"org_id": orgID, "$or": or}, options.Find().SetLimit(search.Limit))
+	if err != nil {
+		return nil, err
+	}
+	more := []domain.Task{}
+	if err := cur.All(c, &more); err != nil {
+		return nil, err
+	}
+	seen := make(map[primitive.ObjectID]bool, len(tasks))
+	for _, task := range tasks {
+		seen[task.ID] = true
+	}
+	for _, task := range more {
+		if !seen[task.ID] {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (tr *taskRepository) ensureTextIndex(c context.Context) error {
+	tr.indexMu.Lock()
+	defer tr.indexMu.Unlock()
+	if tr.indexed {
+		return nil
+	}
+	_, err := tr.database.Collection(tr.collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().SetName("task_text").SetWeights(bson.M{"title": 3, "description": 1}),
+	})
+	if err != nil {
+		return err
+	}
+	tr.indexed = true
+	return nil
+}
+
+// compileFilter turns a checked filter expression into a MongoDB query.
+// Negation uses $nor, as $not only applies to operator expressions.
+func compileFilter(expr domain.FilterExpr) bson.M {
+	switch expr.Kind {
+	case domain.FilterAnd, domain.FilterOr:
+		children := bson.A{}
+		for _, child := range expr.Children {
+			children = append(children, compileFilter(child))
+		}
+		return bson.M{"$" + expr.Kind: children}
+	case domain.FilterNot:
+		return bson.M{"$nor": bson.A{compileFilter(expr.Children[0])}}
+	}
+
+	switch expr.Operator {
+	case domain.FilterEq:
+		return bson.M{expr.Field: expr.Values[0]}
+	case domain.FilterIn:
+		return bson.M{expr.Field: bson.M{"$in": expr.Values}}
+	case domain.FilterContains:
+		text, _ := expr.Values[0].(string)
+		return bson.M{expr.Field: primitive.Regex{Pattern: regexp.QuoteMeta(text), Options: "i"}}
+	}
+	operators := map[string]string{
+		domain.FilterNe:  "$ne",
+		domain.FilterLt:  "$lt",
+		domain.FilterLte: "$lte",
+		domain.FilterGt:  "$gt",
+		domain.FilterGte: "$gte",
+	}
+	return bson.M{expr.Field: bson.M{operators[expr.Operator]: expr.Values[0]}}
+}
+
+func (tr *taskRepository) byID(c context.Context, taskID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
+
+func (tr *taskRepository) byChecklistItem(c context.Context, taskID string, itemID string) (bson.M, error) {
+	filter, err := tr.byID(c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	itemObjID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return nil, domain.ErrChecklistItemNotFound
+	}
+	filter["checklist._id"] = itemObjID
+	return filter, nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type taskTemplateRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewTaskTemplateRepository(db *mongo.Database, collection string) domain.TaskTemplateRepository {
+	return &taskTemplateRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (tr *taskTemplateRepository) Create(c context.Context, template *domain.TaskTemplate) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	template.ID = primitive.NewObjectID()
+	template.Org_id = orgID
+	template.Created_at = time.Now()
+	_, err := tr.database.Collection(tr.collection).InsertOne(c, template)
+	return err
+}
+
+func (tr *taskTemplateRepository) FetchAll(c context.Context) ([]domain.TaskTemplate, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := tr.database.Collection(tr.collection).Find(c, bson.M{"org_id": orgID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	templates := []domain.TaskTemplate{}
+	if err := cur.All(c, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (tr *taskTemplateRepository) FetchByID(c context.Context, templateID string) (domain.TaskTemplate, error) {
+	filter, err := tr.byID(c, templateID)
+	if err != nil {
+		return domain.TaskTemplate{}, err
+	}
+
+	var template domain.TaskTemplate
+	err = tr.database.Collection(tr.collection).FindOne(c, filter).Decode(&template)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.TaskTemplate{}, domain.ErrTaskTemplateNotFound
+	}
+	if err != nil {
+		return domain.TaskTemplate{}, err
+	}
+	return template, nil
+}
+
+func (tr *taskTemplateRepository) Update(c context.Context, template domain.TaskTemplate) error {
+	filter, err := tr.byID(c, template.ID.Hex())
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{
+		"name":      template.Name,
+		"task":      template.Task,
+		"variables": template.Variables,
+	}}
+	result, err := tr.database.Collection(tr.collection).UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTaskTemplateNotFound
+	}
+	return nil
+}
+
+func (tr *taskTemplateRepository) Delete(c context.Context, templateID string) error {
+	filter, err := tr.byID(c, templateID)
+	if err != nil {
+		return err
+	}
+	result, err := tr.database.Collection(tr.collection).DeleteOne(c, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrTaskTemplateNotFound
+	}
+	return nil
+}
+
+func (tr *taskTemplateRepository) byID(c context.Context, templateID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return nil, domain.ErrTaskTemplateNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
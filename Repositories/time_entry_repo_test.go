@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type timeEntryRepositorySuite struct {
+	suite.Suite
+	repository domain.TimeEntryRepository
+	ctx        context.Context
+}
+
+func (suite *timeEntryRepositorySuite) SetupSuite() {
+	configs := config.GetConfig()
+	_, db := config.ConnectDB(configs)
+	suite.repository = NewTimeEntryRepository(db, domain.CollectionTimeEntry)
+	suite.ctx = domain.WithTenant(context.TODO(), primitive.NewObjectID().Hex())
+}
+
+func (suite *timeEntryRepositorySuite) TestOneRunningTimerPerUser() {
+	userID := primitive.NewObjectID().Hex()
+	otherTenant := domain.WithTenant(context.TODO(), primitive.NewObjectID().Hex())
+
+	suite.NoError(suite.repository.StartTimer(suite.ctx, &domain.TimeEntry{Task_id: "t1", User_id: userID, Started_at: time.Now()}))
+	suite.ErrorIs(suite.repository.StartTimer(otherTenant, &domain.TimeEntry{Task_id: "t2", User_id: userID, Started_at: time.Now()}), domain.ErrTimerRunning)
+
+	entry, err := suite.repository.StopTimer(context.TODO(), userID, time.Now(), 24*time.Hour)
+	suite.Require().NoError(err)
+	suite.Equal("t1", entry.Task_id)
+	suite.False(entry.Running)
+
+	_, err = suite.repository.StopTimer(context.TODO(), userID, time.Now(), 24*time.Hour)
+	suite.ErrorIs(err, domain.ErrNoRunningTimer)
+	suite.NoError(suite.repository.StartTimer(otherTenant, &domain.TimeEntry{Task_id: "t2", User_id: userID, Started_at: time.Now()}))
+}
+
+func (suite *timeEntryRepositorySuite) TestStopTimer_CapsForgottenTimers() {
+	userID := primitive.NewObjectID().Hex()
+	started := time.Now().Add(-72 * time.Hour).Truncate(time.Millisecond)
+	suite.NoError(suite.repository.StartTimer(suite.ctx, &domain.TimeEntry{Task_id: "t1", User_id: userID, Started_at: started}))
+
+	entry, err := suite.repository.StopTimer(context.TODO(), userID, time.Now(), 24*time.Hour)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(entry.Ended_at)
+	suite.True(started.Add(24*time.Hour).Equal(*entry.Ended_at))
+}
+
+func (suite *timeEntryRepositorySuite) TestReport_ByDay() {
+	userID := primitive.NewObjectID().Hex()
+	day := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, hours := range []int{1, 2} {
+		ended := day.Add(time.Duration(hours) * time.Hour)
+		suite.NoError(suite.repository.Create(suite.ctx, &domain.TimeEntry{Task_id: "t1", User_id: userID, Started_at: day, Ended_at: &ended}))
+	}
+
+	rows, err := suite.repository.Report(suite.ctx, domain.TimeReportFilter{
+		Group_by: domain.TimeReportByDay,
+		From:     day.Add(-time.Hour),
+		To:       day.Add(24 * time.Hour),
+		User_id:  userID,
+	})
+	suite.NoError(err)
+	suite.Equal([]domain.TimeReportRow{{Key: "2024-03-01", Seconds: 3 * 3600, Entries: 2}}, rows)
+}
+
+func TestTimeEntryRepository(t *testing.T) {
+	suite.Run(t, new(timeEntryRepositorySuite))
+}
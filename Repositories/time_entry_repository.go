@@ -0,0 +1,241 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type timeEntryRepository struct {
+	database   *mongo.Database
+	collection string
+	// the unique index on running timers guards against two concurrent
+	// StartTimer calls both inserting; it is created on first use
+	indexMu sync.Mutex
+	indexed bool
+}
+
+func NewTimeEntryRepository(db *mongo.Database, collection string) domain.TimeEntryRepository {
+	return &timeEntryRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (tr *timeEntryRepository) Create(c context.Context, entry *domain.TimeEntry) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	entry.ID = primitive.NewObjectID()
+	entry.Org_id = orgID
+	entry.Created_at = time.Now()
+	_, err := tr.database.Collection(tr.collection).InsertOne(c, entry)
+	return err
+}
+
+// StartTimer inserts the entry only if the user has no running one: the
+// upsert matches an existing timer instead of creating a second.
+func (tr *timeEntryRepository) StartTimer(c context.Context, entry *domain.TimeEntry) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	if err := tr.ensureTimerIndex(c); err != nil {
+		return err
+	}
+	entry.ID = primitive.NewObjectID()
+	entry.Org_id = orgID
+	entry.Running = true
+	entry.Ended_at = nil
+	entry.Created_at = time.Now()
+
+	filter := bson.M{"user_id": entry.User_id, "running": true}
+	update := bson.M{"$setOnInsert": bson.M{
+		"_id":        entry.ID,
+		"org_id":     entry.Org_id,
+		"task_id":    entry.Task_id,
+		"started_at": entry.Started_at,
+		"note":       entry.Note,
+		"created_at": entry.Created_at,
+	}}
+	result, err := tr.database.Collection(tr.collection).UpdateOne(c, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrTimerRunning
+	}
+	if err != nil {
+		return err
+	}
+	if result.UpsertedCount == 0 {
+		return domain.ErrTimerRunning
+	}
+	return nil
+}
+
+func (tr *timeEntryRepository) ensureTimerIndex(c context.Context) error {
+	tr.indexMu.Lock()
+	defer tr.indexMu.Unlock()
+	if tr.indexed {
+		return nil
+	}
+	_, err := tr.database.Collection(tr.collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetName("one_running_timer_per_user").SetUnique(true).SetPartialFilterExpression(bson.M{"running": true}),
+	})
+	if err != nil {
+		return err
+	}
+	tr.indexed = true
+	return nil
+}
+
+// StopTimer works across organizations, since a user's timer is personal.
+// The end is capped in the update itself, against the start it stops.
+func (tr *timeEntryRepository) StopTimer(c context.Context, userID string, endedAt time.Time, longest time.Duration) (domain.TimeEntry, error) {
+	filter := bson.M{"user_id": userID, "running": true}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"running":  false,
+		"ended_at": bson.M{"$min": bson.A{endedAt, bson.M{"$add": bson.A{"$started_at", longest.Milliseconds()}}}},
+	}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var entry domain.TimeEntry
+	err := tr.database.Collection(tr.collection).FindOneAndUpdate(c, filter, update, opts).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.TimeEntry{}, domain.ErrNoRunningTimer
+	}
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+func (tr *timeEntryRepository) FetchRunning(c context.Context, userID string) (domain.TimeEntry, error) {
+	var entry domain.TimeEntry
+	err := tr.database.Collection(tr.collection).FindOne(c, bson.M{"user_id": userID, "running": true}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.TimeEntry{}, domain.ErrNoRunningTimer
+	}
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+func (tr *timeEntryRepository) FetchByID(c context.Context, entryID string) (domain.TimeEntry, error) {
+	filter, err := tr.byID(c, entryID)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	var entry domain.TimeEntry
+	err = tr.database.Collection(tr.collection).FindOne(c, filter).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.TimeEntry{}, domain.ErrTimeEntryNotFound
+	}
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+func (tr *timeEntryRepository) FetchByTask(c context.Context, taskID string) ([]domain.TimeEntry, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := tr.database.Collection(tr.collection).Find(c, bson.M{"org_id": orgID, "task_id": taskID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	entries := []domain.TimeEntry{}
+	if err := cur.All(c, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (tr *timeEntryRepository) Delete(c context.Context, entryID string) error {
+	filter, err := tr.byID(c, entryID)
+	if err != nil {
+		return err
+	}
+	result, err := tr.database.Collection(tr.collection).DeleteOne(c, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrTimeEntryNotFound
+	}
+	return nil
+}
+
+// Report sums finished entries of the active organization. Entries count
+// toward the day, and the range, they started in.
+func (tr *timeEntryRepository) Report(c context.Context, filter domain.TimeReportFilter) ([]domain.TimeReportRow, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	match := bson.M{
+		"org_id":     orgID,
+		"running":    false,
+		"started_at": bson.M{"$gte": filter.From, "$lt": filter.To},
+	}
+	if filter.User_id != "" {
+		match["user_id"] = filter.User_id
+	}
+	if filter.Task_id != "" {
+		match["task_id"] = filter.Task_id
+	}
+
+	var key interface{}
+	switch filter.Group_by {
+	case domain.TimeReportByUser:
+		key = "$user_id"
+	case domain.TimeReportByTask:
+		key = "$task_id"
+	default:
+		key = bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$started_at", "timezone": "UTC"}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": key,
+			"seconds": bson.M{"$sum": bson.M{"$toLong": bson.M{"$divide": bson.A{
+				bson.M{"$subtract": bson.A{"$ended_at", "$started_at"}}, 1000,
+			}}}},
+			"entries": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	cur, err := tr.database.Collection(tr.collection).Aggregate(c, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	rows := []domain.TimeReportRow{}
+	if err := cur.All(c, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (tr *timeEntryRepository) byID(c context.Context, entryID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(entryID)
+	if err != nil {
+		return nil, domain.ErrTimeEntryNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type transactor struct {
+	client *mongo.Client
+}
+
+// NewTransactor runs transactions on the client db belongs to. MongoDB only
+// supports them on replica sets and sharded clusters.
+func NewTransactor(db *mongo.Database) domain.Transactor {
+	return &transactor{client: db.Client()}
+}
+
+// WithTransaction hands fn a session context; every repository call made
+// with it, or with a context derived from it, runs inside the transaction.
+func (t *transactor) WithTransaction(c context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(c)
+
+	_, err = session.WithTransaction(c, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
@@ -3,7 +3,7 @@ package repositories
 import (
 	"context"
 	"errors"
-	"fmt"
+	"regexp"
 	domain "task-manger-api_test/Domain"
 	infrastructure "task-manger-api_test/Infrastructure"
 	"time"
@@ -12,10 +12,14 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var validate = validator.New()
 
+// emailCollation compares emails without regard to case.
+var emailCollation = &options.Collation{Locale: "en", Strength: 2}
+
 type userRepository struct {
 	database   *mongo.Database
 	collection string
@@ -38,7 +42,7 @@ func (ur *userRepository) Create(c context.Context, user *domain.User) error {
 		return errors.New("this username already exists")
 	}
 
-	count, err = userCollection.CountDocuments(c, bson.M{"email":user.Email})
+	count, err = userCollection.CountDocuments(c, bson.M{"email":user.Email}, options.Count().SetCollation(emailCollation))
 	// defer cancel()
 	if err!= nil {
 		// log.Panic(err)
@@ -49,23 +53,17 @@ func (ur *userRepository) Create(c context.Context, user *domain.User) error {
 		return errors.New("this email already exists. ")
 	}
 
-	password := infrastructure.HashPassword(*user.Password)
+	password, err := infrastructure.HashPassword(*user.Password)
+	if err != nil {
+		return err
+	}
 	user.Password = &password
 	user.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 	user.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 	user.ID = primitive.NewObjectID()
 	user.User_id = user.ID.Hex() 
 
-	countUsers, err := userCollection.CountDocuments(c, bson.M{})
-	if err!= nil {
-		// log.Panic(err)
-		return err
-	}
-	if countUsers == 0{
-		user.User_type = "ADMIN"
-	}else{
 	user.User_type = "USER"
-	}
 
 	_, insertionErr := userCollection.InsertOne(c, user)
 	if insertionErr != nil{
@@ -89,7 +87,6 @@ func (ur *userRepository) FindByUsername(c context.Context, username string) (do
 
 func (ur *userRepository) Update(c context.Context, userID string) error {
 	objID, err := primitive.ObjectIDFromHex(userID)
-	fmt.Println(objID, err)
 	userCollection := ur.database.Collection(ur.collection)
     if err != nil {
         return err
@@ -101,12 +98,274 @@ func (ur *userRepository) Update(c context.Context, userID string) error {
 			{Key: "user_type", Value: "ADMIN"},
 		}},
 	}
-	updateResult, msg := userCollection.UpdateOne(context.TODO(), filter, update)
+	updateResult, msg := userCollection.UpdateOne(c, filter, update)
 	if msg != nil {
 		return msg
 	}
 	if updateResult.MatchedCount == 0{
-		return errors.New("USER NOT FOUND")
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *userRepository) FindByEmail(c context.Context, email string) (domain.User, error) {
+	var foundUser domain.User
+	userCollection := ur.database.Collection(ur.collection)
+
+	result := userCollection.FindOne(c, bson.M{"email": email}, options.FindOne().SetCollation(emailCollation)).Decode(&foundUser)
+	if result != nil {
+		return domain.User{}, result
+	}
+	return foundUser, nil
+}
+
+func (ur *userRepository) FindByIdentity(c context.Context, provider string, subject string) (domain.User, error) {
+	var foundUser domain.User
+	userCollection := ur.database.Collection(ur.collection)
+
+	filter := bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}}
+	result := userCollection.FindOne(c, filter).Decode(&foundUser)
+	if result != nil {
+		return domain.User{}, result
+	}
+	return foundUser, nil
+}
+
+func (ur *userRepository) AddIdentity(c context.Context, userID string, identity domain.ExternalIdentity) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	userCollection := ur.database.Collection(ur.collection)
+
+	update := bson.D{
+		{Key: "$push", Value: bson.D{{Key: "identities", Value: identity}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+	}
+	updateResult, err := userCollection.UpdateOne(c, bson.D{{Key: "_id", Value: objID}}, update)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// CreateExternal inserts a user provisioned from an external identity. Such
+// users have no local password and always start as USER.
+func (ur *userRepository) CreateExternal(c context.Context, user *domain.User) error {
+	userCollection := ur.database.Collection(ur.collection)
+
+	if user.Username == nil || user.Email == nil {
+		return errors.New("username and email are required")
+	}
+	count, err := userCollection.CountDocuments(c, bson.M{"email": user.Email}, options.Count().SetCollation(emailCollation))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("this email already exists. ")
+	}
+
+	user.Password = nil
+	user.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	user.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	user.ID = primitive.NewObjectID()
+	user.User_id = user.ID.Hex()
+	user.User_type = "USER"
+
+	_, err = userCollection.InsertOne(c, user)
+	return err
+}
+
+func (ur *userRepository) FetchByUserID(c context.Context, userID string) (domain.User, error) {
+	var foundUser domain.User
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	userCollection := ur.database.Collection(ur.collection)
+
+	result := userCollection.FindOne(c, bson.M{"_id": objID}).Decode(&foundUser)
+	if errors.Is(result, mongo.ErrNoDocuments) {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	if result != nil {
+		return domain.User{}, result
+	}
+	return foundUser, nil
+}
+
+func (ur *userRepository) FetchAll(c context.Context, filter domain.UserFilter) ([]domain.User, int64, error) {
+	userCollection := ur.database.Collection(ur.collection)
+
+	query := bson.M{}
+	if filter.Query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Query), Options: "i"}
+		query["$or"] = bson.A{
+			bson.M{"username": pattern},
+			bson.M{"name": pattern},
+			bson.M{"email": pattern},
+		}
+	}
+	if filter.User_type != "" {
+		query["user_type"] = filter.User_type
+	}
+
+	total, err := userCollection.CountDocuments(c, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip((filter.Page - 1) * filter.Limit).
+		SetLimit(filter.Limit)
+	cur, err := userCollection.Find(c, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cur.Close(c)
+
+	users := []domain.User{}
+	if err := cur.All(c, &users); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (ur *userRepository) UpdateProfile(c context.Context, userID string, update domain.ProfileUpdate) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	if validationErr := validate.Struct(update); validationErr != nil {
+		return validationErr
+	}
+	userCollection := ur.database.Collection(ur.collection)
+
+	set := bson.D{{Key: "updated_at", Value: time.Now()}}
+	if update.Name != nil {
+		set = append(set, bson.E{Key: "name", Value: *update.Name})
+	}
+	if update.Username != nil {
+		count, err := userCollection.CountDocuments(c, bson.M{"username": *update.Username, "_id": bson.M{"$ne": objID}})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("this username already exists")
+		}
+		set = append(set, bson.E{Key: "username", Value: *update.Username})
+	}
+	if update.Email != nil {
+		count, err := userCollection.CountDocuments(c, bson.M{"email": *update.Email, "_id": bson.M{"$ne": objID}}, options.Count().SetCollation(emailCollation))
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("this email already exists. ")
+		}
+		set = append(set, bson.E{Key: "email", Value: *update.Email})
+	}
+
+	updates := bson.D{{Key: "$set", Value: set}}
+	if update.Email != nil {
+		// a new address has not been verified, whatever the old one was
+		updates = append(updates, bson.E{Key: "$unset", Value: bson.D{{Key: "email_verified_at", Value: ""}}})
+	}
+	return ur.updateOne(c, objID, updates)
+}
+
+func (ur *userRepository) UpdatePassword(c context.Context, userID string, passwordHash string) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	return ur.updateOne(c, objID, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "password", Value: passwordHash},
+		{Key: "updated_at", Value: time.Now()},
+	}}})
+}
+
+func (ur *userRepository) SetUserType(c context.Context, userID string, userType string) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	return ur.updateOne(c, objID, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "user_type", Value: userType},
+		{Key: "updated_at", Value: time.Now()},
+	}}})
+}
+
+func (ur *userRepository) SetDeactivated(c context.Context, userID string, deactivatedAt *time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "deactivated_at", Value: deactivatedAt}, {Key: "updated_at", Value: time.Now()}}},
+	}
+	if deactivatedAt == nil {
+		update = bson.D{
+			{Key: "$unset", Value: bson.D{{Key: "deactivated_at", Value: ""}}},
+			{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+		}
+	}
+	return ur.updateOne(c, objID, update)
+}
+
+func (ur *userRepository) Delete(c context.Context, userID string) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	userCollection := ur.database.Collection(ur.collection)
+
+	result, err := userCollection.DeleteOne(c, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// Anonymize strips personal data from the user but keeps the record, so
+// tasks and audit entries referring to it stay valid.
+func (ur *userRepository) Anonymize(c context.Context, userID string, erasedAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	placeholder := "deleted-" + userID
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "name", Value: "Deleted user"},
+			{Key: "username", Value: placeholder},
+			{Key: "email", Value: placeholder + "@invalid"},
+			{Key: "user_type", Value: "USER"},
+			{Key: "deactivated_at", Value: erasedAt},
+			{Key: "erased_at", Value: erasedAt},
+			{Key: "updated_at", Value: erasedAt},
+		}},
+		{Key: "$unset", Value: bson.D{{Key: "password", Value: ""}, {Key: "identities", Value: ""}, {Key: "email_verified_at", Value: ""}}},
+	}
+	return ur.updateOne(c, objID, update)
+}
+
+func (ur *userRepository) updateOne(c context.Context, objID primitive.ObjectID, update bson.D) error {
+	userCollection := ur.database.Collection(ur.collection)
+
+	updateResult, err := userCollection.UpdateOne(c, bson.M{"_id": objID}, update)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return domain.ErrUserNotFound
 	}
 	return nil
 }
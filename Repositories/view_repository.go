@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type viewRepository struct {
+	database          *mongo.Database
+	collection        string
+	defaultCollection string
+}
+
+func NewViewRepository(db *mongo.Database, collection string, defaultCollection string) domain.ViewRepository {
+	return &viewRepository{
+		database:          db,
+		collection:        collection,
+		defaultCollection: defaultCollection,
+	}
+}
+
+func (vr *viewRepository) Create(c context.Context, view *domain.View) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	view.ID = primitive.NewObjectID()
+	view.Org_id = orgID
+	view.Created_at = time.Now()
+	view.Updated_at = view.Created_at
+	_, err := vr.database.Collection(vr.collection).InsertOne(c, view)
+	return err
+}
+
+func (vr *viewRepository) FetchByID(c context.Context, viewID string) (domain.View, error) {
+	filter, err := vr.byID(c, viewID)
+	if err != nil {
+		return domain.View{}, err
+	}
+
+	var view domain.View
+	err = vr.database.Collection(vr.collection).FindOne(c, filter).Decode(&view)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.View{}, domain.ErrViewNotFound
+	}
+	if err != nil {
+		return domain.View{}, err
+	}
+	return view, nil
+}
+
+func (vr *viewRepository) FetchVisible(c context.Context, userID string) ([]domain.View, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	filter := bson.M{"org_id": orgID, "$or": bson.A{bson.M{"owner_id": userID}, bson.M{"visibility": domain.ViewShared}}}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := vr.database.Collection(vr.collection).Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	views := []domain.View{}
+	if err := cur.All(c, &views); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func (vr *viewRepository) Update(c context.Context, view domain.View) error {
+	filter, err := vr.byID(c, view.ID.Hex())
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{
+		"name":       view.Name,
+		"filter":     view.Filter,
+		"sort":       view.Sort,
+		"columns":    view.Columns,
+		"visibility": view.Visibility,
+		"updated_at": view.Updated_at,
+	}}
+	result, err := vr.database.Collection(vr.collection).UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrViewNotFound
+	}
+	return nil
+}
+
+func (vr *viewRepository) Delete(c context.Context, viewID string) error {
+	filter, err := vr.byID(c, viewID)
+	if err != nil {
+		return err
+	}
+	result, err := vr.database.Collection(vr.collection).DeleteOne(c, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrViewNotFound
+	}
+	_, err = vr.database.Collection(vr.defaultCollection).DeleteMany(c, bson.M{"org_id": filter["org_id"], "view_id": viewID})
+	return err
+}
+
+func (vr *viewRepository) SetDefault(c context.Context, userID string, viewID string) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	filter := bson.M{"org_id": orgID, "user_id": userID}
+	update := bson.M{"$set": bson.M{"view_id": viewID}}
+	_, err := vr.database.Collection(vr.defaultCollection).UpdateOne(c, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (vr *viewRepository) FetchDefault(c context.Context, userID string) (string, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return "", domain.ErrNoTenant
+	}
+	var preference struct {
+		View_id string `bson:"view_id"`
+	}
+	err := vr.database.Collection(vr.defaultCollection).FindOne(c, bson.M{"org_id": orgID, "user_id": userID}).Decode(&preference)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return preference.View_id, nil
+}
+
+func (vr *viewRepository) ClearDefault(c context.Context, userID string) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	_, err := vr.database.Collection(vr.defaultCollection).DeleteOne(c, bson.M{"org_id": orgID, "user_id": userID})
+	return err
+}
+
+func (vr *viewRepository) DeletePrivateByOwner(c context.Context, userID string) error {
+	_, err := vr.database.Collection(vr.collection).DeleteMany(c, bson.M{"owner_id": userID, "visibility": domain.ViewPrivate})
+	if err != nil {
+		return err
+	}
+	_, err = vr.database.Collection(vr.defaultCollection).DeleteMany(c, bson.M{"user_id": userID})
+	return err
+}
+
+func (vr *viewRepository) byID(c context.Context, viewID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(viewID)
+	if err != nil {
+		return nil, domain.ErrViewNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
@@ -0,0 +1,350 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type webhookRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewWebhookRepository(db *mongo.Database, collection string) domain.WebhookRepository {
+	return &webhookRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (wr *webhookRepository) Create(c context.Context, webhook *domain.Webhook) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	webhook.ID = primitive.NewObjectID()
+	webhook.Org_id = orgID
+	webhook.Created_at = time.Now()
+	webhook.Updated_at = webhook.Created_at
+	_, err := wr.database.Collection(wr.collection).InsertOne(c, webhook)
+	return err
+}
+
+func (wr *webhookRepository) FetchAll(c context.Context) ([]domain.Webhook, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	return wr.find(c, bson.M{"org_id": orgID})
+}
+
+func (wr *webhookRepository) FetchSubscribed(c context.Context, event string) ([]domain.Webhook, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	return wr.find(c, bson.M{"org_id": orgID, "active": true, "events": event})
+}
+
+func (wr *webhookRepository) find(c context.Context, filter bson.M) ([]domain.Webhook, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := wr.database.Collection(wr.collection).Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	webhooks := []domain.Webhook{}
+	if err := cur.All(c, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (wr *webhookRepository) FetchByID(c context.Context, webhookID string) (domain.Webhook, error) {
+	filter, err := wr.byID(c, webhookID)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	var webhook domain.Webhook
+	err = wr.database.Collection(wr.collection).FindOne(c, filter).Decode(&webhook)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Webhook{}, domain.ErrWebhookNotFound
+	}
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (wr *webhookRepository) Update(c context.Context, webhook domain.Webhook) error {
+	filter, err := wr.byID(c, webhook.ID.Hex())
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{
+		"url":             webhook.URL,
+		"events":          webhook.Events,
+		"secret":          webhook.Secret,
+		"active":          webhook.Active,
+		"failure_count":   webhook.Failure_count,
+		"disabled_at":     webhook.Disabled_at,
+		"disabled_reason": webhook.Disabled_reason,
+		"updated_at":      webhook.Updated_at,
+	}}
+	result, err := wr.database.Collection(wr.collection).UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (wr *webhookRepository) Delete(c context.Context, webhookID string) error {
+	filter, err := wr.byID(c, webhookID)
+	if err != nil {
+		return err
+	}
+	result, err := wr.database.Collection(wr.collection).DeleteOne(c, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// RecordFailure increments the count atomically, so deliveries failing at
+// the same time each count, and only the one that crosses disableAfter
+// disables the webhook.
+func (wr *webhookRepository) RecordFailure(c context.Context, webhookID string, disableAfter int, at time.Time) (domain.Webhook, error) {
+	filter, err := wr.byID(c, webhookID)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	collection := wr.database.Collection(wr.collection)
+
+	var webhook domain.Webhook
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = collection.FindOneAndUpdate(c, filter, bson.M{"$inc": bson.M{"failure_count": 1}}, opts).Decode(&webhook)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Webhook{}, domain.ErrWebhookNotFound
+	}
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	if webhook.Failure_count < disableAfter || !webhook.Active {
+		return webhook, nil
+	}
+
+	reason := fmt.Sprintf("%d deliveries in a row failed", webhook.Failure_count)
+	filter["active"] = true
+	update := bson.M{"$set": bson.M{"active": false, "disabled_at": at, "disabled_reason": reason, "updated_at": at}}
+	result, err := collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	if result.ModifiedCount > 0 {
+		webhook.Active = false
+		webhook.Disabled_at = &at
+		webhook.Disabled_reason = reason
+	}
+	return webhook, nil
+}
+
+func (wr *webhookRepository) ResetFailures(c context.Context, webhookID string) error {
+	filter, err := wr.byID(c, webhookID)
+	if err != nil {
+		return err
+	}
+	filter["failure_count"] = bson.M{"$gt": 0}
+	_, err = wr.database.Collection(wr.collection).UpdateOne(c, filter, bson.M{"$set": bson.M{"failure_count": 0}})
+	return err
+}
+
+func (wr *webhookRepository) byID(c context.Context, webhookID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, domain.ErrWebhookNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
+
+type webhookDeliveryRepository struct {
+	database   *mongo.Database
+	collection string
+	indexMu    sync.Mutex
+	indexed    bool
+}
+
+func NewWebhookDeliveryRepository(db *mongo.Database, collection string) domain.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (dr *webhookDeliveryRepository) Create(c context.Context, delivery *domain.WebhookDelivery) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	if err := dr.ensureIndex(c); err != nil {
+		return err
+	}
+	delivery.ID = primitive.NewObjectID()
+	delivery.Org_id = orgID
+	delivery.Created_at = time.Now()
+	if delivery.Attempts == nil {
+		delivery.Attempts = []domain.WebhookAttempt{}
+	}
+	_, err := dr.database.Collection(dr.collection).InsertOne(c, delivery)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrWebhookDeliveryExists
+	}
+	return err
+}
+
+func (dr *webhookDeliveryRepository) FetchByID(c context.Context, deliveryID string) (domain.WebhookDelivery, error) {
+	filter, err := dr.byID(c, deliveryID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	var delivery domain.WebhookDelivery
+	err = dr.database.Collection(dr.collection).FindOne(c, filter).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.WebhookDelivery{}, domain.ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+func (dr *webhookDeliveryRepository) FetchByWebhook(c context.Context, webhookID string, limit int64) ([]domain.WebhookDelivery, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit)
+	cur, err := dr.database.Collection(dr.collection).Find(c, bson.M{"org_id": orgID, "webhook_id": webhookID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []domain.WebhookDelivery{}
+	if err := cur.All(c, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Update records the delivery's attempts and outcome.
+func (dr *webhookDeliveryRepository) Update(c context.Context, delivery domain.WebhookDelivery) error {
+	filter, err := dr.byID(c, delivery.ID.Hex())
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{
+		"status":       delivery.Status,
+		"attempts":     delivery.Attempts,
+		"completed_at": delivery.Completed_at,
+	}}
+	result, err := dr.database.Collection(dr.collection).UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
+
+func (dr *webhookDeliveryRepository) DeleteByWebhook(c context.Context, webhookID string) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	_, err := dr.database.Collection(dr.collection).DeleteMany(c, bson.M{"org_id": orgID, "webhook_id": webhookID})
+	return err
+}
+
+func (dr *webhookDeliveryRepository) FetchByEvent(c context.Context, webhookID string, eventID string) (domain.WebhookDelivery, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return domain.WebhookDelivery{}, domain.ErrNoTenant
+	}
+	filter := bson.M{"org_id": orgID, "webhook_id": webhookID, "event_id": eventID, "redelivery_of": bson.M{"$in": bson.A{"", nil}}}
+
+	var delivery domain.WebhookDelivery
+	err := dr.database.Collection(dr.collection).FindOne(c, filter).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.WebhookDelivery{}, domain.ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// FetchPending is not tenant scoped: it serves the sweep that resumes
+// deliveries after a restart.
+func (dr *webhookDeliveryRepository) FetchPending(c context.Context, cutoff time.Time) ([]domain.WebhookDelivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cur, err := dr.database.Collection(dr.collection).Find(c, bson.M{"status": domain.DeliveryPending, "created_at": bson.M{"$lt": cutoff}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []domain.WebhookDelivery{}
+	if err := cur.All(c, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ensureIndex allows one first delivery per webhook and event, so relays
+// publishing an event twice cannot both send it. Redeliveries are left out.
+func (dr *webhookDeliveryRepository) ensureIndex(c context.Context) error {
+	dr.indexMu.Lock()
+	defer dr.indexMu.Unlock()
+	if dr.indexed {
+		return nil
+	}
+	_, err := dr.database.Collection(dr.collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "event_id", Value: 1}},
+		Options: options.Index().SetName("webhook_delivery_event").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"redelivery_of": ""}),
+	})
+	if err != nil {
+		return err
+	}
+	dr.indexed = true
+	return nil
+}
+
+func (dr *webhookDeliveryRepository) byID(c context.Context, deliveryID string) (bson.M, error) {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	objID, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return nil, domain.ErrWebhookDeliveryNotFound
+	}
+	return bson.M{"_id": objID, "org_id": orgID}, nil
+}
@@ -0,0 +1,235 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const defaultMaxAttachmentSize = 10 << 20
+
+type attachmentUsecase struct {
+	attachmentRepository domain.AttachmentRepository
+	taskRepository       domain.TaskRepository
+	blobStore            domain.BlobStore
+	contextTimeout       time.Duration
+	// maxSize is the largest upload accepted, in bytes.
+	maxSize int64
+}
+
+func NewAttachmentUsecase(attachmentRepository domain.AttachmentRepository, taskRepository domain.TaskRepository, blobStore domain.BlobStore, timeout time.Duration) domain.AttachmentUsecase {
+	return &attachmentUsecase{
+		attachmentRepository: attachmentRepository,
+		taskRepository:       taskRepository,
+		blobStore:            blobStore,
+		contextTimeout:       timeout,
+		maxSize:              maxAttachmentSizeFromEnv(),
+	}
+}
+
+// maxAttachmentSizeFromEnv reads ATTACHMENT_MAX_BYTES, defaulting to 10 MiB.
+func maxAttachmentSizeFromEnv() int64 {
+	raw := os.Getenv("ATTACHMENT_MAX_BYTES")
+	if raw == "" {
+		return defaultMaxAttachmentSize
+	}
+	size, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || size <= 0 {
+		log.Printf("ignoring invalid ATTACHMENT_MAX_BYTES %q", raw)
+		return defaultMaxAttachmentSize
+	}
+	return size
+}
+
+// Upload streams r into the blob store while counting and hashing it, then
+// records the attachment. Nothing is kept when the upload is too large.
+func (au *attachmentUsecase) Upload(c context.Context, taskID string, filename string, r io.Reader, uploadedBy string) (*domain.Attachment, error) {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	if err := ensureTask(ctx, au.taskRepository, taskID); err != nil {
+		return nil, err
+	}
+	orgID, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	attachment := domain.Attachment{
+		ID:           primitive.NewObjectID(),
+		Task_id:      taskID,
+		Filename:     cleanFilename(filename),
+		Content_type: http.DetectContentType(head),
+		Uploaded_by:  uploadedBy,
+	}
+	attachment.Storage_key = path.Join("attachments", orgID, taskID, attachment.ID.Hex())
+
+	body := &measuredReader{r: io.MultiReader(bytes.NewReader(head), r), limit: au.maxSize, hash: sha256.New()}
+	if err := au.blobStore.Put(ctx, attachment.Storage_key, body); err != nil {
+		au.discard(attachment.Storage_key)
+		return nil, err
+	}
+	attachment.Size = body.size
+	attachment.Checksum = hex.EncodeToString(body.hash.Sum(nil))
+
+	if err := au.attachmentRepository.Create(ctx, &attachment); err != nil {
+		au.discard(attachment.Storage_key)
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (au *attachmentUsecase) FetchByTask(c context.Context, taskID string) (*[]domain.Attachment, error) {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	if err := ensureTask(ctx, au.taskRepository, taskID); err != nil {
+		return nil, err
+	}
+	attachments, err := au.attachmentRepository.FetchByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return &attachments, nil
+}
+
+// Open returns the attachment and its contents. The caller closes the reader.
+func (au *attachmentUsecase) Open(c context.Context, taskID string, attachmentID string) (*domain.Attachment, io.ReadSeekCloser, error) {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	attachment, err := au.fetchOnTask(ctx, taskID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the download outlives this call, so the blob is opened with the
+	// caller's context rather than the timeout one
+	blob, err := au.blobStore.Open(c, attachment.Storage_key)
+	if errors.Is(err, domain.ErrBlobNotFound) {
+		return nil, nil, domain.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &attachment, blob, nil
+}
+
+// Delete lets uploaders remove their own attachments, and moderators (org
+// owners and admins) remove anyone's.
+func (au *attachmentUsecase) Delete(c context.Context, taskID string, attachmentID string, actorID string, moderator bool) error {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	attachment, err := au.fetchOnTask(ctx, taskID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if attachment.Uploaded_by != actorID && !moderator {
+		return domain.ErrNotUploader
+	}
+	if err := au.blobStore.Delete(ctx, attachment.Storage_key); err != nil {
+		return err
+	}
+	return au.attachmentRepository.Delete(ctx, attachmentID)
+}
+
+func (au *attachmentUsecase) fetchOnTask(ctx context.Context, taskID string, attachmentID string) (domain.Attachment, error) {
+	attachment, err := au.attachmentRepository.FetchByID(ctx, attachmentID)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if attachment.Task_id != taskID {
+		return domain.Attachment{}, domain.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// discard removes a blob left by a failed upload. The request context may
+// already be done, so it gets a fresh one.
+func (au *attachmentUsecase) discard(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), au.contextTimeout)
+	defer cancel()
+	if err := au.blobStore.Delete(ctx, key); err != nil {
+		log.Printf("failed to discard blob %s: %v", key, err)
+	}
+}
+
+// purgeAttachments removes the attachments of a deleted task. Records whose
+// blob could not be removed are kept so the blob stays traceable.
+func purgeAttachments(ctx context.Context, attachmentRepository domain.AttachmentRepository, blobStore domain.BlobStore, taskID string) error {
+	attachments, err := attachmentRepository.FetchByTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, attachment := range attachments {
+		if err := blobStore.Delete(ctx, attachment.Storage_key); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := attachmentRepository.Delete(ctx, attachment.ID.Hex()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cleanFilename keeps the base name of an uploaded file, without control
+// characters, so it is safe to echo back in a Content-Disposition header.
+func cleanFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, filename)
+	if len(filename) > 255 {
+		filename = filename[:255]
+	}
+	if filename == "" || filename == "." || filename == "/" {
+		return "attachment"
+	}
+	return filename
+}
+
+// measuredReader counts and hashes what passes through it and fails with
+// ErrAttachmentTooLarge once more than limit bytes have been read.
+type measuredReader struct {
+	r     io.Reader
+	limit int64
+	size  int64
+	hash  hash.Hash
+}
+
+func (mr *measuredReader) Read(p []byte) (int, error) {
+	n, err := mr.r.Read(p)
+	mr.size += int64(n)
+	if mr.size > mr.limit {
+		return 0, domain.ErrAttachmentTooLarge
+	}
+	mr.hash.Write(p[:n])
+	return n, err
+}
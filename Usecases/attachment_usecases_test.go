@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type attachmentUsecaseSuite struct {
+	suite.Suite
+	attachments *mocks.AttachmentRepository
+	tasks       *mocks.TaskRepository
+	blobs       *mocks.BlobStore
+	usecase     *attachmentUsecase
+	ctx         context.Context
+}
+
+func (suite *attachmentUsecaseSuite) SetupTest() {
+	suite.attachments = new(mocks.AttachmentRepository)
+	suite.tasks = new(mocks.TaskRepository)
+	suite.blobs = new(mocks.BlobStore)
+	suite.usecase = NewAttachmentUsecase(suite.attachments, suite.tasks, suite.blobs, 10*time.Second).(*attachmentUsecase)
+	suite.usecase.maxSize = 1024
+	suite.ctx = domain.WithTenant(context.TODO(), "org1")
+
+	suite.tasks.On("FetchByTaskID", mock.Anything, "t1").Return(&domain.Task{}, nil).Maybe()
+}
+
+// drainBlob makes the BlobStore mock consume the upload like a real store.
+func (suite *attachmentUsecaseSuite) drainBlob(stored *bytes.Buffer) {
+	suite.blobs.On("Put", mock.Anything, mock.Anything, mock.Anything).Return(func(_ context.Context, _ string, r io.Reader) error {
+		_, err := io.Copy(stored, r)
+		return err
+	})
+}
+
+func (suite *attachmentUsecaseSuite) TestUpload_SniffsAndHashes() {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 600)...)
+	var stored bytes.Buffer
+	suite.drainBlob(&stored)
+	suite.attachments.On("Create", mock.Anything, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+
+	attachment, err := suite.usecase.Upload(suite.ctx, "t1", `C:\Users\me\shot.png`, bytes.NewReader(png), "u1")
+	suite.Require().NoError(err)
+
+	sum := sha256.Sum256(png)
+	suite.Equal("image/png", attachment.Content_type)
+	suite.Equal("shot.png", attachment.Filename)
+	suite.Equal(int64(len(png)), attachment.Size)
+	suite.Equal(hex.EncodeToString(sum[:]), attachment.Checksum)
+	suite.Equal("attachments/org1/t1/"+attachment.ID.Hex(), attachment.Storage_key)
+	suite.Equal(png, stored.Bytes())
+}
+
+func (suite *attachmentUsecaseSuite) TestUpload_TooLargeLeavesNothing() {
+	var stored bytes.Buffer
+	suite.drainBlob(&stored)
+	suite.blobs.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	_, err := suite.usecase.Upload(suite.ctx, "t1", "big.bin", bytes.NewReader(make([]byte, 2048)), "u1")
+	suite.ErrorIs(err, domain.ErrAttachmentTooLarge)
+	suite.blobs.AssertCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.attachments.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *attachmentUsecaseSuite) TestOpen_OtherTask() {
+	id := primitive.NewObjectID()
+	suite.attachments.On("FetchByID", mock.Anything, id.Hex()).Return(domain.Attachment{ID: id, Task_id: "t2"}, nil)
+
+	_, _, err := suite.usecase.Open(suite.ctx, "t1", id.Hex())
+	suite.ErrorIs(err, domain.ErrAttachmentNotFound)
+	suite.blobs.AssertNotCalled(suite.T(), "Open", mock.Anything, mock.Anything)
+}
+
+func (suite *attachmentUsecaseSuite) TestDelete_OnlyUploaderOrModerator() {
+	id := primitive.NewObjectID()
+	suite.attachments.On("FetchByID", mock.Anything, id.Hex()).Return(domain.Attachment{ID: id, Task_id: "t1", Uploaded_by: "u1", Storage_key: "k"}, nil)
+	suite.blobs.On("Delete", mock.Anything, "k").Return(nil)
+	suite.attachments.On("Delete", mock.Anything, id.Hex()).Return(nil)
+
+	suite.ErrorIs(suite.usecase.Delete(suite.ctx, "t1", id.Hex(), "u2", false), domain.ErrNotUploader)
+	suite.NoError(suite.usecase.Delete(suite.ctx, "t1", id.Hex(), "u2", true))
+	suite.blobs.AssertNumberOfCalls(suite.T(), "Delete", 1)
+}
+
+func TestAttachmentUsecase(t *testing.T) {
+	suite.Run(t, new(attachmentUsecaseSuite))
+}
@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	domain "task-manger-api_test/Domain"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"time"
+)
+
+// calendarRefresh is how often subscribed calendar clients are asked to
+// fetch the feed again.
+const calendarRefresh = "PT15M"
+
+type calendarUsecase struct {
+	calendarFeedRepository domain.CalendarFeedRepository
+	taskRepository         domain.TaskRepository
+	userRepository         domain.UserRepository
+	organizationRepository domain.OrganizationRepository
+	contextTimeout         time.Duration
+	// feedURL is prepended to the token to build the subscription URL,
+	// e.g. https://api.example.com/calendar/
+	feedURL string
+	now     func() time.Time
+}
+
+func NewCalendarUsecase(calendarFeedRepository domain.CalendarFeedRepository, taskRepository domain.TaskRepository, userRepository domain.UserRepository, organizationRepository domain.OrganizationRepository, timeout time.Duration) domain.CalendarUsecase {
+	feedURL := os.Getenv("CALENDAR_FEED_URL")
+	if feedURL == "" {
+		feedURL = "/calendar/"
+	}
+	return &calendarUsecase{
+		calendarFeedRepository: calendarFeedRepository,
+		taskRepository:         taskRepository,
+		userRepository:         userRepository,
+		organizationRepository: organizationRepository,
+		contextTimeout:         timeout,
+		feedURL:                feedURL,
+		now:                    time.Now,
+	}
+}
+
+// CreateFeed issues a new feed URL, revoking the user's previous one.
+func (cu *calendarUsecase) CreateFeed(c context.Context, userID string) (*domain.CalendarFeed, error) {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	token, hash, err := infrastructure.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	feed := domain.CalendarFeed{User_id: userID, Token_hash: hash}
+	if err := cu.calendarFeedRepository.Replace(ctx, &feed); err != nil {
+		return nil, err
+	}
+	feed.URL = cu.feedURL + token + ".ics"
+	return &feed, nil
+}
+
+func (cu *calendarUsecase) FetchFeed(c context.Context, userID string) (*domain.CalendarFeed, error) {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	feed, err := cu.calendarFeedRepository.FetchByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (cu *calendarUsecase) RevokeFeed(c context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+	return cu.calendarFeedRepository.Delete(ctx, userID)
+}
+
+// WriteFeed lists the tasks assigned to or created by the feed's owner
+// that have a due date, soonest first. It is built on every request, so
+// changes show up the next time the calendar client refreshes.
+func (cu *calendarUsecase) WriteFeed(c context.Context, token string, kind string, w io.Writer) error {
+	if kind == "" {
+		kind = domain.CalendarTodo
+	}
+	if kind != domain.CalendarTodo && kind != domain.CalendarEvent {
+		return fmt.Errorf("%w: unknown calendar entry type %q", domain.ErrInvalidTaskQuery, kind)
+	}
+
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	feed, err := cu.fetchUsable(ctx, token)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	tenant := domain.WithTenant(c, feed.Org_id)
+	owned := domain.FilterExpr{Kind: domain.FilterOr, Children: []domain.FilterExpr{
+		{Kind: domain.FilterCompare, Field: "assignee_id", Operator: domain.FilterEq, Values: []interface{}{feed.User_id}},
+		{Kind: domain.FilterCompare, Field: "created_by", Operator: domain.FilterEq, Values: []interface{}{feed.User_id}},
+	}}
+	ical := newICalWriter(w, cu.now())
+	ical.begin("Tasks")
+	ical.line("REFRESH-INTERVAL;VALUE=DURATION", calendarRefresh)
+	ical.line("X-PUBLISHED-TTL", calendarRefresh)
+	err = cu.taskRepository.Stream(tenant, domain.TaskFilter{Expression: &owned, Sort_by: "duedate"}, func(task domain.Task) error {
+		if task.DueDate.IsZero() {
+			return nil
+		}
+		fillCompletion(&task)
+		if kind == domain.CalendarEvent {
+			return ical.event(task)
+		}
+		return ical.todo(task)
+	})
+	if err != nil {
+		return err
+	}
+	if err := ical.end(); err != nil {
+		return err
+	}
+
+	touchCtx, cancel := context.WithTimeout(tenant, cu.contextTimeout)
+	defer cancel()
+	if err := cu.calendarFeedRepository.Touch(touchCtx, feed.ID.Hex(), cu.now()); err != nil {
+		log.Printf("failed to record use of calendar feed %s: %v", feed.ID.Hex(), err)
+	}
+	return nil
+}
+
+// fetchUsable finds the token's feed, treating feeds of deactivated users
+// and of users who left the organization as revoked.
+func (cu *calendarUsecase) fetchUsable(ctx context.Context, token string) (domain.CalendarFeed, error) {
+	if token == "" {
+		return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+	}
+	feed, err := cu.calendarFeedRepository.FetchByTokenHash(ctx, infrastructure.HashOpaqueToken(token))
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+	user, err := cu.userRepository.FetchByUserID(ctx, feed.User_id)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+	if !user.IsActive() || user.Erased_at != nil {
+		return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+	}
+	_, err = cu.organizationRepository.FetchMembership(ctx, feed.Org_id, feed.User_id)
+	if errors.Is(err, domain.ErrNotMember) {
+		return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+	return feed, nil
+}
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type calendarUsecaseSuite struct {
+	suite.Suite
+	feeds   *mocks.CalendarFeedRepository
+	tasks   *mocks.TaskRepository
+	users   *mocks.UserRepository
+	orgs    *mocks.OrganizationRepository
+	usecase *calendarUsecase
+	feed    domain.CalendarFeed
+}
+
+func (suite *calendarUsecaseSuite) SetupTest() {
+	suite.feeds = new(mocks.CalendarFeedRepository)
+	suite.tasks = new(mocks.TaskRepository)
+	suite.users = new(mocks.UserRepository)
+	suite.orgs = new(mocks.OrganizationRepository)
+	suite.usecase = NewCalendarUsecase(suite.feeds, suite.tasks, suite.users, suite.orgs, 10*time.Second).(*calendarUsecase)
+	suite.usecase.feedURL = "https://api.example.com/calendar/"
+
+	suite.feed = domain.CalendarFeed{ID: primitive.NewObjectID(), Org_id: "org1", User_id: "u1", Token_hash: infrastructure.HashOpaqueToken("secret")}
+	suite.feeds.On("FetchByTokenHash", mock.Anything, suite.feed.Token_hash).Return(suite.feed, nil).Maybe()
+	suite.feeds.On("FetchByTokenHash", mock.Anything, mock.Anything).Return(domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound).Maybe()
+	suite.feeds.On("Touch", mock.Anything, suite.feed.ID.Hex(), mock.Anything).Return(nil).Maybe()
+}
+
+func (suite *calendarUsecaseSuite) member(active bool, member bool) {
+	user := domain.User{User_id: "u1"}
+	if !active {
+		now := time.Now()
+		user.Deactivated_at = &now
+	}
+	suite.users.On("FetchByUserID", mock.Anything, "u1").Return(user, nil)
+	if member {
+		suite.orgs.On("FetchMembership", mock.Anything, "org1", "u1").Return(domain.Membership{}, nil).Maybe()
+	} else {
+		suite.orgs.On("FetchMembership", mock.Anything, "org1", "u1").Return(domain.Membership{}, domain.ErrNotMember).Maybe()
+	}
+}
+
+func (suite *calendarUsecaseSuite) TestCreateFeed_StoresOnlyTheHash() {
+	var stored domain.CalendarFeed
+	suite.feeds.On("Replace", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = *args.Get(1).(*domain.CalendarFeed)
+	}).Return(nil)
+
+	feed, err := suite.usecase.CreateFeed(context.TODO(), "u1")
+	suite.Require().NoError(err)
+	token, ok := strings.CutPrefix(feed.URL, "https://api.example.com/calendar/")
+	suite.Require().True(ok)
+	token, ok = strings.CutSuffix(token, ".ics")
+	suite.Require().True(ok)
+	suite.Equal(infrastructure.HashOpaqueToken(token), stored.Token_hash)
+	suite.Equal("u1", stored.User_id)
+	suite.Empty(stored.URL)
+}
+
+func (suite *calendarUsecaseSuite) TestWriteFeed_ListsOwnTasksWithDueDates() {
+	suite.member(true, true)
+	due := time.Date(2026, 11, 3, 17, 0, 0, 0, time.UTC)
+	suite.tasks.On("Stream", mock.Anything, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+		return filter.Expression != nil && filter.Expression.Kind == domain.FilterOr && filter.Sort_by == "duedate"
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		orgID, _ := domain.TenantFromContext(ctx)
+		suite.Equal("org1", orgID, "tasks are read in the feed's organization")
+		fn := args.Get(2).(func(domain.Task) error)
+		fn(domain.Task{ID: primitive.NewObjectID(), Title: "Due soon", DueDate: due})
+		fn(domain.Task{ID: primitive.NewObjectID(), Title: "Someday"})
+	}).Return(nil)
+
+	var out bytes.Buffer
+	suite.Require().NoError(suite.usecase.WriteFeed(context.TODO(), "secret", "", &out))
+	suite.Contains(out.String(), "REFRESH-INTERVAL;VALUE=DURATION:PT15M\r\n")
+	suite.Contains(out.String(), "SUMMARY:Due soon\r\nDUE:20261103T170000Z\r\n")
+	suite.NotContains(out.String(), "Someday")
+	suite.feeds.AssertCalled(suite.T(), "Touch", mock.Anything, suite.feed.ID.Hex(), mock.Anything)
+
+	out.Reset()
+	suite.Require().NoError(suite.usecase.WriteFeed(context.TODO(), "secret", domain.CalendarEvent, &out))
+	suite.Contains(out.String(), "BEGIN:VEVENT\r\n")
+	suite.Contains(out.String(), "DTSTART;VALUE=DATE:20261103\r\nDTEND;VALUE=DATE:20261104\r\n")
+}
+
+func (suite *calendarUsecaseSuite) TestWriteFeed_RevokedOrLostAccess() {
+	var out bytes.Buffer
+	suite.ErrorIs(suite.usecase.WriteFeed(context.TODO(), "guess", "", &out), domain.ErrCalendarFeedNotFound)
+	suite.ErrorIs(suite.usecase.WriteFeed(context.TODO(), "secret", "agenda", &out), domain.ErrInvalidTaskQuery)
+
+	suite.member(true, false)
+	suite.ErrorIs(suite.usecase.WriteFeed(context.TODO(), "secret", "", &out), domain.ErrCalendarFeedNotFound)
+	suite.Zero(out.Len())
+	suite.tasks.AssertNotCalled(suite.T(), "Stream", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *calendarUsecaseSuite) TestWriteFeed_DeactivatedUser() {
+	suite.member(false, true)
+	var out bytes.Buffer
+	suite.ErrorIs(suite.usecase.WriteFeed(context.TODO(), "secret", "", &out), domain.ErrCalendarFeedNotFound)
+	suite.Zero(out.Len())
+}
+
+func TestCalendarUsecase(t *testing.T) {
+	suite.Run(t, new(calendarUsecaseSuite))
+}
@@ -0,0 +1,218 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxMentions caps how many users one comment can notify.
+const maxMentions = 20
+
+type commentUsecase struct {
+	commentRepository      domain.CommentRepository
+	taskRepository         domain.TaskRepository
+	userRepository         domain.UserRepository
+	organizationRepository domain.OrganizationRepository
+	contextTimeout         time.Duration
+}
+
+func NewCommentUsecase(commentRepository domain.CommentRepository, taskRepository domain.TaskRepository, userRepository domain.UserRepository, organizationRepository domain.OrganizationRepository, timeout time.Duration) domain.CommentUsecase {
+	return &commentUsecase{
+		commentRepository:      commentRepository,
+		taskRepository:         taskRepository,
+		userRepository:         userRepository,
+		organizationRepository: organizationRepository,
+		contextTimeout:         timeout,
+	}
+}
+
+func (cu *commentUsecase) Create(c context.Context, taskID string, comment *domain.Comment) error {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	if err := ensureTask(ctx, cu.taskRepository, taskID); err != nil {
+		return err
+	}
+	if comment.Parent_id != "" {
+		parent, err := cu.fetchOnTask(ctx, taskID, comment.Parent_id)
+		if err != nil {
+			return err
+		}
+		// replies to a reply join the thread of its top-level comment
+		if parent.Parent_id != "" {
+			comment.Parent_id = parent.Parent_id
+		}
+	}
+
+	mentions, err := cu.resolveMentions(ctx, comment.Body)
+	if err != nil {
+		return err
+	}
+	comment.Task_id = taskID
+	comment.Mentions = mentions
+	comment.Edited_at = nil
+	comment.Deleted_at = nil
+	if err := cu.commentRepository.Create(ctx, comment); err != nil {
+		return err
+	}
+	render(comment)
+	return nil
+}
+
+// FetchByTask pages through top-level comments, each with all its replies.
+func (cu *commentUsecase) FetchByTask(c context.Context, taskID string, page int64, limit int64) (*domain.CommentPage, error) {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	if err := ensureTask(ctx, cu.taskRepository, taskID); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	threads, total, err := cu.commentRepository.FetchThreads(ctx, taskID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(threads) > 0 {
+		parentIDs := make([]string, len(threads))
+		for i := range threads {
+			parentIDs[i] = threads[i].ID.Hex()
+		}
+		replies, err := cu.commentRepository.FetchReplies(ctx, parentIDs)
+		if err != nil {
+			return nil, err
+		}
+		byParent := make(map[string][]domain.Comment, len(threads))
+		for i := range replies {
+			render(&replies[i])
+			byParent[replies[i].Parent_id] = append(byParent[replies[i].Parent_id], replies[i])
+		}
+		for i := range threads {
+			render(&threads[i])
+			threads[i].Replies = byParent[threads[i].ID.Hex()]
+		}
+	}
+	return &domain.CommentPage{Comments: threads, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (cu *commentUsecase) Update(c context.Context, taskID string, commentID string, authorID string, body string) (*domain.Comment, error) {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	comment, err := cu.fetchOnTask(ctx, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Author_id != authorID {
+		return nil, domain.ErrNotCommentAuthor
+	}
+
+	mentions, err := cu.resolveMentions(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	editedAt := time.Now()
+	if err := cu.commentRepository.Update(ctx, commentID, body, mentions, editedAt); err != nil {
+		return nil, err
+	}
+	comment.Body = body
+	comment.Mentions = mentions
+	comment.Edited_at = &editedAt
+	render(&comment)
+	return &comment, nil
+}
+
+// Delete lets authors remove their own comments, and moderators (org owners
+// and admins) remove anyone's.
+func (cu *commentUsecase) Delete(c context.Context, taskID string, commentID string, actorID string, moderator bool) error {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	comment, err := cu.fetchOnTask(ctx, taskID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.Author_id != actorID && !moderator {
+		return domain.ErrNotCommentAuthor
+	}
+	return cu.commentRepository.Delete(ctx, commentID, time.Now())
+}
+
+// ensureTask checks taskID names a task in the active organization.
+func ensureTask(ctx context.Context, taskRepository domain.TaskRepository, taskID string) error {
+	_, err := taskRepository.FetchByTaskID(ctx, taskID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ErrTaskNotFound
+	}
+	return err
+}
+
+// fetchOnTask loads a live comment, treating comments on other tasks as
+// missing.
+func (cu *commentUsecase) fetchOnTask(ctx context.Context, taskID string, commentID string) (domain.Comment, error) {
+	comment, err := cu.commentRepository.FetchByID(ctx, commentID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if comment.Task_id != taskID || comment.Deleted_at != nil {
+		return domain.Comment{}, domain.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// resolveMentions maps @usernames in body to the IDs of members of the
+// active organization. Other usernames, known or not, are left as plain
+// text, so a comment cannot notify or reveal users of other organizations.
+func (cu *commentUsecase) resolveMentions(ctx context.Context, body string) ([]string, error) {
+	userIDs := []string{}
+	usernames := infrastructure.ExtractMentions(body)
+	if len(usernames) == 0 {
+		return userIDs, nil
+	}
+	orgID, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	for _, username := range usernames {
+		if len(userIDs) == maxMentions {
+			break
+		}
+		user, err := cu.userRepository.FindByUsername(ctx, username)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, err = cu.organizationRepository.FetchMembership(ctx, orgID, user.User_id)
+		if errors.Is(err, domain.ErrNotMember) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, user.User_id)
+	}
+	return userIDs, nil
+}
+
+func render(comment *domain.Comment) {
+	if comment.Deleted_at != nil {
+		comment.Html = ""
+		return
+	}
+	comment.Html = infrastructure.RenderMarkdown(comment.Body)
+}
@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type commentUsecaseSuite struct {
+	suite.Suite
+	comments *mocks.CommentRepository
+	tasks    *mocks.TaskRepository
+	users    *mocks.UserRepository
+	orgs     *mocks.OrganizationRepository
+	usecase  domain.CommentUsecase
+}
+
+func (suite *commentUsecaseSuite) SetupTest() {
+	suite.comments = new(mocks.CommentRepository)
+	suite.tasks = new(mocks.TaskRepository)
+	suite.users = new(mocks.UserRepository)
+	suite.orgs = new(mocks.OrganizationRepository)
+	suite.usecase = NewCommentUsecase(suite.comments, suite.tasks, suite.users, suite.orgs, 10*time.Second)
+
+	suite.tasks.On("FetchByTaskID", mock.Anything, "t1").Return(&domain.Task{}, nil).Maybe()
+	suite.tasks.On("FetchByTaskID", mock.Anything, "missing").Return(nil, mongo.ErrNoDocuments).Maybe()
+}
+
+func (suite *commentUsecaseSuite) TestCreate_ResolvesMentionsAndRenders() {
+	suite.users.On("FindByUsername", mock.Anything, "alice").Return(domain.User{User_id: "u-alice"}, nil)
+	suite.users.On("FindByUsername", mock.Anything, "nobody").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.users.On("FindByUsername", mock.Anything, "mallory").Return(domain.User{User_id: "u-mallory"}, nil)
+	suite.orgs.On("FetchMembership", mock.Anything, "org1", "u-alice").Return(domain.Membership{Org_id: "org1", User_id: "u-alice"}, nil)
+	suite.orgs.On("FetchMembership", mock.Anything, "org1", "u-mallory").Return(domain.Membership{}, domain.ErrNotMember)
+	suite.comments.On("Create", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil)
+
+	comment := domain.Comment{Author_id: "u1", Body: "**ping** @alice, @mallory and @nobody <script>alert(1)</script>"}
+	err := suite.usecase.Create(domain.WithTenant(context.TODO(), "org1"), "t1", &comment)
+	suite.NoError(err)
+	suite.Equal("t1", comment.Task_id)
+	suite.Equal([]string{"u-alice"}, comment.Mentions)
+	suite.Contains(comment.Html, "<strong>ping</strong>")
+	suite.NotContains(comment.Html, "<script>")
+}
+
+func (suite *commentUsecaseSuite) TestCreate_ReplyToReplyJoinsRootThread() {
+	root := primitive.NewObjectID().Hex()
+	reply := primitive.NewObjectID()
+	suite.comments.On("FetchByID", mock.Anything, reply.Hex()).Return(domain.Comment{ID: reply, Task_id: "t1", Parent_id: root}, nil)
+	suite.comments.On("Create", mock.Anything, mock.MatchedBy(func(comment *domain.Comment) bool {
+		return comment.Parent_id == root
+	})).Return(nil)
+
+	err := suite.usecase.Create(context.TODO(), "t1", &domain.Comment{Author_id: "u1", Body: "agreed", Parent_id: reply.Hex()})
+	suite.NoError(err)
+	suite.comments.AssertExpectations(suite.T())
+}
+
+func (suite *commentUsecaseSuite) TestCreate_ParentOnAnotherTask() {
+	parent := primitive.NewObjectID()
+	suite.comments.On("FetchByID", mock.Anything, parent.Hex()).Return(domain.Comment{ID: parent, Task_id: "t2"}, nil)
+
+	err := suite.usecase.Create(context.TODO(), "t1", &domain.Comment{Author_id: "u1", Body: "hi", Parent_id: parent.Hex()})
+	suite.ErrorIs(err, domain.ErrCommentNotFound)
+	suite.comments.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *commentUsecaseSuite) TestCreate_UnknownTask() {
+	err := suite.usecase.Create(context.TODO(), "missing", &domain.Comment{Author_id: "u1", Body: "hi"})
+	suite.ErrorIs(err, domain.ErrTaskNotFound)
+}
+
+func (suite *commentUsecaseSuite) TestFetchByTask_AttachesReplies() {
+	root := primitive.NewObjectID()
+	now := time.Now()
+	suite.comments.On("FetchThreads", mock.Anything, "t1", int64(1), int64(100)).Return([]domain.Comment{{ID: root, Task_id: "t1", Body: "gone", Deleted_at: &now}}, int64(1), nil)
+	suite.comments.On("FetchReplies", mock.Anything, []string{root.Hex()}).Return([]domain.Comment{{Task_id: "t1", Parent_id: root.Hex(), Body: "_reply_"}}, nil)
+
+	page, err := suite.usecase.FetchByTask(context.TODO(), "t1", 0, 500)
+	suite.Require().NoError(err)
+	suite.Equal(int64(100), page.Limit)
+	suite.Require().Len(page.Comments, 1)
+	suite.Empty(page.Comments[0].Html, "deleted comments keep their place but render nothing")
+	suite.Require().Len(page.Comments[0].Replies, 1)
+	suite.Contains(page.Comments[0].Replies[0].Html, "<em>reply</em>")
+}
+
+func (suite *commentUsecaseSuite) TestUpdate_OnlyAuthor() {
+	id := primitive.NewObjectID()
+	suite.comments.On("FetchByID", mock.Anything, id.Hex()).Return(domain.Comment{ID: id, Task_id: "t1", Author_id: "u1"}, nil)
+
+	_, err := suite.usecase.Update(context.TODO(), "t1", id.Hex(), "u2", "edited")
+	suite.ErrorIs(err, domain.ErrNotCommentAuthor)
+	suite.comments.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *commentUsecaseSuite) TestUpdate_SetsEditedAt() {
+	id := primitive.NewObjectID()
+	suite.comments.On("FetchByID", mock.Anything, id.Hex()).Return(domain.Comment{ID: id, Task_id: "t1", Author_id: "u1"}, nil)
+	suite.comments.On("Update", mock.Anything, id.Hex(), "edited", []string{}, mock.Anything).Return(nil)
+
+	comment, err := suite.usecase.Update(context.TODO(), "t1", id.Hex(), "u1", "edited")
+	suite.Require().NoError(err)
+	suite.NotNil(comment.Edited_at)
+	suite.Equal("edited", comment.Body)
+}
+
+func (suite *commentUsecaseSuite) TestDelete_ModeratorMayRemoveOthers() {
+	id := primitive.NewObjectID()
+	suite.comments.On("FetchByID", mock.Anything, id.Hex()).Return(domain.Comment{ID: id, Task_id: "t1", Author_id: "u1"}, nil)
+	suite.comments.On("Delete", mock.Anything, id.Hex(), mock.Anything).Return(nil)
+
+	suite.ErrorIs(suite.usecase.Delete(context.TODO(), "t1", id.Hex(), "u2", false), domain.ErrNotCommentAuthor)
+	suite.NoError(suite.usecase.Delete(context.TODO(), "t1", id.Hex(), "u2", true))
+	suite.comments.AssertNumberOfCalls(suite.T(), "Delete", 1)
+}
+
+func TestCommentUsecase(t *testing.T) {
+	suite.Run(t, new(commentUsecaseSuite))
+}
@@ -0,0 +1,328 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxCustomTextLength = 1000
+
+var customFieldKey = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type customFieldUsecase struct {
+	customFieldRepository  domain.CustomFieldRepository
+	taskRepository         domain.TaskRepository
+	organizationRepository domain.OrganizationRepository
+	contextTimeout         time.Duration
+}
+
+func NewCustomFieldUsecase(customFieldRepository domain.CustomFieldRepository, taskRepository domain.TaskRepository, organizationRepository domain.OrganizationRepository, timeout time.Duration) domain.CustomFieldUsecase {
+	return &customFieldUsecase{
+		customFieldRepository:  customFieldRepository,
+		taskRepository:         taskRepository,
+		organizationRepository: organizationRepository,
+		contextTimeout:         timeout,
+	}
+}
+
+// Create adds a field. A required field needs a default when tasks already
+// exist, and the default is written to them.
+func (cu *customFieldUsecase) Create(c context.Context, field *domain.CustomField) error {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	if !customFieldKey.MatchString(field.Key) {
+		return invalidCustomField(field.Key, "keys use lowercase letters, digits and underscores, starting with a letter")
+	}
+	if err := cu.checkDefinition(ctx, field); err != nil {
+		return err
+	}
+	if field.Required {
+		if err := cu.checkCanRequire(ctx, *field); err != nil {
+			return err
+		}
+	}
+	if err := cu.customFieldRepository.Create(ctx, field); err != nil {
+		return err
+	}
+	if field.Required && field.Default != nil {
+		return cu.taskRepository.BackfillCustomField(ctx, field.Key, field.Default)
+	}
+	return nil
+}
+
+func (cu *customFieldUsecase) FetchAll(c context.Context) (*[]domain.CustomField, error) {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	fields, err := cu.customFieldRepository.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &fields, nil
+}
+
+// Update changes a field's definition without invalidating existing tasks:
+// enum options still in use cannot be removed, and a field only becomes
+// required once every task has a value.
+func (cu *customFieldUsecase) Update(c context.Context, fieldID string, update domain.CustomFieldUpdate) (*domain.CustomField, error) {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	field, err := cu.customFieldRepository.FetchByID(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	wasRequired := field.Required
+
+	if update.Name != nil {
+		field.Name = *update.Name
+	}
+	if update.Required != nil {
+		field.Required = *update.Required
+	}
+	if update.Default != nil {
+		field.Default = update.Default
+	}
+	if update.Options != nil {
+		removed := []interface{}{}
+		for _, option := range field.Options {
+			if !containsString(*update.Options, option) {
+				removed = append(removed, option)
+			}
+		}
+		if len(removed) > 0 {
+			inUse, err := cu.taskRepository.CountCustomFieldValues(ctx, field.Key, removed)
+			if err != nil {
+				return nil, err
+			}
+			if inUse > 0 {
+				return nil, domain.ErrCustomFieldInUse
+			}
+		}
+		field.Options = *update.Options
+	}
+
+	if err := cu.checkDefinition(ctx, &field); err != nil {
+		return nil, err
+	}
+	becomesRequired := field.Required && !wasRequired
+	if becomesRequired {
+		if err := cu.checkCanRequire(ctx, field); err != nil {
+			return nil, err
+		}
+	}
+	if err := cu.customFieldRepository.Update(ctx, field); err != nil {
+		return nil, err
+	}
+	if becomesRequired && field.Default != nil {
+		if err := cu.taskRepository.BackfillCustomField(ctx, field.Key, field.Default); err != nil {
+			return nil, err
+		}
+	}
+	return &field, nil
+}
+
+// Delete removes the definition and its values from every task, so the key
+// can later be reused with another type.
+func (cu *customFieldUsecase) Delete(c context.Context, fieldID string) error {
+	ctx, cancel := context.WithTimeout(c, cu.contextTimeout)
+	defer cancel()
+
+	field, err := cu.customFieldRepository.FetchByID(ctx, fieldID)
+	if err != nil {
+		return err
+	}
+	if err := cu.customFieldRepository.Delete(ctx, fieldID); err != nil {
+		return err
+	}
+	return cu.taskRepository.UnsetCustomField(ctx, field.Key)
+}
+
+// checkDefinition validates options and default against the field type,
+// normalizing the default to its stored form.
+func (cu *customFieldUsecase) checkDefinition(ctx context.Context, field *domain.CustomField) error {
+	if field.Type == domain.CustomFieldEnum {
+		if len(field.Options) == 0 {
+			return invalidCustomField(field.Key, "enum fields need at least one option")
+		}
+		seen := map[string]bool{}
+		for _, option := range field.Options {
+			if strings.TrimSpace(option) == "" || seen[option] {
+				return invalidCustomField(field.Key, "enum options must be distinct and not blank")
+			}
+			seen[option] = true
+		}
+	} else if len(field.Options) > 0 {
+		return invalidCustomField(field.Key, "only enum fields take options")
+	}
+
+	if field.Default == nil {
+		return nil
+	}
+	value, err := customFieldValue(ctx, cu.organizationRepository, *field, field.Default)
+	if err != nil {
+		return err
+	}
+	field.Default = value
+	return nil
+}
+
+func (cu *customFieldUsecase) checkCanRequire(ctx context.Context, field domain.CustomField) error {
+	if field.Default != nil {
+		return nil
+	}
+	missing, err := cu.taskRepository.CountMissingCustomField(ctx, field.Key)
+	if err != nil {
+		return err
+	}
+	if missing > 0 {
+		return invalidCustomField(field.Key, "a default is needed to require this field while tasks have no value")
+	}
+	return nil
+}
+
+// resolveCustomFields checks a task's custom field values against the
+// organization's fields. It drops nulls, fills defaults, enforces required
+// fields and returns the values in their stored form.
+func resolveCustomFields(ctx context.Context, organizationRepository domain.OrganizationRepository, fields []domain.CustomField, values map[string]interface{}) (map[string]interface{}, error) {
+	byKey := make(map[string]domain.CustomField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	resolved := make(map[string]interface{}, len(values))
+	for key, raw := range values {
+		field, ok := byKey[key]
+		if !ok {
+			return nil, invalidCustomField(key, "no such field")
+		}
+		if raw == nil {
+			continue
+		}
+		value, err := customFieldValue(ctx, organizationRepository, field, raw)
+		if err != nil {
+			return nil, err
+		}
+		resolved[key] = value
+	}
+	for _, field := range fields {
+		if _, ok := resolved[field.Key]; ok {
+			continue
+		}
+		if field.Default != nil {
+			resolved[field.Key] = field.Default
+		} else if field.Required {
+			return nil, invalidCustomField(field.Key, "a value is required")
+		}
+	}
+	return resolved, nil
+}
+
+// customFieldValue checks raw against the field type. Numbers are stored as
+// float64 and dates as UTC times so both sort correctly.
+func customFieldValue(ctx context.Context, organizationRepository domain.OrganizationRepository, field domain.CustomField, raw interface{}) (interface{}, error) {
+	switch field.Type {
+	case domain.CustomFieldText:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, invalidCustomField(field.Key, "expected text")
+		}
+		if field.Required && strings.TrimSpace(text) == "" {
+			return nil, invalidCustomField(field.Key, "a value is required")
+		}
+		if len(text) > maxCustomTextLength {
+			return nil, invalidCustomField(field.Key, fmt.Sprintf("text is limited to %d bytes", maxCustomTextLength))
+		}
+		return text, nil
+	case domain.CustomFieldNumber:
+		switch number := raw.(type) {
+		case float64:
+			return number, nil
+		case int:
+			return float64(number), nil
+		case int32:
+			return float64(number), nil
+		case int64:
+			return float64(number), nil
+		}
+		return nil, invalidCustomField(field.Key, "expected a number")
+	case domain.CustomFieldEnum:
+		option, ok := raw.(string)
+		if !ok || !containsString(field.Options, option) {
+			return nil, invalidCustomField(field.Key, "expected one of "+strings.Join(field.Options, ", "))
+		}
+		return option, nil
+	case domain.CustomFieldDate:
+		switch date := raw.(type) {
+		case time.Time:
+			return date.UTC(), nil
+		case primitive.DateTime:
+			return date.Time().UTC(), nil
+		case string:
+			parsed, err := parseCustomDate(date)
+			if err != nil {
+				return nil, invalidCustomField(field.Key, "expected a date like 2006-01-02")
+			}
+			return parsed, nil
+		}
+		return nil, invalidCustomField(field.Key, "expected a date like 2006-01-02")
+	case domain.CustomFieldUser:
+		userID, ok := raw.(string)
+		if !ok || userID == "" {
+			return nil, invalidCustomField(field.Key, "expected a user ID")
+		}
+		orgID, ok := domain.TenantFromContext(ctx)
+		if !ok {
+			return nil, domain.ErrNoTenant
+		}
+		if _, err := organizationRepository.FetchMembership(ctx, orgID, userID); err != nil {
+			if errors.Is(err, domain.ErrNotMember) {
+				return nil, invalidCustomField(field.Key, "the user is not a member of this organization")
+			}
+			return nil, err
+		}
+		return userID, nil
+	}
+	return nil, invalidCustomField(field.Key, "unknown field type")
+}
+
+// customFieldQueryValue parses a GET /tasks query parameter for field.
+func customFieldQueryValue(field domain.CustomField, raw string) (interface{}, error) {
+	switch field.Type {
+	case domain.CustomFieldNumber:
+		return strconv.ParseFloat(raw, 64)
+	case domain.CustomFieldDate:
+		return parseCustomDate(raw)
+	}
+	return raw, nil
+}
+
+func parseCustomDate(raw string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, raw); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, raw)
+	return date.UTC(), err
+}
+
+func invalidCustomField(key string, reason string) error {
+	return fmt.Errorf("%w %q: %s", domain.ErrInvalidCustomField, key, reason)
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
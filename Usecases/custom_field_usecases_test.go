@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type customFieldUsecaseSuite struct {
+	suite.Suite
+	fields  *mocks.CustomFieldRepository
+	tasks   *mocks.TaskRepository
+	orgs    *mocks.OrganizationRepository
+	usecase domain.CustomFieldUsecase
+	ctx     context.Context
+}
+
+func (suite *customFieldUsecaseSuite) SetupTest() {
+	suite.fields = new(mocks.CustomFieldRepository)
+	suite.tasks = new(mocks.TaskRepository)
+	suite.orgs = new(mocks.OrganizationRepository)
+	suite.usecase = NewCustomFieldUsecase(suite.fields, suite.tasks, suite.orgs, 10*time.Second)
+	suite.ctx = domain.WithTenant(context.TODO(), "org1")
+}
+
+func (suite *customFieldUsecaseSuite) TestCreate_InvalidDefinitions() {
+	for _, field := range []domain.CustomField{
+		{Key: "Priority", Type: domain.CustomFieldText},
+		{Key: "priority", Type: domain.CustomFieldEnum},
+		{Key: "priority", Type: domain.CustomFieldEnum, Options: []string{"low", "low"}},
+		{Key: "points", Type: domain.CustomFieldNumber, Options: []string{"1"}},
+		{Key: "points", Type: domain.CustomFieldNumber, Default: "three"},
+		{Key: "due", Type: domain.CustomFieldDate, Default: "next week"},
+	} {
+		err := suite.usecase.Create(suite.ctx, &field)
+		suite.ErrorIs(err, domain.ErrInvalidCustomField, field)
+	}
+	suite.fields.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *customFieldUsecaseSuite) TestCreate_RequiredBackfillsDefault() {
+	field := domain.CustomField{Key: "points", Type: domain.CustomFieldNumber, Required: true, Default: 3}
+	suite.fields.On("Create", mock.Anything, &field).Return(nil)
+	suite.tasks.On("BackfillCustomField", mock.Anything, "points", float64(3)).Return(nil)
+
+	suite.Require().NoError(suite.usecase.Create(suite.ctx, &field))
+	suite.Equal(float64(3), field.Default, "defaults are stored in their typed form")
+	suite.tasks.AssertExpectations(suite.T())
+}
+
+func (suite *customFieldUsecaseSuite) TestCreate_RequiredWithoutDefault() {
+	suite.tasks.On("CountMissingCustomField", mock.Anything, "points").Return(int64(2), nil)
+
+	err := suite.usecase.Create(suite.ctx, &domain.CustomField{Key: "points", Type: domain.CustomFieldNumber, Required: true})
+	suite.ErrorIs(err, domain.ErrInvalidCustomField)
+	suite.fields.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *customFieldUsecaseSuite) TestUpdate_RemovingUsedOption() {
+	field := domain.CustomField{ID: primitive.NewObjectID(), Key: "priority", Type: domain.CustomFieldEnum, Options: []string{"low", "high"}}
+	suite.fields.On("FetchByID", mock.Anything, field.ID.Hex()).Return(field, nil)
+	suite.tasks.On("CountCustomFieldValues", mock.Anything, "priority", []interface{}{"high"}).Return(int64(1), nil)
+
+	_, err := suite.usecase.Update(suite.ctx, field.ID.Hex(), domain.CustomFieldUpdate{Options: &[]string{"low"}})
+	suite.ErrorIs(err, domain.ErrCustomFieldInUse)
+	suite.fields.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (suite *customFieldUsecaseSuite) TestUpdate_BecomesRequired() {
+	field := domain.CustomField{ID: primitive.NewObjectID(), Key: "priority", Type: domain.CustomFieldEnum, Options: []string{"low", "high"}}
+	required := true
+	suite.fields.On("FetchByID", mock.Anything, field.ID.Hex()).Return(field, nil)
+	suite.fields.On("Update", mock.Anything, mock.MatchedBy(func(saved domain.CustomField) bool {
+		return saved.Required && saved.Default == "low"
+	})).Return(nil)
+	suite.tasks.On("BackfillCustomField", mock.Anything, "priority", "low").Return(nil)
+
+	updated, err := suite.usecase.Update(suite.ctx, field.ID.Hex(), domain.CustomFieldUpdate{Required: &required, Default: "low"})
+	suite.Require().NoError(err)
+	suite.True(updated.Required)
+	suite.tasks.AssertExpectations(suite.T())
+}
+
+func (suite *customFieldUsecaseSuite) TestDelete_UnsetsValues() {
+	field := domain.CustomField{ID: primitive.NewObjectID(), Key: "priority"}
+	suite.fields.On("FetchByID", mock.Anything, field.ID.Hex()).Return(field, nil)
+	suite.fields.On("Delete", mock.Anything, field.ID.Hex()).Return(nil)
+	suite.tasks.On("UnsetCustomField", mock.Anything, "priority").Return(nil)
+
+	suite.NoError(suite.usecase.Delete(suite.ctx, field.ID.Hex()))
+	suite.tasks.AssertExpectations(suite.T())
+}
+
+func (suite *customFieldUsecaseSuite) TestResolveCustomFields() {
+	fields := []domain.CustomField{
+		{Key: "notes", Type: domain.CustomFieldText},
+		{Key: "points", Type: domain.CustomFieldNumber, Required: true},
+		{Key: "priority", Type: domain.CustomFieldEnum, Options: []string{"low", "high"}, Default: "low"},
+		{Key: "launch", Type: domain.CustomFieldDate},
+		{Key: "reviewer", Type: domain.CustomFieldUser},
+	}
+	suite.orgs.On("FetchMembership", mock.Anything, "org1", "u1").Return(domain.Membership{}, nil)
+	suite.orgs.On("FetchMembership", mock.Anything, "org1", "stranger").Return(domain.Membership{}, domain.ErrNotMember)
+
+	values, err := resolveCustomFields(suite.ctx, suite.orgs, fields, map[string]interface{}{
+		"notes": nil, "points": float64(5), "launch": "2024-05-01", "reviewer": "u1",
+	})
+	suite.Require().NoError(err)
+	suite.Equal(map[string]interface{}{
+		"points":   float64(5),
+		"priority": "low",
+		"launch":   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		"reviewer": "u1",
+	}, values)
+
+	for _, bad := range []map[string]interface{}{
+		{},
+		{"points": "five"},
+		{"points": float64(1), "priority": "urgent"},
+		{"points": float64(1), "reviewer": "stranger"},
+		{"points": float64(1), "unknown": "x"},
+	} {
+		_, err := resolveCustomFields(suite.ctx, suite.orgs, fields, bad)
+		suite.ErrorIs(err, domain.ErrInvalidCustomField, bad)
+	}
+}
+
+func TestCustomFieldUsecase(t *testing.T) {
+	suite.Run(t, new(customFieldUsecaseSuite))
+}
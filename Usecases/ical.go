@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalProductID = "-//task-manger-api//Tasks//EN"
+	icalTimestamp = "20060102T150405Z"
+	// icalLineOctets is where RFC 5545 folds content lines.
+	icalLineOctets = 75
+)
+
+// icalWriter writes tasks as an iCalendar (RFC 5545) stream. The first
+// write error sticks and is returned by every later call.
+type icalWriter struct {
+	w   io.Writer
+	now time.Time
+	err error
+}
+
+func newICalWriter(w io.Writer, now time.Time) *icalWriter {
+	return &icalWriter{w: w, now: now.UTC()}
+}
+
+// begin opens the calendar, naming it for clients that show names.
+func (iw *icalWriter) begin(name string) error {
+	iw.line("BEGIN", "VCALENDAR")
+	iw.line("VERSION", "2.0")
+	iw.line("PRODID", icalProductID)
+	iw.line("CALSCALE", "GREGORIAN")
+	if name != "" {
+		iw.line("X-WR-CALNAME", icalText(name))
+	}
+	return iw.err
+}
+
+// todo writes the task as a VTODO, due when the task is.
+func (iw *icalWriter) todo(task domain.Task) error {
+	iw.line("BEGIN", "VTODO")
+	iw.common(task)
+	if !task.DueDate.IsZero() {
+		iw.line("DUE", task.DueDate.UTC().Format(icalTimestamp))
+	}
+	iw.line("STATUS", icalStatus(task.Status))
+	if task.Completion != nil {
+		iw.line("PERCENT-COMPLETE", fmt.Sprint(*task.Completion))
+	}
+	iw.line("END", "VTODO")
+	return iw.err
+}
+
+// event writes the task as an all-day VEVENT on its due date, for
+// calendars that do not show to-dos.
+func (iw *icalWriter) event(task domain.Task) error {
+	due := task.DueDate.UTC()
+	iw.line("BEGIN", "VEVENT")
+	iw.common(task)
+	iw.line("DTSTART;VALUE=DATE", due.Format("20060102"))
+	iw.line("DTEND;VALUE=DATE", due.AddDate(0, 0, 1).Format("20060102"))
+	iw.line("TRANSP", "TRANSPARENT")
+	iw.line("END", "VEVENT")
+	return iw.err
+}
+
+func (iw *icalWriter) end() error {
+	iw.line("END", "VCALENDAR")
+	return iw.err
+}
+
+func (iw *icalWriter) common(task domain.Task) {
+	iw.line("UID", task.ID.Hex()+"@task-manger-api")
+	iw.line("DTSTAMP", iw.now.Format(icalTimestamp))
+	iw.line("SUMMARY", icalText(task.Title))
+	if task.Description != "" {
+		iw.line("DESCRIPTION", icalText(task.Description))
+	}
+	if len(task.Labels) > 0 {
+		labels := make([]string, len(task.Labels))
+		for i, label := range task.Labels {
+			labels[i] = icalText(label)
+		}
+		iw.line("CATEGORIES", strings.Join(labels, ","))
+	}
+}
+
+// line writes one content line, folded so no line exceeds 75 octets
+// without splitting a UTF-8 sequence.
+func (iw *icalWriter) line(name string, value string) {
+	if iw.err != nil {
+		return
+	}
+	rest := name + ":" + value
+	var b strings.Builder
+	limit := icalLineOctets
+	for len(rest) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(rest[cut]) {
+			cut--
+		}
+		b.WriteString(rest[:cut])
+		b.WriteString("\r\n ")
+		rest = rest[cut:]
+		// the leading space of a continuation line counts
+		limit = icalLineOctets - 1
+	}
+	b.WriteString(rest)
+	b.WriteString("\r\n")
+	_, iw.err = io.WriteString(iw.w, b.String())
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// icalText escapes a TEXT value.
+func icalText(text string) string {
+	return icalEscaper.Replace(text)
+}
+
+// icalStatus maps the free-form task status onto the VTODO statuses.
+func icalStatus(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "done", "completed", "complete", "closed", "resolved":
+		return "COMPLETED"
+	case "in progress", "in_progress", "in-progress", "doing", "started":
+		return "IN-PROCESS"
+	case "cancelled", "canceled":
+		return "CANCELLED"
+	}
+	return "NEEDS-ACTION"
+}
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"time"
+)
+
+type impersonationUsecase struct {
+	userRepository         domain.UserRepository
+	organizationRepository domain.OrganizationRepository
+	auditRepository        domain.AuditRepository
+	contextTimeout         time.Duration
+}
+
+func NewImpersonationUsecase(userRepository domain.UserRepository, organizationRepository domain.OrganizationRepository, auditRepository domain.AuditRepository, timeout time.Duration) domain.ImpersonationUsecase {
+	return &impersonationUsecase{
+		userRepository:         userRepository,
+		organizationRepository: organizationRepository,
+		auditRepository:        auditRepository,
+		contextTimeout:         timeout,
+	}
+}
+
+// Impersonate issues a short-lived token that acts as userID on behalf of
+// the admin actorID. Other admins cannot be impersonated, so the token never
+// grants more than what the actor could already see.
+func (iu *impersonationUsecase) Impersonate(c context.Context, actorID string, userID string) (string, error) {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	if actorID == userID {
+		return "", domain.ErrCannotImpersonate
+	}
+	actor, err := iu.userRepository.FetchByUserID(ctx, actorID)
+	if err != nil {
+		return "", err
+	}
+	user, err := iu.userRepository.FetchByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user.User_type == "ADMIN" {
+		return "", domain.ErrCannotImpersonate
+	}
+	if !user.IsActive() {
+		return "", domain.ErrUserDeactivated
+	}
+	if user.Username == nil || user.Email == nil {
+		return "", errors.New("invalid user data")
+	}
+
+	orgID, err := defaultOrganization(ctx, iu.organizationRepository, user.User_id)
+	if err != nil {
+		return "", err
+	}
+	// The start of every impersonation is on record before a token exists.
+	err = iu.auditRepository.Create(ctx, &domain.AuditEntry{
+		Action:     domain.AuditImpersonationStart,
+		Actor_id:   actorID,
+		Subject_id: userID,
+		Org_id:     orgID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	actorClaim := infrastructure.Actor{Sub: actor.User_id}
+	if actor.Username != nil {
+		actorClaim.Username = *actor.Username
+	}
+	return infrastructure.GenerateImpersonationToken(actorClaim, user.User_id, *user.Username, *user.Email, user.User_type, orgID)
+}
+
+func (iu *impersonationUsecase) FetchAuditLog(c context.Context, filter domain.AuditFilter) (*[]domain.AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	if filter.Limit < 1 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+	entries, err := iu.auditRepository.FetchAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &entries, nil
+}
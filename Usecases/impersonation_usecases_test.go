@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type impersonationUsecaseSuite struct {
+	suite.Suite
+	users         *mocks.UserRepository
+	organizations *mocks.OrganizationRepository
+	audit         *mocks.AuditRepository
+	usecase       domain.ImpersonationUsecase
+}
+
+func (suite *impersonationUsecaseSuite) SetupTest() {
+	suite.users = new(mocks.UserRepository)
+	suite.organizations = new(mocks.OrganizationRepository)
+	suite.audit = new(mocks.AuditRepository)
+	suite.usecase = NewImpersonationUsecase(suite.users, suite.organizations, suite.audit, 10*time.Second)
+
+	suite.users.On("FetchByUserID", mock.Anything, "admin-id").Return(domain.User{User_id: "admin-id", Username: ptr("admin"), User_type: "ADMIN"}, nil).Maybe()
+}
+
+func (suite *impersonationUsecaseSuite) TestImpersonate_RecordsStartAndCarriesActor() {
+	suite.users.On("FetchByUserID", mock.Anything, "user-id").Return(domain.User{User_id: "user-id", Username: ptr("user"), Email: ptr("user@example.com"), User_type: "USER"}, nil)
+	suite.organizations.On("FetchMemberships", mock.Anything, "user-id").Return([]domain.Membership{{Org_id: "org1"}}, nil)
+	suite.audit.On("Create", mock.Anything, &domain.AuditEntry{
+		Action:     domain.AuditImpersonationStart,
+		Actor_id:   "admin-id",
+		Subject_id: "user-id",
+		Org_id:     "org1",
+	}).Return(nil)
+
+	token, err := suite.usecase.Impersonate(context.TODO(), "admin-id", "user-id")
+	suite.Require().NoError(err)
+	suite.audit.AssertExpectations(suite.T())
+
+	claims, err := infrastructure.ValidateToken(token)
+	suite.Require().NoError(err)
+	suite.Equal("user-id", claims.User_id)
+	suite.Equal("org1", claims.Org_id)
+	suite.Equal(&infrastructure.Actor{Sub: "admin-id", Username: "admin"}, claims.Act)
+}
+
+func (suite *impersonationUsecaseSuite) TestImpersonate_OtherAdmin() {
+	suite.users.On("FetchByUserID", mock.Anything, "other-admin").Return(domain.User{User_id: "other-admin", Username: ptr("other"), Email: ptr("other@example.com"), User_type: "ADMIN"}, nil)
+
+	_, err := suite.usecase.Impersonate(context.TODO(), "admin-id", "other-admin")
+	suite.ErrorIs(err, domain.ErrCannotImpersonate)
+	suite.audit.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *impersonationUsecaseSuite) TestImpersonate_Self() {
+	_, err := suite.usecase.Impersonate(context.TODO(), "admin-id", "admin-id")
+	suite.ErrorIs(err, domain.ErrCannotImpersonate)
+}
+
+func (suite *impersonationUsecaseSuite) TestImpersonate_AuditFailureIssuesNoToken() {
+	suite.users.On("FetchByUserID", mock.Anything, "user-id").Return(domain.User{User_id: "user-id", Username: ptr("user"), Email: ptr("user@example.com"), User_type: "USER"}, nil)
+	suite.organizations.On("FetchMemberships", mock.Anything, "user-id").Return([]domain.Membership{}, nil)
+	suite.audit.On("Create", mock.Anything, mock.Anything).Return(context.DeadlineExceeded)
+
+	token, err := suite.usecase.Impersonate(context.TODO(), "admin-id", "user-id")
+	suite.Error(err)
+	suite.Empty(token)
+}
+
+func TestImpersonationUsecase(t *testing.T) {
+	suite.Run(t, new(impersonationUsecaseSuite))
+}
@@ -0,0 +1,215 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const defaultInvitationTTL = 72 * time.Hour
+
+type invitationUsecase struct {
+	invitationRepository   domain.InvitationRepository
+	userRepository         domain.UserRepository
+	organizationRepository domain.OrganizationRepository
+	outboxRepository       domain.OutboxRepository
+	transactor             domain.Transactor
+	mailer                 domain.Mailer
+	contextTimeout         time.Duration
+	// invitationTTL is how long an invitation link stays valid.
+	invitationTTL time.Duration
+	// acceptURL is prepended to the token to build the link in the email,
+	// e.g. https://app.example.com/accept-invite?token=
+	acceptURL string
+	now       func() time.Time
+}
+
+func NewInvitationUsecase(invitationRepository domain.InvitationRepository, userRepository domain.UserRepository, organizationRepository domain.OrganizationRepository, outboxRepository domain.OutboxRepository, transactor domain.Transactor, mailer domain.Mailer, timeout time.Duration) domain.InvitationUsecase {
+	return &invitationUsecase{
+		invitationRepository:   invitationRepository,
+		userRepository:         userRepository,
+		organizationRepository: organizationRepository,
+		outboxRepository:       outboxRepository,
+		transactor:             transactor,
+		mailer:                 mailer,
+		contextTimeout:         timeout,
+		invitationTTL:          invitationTTLFromEnv(),
+		acceptURL:              os.Getenv("INVITATION_URL"),
+		now:                    time.Now,
+	}
+}
+
+// invitationTTLFromEnv reads INVITATION_TTL as a Go duration, defaulting to
+// three days.
+func invitationTTLFromEnv() time.Duration {
+	raw := os.Getenv("INVITATION_TTL")
+	if raw == "" {
+		return defaultInvitationTTL
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl <= 0 {
+		log.Printf("ignoring invalid INVITATION_TTL %q", raw)
+		return defaultInvitationTTL
+	}
+	return ttl
+}
+
+func (iu *invitationUsecase) Invite(c context.Context, invitation *domain.Invitation, invitedBy string) error {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	invitation.Email = strings.TrimSpace(invitation.Email)
+	_, err := iu.userRepository.FindByEmail(ctx, invitation.Email)
+	if err == nil {
+		return domain.ErrEmailTaken
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	_, err = iu.invitationRepository.FetchPendingByEmail(ctx, invitation.Email)
+	if err == nil {
+		return domain.ErrInvitationPending
+	}
+	if !errors.Is(err, domain.ErrInvitationNotFound) {
+		return err
+	}
+
+	token, hash, err := infrastructure.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	invitation.Token_hash = hash
+	invitation.Invited_by = invitedBy
+	invitation.Expires_at = iu.now().Add(iu.invitationTTL)
+	if err := iu.invitationRepository.Create(ctx, invitation); err != nil {
+		return err
+	}
+	invitation.Status = domain.InvitationPending
+	return iu.send(ctx, invitation, token)
+}
+
+func (iu *invitationUsecase) FetchAll(c context.Context) (*[]domain.Invitation, error) {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	invitations, err := iu.invitationRepository.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := iu.now()
+	for i := range invitations {
+		invitations[i].Status = invitations[i].StatusAt(now)
+	}
+	return &invitations, nil
+}
+
+// Resend issues a fresh token and expiry, invalidating the previous link.
+func (iu *invitationUsecase) Resend(c context.Context, invitationID string) error {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	invitation, err := iu.invitationRepository.FetchByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if status := invitation.StatusAt(iu.now()); status == domain.InvitationAccepted || status == domain.InvitationRevoked {
+		return domain.ErrInvitationInvalid
+	}
+
+	token, hash, err := infrastructure.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	invitation.Expires_at = iu.now().Add(iu.invitationTTL)
+	if err := iu.invitationRepository.Renew(ctx, invitationID, hash, invitation.Expires_at); err != nil {
+		return err
+	}
+	return iu.send(ctx, &invitation, token)
+}
+
+func (iu *invitationUsecase) Revoke(c context.Context, invitationID string) error {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	if _, err := iu.invitationRepository.FetchByID(ctx, invitationID); err != nil {
+		return err
+	}
+	return iu.invitationRepository.Revoke(ctx, invitationID, iu.now())
+}
+
+// Accept creates the invited user with the user type chosen by the admin.
+// It works even when open registration is disabled. The invitation is
+// claimed in the same transaction that creates the user, so a token is
+// used at most once and a failed signup leaves it open.
+func (iu *invitationUsecase) Accept(c context.Context, acceptance domain.InvitationAcceptance) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	invitation, err := iu.invitationRepository.FetchByTokenHash(ctx, infrastructure.HashOpaqueToken(acceptance.Token))
+	if err != nil {
+		return nil, err
+	}
+	switch invitation.StatusAt(iu.now()) {
+	case domain.InvitationExpired:
+		return nil, domain.ErrInvitationExpired
+	case domain.InvitationAccepted, domain.InvitationRevoked:
+		return nil, domain.ErrInvitationInvalid
+	}
+
+	if err := infrastructure.CheckPasswordPolicy(acceptance.Password); err != nil {
+		return nil, err
+	}
+	// the token was mailed to the invited address
+	verifiedAt := iu.now()
+	user := &domain.User{
+		Name:              &acceptance.Name,
+		Username:          &acceptance.Username,
+		Password:          &acceptance.Password,
+		Email:             &invitation.Email,
+		Email_verified_at: &verifiedAt,
+	}
+	err = iu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := iu.invitationRepository.MarkAccepted(tx, invitation.ID.Hex(), iu.now()); err != nil {
+			return err
+		}
+		if err := iu.userRepository.Create(tx, user); err != nil {
+			return err
+		}
+		if invitation.User_type != user.User_type {
+			if err := iu.userRepository.SetUserType(tx, user.User_id, invitation.User_type); err != nil {
+				return err
+			}
+			user.User_type = invitation.User_type
+		}
+		return recordEvents(tx, iu.outboxRepository, registered(user))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := createPersonalOrganization(ctx, iu.organizationRepository, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (iu *invitationUsecase) send(ctx context.Context, invitation *domain.Invitation, token string) error {
+	link := token
+	if iu.acceptURL != "" {
+		link = iu.acceptURL + token
+	}
+	body := fmt.Sprintf("You have been invited to join the task manager.\n\nAccept your invitation before %s:\n%s\n",
+		invitation.Expires_at.UTC().Format(time.RFC1123), link)
+
+	if err := iu.mailer.Send(ctx, invitation.Email, "You're invited to the task manager", body); err != nil {
+		return fmt.Errorf("the invitation was saved but the email could not be sent: %w", err)
+	}
+	return nil
+}
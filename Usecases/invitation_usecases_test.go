@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type invitationUsecaseSuite struct {
+	suite.Suite
+	invitations   *mocks.InvitationRepository
+	users         *mocks.UserRepository
+	organizations *mocks.OrganizationRepository
+	outbox        *mocks.OutboxRepository
+	mailer        *mocks.Mailer
+	usecase       *invitationUsecase
+	now           time.Time
+	// sentToken is the token from the last invitation email.
+	sentToken string
+}
+
+func (suite *invitationUsecaseSuite) SetupSuite() {
+	config := infrastructure.DefaultPasswordConfig()
+	config.Argon2id.Memory, config.Argon2id.Iterations = 1024, 1
+	suite.Require().NoError(infrastructure.InitPasswords(config))
+}
+
+func (suite *invitationUsecaseSuite) SetupTest() {
+	suite.invitations = new(mocks.InvitationRepository)
+	suite.users = new(mocks.UserRepository)
+	suite.organizations = new(mocks.OrganizationRepository)
+	suite.outbox = new(mocks.OutboxRepository)
+	suite.outbox.On("Append", mock.Anything, mock.Anything).Return(nil).Maybe()
+	suite.mailer = new(mocks.Mailer)
+	suite.now = time.Now()
+	suite.sentToken = ""
+
+	suite.usecase = NewInvitationUsecase(suite.invitations, suite.users, suite.organizations, suite.outbox, passThroughTransactor(), suite.mailer, 10*time.Second).(*invitationUsecase)
+	suite.usecase.acceptURL = "https://tasks.example.com/accept?token="
+	suite.usecase.now = func() time.Time { return suite.now }
+
+	suite.mailer.On("Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		body := args.String(3)
+		start := strings.Index(body, "token=") + len("token=")
+		suite.sentToken = strings.TrimSpace(body[start:])
+	}).Return(nil).Maybe()
+}
+
+func (suite *invitationUsecaseSuite) pending() domain.Invitation {
+	return domain.Invitation{
+		ID:         primitive.NewObjectID(),
+		Email:      "new@example.com",
+		User_type:  "ADMIN",
+		Expires_at: suite.now.Add(time.Hour),
+	}
+}
+
+func (suite *invitationUsecaseSuite) TestInvite_SendsTokenAndStoresHash() {
+	suite.users.On("FindByEmail", mock.Anything, "new@example.com").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.invitations.On("FetchPendingByEmail", mock.Anything, "new@example.com").Return(domain.Invitation{}, domain.ErrInvitationNotFound)
+	suite.invitations.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	invitation := domain.Invitation{Email: " new@example.com ", User_type: "USER"}
+	err := suite.usecase.Invite(context.TODO(), &invitation, "admin1")
+	suite.Require().NoError(err)
+
+	suite.Equal("admin1", invitation.Invited_by)
+	suite.Equal(domain.InvitationPending, invitation.Status)
+	suite.Equal(suite.now.Add(defaultInvitationTTL), invitation.Expires_at)
+	suite.NotEmpty(suite.sentToken)
+	suite.Equal(infrastructure.HashOpaqueToken(suite.sentToken), invitation.Token_hash)
+	suite.mailer.AssertCalled(suite.T(), "Send", mock.Anything, "new@example.com", mock.Anything, mock.Anything)
+}
+
+func (suite *invitationUsecaseSuite) TestInvite_ExistingUser() {
+	suite.users.On("FindByEmail", mock.Anything, "taken@example.com").Return(domain.User{User_id: "u1"}, nil)
+
+	err := suite.usecase.Invite(context.TODO(), &domain.Invitation{Email: "taken@example.com", User_type: "USER"}, "admin1")
+	suite.ErrorIs(err, domain.ErrEmailTaken)
+	suite.invitations.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *invitationUsecaseSuite) TestInvite_AlreadyPending() {
+	suite.users.On("FindByEmail", mock.Anything, "new@example.com").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.invitations.On("FetchPendingByEmail", mock.Anything, "new@example.com").Return(suite.pending(), nil)
+
+	err := suite.usecase.Invite(context.TODO(), &domain.Invitation{Email: "new@example.com", User_type: "USER"}, "admin1")
+	suite.ErrorIs(err, domain.ErrInvitationPending)
+}
+
+func (suite *invitationUsecaseSuite) TestResend_RotatesToken() {
+	invitation := suite.pending()
+	invitation.Expires_at = suite.now.Add(-time.Minute)
+	suite.invitations.On("FetchByID", mock.Anything, invitation.ID.Hex()).Return(invitation, nil)
+	suite.invitations.On("Renew", mock.Anything, invitation.ID.Hex(), mock.Anything, suite.now.Add(defaultInvitationTTL)).Return(nil)
+
+	err := suite.usecase.Resend(context.TODO(), invitation.ID.Hex())
+	suite.Require().NoError(err)
+	suite.invitations.AssertCalled(suite.T(), "Renew", mock.Anything, invitation.ID.Hex(), infrastructure.HashOpaqueToken(suite.sentToken), mock.Anything)
+}
+
+func (suite *invitationUsecaseSuite) TestResend_Accepted() {
+	invitation := suite.pending()
+	invitation.Accepted_at = &suite.now
+	suite.invitations.On("FetchByID", mock.Anything, invitation.ID.Hex()).Return(invitation, nil)
+
+	err := suite.usecase.Resend(context.TODO(), invitation.ID.Hex())
+	suite.ErrorIs(err, domain.ErrInvitationInvalid)
+	suite.mailer.AssertNotCalled(suite.T(), "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *invitationUsecaseSuite) TestAccept_CreatesUserWithInvitedRole() {
+	invitation := suite.pending()
+	suite.invitations.On("FetchByTokenHash", mock.Anything, infrastructure.HashOpaqueToken("secret-token")).Return(invitation, nil)
+	suite.users.On("Create", mock.Anything, mock.MatchedBy(func(user *domain.User) bool {
+		return *user.Email == invitation.Email && *user.Username == "newbie" && user.Email_verified_at != nil
+	})).Run(func(args mock.Arguments) {
+		user := args.Get(1).(*domain.User)
+		user.User_id = "u2"
+		user.User_type = "USER"
+	}).Return(nil)
+	suite.invitations.On("MarkAccepted", mock.Anything, invitation.ID.Hex(), suite.now).Return(nil)
+	suite.users.On("SetUserType", mock.Anything, "u2", "ADMIN").Return(nil)
+	suite.organizations.On("Create", mock.Anything, mock.Anything).Return(nil)
+	suite.organizations.On("AddMember", mock.Anything, mock.Anything).Return(nil)
+
+	user, err := suite.usecase.Accept(context.TODO(), domain.InvitationAcceptance{
+		Token:    "secret-token",
+		Name:     "New Person",
+		Username: "newbie",
+		Password: "strongpassword",
+	})
+	suite.Require().NoError(err)
+	suite.Equal("ADMIN", user.User_type)
+	suite.invitations.AssertExpectations(suite.T())
+	suite.users.AssertExpectations(suite.T())
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.UserRegistered) bool {
+		return event == domain.UserRegistered{User_id: "u2", Username: "newbie", User_type: "ADMIN"}
+	}))
+}
+
+func (suite *invitationUsecaseSuite) TestAccept_ClaimsTheInvitationBeforeCreatingTheUser() {
+	invitation := suite.pending()
+	suite.invitations.On("FetchByTokenHash", mock.Anything, mock.Anything).Return(invitation, nil)
+	// another request accepted it after it was fetched
+	suite.invitations.On("MarkAccepted", mock.Anything, invitation.ID.Hex(), suite.now).Return(domain.ErrInvitationInvalid)
+
+	_, err := suite.usecase.Accept(context.TODO(), domain.InvitationAcceptance{Token: "t", Name: "New Person", Username: "newbie", Password: "strongpassword"})
+	suite.ErrorIs(err, domain.ErrInvitationInvalid)
+	suite.users.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.outbox.AssertNotCalled(suite.T(), "Append", mock.Anything, mock.Anything)
+	suite.organizations.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *invitationUsecaseSuite) TestAccept_Expired() {
+	invitation := suite.pending()
+	invitation.Expires_at = suite.now
+	suite.invitations.On("FetchByTokenHash", mock.Anything, mock.Anything).Return(invitation, nil)
+
+	_, err := suite.usecase.Accept(context.TODO(), domain.InvitationAcceptance{Token: "t", Name: "New Person", Username: "newbie", Password: "strongpassword"})
+	suite.ErrorIs(err, domain.ErrInvitationExpired)
+	suite.users.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *invitationUsecaseSuite) TestAccept_Revoked() {
+	invitation := suite.pending()
+	invitation.Revoked_at = &suite.now
+	suite.invitations.On("FetchByTokenHash", mock.Anything, mock.Anything).Return(invitation, nil)
+
+	_, err := suite.usecase.Accept(context.TODO(), domain.InvitationAcceptance{Token: "t", Name: "New Person", Username: "newbie", Password: "strongpassword"})
+	suite.ErrorIs(err, domain.ErrInvitationInvalid)
+}
+
+func TestInvitationUsecase(t *testing.T) {
+	suite.Run(t, new(invitationUsecaseSuite))
+}
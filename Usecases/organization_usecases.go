@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"time"
+)
+
+type organizationUsecase struct {
+	organizationRepository domain.OrganizationRepository
+	userRepository         domain.UserRepository
+	contextTimeout         time.Duration
+}
+
+func NewOrganizationUsecase(organizationRepository domain.OrganizationRepository, userRepository domain.UserRepository, timeout time.Duration) domain.OrganizationUsecase {
+	return &organizationUsecase{
+		organizationRepository: organizationRepository,
+		userRepository:         userRepository,
+		contextTimeout:         timeout,
+	}
+}
+
+func (ou *organizationUsecase) Create(c context.Context, org *domain.Organization, ownerID string) error {
+	ctx, cancel := context.WithTimeout(c, ou.contextTimeout)
+	defer cancel()
+
+	org.Created_by = ownerID
+	if err := ou.organizationRepository.Create(ctx, org); err != nil {
+		return err
+	}
+	org.Role = domain.OrgRoleOwner
+	return ou.organizationRepository.AddMember(ctx, &domain.Membership{
+		Org_id:  org.ID.Hex(),
+		User_id: ownerID,
+		Role:    domain.OrgRoleOwner,
+	})
+}
+
+func (ou *organizationUsecase) FetchForUser(c context.Context, userID string) (*[]domain.Organization, error) {
+	ctx, cancel := context.WithTimeout(c, ou.contextTimeout)
+	defer cancel()
+
+	memberships, err := ou.organizationRepository.FetchMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]string, len(memberships))
+	orgIDs := make([]string, 0, len(memberships))
+	for _, membership := range memberships {
+		roles[membership.Org_id] = membership.Role
+		orgIDs = append(orgIDs, membership.Org_id)
+	}
+
+	orgs, err := ou.organizationRepository.FetchByIDs(ctx, orgIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orgs {
+		orgs[i].Role = roles[orgs[i].ID.Hex()]
+	}
+	return &orgs, nil
+}
+
+func (ou *organizationUsecase) FetchMembers(c context.Context, orgID string) (*[]domain.Membership, error) {
+	ctx, cancel := context.WithTimeout(c, ou.contextTimeout)
+	defer cancel()
+
+	members, err := ou.organizationRepository.FetchMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return &members, nil
+}
+
+func (ou *organizationUsecase) AddMember(c context.Context, membership *domain.Membership) error {
+	ctx, cancel := context.WithTimeout(c, ou.contextTimeout)
+	defer cancel()
+
+	if _, err := ou.userRepository.FetchByUserID(ctx, membership.User_id); err != nil {
+		return err
+	}
+	return ou.organizationRepository.AddMember(ctx, membership)
+}
+
+func (ou *organizationUsecase) UpdateMemberRole(c context.Context, orgID string, userID string, role string) error {
+	ctx, cancel := context.WithTimeout(c, ou.contextTimeout)
+	defer cancel()
+
+	if role != domain.OrgRoleOwner {
+		membership, err := ou.organizationRepository.FetchMembership(ctx, orgID, userID)
+		if err != nil {
+			return err
+		}
+		if err := ou.ensureNotLastOwner(ctx, membership); err != nil {
+			return err
+		}
+	}
+	return ou.organizationRepository.UpdateMemberRole(ctx, orgID, userID, role)
+}
+
+// RemoveMember takes the role of the member removing userID: only owners
+// may remove owners, as only they may change roles.
+func (ou *organizationUsecase) RemoveMember(c context.Context, orgID string, userID string, actorRole string) error {
+	ctx, cancel := context.WithTimeout(c, ou.contextTimeout)
+	defer cancel()
+
+	membership, err := ou.organizationRepository.FetchMembership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if membership.Role == domain.OrgRoleOwner && actorRole != domain.OrgRoleOwner {
+		return domain.ErrOwnerRequired
+	}
+	if err := ou.ensureNotLastOwner(ctx, membership); err != nil {
+		return err
+	}
+	return ou.organizationRepository.RemoveMember(ctx, orgID, userID)
+}
+
+func (ou *organizationUsecase) ensureNotLastOwner(ctx context.Context, membership domain.Membership) error {
+	if membership.Role != domain.OrgRoleOwner {
+		return nil
+	}
+	owners, err := ou.organizationRepository.CountOwners(ctx, membership.Org_id)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrLastOwner
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type organizationUsecaseSuite struct {
+	suite.Suite
+	repository *mocks.OrganizationRepository
+	users      *mocks.UserRepository
+	usecase    domain.OrganizationUsecase
+}
+
+func (suite *organizationUsecaseSuite) SetupTest() {
+	suite.repository = new(mocks.OrganizationRepository)
+	suite.users = new(mocks.UserRepository)
+	suite.usecase = NewOrganizationUsecase(suite.repository, suite.users, 10*time.Second)
+}
+
+func (suite *organizationUsecaseSuite) TestCreate_CreatorBecomesOwner() {
+	org := domain.Organization{Name: "Acme"}
+	suite.repository.On("Create", mock.Anything, &org).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Organization).ID = primitive.NewObjectID()
+	}).Return(nil)
+	suite.repository.On("AddMember", mock.Anything, mock.MatchedBy(func(membership *domain.Membership) bool {
+		return membership.User_id == "u1" && membership.Role == domain.OrgRoleOwner && membership.Org_id == org.ID.Hex()
+	})).Return(nil)
+
+	err := suite.usecase.Create(context.TODO(), &org, "u1")
+	suite.NoError(err)
+	suite.Equal("u1", org.Created_by)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *organizationUsecaseSuite) TestFetchForUser_IncludesRole() {
+	orgID := primitive.NewObjectID()
+	suite.repository.On("FetchMemberships", mock.Anything, "u1").Return([]domain.Membership{{Org_id: orgID.Hex(), User_id: "u1", Role: domain.OrgRoleAdmin}}, nil)
+	suite.repository.On("FetchByIDs", mock.Anything, []string{orgID.Hex()}).Return([]domain.Organization{{ID: orgID, Name: "Acme"}}, nil)
+
+	orgs, err := suite.usecase.FetchForUser(context.TODO(), "u1")
+	suite.NoError(err)
+	suite.Len(*orgs, 1)
+	suite.Equal(domain.OrgRoleAdmin, (*orgs)[0].Role)
+}
+
+func (suite *organizationUsecaseSuite) TestRemoveMember_LastOwner() {
+	suite.repository.On("FetchMembership", mock.Anything, "org1", "u1").Return(domain.Membership{Org_id: "org1", User_id: "u1", Role: domain.OrgRoleOwner}, nil)
+	suite.repository.On("CountOwners", mock.Anything, "org1").Return(int64(1), nil)
+
+	err := suite.usecase.RemoveMember(context.TODO(), "org1", "u1", domain.OrgRoleOwner)
+	suite.ErrorIs(err, domain.ErrLastOwner)
+	suite.repository.AssertNotCalled(suite.T(), "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *organizationUsecaseSuite) TestRemoveMember_OnlyOwnersRemoveOwners() {
+	suite.repository.On("FetchMembership", mock.Anything, "org1", "u1").Return(domain.Membership{Org_id: "org1", User_id: "u1", Role: domain.OrgRoleOwner}, nil)
+	suite.repository.On("FetchMembership", mock.Anything, "org1", "u2").Return(domain.Membership{Org_id: "org1", User_id: "u2", Role: domain.OrgRoleMember}, nil)
+	suite.repository.On("CountOwners", mock.Anything, "org1").Return(int64(2), nil)
+	suite.repository.On("RemoveMember", mock.Anything, "org1", mock.Anything).Return(nil)
+
+	suite.ErrorIs(suite.usecase.RemoveMember(context.TODO(), "org1", "u1", domain.OrgRoleAdmin), domain.ErrOwnerRequired)
+	suite.repository.AssertNotCalled(suite.T(), "RemoveMember", mock.Anything, "org1", "u1")
+
+	suite.NoError(suite.usecase.RemoveMember(context.TODO(), "org1", "u2", domain.OrgRoleAdmin))
+	suite.NoError(suite.usecase.RemoveMember(context.TODO(), "org1", "u1", domain.OrgRoleOwner))
+	suite.repository.AssertNumberOfCalls(suite.T(), "RemoveMember", 2)
+}
+
+func (suite *organizationUsecaseSuite) TestUpdateMemberRole_DemoteOneOfTwoOwners() {
+	suite.repository.On("FetchMembership", mock.Anything, "org1", "u1").Return(domain.Membership{Org_id: "org1", User_id: "u1", Role: domain.OrgRoleOwner}, nil)
+	suite.repository.On("CountOwners", mock.Anything, "org1").Return(int64(2), nil)
+	suite.repository.On("UpdateMemberRole", mock.Anything, "org1", "u1", domain.OrgRoleMember).Return(nil)
+
+	err := suite.usecase.UpdateMemberRole(context.TODO(), "org1", "u1", domain.OrgRoleMember)
+	suite.NoError(err)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *organizationUsecaseSuite) TestAddMember_UnknownUser() {
+	suite.users.On("FetchByUserID", mock.Anything, "ghost").Return(domain.User{}, domain.ErrUserNotFound)
+
+	err := suite.usecase.AddMember(context.TODO(), &domain.Membership{Org_id: "org1", User_id: "ghost", Role: domain.OrgRoleMember})
+	suite.ErrorIs(err, domain.ErrUserNotFound)
+	suite.repository.AssertNotCalled(suite.T(), "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestOrganizationUsecase(t *testing.T) {
+	suite.Run(t, new(organizationUsecaseSuite))
+}
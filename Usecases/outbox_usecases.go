@@ -0,0 +1,230 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultOutboxAttempts       = 10
+	defaultOutboxRetentionHours = 72
+	// outboxLease is how long a claimed record is left to its relay. It
+	// must outlast publishing one event.
+	outboxLease = time.Minute
+	// outboxRetryBase is the wait after the first failed attempt; each
+	// attempt after it waits twice as long, up to outboxMaxRetryWait.
+	outboxRetryBase    = 5 * time.Second
+	outboxMaxRetryWait = time.Hour
+	outboxBatchSize    = 100
+	outboxPollInterval = time.Second
+	outboxPurgeEvery   = time.Hour
+)
+
+// eventDecoders turn stored payloads back into the events recorded, by
+// event name.
+var eventDecoders = map[string]func(payload []byte) (domain.DomainEvent, error){
+	domain.EventTaskCreated:         decodeEventAs[domain.TaskCreated],
+	domain.EventTaskUpdated:         decodeEventAs[domain.TaskUpdated],
+	domain.EventTaskStatusChanged:   decodeEventAs[domain.TaskStatusChanged],
+	domain.EventTaskDeleted:         decodeEventAs[domain.TaskDeleted],
+	domain.EventUserRegistered:      decodeEventAs[domain.UserRegistered],
+	domain.EventUserPromoted:        decodeEventAs[domain.UserPromoted],
+	domain.EventUserDemoted:         decodeEventAs[domain.UserDemoted],
+	domain.EventUserDeactivated:     decodeEventAs[domain.UserDeactivated],
+	domain.EventUserReactivated:     decodeEventAs[domain.UserReactivated],
+	domain.EventUserPasswordChanged: decodeEventAs[domain.UserPasswordChanged],
+	domain.EventUserDeleted:         decodeEventAs[domain.UserDeleted],
+	domain.EventUserErased:          decodeEventAs[domain.UserErased],
+}
+
+func decodeEventAs[E domain.DomainEvent](payload []byte) (domain.DomainEvent, error) {
+	var event E
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func decodeEvent(record domain.OutboxRecord) (domain.DomainEvent, error) {
+	decode, ok := eventDecoders[record.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %s", record.Name)
+	}
+	return decode([]byte(record.Payload))
+}
+
+// recordEvents appends events to the outbox. Give it the context of the
+// transaction making the change, so the events are stored if and only if
+// the change is.
+func recordEvents(c context.Context, outboxRepository domain.OutboxRepository, events ...domain.DomainEvent) error {
+	orgID, _ := domain.TenantFromContext(c)
+	now := time.Now()
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		record := domain.OutboxRecord{
+			Event_id:        primitive.NewObjectID().Hex(),
+			Name:            event.EventName(),
+			Aggregate_id:    event.AggregateID(),
+			Org_id:          orgID,
+			Payload:         string(payload),
+			Status:          domain.OutboxPending,
+			Occurred_at:     now,
+			Next_attempt_at: now,
+		}
+		if err := outboxRepository.Append(c, &record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type outboxRelay struct {
+	outboxRepository domain.OutboxRepository
+	events           domain.EventBus
+	contextTimeout   time.Duration
+	// maxAttempts bounds how often a record is published before the
+	// relay gives up on it.
+	maxAttempts int
+	retention   time.Duration
+	lease       time.Duration
+	retryBase   time.Duration
+	batchSize   int
+	now         func() time.Time
+}
+
+func NewOutboxRelay(outboxRepository domain.OutboxRepository, events domain.EventBus, timeout time.Duration) domain.OutboxRelay {
+	return &outboxRelay{
+		outboxRepository: outboxRepository,
+		events:           events,
+		contextTimeout:   timeout,
+		maxAttempts:      positiveIntFromEnv("OUTBOX_MAX_ATTEMPTS", defaultOutboxAttempts),
+		retention:        time.Duration(positiveIntFromEnv("OUTBOX_RETENTION_HOURS", defaultOutboxRetentionHours)) * time.Hour,
+		lease:            outboxLease,
+		retryBase:        outboxRetryBase,
+		batchSize:        outboxBatchSize,
+		now:              time.Now,
+	}
+}
+
+// Relay publishes up to a batch of due records, oldest first and in order
+// within each aggregate. A record is marked delivered once every
+// synchronous subscriber has handled it; until then it is published again,
+// with the same event ID, after a back-off, holding back the later records
+// of its aggregate.
+func (or *outboxRelay) Relay(c context.Context) (int, error) {
+	relayed := 0
+	for relayed < or.batchSize {
+		ctx, cancel := context.WithTimeout(c, or.contextTimeout)
+		record, err := or.outboxRepository.ClaimNext(ctx, or.now(), or.lease)
+		cancel()
+		if err != nil {
+			return relayed, err
+		}
+		if record == nil {
+			break
+		}
+		or.relay(c, *record)
+		relayed++
+	}
+	return relayed, nil
+}
+
+func (or *outboxRelay) relay(c context.Context, record domain.OutboxRecord) {
+	ctx, cancel := context.WithTimeout(c, or.contextTimeout)
+	defer cancel()
+
+	recordID := record.ID.Hex()
+	payload, err := decodeEvent(record)
+	if err != nil {
+		// it will not decode any better next time
+		log.Printf("giving up on outbox record %s: %v", recordID, err)
+		if err := or.outboxRepository.MarkFailed(ctx, recordID, err.Error()); err != nil {
+			log.Printf("failed to mark outbox record %s failed: %v", recordID, err)
+		}
+		return
+	}
+
+	eventCtx := ctx
+	if record.Org_id != "" {
+		eventCtx = domain.WithTenant(ctx, record.Org_id)
+	}
+	err = or.events.Deliver(eventCtx, domain.Event{ID: record.Event_id, Occurred_at: record.Occurred_at, Payload: payload})
+	if err == nil {
+		if err := or.outboxRepository.MarkDelivered(ctx, recordID, or.now()); err != nil {
+			// the lease runs out and the event is published again
+			log.Printf("failed to mark outbox record %s delivered: %v", recordID, err)
+		}
+		return
+	}
+
+	if record.Attempts >= or.maxAttempts {
+		log.Printf("giving up on outbox record %s after %d attempts: %v", recordID, record.Attempts, err)
+		if err := or.outboxRepository.MarkFailed(ctx, recordID, err.Error()); err != nil {
+			log.Printf("failed to mark outbox record %s failed: %v", recordID, err)
+		}
+		return
+	}
+	if err := or.outboxRepository.Reschedule(ctx, recordID, err.Error(), or.now().Add(or.retryWait(record.Attempts))); err != nil {
+		log.Printf("failed to reschedule outbox record %s: %v", recordID, err)
+	}
+}
+
+// retryWait is the back-off after the given number of failed attempts.
+func (or *outboxRelay) retryWait(attempts int) time.Duration {
+	wait := or.retryBase
+	for i := 1; i < attempts && wait < outboxMaxRetryWait; i++ {
+		wait *= 2
+	}
+	if wait > outboxMaxRetryWait {
+		return outboxMaxRetryWait
+	}
+	return wait
+}
+
+func (or *outboxRelay) Purge(c context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, or.contextTimeout)
+	defer cancel()
+	return or.outboxRepository.PurgeDelivered(ctx, or.now().Add(-or.retention))
+}
+
+// Start polls the outbox every second, carrying straight on while full
+// batches keep coming, and purges it every hour.
+func (or *outboxRelay) Start(stop <-chan struct{}) {
+	go func() {
+		poll := time.NewTicker(outboxPollInterval)
+		defer poll.Stop()
+		purge := time.NewTicker(outboxPurgeEvery)
+		defer purge.Stop()
+		for {
+			select {
+			case <-poll.C:
+				for {
+					relayed, err := or.Relay(context.Background())
+					if err != nil {
+						log.Println("outbox relay failed:", err)
+					}
+					if err != nil || relayed < or.batchSize {
+						break
+					}
+				}
+			case <-purge.C:
+				if purged, err := or.Purge(context.Background()); err != nil {
+					log.Println("outbox purge failed:", err)
+				} else if purged > 0 {
+					log.Printf("purged %d delivered outbox records", purged)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,194 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// passThroughTransactor runs fn straight away and hands back its error.
+func passThroughTransactor() *mocks.Transactor {
+	transactor := new(mocks.Transactor)
+	transactor.On("WithTransaction", mock.Anything, mock.Anything).Return(func(c context.Context, fn func(context.Context) error) error {
+		return fn(c)
+	}).Maybe()
+	return transactor
+}
+
+// recordedEvent matches an outbox record holding an event of type E that
+// satisfies match.
+func recordedEvent[E domain.DomainEvent](match func(event E) bool) interface{} {
+	return mock.MatchedBy(func(record *domain.OutboxRecord) bool {
+		event, err := decodeEvent(*record)
+		if err != nil {
+			return false
+		}
+		typed, ok := event.(E)
+		return ok && match(typed)
+	})
+}
+
+type outboxRelaySuite struct {
+	suite.Suite
+	outbox  *mocks.OutboxRepository
+	bus     *infrastructure.EventBus
+	relay   *outboxRelay
+	now     time.Time
+	records []domain.OutboxRecord
+}
+
+func (suite *outboxRelaySuite) SetupTest() {
+	suite.outbox = new(mocks.OutboxRepository)
+	suite.bus = infrastructure.NewEventBus()
+	suite.relay = NewOutboxRelay(suite.outbox, suite.bus, 10*time.Second).(*outboxRelay)
+	suite.relay.maxAttempts = 3
+	suite.now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	suite.relay.now = func() time.Time { return suite.now }
+
+	suite.records = nil
+	suite.outbox.On("Append", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		record := args.Get(1).(*domain.OutboxRecord)
+		record.ID = primitive.NewObjectID()
+		suite.records = append(suite.records, *record)
+	}).Return(nil).Maybe()
+}
+
+func (suite *outboxRelaySuite) TearDownTest() {
+	suite.bus.Close()
+}
+
+// claim makes ClaimNext hand out the recorded records once each, in order.
+func (suite *outboxRelaySuite) claim() {
+	for i := range suite.records {
+		suite.outbox.On("ClaimNext", mock.Anything, suite.now, outboxLease).Return(&suite.records[i], nil).Once()
+	}
+	suite.outbox.On("ClaimNext", mock.Anything, suite.now, outboxLease).Return(nil, nil)
+}
+
+func (suite *outboxRelaySuite) TestRecordEvents() {
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Ship it", Status: "Completed", Labels: []string{"release"}}
+	ctx := domain.WithTenant(context.TODO(), "org1")
+
+	err := recordEvents(ctx, suite.outbox, domain.TaskUpdated{Task: task}, domain.TaskStatusChanged{Task: task, Previous_status: "Pending"})
+	suite.Require().NoError(err)
+	err = recordEvents(context.TODO(), suite.outbox, domain.UserPromoted{User_id: "u1"})
+	suite.Require().NoError(err)
+
+	suite.Require().Len(suite.records, 3)
+	changed := suite.records[1]
+	suite.Equal(domain.EventTaskStatusChanged, changed.Name)
+	suite.Equal(task.ID.Hex(), changed.Aggregate_id)
+	suite.Equal("org1", changed.Org_id)
+	suite.Equal(domain.OutboxPending, changed.Status)
+	suite.Equal(changed.Occurred_at, changed.Next_attempt_at)
+	suite.NotEqual(suite.records[0].Event_id, changed.Event_id)
+	event, err := decodeEvent(changed)
+	suite.Require().NoError(err)
+	suite.Equal(domain.TaskStatusChanged{Task: task, Previous_status: "Pending"}, event)
+
+	suite.Empty(suite.records[2].Org_id)
+	event, err = decodeEvent(suite.records[2])
+	suite.Require().NoError(err)
+	suite.Equal(domain.UserPromoted{User_id: "u1"}, event)
+}
+
+func (suite *outboxRelaySuite) TestRelay_PublishesWithTheRecordedID() {
+	var received []domain.Event
+	var orgs []string
+	suite.bus.Subscribe(domain.AllEvents, func(c context.Context, event domain.Event) error {
+		received = append(received, event)
+		org, _ := domain.TenantFromContext(c)
+		orgs = append(orgs, org)
+		return nil
+	})
+	suite.Require().NoError(recordEvents(domain.WithTenant(context.TODO(), "org1"), suite.outbox, domain.TaskCreated{Task: domain.Task{Title: "one"}}))
+	suite.Require().NoError(recordEvents(context.TODO(), suite.outbox, domain.UserDeleted{User_id: "u1"}))
+	suite.claim()
+	for _, record := range suite.records {
+		suite.outbox.On("MarkDelivered", mock.Anything, record.ID.Hex(), suite.now).Return(nil).Once()
+	}
+
+	relayed, err := suite.relay.Relay(context.TODO())
+	suite.Require().NoError(err)
+	suite.Equal(2, relayed)
+	suite.Require().Len(received, 2)
+	suite.Equal(suite.records[0].Event_id, received[0].ID)
+	suite.Equal(suite.records[0].Occurred_at, received[0].Occurred_at)
+	suite.Equal("one", received[0].Payload.(domain.TaskCreated).Task.Title)
+	suite.Equal(domain.UserDeleted{User_id: "u1"}, received[1].Payload)
+	suite.Equal([]string{"org1", ""}, orgs, "the tenant the event was raised in is restored")
+	suite.outbox.AssertExpectations(suite.T())
+}
+
+func (suite *outboxRelaySuite) TestRelay_ReschedulesFailedPublications() {
+	suite.bus.Subscribe(domain.EventUserPromoted, func(c context.Context, event domain.Event) error {
+		return errors.New("downstream is down")
+	})
+	suite.Require().NoError(recordEvents(context.TODO(), suite.outbox, domain.UserPromoted{User_id: "u1"}))
+	suite.records[0].Attempts = 2
+	suite.claim()
+	suite.outbox.On("Reschedule", mock.Anything, suite.records[0].ID.Hex(), "downstream is down", suite.now.Add(2*outboxRetryBase)).Return(nil).Once()
+
+	relayed, err := suite.relay.Relay(context.TODO())
+	suite.Require().NoError(err)
+	suite.Equal(1, relayed)
+	suite.outbox.AssertExpectations(suite.T())
+	suite.outbox.AssertNotCalled(suite.T(), "MarkDelivered", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *outboxRelaySuite) TestRelay_GivesUp() {
+	suite.bus.Subscribe(domain.EventUserPromoted, func(c context.Context, event domain.Event) error {
+		panic("boom")
+	})
+	suite.Require().NoError(recordEvents(context.TODO(), suite.outbox, domain.UserPromoted{User_id: "u1"}))
+	suite.records[0].Attempts = suite.relay.maxAttempts
+	unknown := domain.OutboxRecord{ID: primitive.NewObjectID(), Name: "task.archived", Payload: "{}", Attempts: 1}
+	suite.records = append(suite.records, unknown)
+	suite.claim()
+	suite.outbox.On("MarkFailed", mock.Anything, suite.records[0].ID.Hex(), mock.AnythingOfType("string")).Return(nil).Once()
+	suite.outbox.On("MarkFailed", mock.Anything, unknown.ID.Hex(), "unknown event task.archived").Return(nil).Once()
+
+	relayed, err := suite.relay.Relay(context.TODO())
+	suite.Require().NoError(err)
+	suite.Equal(2, relayed)
+	suite.outbox.AssertExpectations(suite.T())
+	suite.outbox.AssertNotCalled(suite.T(), "Reschedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *outboxRelaySuite) TestRelay_StopsAtBatchSize() {
+	suite.relay.batchSize = 1
+	suite.Require().NoError(recordEvents(context.TODO(), suite.outbox, domain.UserPromoted{User_id: "u1"}, domain.UserDemoted{User_id: "u1"}))
+	suite.claim()
+	suite.outbox.On("MarkDelivered", mock.Anything, mock.Anything, suite.now).Return(nil)
+
+	relayed, err := suite.relay.Relay(context.TODO())
+	suite.Require().NoError(err)
+	suite.Equal(1, relayed)
+	suite.outbox.AssertNumberOfCalls(suite.T(), "ClaimNext", 1)
+}
+
+func (suite *outboxRelaySuite) TestRetryWait() {
+	suite.Equal(outboxRetryBase, suite.relay.retryWait(1))
+	suite.Equal(4*outboxRetryBase, suite.relay.retryWait(3))
+	suite.Equal(outboxMaxRetryWait, suite.relay.retryWait(40))
+}
+
+func (suite *outboxRelaySuite) TestPurge() {
+	suite.outbox.On("PurgeDelivered", mock.Anything, suite.now.Add(-defaultOutboxRetentionHours*time.Hour)).Return(int64(4), nil)
+
+	purged, err := suite.relay.Purge(context.TODO())
+	suite.Require().NoError(err)
+	suite.Equal(int64(4), purged)
+}
+
+func TestOutboxRelay(t *testing.T) {
+	suite.Run(t, new(outboxRelaySuite))
+}
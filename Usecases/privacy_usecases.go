@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"time"
+)
+
+type privacyUsecase struct {
+	userRepository         domain.UserRepository
+	organizationRepository domain.OrganizationRepository
+	sessionRepository      domain.SessionRepository
+	taskRepository         domain.TaskRepository
+	commentRepository      domain.CommentRepository
+	calendarFeedRepository domain.CalendarFeedRepository
+	viewRepository         domain.ViewRepository
+	auditRepository        domain.AuditRepository
+	outboxRepository       domain.OutboxRepository
+	transactor             domain.Transactor
+	contextTimeout         time.Duration
+}
+
+func NewPrivacyUsecase(userRepository domain.UserRepository, organizationRepository domain.OrganizationRepository, sessionRepository domain.SessionRepository, taskRepository domain.TaskRepository, commentRepository domain.CommentRepository, calendarFeedRepository domain.CalendarFeedRepository, viewRepository domain.ViewRepository, auditRepository domain.AuditRepository, outboxRepository domain.OutboxRepository, transactor domain.Transactor, timeout time.Duration) domain.PrivacyUsecase {
+	return &privacyUsecase{
+		userRepository:         userRepository,
+		organizationRepository: organizationRepository,
+		sessionRepository:      sessionRepository,
+		taskRepository:         taskRepository,
+		commentRepository:      commentRepository,
+		calendarFeedRepository: calendarFeedRepository,
+		viewRepository:         viewRepository,
+		auditRepository:        auditRepository,
+		outboxRepository:       outboxRepository,
+		transactor:             transactor,
+		contextTimeout:         timeout,
+	}
+}
+
+func (pu *privacyUsecase) Export(c context.Context, userID string) (*domain.PersonalDataExport, error) {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	user, err := pu.userRepository.FetchByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := pu.organizationRepository.FetchMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := pu.sessionRepository.FetchActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := pu.taskRepository.FetchByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := pu.commentRepository.FetchByAuthor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	audit, err := pu.auditRepository.FetchAll(ctx, domain.AuditFilter{Involving: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PersonalDataExport{
+		Exported_at: time.Now(),
+		Profile:     user,
+		Memberships: memberships,
+		Sessions:    sessions,
+		Tasks:       tasks,
+		Comments:    comments,
+		Audit:       audit,
+	}, nil
+}
+
+func (pu *privacyUsecase) EraseAccount(c context.Context, userID string, password string) error {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	if _, err := reauthenticate(ctx, pu.userRepository, userID, password); err != nil {
+		return err
+	}
+	return pu.erase(ctx, userID, userID)
+}
+
+func (pu *privacyUsecase) Erase(c context.Context, actorID string, userID string) error {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+	return pu.erase(ctx, actorID, userID)
+}
+
+// erase anonymizes the user in place instead of deleting it, so tasks and
+// audit entries that point at the user keep resolving. Tasks assigned to the
+// user are unassigned; tasks and comments they wrote stay with the anonymized
+// record. Every change is made in one transaction, with UserErased.
+func (pu *privacyUsecase) erase(ctx context.Context, actorID string, userID string) error {
+	user, err := pu.userRepository.FetchByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Erased_at != nil {
+		return domain.ErrUserErased
+	}
+
+	// Refuse before changing anything if an organization would be left
+	// with members but no owner.
+	memberships, err := pu.organizationRepository.FetchMemberships(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, membership := range memberships {
+		if membership.Role != domain.OrgRoleOwner {
+			continue
+		}
+		owners, err := pu.organizationRepository.CountOwners(ctx, membership.Org_id)
+		if err != nil {
+			return err
+		}
+		members, err := pu.organizationRepository.FetchMembers(ctx, membership.Org_id)
+		if err != nil {
+			return err
+		}
+		if owners <= 1 && len(members) > 1 {
+			return domain.ErrLastOwner
+		}
+	}
+
+	now := time.Now()
+	return pu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := pu.userRepository.Anonymize(tx, userID, now); err != nil {
+			return err
+		}
+		if err := pu.taskRepository.Unassign(tx, userID); err != nil {
+			return err
+		}
+		if err := pu.organizationRepository.RemoveUser(tx, userID); err != nil {
+			return err
+		}
+		if err := pu.sessionRepository.RevokeAll(tx, userID, now); err != nil {
+			return err
+		}
+		if err := pu.calendarFeedRepository.DeleteAllByUser(tx, userID); err != nil {
+			return err
+		}
+		if err := pu.viewRepository.DeletePrivateByOwner(tx, userID); err != nil {
+			return err
+		}
+		err := pu.auditRepository.Create(tx, &domain.AuditEntry{
+			Action:     domain.AuditUserErased,
+			Actor_id:   actorID,
+			Subject_id: userID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordEvents(tx, pu.outboxRepository, domain.UserErased{User_id: userID})
+	})
+}
@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type privacyUsecaseSuite struct {
+	suite.Suite
+	users         *mocks.UserRepository
+	organizations *mocks.OrganizationRepository
+	sessions      *mocks.SessionRepository
+	tasks         *mocks.TaskRepository
+	comments      *mocks.CommentRepository
+	feeds         *mocks.CalendarFeedRepository
+	views         *mocks.ViewRepository
+	audit         *mocks.AuditRepository
+	outbox        *mocks.OutboxRepository
+	transactor    *mocks.Transactor
+	usecase       domain.PrivacyUsecase
+}
+
+func (suite *privacyUsecaseSuite) SetupTest() {
+	suite.users = new(mocks.UserRepository)
+	suite.organizations = new(mocks.OrganizationRepository)
+	suite.sessions = new(mocks.SessionRepository)
+	suite.tasks = new(mocks.TaskRepository)
+	suite.comments = new(mocks.CommentRepository)
+	suite.feeds = new(mocks.CalendarFeedRepository)
+	suite.views = new(mocks.ViewRepository)
+	suite.audit = new(mocks.AuditRepository)
+	suite.outbox = new(mocks.OutboxRepository)
+	suite.outbox.On("Append", mock.Anything, mock.Anything).Return(nil).Maybe()
+	suite.transactor = passThroughTransactor()
+	suite.usecase = NewPrivacyUsecase(suite.users, suite.organizations, suite.sessions, suite.tasks, suite.comments, suite.feeds, suite.views, suite.audit, suite.outbox, suite.transactor, 10*time.Second)
+}
+
+func (suite *privacyUsecaseSuite) TestExport_CollectsEverything() {
+	suite.users.On("FetchByUserID", mock.Anything, "u1").Return(domain.User{User_id: "u1", Username: ptr("johndoe")}, nil)
+	suite.organizations.On("FetchMemberships", mock.Anything, "u1").Return([]domain.Membership{{Org_id: "org1", User_id: "u1"}}, nil)
+	suite.sessions.On("FetchActiveByUser", mock.Anything, "u1").Return([]domain.Session{{User_id: "u1", Ip: "203.0.113.7"}}, nil)
+	suite.tasks.On("FetchByUser", mock.Anything, "u1").Return([]domain.Task{{Title: "mine", Created_by: "u1"}}, nil)
+	suite.comments.On("FetchByAuthor", mock.Anything, "u1").Return([]domain.Comment{{Body: "looks good", Author_id: "u1"}}, nil)
+	suite.audit.On("FetchAll", mock.Anything, domain.AuditFilter{Involving: "u1"}).Return([]domain.AuditEntry{{Action: domain.AuditImpersonationStart, Subject_id: "u1"}}, nil)
+
+	export, err := suite.usecase.Export(context.TODO(), "u1")
+	suite.Require().NoError(err)
+	suite.Equal("u1", export.Profile.User_id)
+	suite.Len(export.Memberships, 1)
+	suite.Len(export.Sessions, 1)
+	suite.Len(export.Tasks, 1)
+	suite.Len(export.Comments, 1)
+	suite.Len(export.Audit, 1)
+}
+
+func (suite *privacyUsecaseSuite) TestErase_AnonymizesAndUnassigns() {
+	suite.users.On("FetchByUserID", mock.Anything, "u1").Return(domain.User{User_id: "u1"}, nil)
+	suite.organizations.On("FetchMemberships", mock.Anything, "u1").Return([]domain.Membership{{Org_id: "personal", User_id: "u1", Role: domain.OrgRoleOwner}}, nil)
+	suite.organizations.On("CountOwners", mock.Anything, "personal").Return(int64(1), nil)
+	suite.organizations.On("FetchMembers", mock.Anything, "personal").Return([]domain.Membership{{Org_id: "personal", User_id: "u1"}}, nil)
+	suite.users.On("Anonymize", mock.Anything, "u1", mock.Anything).Return(nil)
+	suite.tasks.On("Unassign", mock.Anything, "u1").Return(nil)
+	suite.organizations.On("RemoveUser", mock.Anything, "u1").Return(nil)
+	suite.sessions.On("RevokeAll", mock.Anything, "u1", mock.Anything).Return(nil)
+	suite.feeds.On("DeleteAllByUser", mock.Anything, "u1").Return(nil)
+	suite.views.On("DeletePrivateByOwner", mock.Anything, "u1").Return(nil)
+	suite.audit.On("Create", mock.Anything, &domain.AuditEntry{Action: domain.AuditUserErased, Actor_id: "admin", Subject_id: "u1"}).Return(nil)
+
+	suite.NoError(suite.usecase.Erase(context.TODO(), "admin", "u1"))
+	suite.users.AssertExpectations(suite.T())
+	suite.tasks.AssertExpectations(suite.T())
+	suite.sessions.AssertExpectations(suite.T())
+	suite.feeds.AssertExpectations(suite.T())
+	suite.views.AssertExpectations(suite.T())
+	suite.audit.AssertExpectations(suite.T())
+	suite.users.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.transactor.AssertNumberOfCalls(suite.T(), "WithTransaction", 1)
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.UserErased) bool {
+		return event.User_id == "u1"
+	}))
+}
+
+func (suite *privacyUsecaseSuite) TestErase_SoleOwnerOfSharedOrganization() {
+	suite.users.On("FetchByUserID", mock.Anything, "u1").Return(domain.User{User_id: "u1"}, nil)
+	suite.organizations.On("FetchMemberships", mock.Anything, "u1").Return([]domain.Membership{{Org_id: "team", User_id: "u1", Role: domain.OrgRoleOwner}}, nil)
+	suite.organizations.On("CountOwners", mock.Anything, "team").Return(int64(1), nil)
+	suite.organizations.On("FetchMembers", mock.Anything, "team").Return([]domain.Membership{{User_id: "u1"}, {User_id: "u2"}}, nil)
+
+	err := suite.usecase.Erase(context.TODO(), "admin", "u1")
+	suite.ErrorIs(err, domain.ErrLastOwner)
+	suite.users.AssertNotCalled(suite.T(), "Anonymize", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *privacyUsecaseSuite) TestErase_AlreadyErased() {
+	erasedAt := time.Now()
+	suite.users.On("FetchByUserID", mock.Anything, "u1").Return(domain.User{User_id: "u1", Erased_at: &erasedAt}, nil)
+
+	suite.ErrorIs(suite.usecase.Erase(context.TODO(), "admin", "u1"), domain.ErrUserErased)
+}
+
+func TestPrivacyUsecase(t *testing.T) {
+	suite.Run(t, new(privacyUsecaseSuite))
+}
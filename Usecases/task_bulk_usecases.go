@@ -0,0 +1,337 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const defaultMaxBulkOperations = 100
+
+// errRolledBack is reported for items undone by a failed atomic request.
+var errRolledBack = errors.New("rolled back after another operation failed")
+
+type taskBulkUsecase struct {
+	taskRepository         domain.TaskRepository
+	attachmentRepository   domain.AttachmentRepository
+	blobStore              domain.BlobStore
+	customFieldRepository  domain.CustomFieldRepository
+	organizationRepository domain.OrganizationRepository
+	outboxRepository       domain.OutboxRepository
+	transactor             domain.Transactor
+	contextTimeout         time.Duration
+	maxOperations          int
+	now                    func() time.Time
+}
+
+func NewTaskBulkUsecase(taskRepository domain.TaskRepository, attachmentRepository domain.AttachmentRepository, blobStore domain.BlobStore, customFieldRepository domain.CustomFieldRepository, organizationRepository domain.OrganizationRepository, outboxRepository domain.OutboxRepository, transactor domain.Transactor, timeout time.Duration) domain.TaskBulkUsecase {
+	return &taskBulkUsecase{
+		taskRepository:         taskRepository,
+		attachmentRepository:   attachmentRepository,
+		blobStore:              blobStore,
+		customFieldRepository:  customFieldRepository,
+		organizationRepository: organizationRepository,
+		outboxRepository:       outboxRepository,
+		transactor:             transactor,
+		contextTimeout:         timeout,
+		maxOperations:          positiveIntFromEnv("BULK_MAX_OPERATIONS", defaultMaxBulkOperations),
+		now:                    time.Now,
+	}
+}
+
+// Execute checks the whole request before running any of it. Without
+// Atomic every operation runs in a transaction of its own; with it, the
+// first failure rolls back the ones before it and the rest are not
+// attempted. Attachments of deleted tasks are only purged once their
+// deletion is committed.
+func (bu *taskBulkUsecase) Execute(c context.Context, userID string, request domain.BulkTaskRequest) (*domain.BulkTaskResult, error) {
+	ctx, cancel := context.WithTimeout(c, bu.contextTimeout)
+	defer cancel()
+
+	operations, err := bu.plan(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := bu.customFieldRepository.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.BulkTaskResult{Atomic: request.Atomic, Results: []domain.BulkTaskItemResult{}}
+	if !request.Atomic {
+		for i, operation := range operations {
+			item := domain.BulkTaskItemResult{Index: i, Op: operation.Op, Task_id: operation.Task_id}
+			err := bu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+				var err error
+				item, err = bu.apply(tx, userID, fields, i, operation)
+				return err
+			})
+			if err != nil {
+				// the commit itself may fail after apply succeeded
+				item.Success = false
+				item.Error = err.Error()
+			}
+			if item.Success && operation.Op == domain.BulkDelete {
+				bu.purge(ctx, operation.Task_id)
+			}
+			result.Results = append(result.Results, item)
+		}
+		result.Committed = true
+		countBulkResults(result)
+		return result, nil
+	}
+
+	failed := false
+	err = bu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		// a retried transaction starts over
+		result.Results = []domain.BulkTaskItemResult{}
+		failed = false
+		for i, operation := range operations {
+			item, err := bu.apply(tx, userID, fields, i, operation)
+			result.Results = append(result.Results, item)
+			if err != nil {
+				// returned as is so transient errors still retry
+				failed = true
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !failed {
+		return nil, err
+	}
+	if failed {
+		for i := range result.Results {
+			if result.Results[i].Success {
+				result.Results[i].Success = false
+				result.Results[i].Error = errRolledBack.Error()
+			}
+		}
+		for i := len(result.Results); i < len(operations); i++ {
+			result.Results = append(result.Results, domain.BulkTaskItemResult{
+				Index: i, Op: operations[i].Op, Task_id: operations[i].Task_id, Error: "not attempted",
+			})
+		}
+		countBulkResults(result)
+		return result, nil
+	}
+	for _, operation := range operations {
+		if operation.Op == domain.BulkDelete {
+			bu.purge(ctx, operation.Task_id)
+		}
+	}
+	result.Committed = true
+	countBulkResults(result)
+	return result, nil
+}
+
+// plan checks the shape and size of the request and turns a filter update
+// into one update operation per matching task.
+func (bu *taskBulkUsecase) plan(ctx context.Context, request domain.BulkTaskRequest) ([]domain.BulkTaskOperation, error) {
+	if len(request.Operations) > 0 {
+		if request.Filter != "" || request.Update != nil {
+			return nil, fmt.Errorf("%w: send either operations or a filter with an update, not both", domain.ErrInvalidBulkRequest)
+		}
+		if len(request.Operations) > bu.maxOperations {
+			return nil, fmt.Errorf("%w: %d operations, at most %d are allowed", domain.ErrBulkTooLarge, len(request.Operations), bu.maxOperations)
+		}
+		for i, operation := range request.Operations {
+			if err := checkBulkOperation(operation); err != nil {
+				return nil, fmt.Errorf("%w: operation %d %s", domain.ErrInvalidBulkRequest, i, err)
+			}
+		}
+		return request.Operations, nil
+	}
+
+	if request.Filter == "" || request.Update == nil {
+		return nil, fmt.Errorf("%w: send a list of operations, or a filter with an update", domain.ErrInvalidBulkRequest)
+	}
+	filter, err := buildTaskFilter(ctx, bu.customFieldRepository, domain.TaskQuery{Filter: request.Filter})
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := bu.taskRepository.FetchAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	operations := []domain.BulkTaskOperation{}
+	if tasks != nil {
+		if len(*tasks) > bu.maxOperations {
+			return nil, fmt.Errorf("%w: the filter matches %d tasks, at most %d are allowed", domain.ErrBulkTooLarge, len(*tasks), bu.maxOperations)
+		}
+		for _, task := range *tasks {
+			operations = append(operations, domain.BulkTaskOperation{Op: domain.BulkUpdate, Task_id: task.ID.Hex(), Update: request.Update})
+		}
+	}
+	return operations, nil
+}
+
+func checkBulkOperation(operation domain.BulkTaskOperation) error {
+	switch operation.Op {
+	case domain.BulkCreate:
+		if operation.Task == nil {
+			return errors.New("needs a task to create")
+		}
+	case domain.BulkUpdate:
+		if operation.Task_id == "" || operation.Update == nil {
+			return errors.New("needs a task_id and an update")
+		}
+	case domain.BulkDelete:
+		if operation.Task_id == "" {
+			return errors.New("needs a task_id")
+		}
+	default:
+		return fmt.Errorf("has unknown op %q", operation.Op)
+	}
+	return nil
+}
+
+// apply runs one operation and records its events, reporting a failure in
+// the item as well as returning it.
+func (bu *taskBulkUsecase) apply(ctx context.Context, userID string, fields []domain.CustomField, index int, operation domain.BulkTaskOperation) (domain.BulkTaskItemResult, error) {
+	item := domain.BulkTaskItemResult{Index: index, Op: operation.Op, Task_id: operation.Task_id}
+	var events []domain.DomainEvent
+	var err error
+	switch operation.Op {
+	case domain.BulkCreate:
+		task := *operation.Task
+		task.ID = primitive.NewObjectID()
+		task.Created_by = userID
+		item.Task_id = task.ID.Hex()
+		if err = prepareTask(ctx, bu.organizationRepository, fields, &task, bu.now()); err == nil {
+			err = bu.taskRepository.Create(ctx, &task)
+		}
+		fillCompletion(&task)
+		events = []domain.DomainEvent{domain.TaskCreated{Task: task}}
+	case domain.BulkUpdate:
+		events, err = bu.update(ctx, fields, operation.Task_id, *operation.Update)
+	case domain.BulkDelete:
+		events, err = bu.delete(ctx, operation.Task_id)
+	}
+	if err == nil {
+		err = recordEvents(ctx, bu.outboxRepository, events...)
+	}
+	if err != nil {
+		item.Error = err.Error()
+		return item, err
+	}
+	item.Success = true
+	return item, nil
+}
+
+func (bu *taskBulkUsecase) update(ctx context.Context, fields []domain.CustomField, taskID string, patch domain.TaskPatch) ([]domain.DomainEvent, error) {
+	task, err := bu.fetch(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	previousStatus := task.Status
+	if err := patchTask(ctx, bu.organizationRepository, fields, task, patch); err != nil {
+		return nil, err
+	}
+	if err := bu.taskRepository.Update(ctx, taskID, *task); err != nil {
+		return nil, err
+	}
+	if task, err = bu.fetch(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return taskUpdatedEvents(*task, previousStatus), nil
+}
+
+func (bu *taskBulkUsecase) delete(ctx context.Context, taskID string) ([]domain.DomainEvent, error) {
+	task, err := bu.fetch(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bu.taskRepository.Delete(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return []domain.DomainEvent{domain.TaskDeleted{Task: *task}}, nil
+}
+
+func (bu *taskBulkUsecase) fetch(ctx context.Context, taskID string) (*domain.Task, error) {
+	task, err := bu.taskRepository.FetchByTaskID(ctx, taskID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	fillCompletion(task)
+	return task, nil
+}
+
+// patchTask applies patch to a stored task, ready for TaskRepository.Update.
+// Custom field values are merged and checked; without any in patch they are
+// cleared from task so the update leaves them alone.
+func patchTask(ctx context.Context, organizationRepository domain.OrganizationRepository, fields []domain.CustomField, task *domain.Task, patch domain.TaskPatch) error {
+	applyTaskPatch(task, patch)
+	if patch.Custom_fields == nil {
+		task.Custom_fields = nil
+		return nil
+	}
+	values := make(map[string]interface{}, len(task.Custom_fields)+len(patch.Custom_fields))
+	for key, value := range task.Custom_fields {
+		values[key] = value
+	}
+	for key, value := range patch.Custom_fields {
+		values[key] = value
+	}
+	resolved, err := resolveCustomFields(ctx, organizationRepository, fields, values)
+	if err != nil {
+		return err
+	}
+	task.Custom_fields = resolved
+	return nil
+}
+
+func applyTaskPatch(task *domain.Task, patch domain.TaskPatch) {
+	if patch.Title != nil {
+		task.Title = *patch.Title
+	}
+	if patch.Description != nil {
+		task.Description = *patch.Description
+	}
+	if patch.Status != nil {
+		task.Status = *patch.Status
+	}
+	if patch.Due_date != nil {
+		task.DueDate = *patch.Due_date
+	}
+	if patch.Assignee_id != nil {
+		task.Assignee_id = *patch.Assignee_id
+	}
+	if patch.Labels != nil {
+		task.Labels = *patch.Labels
+	}
+	if patch.Original_estimate != nil {
+		task.Original_estimate = *patch.Original_estimate
+	}
+	if patch.Remaining_estimate != nil {
+		task.Remaining_estimate = *patch.Remaining_estimate
+	}
+}
+
+// purge removes a deleted task's attachments; leftovers are logged for
+// manual cleanup.
+func (bu *taskBulkUsecase) purge(ctx context.Context, taskID string) {
+	if err := purgeAttachments(ctx, bu.attachmentRepository, bu.blobStore, taskID); err != nil {
+		log.Printf("failed to purge attachments of task %s: %v", taskID, err)
+	}
+}
+
+func countBulkResults(result *domain.BulkTaskResult) {
+	result.Succeeded, result.Failed = 0, 0
+	for _, item := range result.Results {
+		if item.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type taskBulkUsecaseSuite struct {
+	suite.Suite
+	tasks       *mocks.TaskRepository
+	attachments *mocks.AttachmentRepository
+	fields      *mocks.CustomFieldRepository
+	orgs        *mocks.OrganizationRepository
+	outbox      *mocks.OutboxRepository
+	transactor  *mocks.Transactor
+	usecase     *taskBulkUsecase
+}
+
+func (suite *taskBulkUsecaseSuite) SetupTest() {
+	suite.tasks = new(mocks.TaskRepository)
+	suite.attachments = new(mocks.AttachmentRepository)
+	suite.fields = new(mocks.CustomFieldRepository)
+	suite.orgs = new(mocks.OrganizationRepository)
+	suite.outbox = new(mocks.OutboxRepository)
+	suite.transactor = new(mocks.Transactor)
+	suite.usecase = NewTaskBulkUsecase(suite.tasks, suite.attachments, new(mocks.BlobStore), suite.fields, suite.orgs, suite.outbox, suite.transactor, 10*time.Second).(*taskBulkUsecase)
+
+	suite.fields.On("FetchAll", mock.Anything).Return([]domain.CustomField{
+		{Key: "priority", Type: domain.CustomFieldEnum, Options: []string{"low", "high"}},
+		{Key: "points", Type: domain.CustomFieldNumber},
+	}, nil).Maybe()
+	suite.attachments.On("FetchByTask", mock.Anything, mock.Anything).Return([]domain.Attachment{}, nil).Maybe()
+	// the mock transactor runs fn straight away and hands back its error
+	suite.transactor.On("WithTransaction", mock.Anything, mock.Anything).Return(func(c context.Context, fn func(context.Context) error) error {
+		return fn(c)
+	}).Maybe()
+	suite.outbox.On("Append", mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+func (suite *taskBulkUsecaseSuite) TestExecute_RejectsBadShapesAndLargeBatches() {
+	status := "done"
+	for name, request := range map[string]domain.BulkTaskRequest{
+		"empty":             {},
+		"filter, no update": {Filter: "status = todo"},
+		"both":              {Operations: []domain.BulkTaskOperation{{Op: domain.BulkDelete, Task_id: "t1"}}, Filter: "status = todo", Update: &domain.TaskPatch{Status: &status}},
+		"update without id": {Operations: []domain.BulkTaskOperation{{Op: domain.BulkUpdate, Update: &domain.TaskPatch{Status: &status}}}},
+		"create, no task":   {Operations: []domain.BulkTaskOperation{{Op: domain.BulkCreate}}},
+	} {
+		_, err := suite.usecase.Execute(context.TODO(), "u1", request)
+		suite.ErrorIs(err, domain.ErrInvalidBulkRequest, name)
+	}
+
+	suite.usecase.maxOperations = 2
+	operations := []domain.BulkTaskOperation{{Op: domain.BulkDelete, Task_id: "a"}, {Op: domain.BulkDelete, Task_id: "b"}, {Op: domain.BulkDelete, Task_id: "c"}}
+	_, err := suite.usecase.Execute(context.TODO(), "u1", domain.BulkTaskRequest{Operations: operations})
+	suite.ErrorIs(err, domain.ErrBulkTooLarge)
+
+	suite.tasks.On("FetchAll", mock.Anything, mock.Anything).Return(&[]domain.Task{{ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}}, nil)
+	_, err = suite.usecase.Execute(context.TODO(), "u1", domain.BulkTaskRequest{Filter: "status = todo", Update: &domain.TaskPatch{Status: &status}})
+	suite.ErrorIs(err, domain.ErrBulkTooLarge)
+	suite.tasks.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.tasks.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *taskBulkUsecaseSuite) TestExecute_ReportsEachItem() {
+	status := "done"
+	suite.tasks.On("Create", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Title == "New" && task.Created_by == "u1" && !task.ID.IsZero()
+	})).Return(nil)
+	suite.tasks.On("FetchByTaskID", mock.Anything, "gone").Return(&domain.Task{}, mongo.ErrNoDocuments)
+	old := domain.Task{ID: primitive.NewObjectID(), Title: "Old"}
+	suite.tasks.On("FetchByTaskID", mock.Anything, "old").Return(&old, nil)
+	suite.tasks.On("Delete", mock.Anything, "old").Return(nil)
+
+	result, err := suite.usecase.Execute(context.TODO(), "u1", domain.BulkTaskRequest{Operations: []domain.BulkTaskOperation{
+		{Op: domain.BulkCreate, Task: &domain.Task{Title: "New"}},
+		{Op: domain.BulkUpdate, Task_id: "gone", Update: &domain.TaskPatch{Status: &status}},
+		{Op: domain.BulkCreate, Task: &domain.Task{Title: "Bad", Custom_fields: map[string]interface{}{"priority": "urgent"}}},
+		{Op: domain.BulkDelete, Task_id: "old"},
+	}})
+	suite.Require().NoError(err)
+	suite.True(result.Committed)
+	suite.Equal(2, result.Succeeded)
+	suite.Equal(2, result.Failed)
+	suite.Require().Len(result.Results, 4)
+	suite.True(result.Results[0].Success)
+	suite.NotEmpty(result.Results[0].Task_id)
+	suite.Equal(domain.ErrTaskNotFound.Error(), result.Results[1].Error)
+	suite.Contains(result.Results[2].Error, "priority")
+	suite.True(result.Results[3].Success)
+	suite.attachments.AssertCalled(suite.T(), "FetchByTask", mock.Anything, "old")
+	suite.transactor.AssertNumberOfCalls(suite.T(), "WithTransaction", 4)
+	suite.outbox.AssertNumberOfCalls(suite.T(), "Append", 2)
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskCreated) bool {
+		return event.Task.Title == "New" && event.Task.ID.Hex() == result.Results[0].Task_id
+	}))
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskDeleted) bool {
+		return event.Task.Title == "Old"
+	}))
+}
+
+func (suite *taskBulkUsecaseSuite) TestExecute_FailedCommitFailsTheItem() {
+	transactor := new(mocks.Transactor)
+	transactor.On("WithTransaction", mock.Anything, mock.Anything).Return(func(c context.Context, fn func(context.Context) error) error {
+		if err := fn(c); err != nil {
+			return err
+		}
+		return errors.New("commit aborted")
+	})
+	suite.usecase.transactor = transactor
+	suite.tasks.On("FetchByTaskID", mock.Anything, "a").Return(&domain.Task{ID: primitive.NewObjectID()}, nil)
+	suite.tasks.On("Delete", mock.Anything, "a").Return(nil)
+
+	result, err := suite.usecase.Execute(context.TODO(), "u1", domain.BulkTaskRequest{Operations: []domain.BulkTaskOperation{
+		{Op: domain.BulkDelete, Task_id: "a"},
+	}})
+	suite.Require().NoError(err)
+	suite.Equal(0, result.Succeeded)
+	suite.Equal(1, result.Failed)
+	suite.False(result.Results[0].Success)
+	suite.Equal("commit aborted", result.Results[0].Error)
+	suite.attachments.AssertNotCalled(suite.T(), "FetchByTask", mock.Anything, mock.Anything)
+}
+
+func (suite *taskBulkUsecaseSuite) TestExecute_AtomicRollsBackOnFailure() {
+	suite.tasks.On("FetchByTaskID", mock.Anything, mock.Anything).Return(&domain.Task{ID: primitive.NewObjectID()}, nil)
+	suite.tasks.On("Delete", mock.Anything, "a").Return(nil)
+	suite.tasks.On("Delete", mock.Anything, "b").Return(errors.New("task not found"))
+
+	result, err := suite.usecase.Execute(context.TODO(), "u1", domain.BulkTaskRequest{Atomic: true, Operations: []domain.BulkTaskOperation{
+		{Op: domain.BulkDelete, Task_id: "a"},
+		{Op: domain.BulkDelete, Task_id: "b"},
+		{Op: domain.BulkDelete, Task_id: "c"},
+	}})
+	suite.Require().NoError(err)
+	suite.False(result.Committed)
+	suite.Equal(0, result.Succeeded)
+	suite.Equal(3, result.Failed)
+	suite.Equal(errRolledBack.Error(), result.Results[0].Error)
+	suite.Equal("task not found", result.Results[1].Error)
+	suite.Equal("not attempted", result.Results[2].Error)
+	suite.tasks.AssertNotCalled(suite.T(), "Delete", mock.Anything, "c")
+	suite.attachments.AssertNotCalled(suite.T(), "FetchByTask", mock.Anything, mock.Anything)
+}
+
+func (suite *taskBulkUsecaseSuite) TestExecute_AtomicCommitsThenPurges() {
+	suite.tasks.On("FetchByTaskID", mock.Anything, "a").Return(&domain.Task{ID: primitive.NewObjectID()}, nil)
+	suite.tasks.On("Delete", mock.Anything, "a").Return(nil)
+
+	result, err := suite.usecase.Execute(context.TODO(), "u1", domain.BulkTaskRequest{Atomic: true, Operations: []domain.BulkTaskOperation{
+		{Op: domain.BulkDelete, Task_id: "a"},
+	}})
+	suite.Require().NoError(err)
+	suite.True(result.Committed)
+	suite.Equal(1, result.Succeeded)
+	suite.transactor.AssertNumberOfCalls(suite.T(), "WithTransaction", 1)
+	suite.attachments.AssertCalled(suite.T(), "FetchByTask", mock.Anything, "a")
+}
+
+func (suite *taskBulkUsecaseSuite) TestExecute_FilterUpdatePatchesMatches() {
+	first := domain.Task{ID: primitive.NewObjectID(), Title: "One", Status: "todo", Custom_fields: map[string]interface{}{"points": float64(3)}}
+	second := domain.Task{ID: primitive.NewObjectID(), Title: "Two", Status: "todo"}
+	suite.tasks.On("FetchAll", mock.Anything, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+		return filter.Expression != nil
+	})).Return(&[]domain.Task{first, second}, nil)
+	suite.tasks.On("FetchByTaskID", mock.Anything, first.ID.Hex()).Return(&first, nil)
+	suite.tasks.On("FetchByTaskID", mock.Anything, second.ID.Hex()).Return(&second, nil)
+	suite.tasks.On("Update", mock.Anything, first.ID.Hex(), mock.MatchedBy(func(task domain.Task) bool {
+		return task.Title == "One" && task.Status == "done" && task.Custom_fields["points"] == float64(3) && task.Custom_fields["priority"] == "high"
+	})).Return(nil)
+	suite.tasks.On("Update", mock.Anything, second.ID.Hex(), mock.MatchedBy(func(task domain.Task) bool {
+		return task.Title == "Two" && task.Status == "done" && task.Custom_fields["priority"] == "high"
+	})).Return(nil)
+
+	status := "done"
+	result, err := suite.usecase.Execute(context.TODO(), "u1", domain.BulkTaskRequest{
+		Filter: "status = todo",
+		Update: &domain.TaskPatch{Status: &status, Custom_fields: map[string]interface{}{"priority": "high"}},
+	})
+	suite.Require().NoError(err)
+	suite.Equal(2, result.Succeeded)
+	suite.Equal(first.ID.Hex(), result.Results[0].Task_id)
+	suite.Equal(domain.BulkUpdate, result.Results[1].Op)
+	for _, task := range []domain.Task{first, second} {
+		taskID := task.ID
+		suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskUpdated) bool {
+			return event.Task.ID == taskID
+		}))
+		suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskStatusChanged) bool {
+			return event.Task.ID == taskID && event.Task.Status == "done" && event.Previous_status == "todo"
+		}))
+	}
+}
+
+func TestTaskBulkUsecase(t *testing.T) {
+	suite.Run(t, new(taskBulkUsecaseSuite))
+}
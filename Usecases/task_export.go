@@ -0,0 +1,247 @@
+package usecases
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
+)
+
+// taskEncoder writes tasks one at a time in an export format.
+type taskEncoder interface {
+	encode(task domain.Task) error
+	// close writes whatever follows the last task.
+	close() error
+}
+
+// Export checks the query within the usual timeout, then streams without
+// one: a large export takes as long as the client keeps reading.
+func (tu *taskUsecase) Export(c context.Context, query domain.TaskQuery, format string, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	filter, err := buildTaskFilter(ctx, tu.customFieldRepository, query)
+	if err != nil {
+		cancel()
+		return err
+	}
+	fields, err := tu.customFieldRepository.FetchAll(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	var encoder taskEncoder
+	switch format {
+	case domain.ExportCSV:
+		encoder = newCSVTaskEncoder(w, fields)
+	case domain.ExportNDJSON:
+		encoder = &ndjsonTaskEncoder{encoder: json.NewEncoder(w)}
+	case domain.ExportMarkdown:
+		encoder = &markdownTaskEncoder{w: w, fields: fields}
+	case domain.ExportICS:
+		encoder = &icsTaskEncoder{ical: newICalWriter(w, time.Now())}
+	default:
+		return fmt.Errorf("%w: unknown export format %q", domain.ErrInvalidTaskQuery, format)
+	}
+
+	err = tu.taskRepository.Stream(c, filter, func(task domain.Task) error {
+		fillCompletion(&task)
+		return encoder.encode(task)
+	})
+	if err != nil {
+		return err
+	}
+	return encoder.close()
+}
+
+// exportColumns head CSV exports, followed by cf.<key> for every custom
+// field. They are named after the fields POST /tasks/import reads.
+var exportColumns = []string{
+	"id", "external_id", "title", "description", "status", "due_date", "assignee_id", "created_by",
+	"labels", "original_estimate", "remaining_estimate", "parent_id", "completion",
+}
+
+type csvTaskEncoder struct {
+	writer *csv.Writer
+	fields []domain.CustomField
+	header bool
+}
+
+func newCSVTaskEncoder(w io.Writer, fields []domain.CustomField) *csvTaskEncoder {
+	return &csvTaskEncoder{writer: csv.NewWriter(w), fields: fields}
+}
+
+func (ce *csvTaskEncoder) writeHeader() error {
+	if ce.header {
+		return nil
+	}
+	ce.header = true
+	header := append([]string{}, exportColumns...)
+	for _, field := range ce.fields {
+		header = append(header, "cf."+field.Key)
+	}
+	return ce.writer.Write(header)
+}
+
+func (ce *csvTaskEncoder) encode(task domain.Task) error {
+	if err := ce.writeHeader(); err != nil {
+		return err
+	}
+	completion := ""
+	if task.Completion != nil {
+		completion = strconv.Itoa(*task.Completion)
+	}
+	record := []string{
+		task.ID.Hex(), csvText(task.External_id), csvText(task.Title), csvText(task.Description), csvText(task.Status), exportTime(task.DueDate),
+		csvText(task.Assignee_id), csvText(task.Created_by), csvText(strings.Join(task.Labels, ",")),
+		strconv.Itoa(task.Original_estimate), strconv.Itoa(task.Remaining_estimate), csvText(task.Parent_id), completion,
+	}
+	for _, field := range ce.fields {
+		value := task.Custom_fields[field.Key]
+		cell := exportCustomValue(value)
+		if _, ok := value.(string); ok {
+			cell = csvText(cell)
+		}
+		record = append(record, cell)
+	}
+	return ce.writer.Write(record)
+}
+
+// csvFormulaStarts are the characters spreadsheets read as the start of a
+// formula.
+const csvFormulaStarts = "=+-@\t\r"
+
+// csvText keeps spreadsheets from running user text as a formula by
+// prefixing it with an apostrophe, which parseImportCSV takes off again.
+// Numbers and dates are written as they are, so they stay numbers.
+func csvText(text string) string {
+	if text != "" && strings.ContainsRune(csvFormulaStarts, rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+func (ce *csvTaskEncoder) close() error {
+	if err := ce.writeHeader(); err != nil {
+		return err
+	}
+	ce.writer.Flush()
+	return ce.writer.Error()
+}
+
+type ndjsonTaskEncoder struct {
+	encoder *json.Encoder
+}
+
+func (ne *ndjsonTaskEncoder) encode(task domain.Task) error {
+	return ne.encoder.Encode(task)
+}
+
+func (ne *ndjsonTaskEncoder) close() error {
+	return nil
+}
+
+// markdownTaskEncoder writes a GitHub flavored table of the columns people
+// read, rather than every field.
+type markdownTaskEncoder struct {
+	w      io.Writer
+	fields []domain.CustomField
+	header bool
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+func (me *markdownTaskEncoder) writeHeader() error {
+	if me.header {
+		return nil
+	}
+	me.header = true
+	columns := []string{"Title", "Status", "Due date", "Assignee", "Labels", "Completion"}
+	for _, field := range me.fields {
+		columns = append(columns, markdownEscaper.Replace(field.Name))
+	}
+	return me.row(columns, strings.Repeat("| --- ", len(columns))+"|\n")
+}
+
+func (me *markdownTaskEncoder) encode(task domain.Task) error {
+	if err := me.writeHeader(); err != nil {
+		return err
+	}
+	completion := ""
+	if task.Completion != nil {
+		completion = fmt.Sprintf("%d%%", *task.Completion)
+	}
+	due := ""
+	if !task.DueDate.IsZero() {
+		due = task.DueDate.UTC().Format(time.DateOnly)
+	}
+	cells := []string{task.Title, task.Status, due, task.Assignee_id, strings.Join(task.Labels, ", "), completion}
+	for _, field := range me.fields {
+		cells = append(cells, exportCustomValue(task.Custom_fields[field.Key]))
+	}
+	for i := range cells {
+		cells[i] = markdownEscaper.Replace(cells[i])
+	}
+	return me.row(cells, "")
+}
+
+func (me *markdownTaskEncoder) row(cells []string, after string) error {
+	_, err := io.WriteString(me.w, "| "+strings.Join(cells, " | ")+" |\n"+after)
+	return err
+}
+
+func (me *markdownTaskEncoder) close() error {
+	return me.writeHeader()
+}
+
+type icsTaskEncoder struct {
+	ical  *icalWriter
+	begun bool
+}
+
+func (ie *icsTaskEncoder) encode(task domain.Task) error {
+	if !ie.begun {
+		ie.begun = true
+		if err := ie.ical.begin("Tasks"); err != nil {
+			return err
+		}
+	}
+	return ie.ical.todo(task)
+}
+
+func (ie *icsTaskEncoder) close() error {
+	if !ie.begun {
+		ie.begun = true
+		ie.ical.begin("Tasks")
+	}
+	return ie.ical.end()
+}
+
+func exportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// exportCustomValue writes custom field values the way an import reads
+// them back.
+func exportCustomValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case time.Time:
+		return value.UTC().Format(time.DateOnly)
+	case interface{ Time() time.Time }:
+		return value.Time().UTC().Format(time.DateOnly)
+	}
+	return fmt.Sprint(value)
+}
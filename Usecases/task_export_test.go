@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type taskExportSuite struct {
+	suite.Suite
+	tasks   *mocks.TaskRepository
+	fields  *mocks.CustomFieldRepository
+	usecase domain.TaskUsecase
+	task    domain.Task
+}
+
+func (suite *taskExportSuite) SetupTest() {
+	suite.tasks = new(mocks.TaskRepository)
+	suite.fields = new(mocks.CustomFieldRepository)
+	suite.usecase = NewTaskUsecase(suite.tasks, new(mocks.AttachmentRepository), new(mocks.BlobStore), suite.fields, new(mocks.OrganizationRepository), new(mocks.OutboxRepository), new(mocks.Transactor), 10*time.Second)
+
+	suite.fields.On("FetchAll", mock.Anything).Return([]domain.CustomField{{Key: "points", Name: "Story points", Type: domain.CustomFieldNumber}}, nil).Maybe()
+	suite.task = domain.Task{
+		ID:            primitive.NewObjectID(),
+		Title:         "Ship, then | celebrate",
+		Description:   "line one\nline two",
+		Status:        "done",
+		DueDate:       time.Date(2026, 11, 2, 9, 30, 0, 0, time.UTC),
+		Labels:        []string{"ops", "q4"},
+		Checklist:     []domain.ChecklistItem{{Text: "a", Done: true}, {Text: "b"}},
+		Custom_fields: map[string]interface{}{"points": float64(3)},
+	}
+	suite.tasks.On("Stream", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(domain.Task) error)
+		fn(suite.task)
+	}).Return(nil).Maybe()
+}
+
+func (suite *taskExportSuite) export(format string) string {
+	var out bytes.Buffer
+	suite.Require().NoError(suite.usecase.Export(context.TODO(), domain.TaskQuery{}, format, &out))
+	return out.String()
+}
+
+func (suite *taskExportSuite) TestCSV() {
+	out := suite.export(domain.ExportCSV)
+	suite.True(strings.HasPrefix(out, strings.Join(exportColumns, ",")+",cf.points\n"))
+	suite.Contains(out, `,"Ship, then | celebrate","line one`+"\n"+`line two",done,2026-11-02T09:30:00Z,`)
+	suite.Contains(out, `,"ops,q4",0,0,,50,3`+"\n")
+}
+
+func (suite *taskExportSuite) TestCSV_EscapesFormulas() {
+	suite.task.Title = "=HYPERLINK(\"http://evil.example\")"
+	suite.task.Description = "-1"
+	suite.task.Labels = []string{"@ops"}
+	suite.task.Original_estimate = -2
+
+	out := suite.export(domain.ExportCSV)
+	suite.Contains(out, `,"'=HYPERLINK(""http://evil.example"")",'-1,done,`)
+	suite.Contains(out, `,'@ops,-2,0,`)
+
+	rows, err := parseImportCSV([]byte(out), nil)
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 1)
+	suite.Equal(suite.task.Title, rows[0].values["title"], "importing undoes the escaping")
+	suite.Equal("-1", rows[0].values["description"])
+}
+
+func (suite *taskExportSuite) TestNDJSON() {
+	var task domain.Task
+	suite.Require().NoError(json.Unmarshal([]byte(suite.export(domain.ExportNDJSON)), &task))
+	suite.Equal(suite.task.Title, task.Title)
+	suite.Equal(50, *task.Completion)
+}
+
+func (suite *taskExportSuite) TestMarkdown() {
+	lines := strings.Split(suite.export(domain.ExportMarkdown), "\n")
+	suite.Equal("| Title | Status | Due date | Assignee | Labels | Completion | Story points |", lines[0])
+	suite.Equal(`| Ship, then \| celebrate | done | 2026-11-02 |  | ops, q4 | 50% | 3 |`, lines[2])
+}
+
+func (suite *taskExportSuite) TestICS() {
+	out := suite.export(domain.ExportICS)
+	suite.True(strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	suite.Contains(out, "SUMMARY:Ship\\, then | celebrate\r\n")
+	suite.Contains(out, "DESCRIPTION:line one\\nline two\r\n")
+	suite.Contains(out, "DUE:20261102T093000Z\r\n")
+	suite.Contains(out, "STATUS:COMPLETED\r\n")
+	suite.Contains(out, "CATEGORIES:ops,q4\r\n")
+	suite.True(strings.HasSuffix(out, "END:VTODO\r\nEND:VCALENDAR\r\n"))
+}
+
+func (suite *taskExportSuite) TestInvalidQueryWritesNothing() {
+	var out bytes.Buffer
+	err := suite.usecase.Export(context.TODO(), domain.TaskQuery{Filter: "status ="}, domain.ExportCSV, &out)
+	suite.ErrorIs(err, domain.ErrInvalidFilter)
+	err = suite.usecase.Export(context.TODO(), domain.TaskQuery{}, "xlsx", &out)
+	suite.ErrorIs(err, domain.ErrInvalidTaskQuery)
+	suite.Zero(out.Len())
+	suite.tasks.AssertNotCalled(suite.T(), "Stream", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestICalLinesAreFolded(t *testing.T) {
+	var out bytes.Buffer
+	writer := newICalWriter(&out, time.Now())
+	writer.line("SUMMARY", strings.Repeat("é", 100))
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n") {
+		if len(line) > icalLineOctets {
+			t.Fatalf("line of %d octets", len(line))
+		}
+		if !strings.HasPrefix(line, "SUMMARY:") && !strings.HasPrefix(line, " é") {
+			t.Fatalf("continuation split a character: %q", line)
+		}
+	}
+}
+
+func TestTaskExport(t *testing.T) {
+	suite.Run(t, new(taskExportSuite))
+}
@@ -0,0 +1,528 @@
+package usecases
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxFilterLength      = 2000
+	maxFilterDepth       = 16
+	maxFilterComparisons = 50
+	maxFilterInValues    = 100
+)
+
+// Kinds of filterable fields, which decide the operators they accept.
+const (
+	filterText    = "text"
+	filterKeyword = "keyword"
+	filterNumber  = "number"
+	filterDate    = "date"
+	filterList    = "list"
+)
+
+var filterOperators = map[string][]string{
+	filterText:    {domain.FilterEq, domain.FilterNe, domain.FilterIn, domain.FilterContains},
+	filterKeyword: {domain.FilterEq, domain.FilterNe, domain.FilterIn},
+	filterNumber:  {domain.FilterEq, domain.FilterNe, domain.FilterLt, domain.FilterLte, domain.FilterGt, domain.FilterGte, domain.FilterIn},
+	filterDate:    {domain.FilterEq, domain.FilterNe, domain.FilterLt, domain.FilterLte, domain.FilterGt, domain.FilterGte},
+	filterList:    {domain.FilterEq, domain.FilterNe, domain.FilterIn},
+}
+
+type filterField struct {
+	path    string
+	kind    string
+	options []string
+}
+
+// taskFilterFields is the whitelist of domain.Task fields a filter may
+// name, by the names clients use.
+var taskFilterFields = map[string]filterField{
+	"title":              {path: "title", kind: filterText},
+	"description":        {path: "description", kind: filterText},
+	"status":             {path: "status", kind: filterKeyword},
+	"due":                {path: "duedate", kind: filterDate},
+	"due_date":           {path: "duedate", kind: filterDate},
+	"assignee":           {path: "assignee_id", kind: filterKeyword},
+	"assignee_id":        {path: "assignee_id", kind: filterKeyword},
+	"created_by":         {path: "created_by", kind: filterKeyword},
+	"parent":             {path: "parent_id", kind: filterKeyword},
+	"parent_id":          {path: "parent_id", kind: filterKeyword},
+	"label":              {path: "labels", kind: filterList},
+	"labels":             {path: "labels", kind: filterList},
+	"original_estimate":  {path: "original_estimate", kind: filterNumber},
+	"remaining_estimate": {path: "remaining_estimate", kind: filterNumber},
+}
+
+// parseTaskFilter parses and checks a filter expression. Besides the
+// built-in fields it accepts the organization's custom fields, as cf.<key>
+// or by their bare key when no built-in field has that name.
+//
+//	expr       = or
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" expr ")" | comparison
+//	comparison = field op value | field "in" "(" value { "," value } ")"
+//	op         = "=" | "!=" | "<" | "<=" | ">" | ">=" | "contains"
+//
+// Keywords are case-insensitive; values are bare words or "quoted strings".
+func parseTaskFilter(expression string, customFields []domain.CustomField) (domain.FilterExpr, error) {
+	if utf8.RuneCountInString(expression) > maxFilterLength {
+		return domain.FilterExpr{}, &domain.FilterError{Position: maxFilterLength + 1, Message: fmt.Sprintf("filters are limited to %d characters", maxFilterLength)}
+	}
+	tokens, err := lexFilter(expression)
+	if err != nil {
+		return domain.FilterExpr{}, err
+	}
+	parser := filterParser{tokens: tokens, fields: map[string]filterField{}}
+	for _, field := range customFields {
+		resolved := filterField{path: "custom_fields." + field.Key, kind: filterKeyword, options: field.Options}
+		switch field.Type {
+		case domain.CustomFieldText:
+			resolved.kind = filterText
+		case domain.CustomFieldNumber:
+			resolved.kind = filterNumber
+		case domain.CustomFieldDate:
+			resolved.kind = filterDate
+		}
+		parser.fields["cf."+field.Key] = resolved
+		if _, builtIn := taskFilterFields[field.Key]; !builtIn {
+			parser.fields[field.Key] = resolved
+		}
+	}
+	for name, field := range taskFilterFields {
+		parser.fields[name] = field
+	}
+
+	expr, err := parser.or(0)
+	if err != nil {
+		return domain.FilterExpr{}, err
+	}
+	if next := parser.peek(); next.kind != filterEOF {
+		return domain.FilterExpr{}, next.errorf("unexpected %s", next)
+	}
+	return expr, nil
+}
+
+const (
+	filterEOF      = "end of filter"
+	filterWord     = "word"
+	filterString   = "string"
+	filterOperator = "operator"
+	filterLParen   = "("
+	filterRParen   = ")"
+	filterComma    = ","
+)
+
+type filterToken struct {
+	kind     string
+	text     string
+	position int
+}
+
+func (t filterToken) String() string {
+	switch t.kind {
+	case filterEOF:
+		return filterEOF
+	case filterString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func (t filterToken) errorf(format string, args ...interface{}) error {
+	return &domain.FilterError{Position: t.position, Message: fmt.Sprintf(format, args...)}
+}
+
+// keyword reports whether the token is the given keyword.
+func (t filterToken) keyword(word string) bool {
+	return t.kind == filterWord && strings.EqualFold(t.text, word)
+}
+
+func isFilterWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-:+", r)
+}
+
+func lexFilter(expression string) ([]filterToken, error) {
+	runes := []rune(expression)
+	tokens := []filterToken{}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		position := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, filterToken{kind: string(r), text: string(r), position: position})
+			i++
+		case r == '=':
+			tokens = append(tokens, filterToken{kind: filterOperator, text: "=", position: position})
+			i++
+		case r == '!' || r == '<' || r == '>':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, filterToken{kind: filterOperator, text: string(r) + "=", position: position})
+				i += 2
+				continue
+			}
+			if r == '!' {
+				return nil, &domain.FilterError{Position: position, Message: `"!" must be followed by "="`}
+			}
+			tokens = append(tokens, filterToken{kind: filterOperator, text: string(r), position: position})
+			i++
+		case r == '"':
+			var text strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					text.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				text.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, &domain.FilterError{Position: position, Message: "unterminated string"}
+			}
+			tokens = append(tokens, filterToken{kind: filterString, text: text.String(), position: position})
+		case isFilterWordRune(r):
+			start := i
+			for i < len(runes) && isFilterWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: filterWord, text: string(runes[start:i]), position: position})
+		default:
+			return nil, &domain.FilterError{Position: position, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return append(tokens, filterToken{kind: filterEOF, position: len(runes) + 1}), nil
+}
+
+type filterParser struct {
+	tokens      []filterToken
+	next        int
+	fields      map[string]filterField
+	comparisons int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.next]
+}
+
+func (p *filterParser) take() filterToken {
+	token := p.tokens[p.next]
+	if token.kind != filterEOF {
+		p.next++
+	}
+	return token
+}
+
+func (p *filterParser) or(depth int) (domain.FilterExpr, error) {
+	return p.chain(depth, domain.FilterOr, p.and)
+}
+
+func (p *filterParser) and(depth int) (domain.FilterExpr, error) {
+	return p.chain(depth, domain.FilterAnd, p.unary)
+}
+
+// chain parses operands joined by the keyword kind into one node.
+func (p *filterParser) chain(depth int, kind string, operand func(int) (domain.FilterExpr, error)) (domain.FilterExpr, error) {
+	first, err := operand(depth)
+	if err != nil {
+		return domain.FilterExpr{}, err
+	}
+	children := []domain.FilterExpr{first}
+	for p.peek().keyword(kind) {
+		p.take()
+		next, err := operand(depth)
+		if err != nil {
+			return domain.FilterExpr{}, err
+		}
+		children = append(children, next)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return domain.FilterExpr{Kind: kind, Children: children}, nil
+}
+
+func (p *filterParser) unary(depth int) (domain.FilterExpr, error) {
+	token := p.peek()
+	if depth > maxFilterDepth {
+		return domain.FilterExpr{}, token.errorf("filters nest at most %d levels deep", maxFilterDepth)
+	}
+	if token.keyword("not") {
+		p.take()
+		operand, err := p.unary(depth + 1)
+		if err != nil {
+			return domain.FilterExpr{}, err
+		}
+		return domain.FilterExpr{Kind: domain.FilterNot, Children: []domain.FilterExpr{operand}}, nil
+	}
+	if token.kind == filterLParen {
+		p.take()
+		expr, err := p.or(depth + 1)
+		if err != nil {
+			return domain.FilterExpr{}, err
+		}
+		if closing := p.take(); closing.kind != filterRParen {
+			return domain.FilterExpr{}, closing.errorf("expected \")\" but found %s", closing)
+		}
+		return expr, nil
+	}
+	return p.comparison()
+}
+
+func (p *filterParser) comparison() (domain.FilterExpr, error) {
+	name := p.take()
+	if name.kind != filterWord || name.keyword("and") || name.keyword("or") || name.keyword("in") || name.keyword("contains") {
+		return domain.FilterExpr{}, name.errorf("expected a field name but found %s", name)
+	}
+	field, ok := p.fields[name.text]
+	if !ok {
+		return domain.FilterExpr{}, name.errorf("unknown field %q", name.text)
+	}
+	p.comparisons++
+	if p.comparisons > maxFilterComparisons {
+		return domain.FilterExpr{}, name.errorf("filters hold at most %d comparisons", maxFilterComparisons)
+	}
+
+	operator := p.take()
+	var op string
+	switch {
+	case operator.kind == filterOperator:
+		op = operator.text
+	case operator.keyword(domain.FilterIn):
+		op = domain.FilterIn
+	case operator.keyword(domain.FilterContains):
+		op = domain.FilterContains
+	default:
+		return domain.FilterExpr{}, operator.errorf("expected an operator after %q but found %s", name.text, operator)
+	}
+	if !containsString(filterOperators[field.kind], op) {
+		return domain.FilterExpr{}, operator.errorf("%q does not support %s", name.text, op)
+	}
+
+	values := []filterToken{}
+	if op == domain.FilterIn {
+		if open := p.take(); open.kind != filterLParen {
+			return domain.FilterExpr{}, open.errorf("expected \"(\" after in but found %s", open)
+		}
+		for {
+			value := p.take()
+			if value.kind != filterWord && value.kind != filterString {
+				return domain.FilterExpr{}, value.errorf("expected a value but found %s", value)
+			}
+			values = append(values, value)
+			if len(values) > maxFilterInValues {
+				return domain.FilterExpr{}, value.errorf("in lists hold at most %d values", maxFilterInValues)
+			}
+			separator := p.take()
+			if separator.kind == filterRParen {
+				break
+			}
+			if separator.kind != filterComma {
+				return domain.FilterExpr{}, separator.errorf("expected \",\" or \")\" but found %s", separator)
+			}
+		}
+	} else {
+		value := p.take()
+		if value.kind != filterWord && value.kind != filterString {
+			return domain.FilterExpr{}, value.errorf("expected a value after %s but found %s", op, value)
+		}
+		values = append(values, value)
+	}
+
+	expr := domain.FilterExpr{Kind: domain.FilterCompare, Field: field.path, Operator: op}
+	for _, value := range values {
+		converted, err := filterValue(field, value)
+		if err != nil {
+			return domain.FilterExpr{}, err
+		}
+		expr.Values = append(expr.Values, converted)
+	}
+	return expr, nil
+}
+
+func filterValue(field filterField, value filterToken) (interface{}, error) {
+	switch field.kind {
+	case filterNumber:
+		number, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			return nil, value.errorf("expected a number but found %s", value)
+		}
+		return number, nil
+	case filterDate:
+		date, err := parseCustomDate(value.text)
+		if err != nil {
+			return nil, value.errorf("expected a date like 2006-01-02 but found %s", value)
+		}
+		return date, nil
+	}
+	if len(field.options) > 0 && !containsString(field.options, value.text) {
+		return nil, value.errorf("%s is not one of %s", value, strings.Join(field.options, ", "))
+	}
+	return value.text, nil
+}
+
+// matchesFilter evaluates expr against task in memory, the way the
+// compiled MongoDB filter does: a comparison with a missing field only
+// holds for !=.
+func matchesFilter(expr domain.FilterExpr, task domain.Task) bool {
+	switch expr.Kind {
+	case domain.FilterAnd:
+		for _, child := range expr.Children {
+			if !matchesFilter(child, task) {
+				return false
+			}
+		}
+		return true
+	case domain.FilterOr:
+		for _, child := range expr.Children {
+			if matchesFilter(child, task) {
+				return true
+			}
+		}
+		return false
+	case domain.FilterNot:
+		return !matchesFilter(expr.Children[0], task)
+	}
+
+	actual, ok := taskFieldValue(task, expr.Field)
+	if expr.Operator == domain.FilterNe {
+		return !ok || !anyFilterValue(actual, func(v interface{}) bool { return compareFilterValues(v, expr.Values[0]) == 0 })
+	}
+	if !ok {
+		return false
+	}
+	return anyFilterValue(actual, func(v interface{}) bool {
+		switch expr.Operator {
+		case domain.FilterEq:
+			return compareFilterValues(v, expr.Values[0]) == 0
+		case domain.FilterIn:
+			for _, want := range expr.Values {
+				if compareFilterValues(v, want) == 0 {
+					return true
+				}
+			}
+			return false
+		case domain.FilterContains:
+			text, isText := v.(string)
+			want, _ := expr.Values[0].(string)
+			return isText && strings.Contains(strings.ToLower(text), strings.ToLower(want))
+		}
+		order := compareFilterValues(v, expr.Values[0])
+		switch expr.Operator {
+		case domain.FilterLt:
+			return order == -1
+		case domain.FilterLte:
+			return order == -1 || order == 0
+		case domain.FilterGt:
+			return order == 1
+		case domain.FilterGte:
+			return order == 1 || order == 0
+		}
+		return false
+	})
+}
+
+// taskFieldValue reads the field at a document path, reporting false when
+// the stored document would not have it.
+func taskFieldValue(task domain.Task, path string) (interface{}, bool) {
+	switch path {
+	case "title":
+		return task.Title, true
+	case "description":
+		return task.Description, true
+	case "status":
+		return task.Status, true
+	case "duedate":
+		return task.DueDate, true
+	case "assignee_id":
+		return task.Assignee_id, true
+	case "created_by":
+		return task.Created_by, true
+	case "parent_id":
+		return task.Parent_id, task.Parent_id != ""
+	case "labels":
+		return task.Labels, len(task.Labels) > 0
+	case "original_estimate":
+		return float64(task.Original_estimate), true
+	case "remaining_estimate":
+		return float64(task.Remaining_estimate), true
+	}
+	if key, ok := strings.CutPrefix(path, "custom_fields."); ok {
+		value, ok := task.Custom_fields[key]
+		return value, ok && value != nil
+	}
+	return nil, false
+}
+
+// anyFilterValue applies test to value, or to each element of a list.
+func anyFilterValue(value interface{}, test func(interface{}) bool) bool {
+	if list, ok := value.([]string); ok {
+		for _, element := range list {
+			if test(element) {
+				return true
+			}
+		}
+		return false
+	}
+	return test(value)
+}
+
+// compareFilterValues orders a and b, returning -1, 0 or 1, or 2 when
+// they are of different types and so never match.
+func compareFilterValues(a interface{}, b interface{}) int {
+	a, b = normalizeFilterValue(a), normalizeFilterValue(b)
+	switch a := a.(type) {
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case float64:
+		if b, ok := b.(float64); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b)
+		}
+	}
+	return 2
+}
+
+// normalizeFilterValue brings decoded document values to the types
+// filterValue produces.
+func normalizeFilterValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case int:
+		return float64(value)
+	case int32:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case primitive.DateTime:
+		return value.Time()
+	}
+	return value
+}
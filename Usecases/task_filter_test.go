@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type taskFilterSuite struct {
+	suite.Suite
+	fields []domain.CustomField
+}
+
+func (suite *taskFilterSuite) SetupTest() {
+	suite.fields = []domain.CustomField{
+		{Key: "priority", Type: domain.CustomFieldEnum, Options: []string{"low", "high"}},
+		{Key: "points", Type: domain.CustomFieldNumber},
+		{Key: "status", Type: domain.CustomFieldText},
+	}
+}
+
+func (suite *taskFilterSuite) TestParse_Tree() {
+	expr, err := parseTaskFilter(`status in (todo, "blocked") and (due < 2026-11-01 or priority = high)`, suite.fields)
+	suite.Require().NoError(err)
+
+	suite.Equal(domain.FilterExpr{Kind: domain.FilterAnd, Children: []domain.FilterExpr{
+		{Kind: domain.FilterCompare, Field: "status", Operator: domain.FilterIn, Values: []interface{}{"todo", "blocked"}},
+		{Kind: domain.FilterOr, Children: []domain.FilterExpr{
+			{Kind: domain.FilterCompare, Field: "duedate", Operator: domain.FilterLt, Values: []interface{}{time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}},
+			{Kind: domain.FilterCompare, Field: "custom_fields.priority", Operator: domain.FilterEq, Values: []interface{}{"high"}},
+		}},
+	}}, expr)
+}
+
+func (suite *taskFilterSuite) TestParse_PrecedenceAndKeywords() {
+	expr, err := parseTaskFilter(`NOT label = ops OR cf.status contains "wait" AND points >= 3`, suite.fields)
+	suite.Require().NoError(err)
+
+	suite.Equal(domain.FilterOr, expr.Kind, "and binds tighter than or")
+	suite.Equal(domain.FilterNot, expr.Children[0].Kind)
+	suite.Equal("custom_fields.status", expr.Children[1].Children[0].Field, "cf. reaches a custom field shadowed by a built-in one")
+	suite.Equal([]interface{}{float64(3)}, expr.Children[1].Children[1].Values)
+}
+
+func (suite *taskFilterSuite) TestParse_ErrorPositions() {
+	for expression, position := range map[string]int{
+		`status = todo and`:          18,
+		`color = red`:                1,
+		`status < todo`:              8,
+		`points = many`:              10,
+		`priority = urgent`:          12,
+		`status in (todo blocked)`:   17,
+		`(status = todo`:             15,
+		`status = todo)`:             14,
+		`title = "unterminated`:      9,
+		`status = todo & due > 2026`: 15,
+		`due = tomorrow`:             7,
+		`status ! todo`:              8,
+	} {
+		_, err := parseTaskFilter(expression, suite.fields)
+		var filterErr *domain.FilterError
+		if suite.True(errors.As(err, &filterErr), expression) {
+			suite.Equal(position, filterErr.Position, "%s: %v", expression, err)
+		}
+		suite.ErrorIs(err, domain.ErrInvalidFilter)
+	}
+}
+
+func (suite *taskFilterSuite) TestParse_Limits() {
+	deep := ""
+	for i := 0; i <= maxFilterDepth; i++ {
+		deep += "("
+	}
+	_, err := parseTaskFilter(deep+"status = todo", suite.fields)
+	suite.ErrorIs(err, domain.ErrInvalidFilter)
+
+	long := "status = todo"
+	for i := 0; i < maxFilterComparisons; i++ {
+		long += " or status = todo"
+	}
+	_, err = parseTaskFilter(long, suite.fields)
+	suite.ErrorIs(err, domain.ErrInvalidFilter)
+}
+
+func (suite *taskFilterSuite) TestMatchesFilter() {
+	task := domain.Task{
+		Title:             "Ship the release",
+		Status:            "blocked",
+		DueDate:           time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+		Labels:            []string{"ops", "web"},
+		Original_estimate: 90,
+		Custom_fields:     map[string]interface{}{"priority": "low", "points": int32(5)},
+	}
+	for expression, want := range map[string]bool{
+		`status in (todo, blocked) and (due < 2026-11-01 or priority = high)`: true,
+		`status in (todo, blocked) and due > 2026-11-01 and priority = high`:  false,
+		`label = web and label != db`:                                         true,
+		`label != ops`:                                                        false,
+		`title contains RELEASE`:                                              true,
+		`points > 4 and original_estimate <= 90`:                              true,
+		`not points > 4`:                                                      false,
+		`parent != abc and not parent = abc`:                                  true,
+		`cf.status = blocked`:                                                 false,
+	} {
+		expr, err := parseTaskFilter(expression, suite.fields)
+		suite.Require().NoError(err, expression)
+		suite.Equal(want, matchesFilter(expr, task), expression)
+	}
+}
+
+func TestTaskFilter(t *testing.T) {
+	suite.Run(t, new(taskFilterSuite))
+}
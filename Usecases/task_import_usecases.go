@@ -0,0 +1,550 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxImportRows = 10000
+	// asyncImportRows is the most rows an import may have and still run
+	// within the request.
+	asyncImportRows = 200
+	maxImportErrors = 100
+	// importProgressEvery is how many rows a queued import handles between
+	// progress updates.
+	importProgressEvery = 50
+)
+
+// importFields are the task fields an import column can fill, besides
+// cf.<key>.
+var importFields = []string{
+	"title", "description", "status", "due_date", "assignee_id", "labels",
+	"original_estimate", "remaining_estimate", "external_id",
+}
+
+// importRow holds one record keyed by task field, with the line or
+// position it was read from.
+type importRow struct {
+	row    int
+	values map[string]interface{}
+}
+
+type taskImportUsecase struct {
+	taskRepository         domain.TaskRepository
+	importJobRepository    domain.ImportJobRepository
+	customFieldRepository  domain.CustomFieldRepository
+	organizationRepository domain.OrganizationRepository
+	outboxRepository       domain.OutboxRepository
+	transactor             domain.Transactor
+	contextTimeout         time.Duration
+	now                    func() time.Time
+	// run starts queued imports; tests run them inline.
+	run func(func())
+}
+
+func NewTaskImportUsecase(taskRepository domain.TaskRepository, importJobRepository domain.ImportJobRepository, customFieldRepository domain.CustomFieldRepository, organizationRepository domain.OrganizationRepository, outboxRepository domain.OutboxRepository, transactor domain.Transactor, timeout time.Duration) domain.TaskImportUsecase {
+	return &taskImportUsecase{
+		taskRepository:         taskRepository,
+		importJobRepository:    importJobRepository,
+		customFieldRepository:  customFieldRepository,
+		organizationRepository: organizationRepository,
+		outboxRepository:       outboxRepository,
+		transactor:             transactor,
+		contextTimeout:         timeout,
+		now:                    time.Now,
+		run:                    func(f func()) { go f() },
+	}
+}
+
+// Import reads the whole file before touching any task, so a malformed
+// file imports nothing. Rows are then imported one by one: rows whose
+// external_id matches an earlier import update that task, the others
+// create one, and a failing row does not stop the rest.
+func (iu *taskImportUsecase) Import(c context.Context, userID string, request domain.TaskImportRequest) (*domain.ImportJob, error) {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	if len(request.Data) > domain.MaxImportBytes {
+		return nil, fmt.Errorf("%w: at most %d bytes are allowed", domain.ErrImportTooLarge, domain.MaxImportBytes)
+	}
+	fields, err := iu.customFieldRepository.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImportMapping(request.Mapping, fields); err != nil {
+		return nil, err
+	}
+	rows, err := parseImport(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("%w: %d rows, at most %d are allowed", domain.ErrImportTooLarge, len(rows), maxImportRows)
+	}
+
+	job := &domain.ImportJob{
+		Created_by: userID,
+		Status:     domain.ImportRunning,
+		Dry_run:    request.Dry_run,
+		Total:      len(rows),
+		Errors:     []domain.ImportRowError{},
+	}
+	queue := request.Async || len(rows) > asyncImportRows
+	if queue {
+		job.Status = domain.ImportQueued
+	}
+	if err := iu.importJobRepository.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if !queue {
+		iu.process(ctx, userID, fields, rows, job, nil)
+		iu.finish(ctx, job)
+		return job, nil
+	}
+	// the request context ends with the response, so the job gets its own
+	orgID, _ := domain.TenantFromContext(ctx)
+	background := domain.WithTenant(context.Background(), orgID)
+	queued := *job
+	iu.run(func() { iu.runQueued(background, userID, fields, rows, queued) })
+	return job, nil
+}
+
+func (iu *taskImportUsecase) FetchJob(c context.Context, jobID string) (*domain.ImportJob, error) {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	job, err := iu.importJobRepository.FetchByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// errImportInterrupted explains imports a restart cut off. Their rows are
+// not kept, so they cannot carry on.
+var errImportInterrupted = errors.New("the import was interrupted by a restart; rows it had processed were imported, import the file again for the rest")
+
+func (iu *taskImportUsecase) FailInterrupted(c context.Context, startedAt time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, iu.contextTimeout)
+	defer cancel()
+
+	return iu.importJobRepository.FailUnfinished(ctx, startedAt, errImportInterrupted.Error(), iu.now())
+}
+
+func (iu *taskImportUsecase) runQueued(ctx context.Context, userID string, fields []domain.CustomField, rows []importRow, job domain.ImportJob) {
+	job.Status = domain.ImportRunning
+	iu.save(ctx, job)
+	iu.process(ctx, userID, fields, rows, &job, func() { iu.save(ctx, job) })
+	iu.finish(ctx, &job)
+}
+
+// process imports rows, each within its own timeout, calling progress
+// every importProgressEvery rows.
+func (iu *taskImportUsecase) process(ctx context.Context, userID string, fields []domain.CustomField, rows []importRow, job *domain.ImportJob, progress func()) {
+	seen := map[string]int{}
+	for _, row := range rows {
+		rowCtx, cancel := context.WithTimeout(ctx, iu.contextTimeout)
+		created, externalID, err := iu.importRow(rowCtx, userID, fields, row, job.Dry_run, seen)
+		cancel()
+
+		job.Processed++
+		switch {
+		case err != nil:
+			job.Failed++
+			if len(job.Errors) < maxImportErrors {
+				job.Errors = append(job.Errors, domain.ImportRowError{Row: row.row, External_id: externalID, Message: err.Error()})
+			}
+		case created:
+			job.Created++
+		default:
+			job.Updated++
+		}
+		if progress != nil && job.Processed%importProgressEvery == 0 {
+			progress()
+		}
+	}
+}
+
+func (iu *taskImportUsecase) finish(ctx context.Context, job *domain.ImportJob) {
+	finished := iu.now()
+	job.Status = domain.ImportDone
+	job.Finished_at = &finished
+	iu.save(ctx, *job)
+}
+
+// save records job progress. A failed update only delays what pollers see,
+// so it is logged rather than stopping the import.
+func (iu *taskImportUsecase) save(ctx context.Context, job domain.ImportJob) {
+	saveCtx, cancel := context.WithTimeout(ctx, iu.contextTimeout)
+	defer cancel()
+	if err := iu.importJobRepository.Update(saveCtx, job); err != nil {
+		log.Printf("failed to record progress of import %s: %v", job.ID.Hex(), err)
+	}
+}
+
+// importRow creates or updates the task of one row and records its events,
+// or in a dry run only checks it. seen maps the external IDs of earlier rows to their row.
+func (iu *taskImportUsecase) importRow(ctx context.Context, userID string, fields []domain.CustomField, row importRow, dryRun bool, seen map[string]int) (bool, string, error) {
+	patch, externalID, err := importPatch(row, fields)
+	if err != nil {
+		return false, externalID, err
+	}
+
+	if externalID != "" {
+		if earlier, ok := seen[externalID]; ok {
+			return false, externalID, fmt.Errorf("external_id %q was already used on row %d", externalID, earlier)
+		}
+		seen[externalID] = row.row
+
+		existing, err := iu.taskRepository.FetchByExternalID(ctx, externalID)
+		if err == nil {
+			previousStatus := existing.Status
+			if err := patchTask(ctx, iu.organizationRepository, fields, existing, patch); err != nil {
+				return false, externalID, err
+			}
+			if dryRun {
+				return false, externalID, nil
+			}
+			taskID := existing.ID.Hex()
+			return false, externalID, iu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+				if err := iu.taskRepository.Update(tx, taskID, *existing); err != nil {
+					return err
+				}
+				task, err := iu.taskRepository.FetchByTaskID(tx, taskID)
+				if err != nil {
+					return err
+				}
+				fillCompletion(task)
+				return recordEvents(tx, iu.outboxRepository, taskUpdatedEvents(*task, previousStatus)...)
+			})
+		}
+		if !errors.Is(err, domain.ErrTaskNotFound) {
+			return false, externalID, err
+		}
+	}
+
+	if patch.Title == nil || strings.TrimSpace(*patch.Title) == "" {
+		return false, externalID, errors.New("a title is required")
+	}
+	task := domain.Task{ID: primitive.NewObjectID(), Created_by: userID, External_id: externalID, Custom_fields: patch.Custom_fields}
+	applyTaskPatch(&task, patch)
+	if err := prepareTask(ctx, iu.organizationRepository, fields, &task, iu.now()); err != nil {
+		return false, externalID, err
+	}
+	if dryRun {
+		return true, externalID, nil
+	}
+	return true, externalID, iu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := iu.taskRepository.Create(tx, &task); err != nil {
+			return err
+		}
+		created := task
+		fillCompletion(&created)
+		return recordEvents(tx, iu.outboxRepository, domain.TaskCreated{Task: created})
+	})
+}
+
+// checkImportMapping makes sure every column is mapped to a task field or
+// an existing custom field, and no field is filled twice.
+func checkImportMapping(mapping map[string]string, fields []domain.CustomField) error {
+	targets := map[string]string{}
+	for column, target := range mapping {
+		if !isImportField(target, fields) {
+			return fmt.Errorf("%w: column %q is mapped to unknown field %q", domain.ErrInvalidImport, column, target)
+		}
+		if other, ok := targets[target]; ok {
+			return fmt.Errorf("%w: columns %q and %q are both mapped to %q", domain.ErrInvalidImport, other, column, target)
+		}
+		targets[target] = column
+	}
+	return nil
+}
+
+func isImportField(name string, fields []domain.CustomField) bool {
+	if containsString(importFields, name) {
+		return true
+	}
+	key, ok := strings.CutPrefix(name, "cf.")
+	for _, field := range fields {
+		if ok && field.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// importTarget names the field a column or key fills: its mapping, else
+// the column itself when it names a field. Unknown columns are skipped.
+func importTarget(column string, mapping map[string]string) (string, bool) {
+	if target, ok := mapping[column]; ok {
+		return target, true
+	}
+	name := strings.ToLower(strings.TrimSpace(column))
+	if containsString(importFields, name) || strings.HasPrefix(name, "cf.") {
+		return name, true
+	}
+	return "", false
+}
+
+func parseImport(request domain.TaskImportRequest) ([]importRow, error) {
+	var rows []importRow
+	var err error
+	switch request.Format {
+	case domain.ImportCSV:
+		rows, err = parseImportCSV(request.Data, request.Mapping)
+	case domain.ImportJSON:
+		rows, err = parseImportJSON(request.Data, request.Mapping)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q, expected csv or json", domain.ErrInvalidImport, request.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", domain.ErrInvalidImport)
+	}
+	return rows, nil
+}
+
+// parseImportCSV reads a header row followed by records. Empty cells leave
+// their field alone, and the apostrophe csvText puts before formula-like
+// text is taken off.
+func parseImportCSV(data []byte, mapping map[string]string) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+	}
+
+	rows := []importRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+		}
+		line, _ := reader.FieldPos(0)
+		row := importRow{row: line, values: map[string]interface{}{}}
+		for i, cell := range record {
+			if i >= len(header) || strings.TrimSpace(cell) == "" {
+				continue
+			}
+			if target, ok := importTarget(header[i], mapping); ok {
+				if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune(csvFormulaStarts, rune(cell[1])) {
+					cell = cell[1:]
+				}
+				row.values[target] = cell
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// parseImportJSON reads an array of objects, or one object per line as
+// GET /tasks/export writes them. A custom_fields object is read as its
+// cf.<key> entries.
+func parseImportJSON(data []byte, mapping map[string]string) ([]importRow, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var objects []map[string]interface{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := decoder.Decode(&objects); err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+		}
+	} else {
+		for {
+			var object map[string]interface{}
+			err := decoder.Decode(&object)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: object %d: %v", domain.ErrInvalidImport, len(objects)+1, err)
+			}
+			objects = append(objects, object)
+		}
+	}
+
+	rows := make([]importRow, 0, len(objects))
+	for i, object := range objects {
+		row := importRow{row: i + 1, values: map[string]interface{}{}}
+		if custom, ok := object["custom_fields"].(map[string]interface{}); ok {
+			for key, value := range custom {
+				if target, ok := importTarget("cf."+key, mapping); ok {
+					row.values[target] = value
+				}
+			}
+		}
+		for key, value := range object {
+			if target, ok := importTarget(key, mapping); ok && value != nil {
+				row.values[target] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// importPatch turns a row into the changes it makes to a task, along with
+// its external ID.
+func importPatch(row importRow, fields []domain.CustomField) (domain.TaskPatch, string, error) {
+	patch := domain.TaskPatch{}
+	externalID := ""
+	if raw, ok := row.values["external_id"]; ok {
+		id, err := importText(raw)
+		if err != nil {
+			return patch, "", fmt.Errorf("external_id: %w", err)
+		}
+		externalID = strings.TrimSpace(id)
+	}
+
+	for name, raw := range row.values {
+		var err error
+		switch name {
+		case "title":
+			patch.Title, err = importTextField(raw)
+		case "description":
+			patch.Description, err = importTextField(raw)
+		case "status":
+			patch.Status, err = importTextField(raw)
+		case "assignee_id":
+			patch.Assignee_id, err = importTextField(raw)
+		case "due_date":
+			var text string
+			if text, err = importText(raw); err == nil {
+				var due time.Time
+				if due, err = parseCustomDate(strings.TrimSpace(text)); err != nil {
+					err = errors.New("expected a date like 2006-01-02")
+				}
+				patch.Due_date = &due
+			}
+		case "labels":
+			patch.Labels, err = importLabels(raw)
+		case "original_estimate":
+			patch.Original_estimate, err = importMinutes(raw)
+		case "remaining_estimate":
+			patch.Remaining_estimate, err = importMinutes(raw)
+		case "external_id":
+		default:
+			key := strings.TrimPrefix(name, "cf.")
+			if patch.Custom_fields == nil {
+				patch.Custom_fields = map[string]interface{}{}
+			}
+			patch.Custom_fields[key], err = importCustomValue(key, raw, fields)
+		}
+		if err != nil {
+			return patch, externalID, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return patch, externalID, nil
+}
+
+// importText accepts text, and numbers as JSON files often hold IDs.
+func importText(raw interface{}) (string, error) {
+	switch value := raw.(type) {
+	case string:
+		return value, nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	}
+	return "", errors.New("expected text")
+}
+
+func importTextField(raw interface{}) (*string, error) {
+	text, err := importText(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &text, nil
+}
+
+// importLabels accepts a list, or comma separated text.
+func importLabels(raw interface{}) (*[]string, error) {
+	var labels []string
+	switch value := raw.(type) {
+	case string:
+		for _, label := range strings.Split(value, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			label, ok := item.(string)
+			if !ok {
+				return nil, errors.New("expected a list of labels")
+			}
+			labels = append(labels, label)
+		}
+	default:
+		return nil, errors.New("expected a list of labels")
+	}
+	if len(labels) > 20 {
+		return nil, errors.New("at most 20 labels are allowed")
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 40 {
+			return nil, errors.New("labels must be 1 to 40 characters long")
+		}
+	}
+	return &labels, nil
+}
+
+// importMinutes reads a whole, non-negative number of minutes.
+func importMinutes(raw interface{}) (*int, error) {
+	var minutes float64
+	switch value := raw.(type) {
+	case float64:
+		minutes = value
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, errors.New("expected a number of minutes")
+		}
+		minutes = parsed
+	default:
+		return nil, errors.New("expected a number of minutes")
+	}
+	if minutes < 0 || minutes != math.Trunc(minutes) || minutes > math.MaxInt32 {
+		return nil, errors.New("expected a whole, non-negative number of minutes")
+	}
+	whole := int(minutes)
+	return &whole, nil
+}
+
+// importCustomValue converts CSV text for number fields; every other value
+// is checked by resolveCustomFields like any task's.
+func importCustomValue(key string, raw interface{}, fields []domain.CustomField) (interface{}, error) {
+	text, isText := raw.(string)
+	for _, field := range fields {
+		if field.Key != key || field.Type != domain.CustomFieldNumber || !isText {
+			continue
+		}
+		number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, errors.New("expected a number")
+		}
+		return number, nil
+	}
+	return raw, nil
+}
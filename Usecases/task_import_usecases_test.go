@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type taskImportUsecaseSuite struct {
+	suite.Suite
+	tasks   *mocks.TaskRepository
+	jobs    *mocks.ImportJobRepository
+	fields  *mocks.CustomFieldRepository
+	outbox  *mocks.OutboxRepository
+	usecase *taskImportUsecase
+	ctx     context.Context
+	saved   []domain.ImportJob
+}
+
+func (suite *taskImportUsecaseSuite) SetupTest() {
+	suite.tasks = new(mocks.TaskRepository)
+	suite.jobs = new(mocks.ImportJobRepository)
+	suite.fields = new(mocks.CustomFieldRepository)
+	suite.outbox = new(mocks.OutboxRepository)
+	suite.outbox.On("Append", mock.Anything, mock.Anything).Return(nil).Maybe()
+	suite.usecase = NewTaskImportUsecase(suite.tasks, suite.jobs, suite.fields, new(mocks.OrganizationRepository), suite.outbox, passThroughTransactor(), 10*time.Second).(*taskImportUsecase)
+	suite.ctx = domain.WithTenant(context.TODO(), "org1")
+	suite.saved = nil
+
+	suite.fields.On("FetchAll", mock.Anything).Return([]domain.CustomField{
+		{Key: "points", Type: domain.CustomFieldNumber},
+		{Key: "priority", Type: domain.CustomFieldEnum, Options: []string{"low", "high"}},
+	}, nil).Maybe()
+	suite.jobs.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.ImportJob).ID = primitive.NewObjectID()
+	}).Return(nil).Maybe()
+	suite.jobs.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		suite.saved = append(suite.saved, args.Get(1).(domain.ImportJob))
+	}).Return(nil).Maybe()
+	suite.tasks.On("FetchByExternalID", mock.Anything, mock.Anything).Return(nil, domain.ErrTaskNotFound).Maybe()
+}
+
+func (suite *taskImportUsecaseSuite) TestImport_DryRunReportsRowErrors() {
+	data := "Summary,Due,external_id,Points\n" +
+		"Write report,2024-05-01,A-1,3\n" +
+		"\"Multi\nline\",not a date,A-2,\n" +
+		",2024-05-02,A-3,\n" +
+		"Again,,A-1,\n" +
+		"Bad points,,,many\n"
+	job, err := suite.usecase.Import(suite.ctx, "u1", domain.TaskImportRequest{
+		Format:  domain.ImportCSV,
+		Mapping: map[string]string{"Summary": "title", "Due": "due_date", "Points": "cf.points"},
+		Data:    []byte(data),
+		Dry_run: true,
+	})
+	suite.Require().NoError(err)
+	suite.Equal(domain.ImportDone, job.Status)
+	suite.Equal(5, job.Total)
+	suite.Equal(1, job.Created)
+	suite.Equal(4, job.Failed)
+	suite.Require().Len(job.Errors, 4)
+	suite.Equal(3, job.Errors[0].Row, "rows are the lines records start on")
+	suite.Contains(job.Errors[0].Message, "due_date")
+	suite.Equal(5, job.Errors[1].Row)
+	suite.Contains(job.Errors[1].Message, "title")
+	suite.Contains(job.Errors[2].Message, "row 2")
+	suite.Contains(job.Errors[3].Message, "cf.points")
+	suite.tasks.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.tasks.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *taskImportUsecaseSuite) TestImport_ReimportUpdatesByExternalID() {
+	existing := domain.Task{ID: primitive.NewObjectID(), Title: "Old", Status: "todo", External_id: "A-1", Custom_fields: map[string]interface{}{"priority": "low"}}
+	suite.tasks.ExpectedCalls = nil
+	suite.tasks.On("FetchByExternalID", mock.Anything, "A-1").Return(&existing, nil)
+	suite.tasks.On("FetchByExternalID", mock.Anything, "A-2").Return(nil, domain.ErrTaskNotFound)
+	suite.tasks.On("Update", mock.Anything, existing.ID.Hex(), mock.MatchedBy(func(task domain.Task) bool {
+		return task.Title == "Renamed" && task.Status == "todo" && task.Custom_fields["points"] == float64(5) && task.Custom_fields["priority"] == "low"
+	})).Return(nil)
+	renamed := existing
+	renamed.Title = "Renamed"
+	suite.tasks.On("FetchByTaskID", mock.Anything, existing.ID.Hex()).Return(&renamed, nil)
+	suite.tasks.On("Create", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Title == "Fresh" && task.External_id == "A-2" && task.Created_by == "u1" && len(task.Labels) == 2
+	})).Return(nil)
+
+	data := `{"title": "Renamed", "external_id": "A-1", "custom_fields": {"points": 5}}
+{"title": "Fresh", "external_id": "A-2", "labels": ["ops", "q3"], "id": "ignored"}
+`
+	job, err := suite.usecase.Import(suite.ctx, "u1", domain.TaskImportRequest{Format: domain.ImportJSON, Data: []byte(data)})
+	suite.Require().NoError(err)
+	suite.Equal(1, job.Updated)
+	suite.Equal(1, job.Created)
+	suite.Equal(0, job.Failed)
+	suite.tasks.AssertExpectations(suite.T())
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskUpdated) bool {
+		return event.Task.ID == existing.ID && event.Task.Title == "Renamed"
+	}))
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskCreated) bool {
+		return event.Task.External_id == "A-2"
+	}))
+	suite.outbox.AssertNotCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskStatusChanged) bool { return true }))
+}
+
+func (suite *taskImportUsecaseSuite) TestImport_LargeFilesAreQueued() {
+	var ran []func()
+	suite.usecase.run = func(f func()) { ran = append(ran, f) }
+	suite.tasks.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	var data bytes.Buffer
+	data.WriteString("title\n")
+	for i := 0; i < asyncImportRows+1; i++ {
+		data.WriteString("Task\n")
+	}
+	job, err := suite.usecase.Import(suite.ctx, "u1", domain.TaskImportRequest{Format: domain.ImportCSV, Data: data.Bytes()})
+	suite.Require().NoError(err)
+	suite.Equal(domain.ImportQueued, job.Status)
+	suite.Equal(0, job.Processed)
+	suite.Require().Len(ran, 1)
+
+	ran[0]()
+	suite.Require().NotEmpty(suite.saved)
+	suite.Equal(domain.ImportRunning, suite.saved[0].Status)
+	suite.Equal(importProgressEvery, suite.saved[1].Processed, "progress is recorded along the way")
+	last := suite.saved[len(suite.saved)-1]
+	suite.Equal(domain.ImportDone, last.Status)
+	suite.Equal(asyncImportRows+1, last.Created)
+	suite.NotNil(last.Finished_at)
+}
+
+func (suite *taskImportUsecaseSuite) TestFailInterrupted() {
+	startedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := startedAt.Add(time.Second)
+	suite.usecase.now = func() time.Time { return now }
+	suite.jobs.On("FailUnfinished", mock.Anything, startedAt, errImportInterrupted.Error(), now).Return(int64(2), nil).Once()
+
+	failed, err := suite.usecase.FailInterrupted(context.TODO(), startedAt)
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), failed)
+	suite.jobs.AssertExpectations(suite.T())
+}
+
+func (suite *taskImportUsecaseSuite) TestImport_RejectsBadRequests() {
+	_, err := suite.usecase.Import(suite.ctx, "u1", domain.TaskImportRequest{Format: domain.ImportCSV, Mapping: map[string]string{"Owner": "owner"}, Data: []byte("Owner\nme\n")})
+	suite.ErrorIs(err, domain.ErrInvalidImport)
+	_, err = suite.usecase.Import(suite.ctx, "u1", domain.TaskImportRequest{Format: "xlsx", Data: []byte("x")})
+	suite.ErrorIs(err, domain.ErrInvalidImport)
+	_, err = suite.usecase.Import(suite.ctx, "u1", domain.TaskImportRequest{Format: domain.ImportJSON, Data: []byte(`[{"title": "a"}`)})
+	suite.ErrorIs(err, domain.ErrInvalidImport)
+	_, err = suite.usecase.Import(suite.ctx, "u1", domain.TaskImportRequest{Format: domain.ImportCSV, Data: []byte("title\n")})
+	suite.ErrorIs(err, domain.ErrInvalidImport)
+	_, err = suite.usecase.Import(suite.ctx, "u1", domain.TaskImportRequest{Format: domain.ImportCSV, Data: make([]byte, domain.MaxImportBytes+1)})
+	suite.ErrorIs(err, domain.ErrImportTooLarge)
+	suite.jobs.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func TestTaskImportUsecase(t *testing.T) {
+	suite.Run(t, new(taskImportUsecaseSuite))
+}
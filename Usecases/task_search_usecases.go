@@ -0,0 +1,433 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxSearchParts bounds the words, phrases and prefixes of one query.
+	maxSearchParts  = 10
+	minSearchPrefix = 2
+	// searchCandidateLimit bounds what each repository lookup returns
+	// before matching and ranking. A search reaching it is reported as
+	// truncated.
+	searchCandidateLimit = 500
+	defaultSearchLimit   = 20
+	maxSearchLimit       = 100
+	// snippetLength is roughly how much of a description or comment a
+	// highlight shows, starting up to snippetContext bytes before the
+	// first match.
+	snippetLength  = 160
+	snippetContext = 60
+	// maxCommentHighlights bounds the comment excerpts of one hit.
+	maxCommentHighlights = 3
+)
+
+// searchFieldWeights rank title matches above description matches, and
+// those above matches in comments.
+var searchFieldWeights = map[string]float64{
+	"title":       3,
+	"description": 1,
+	"comment":     0.5,
+}
+
+type taskSearchUsecase struct {
+	taskRepository        domain.TaskRepository
+	commentRepository     domain.CommentRepository
+	customFieldRepository domain.CustomFieldRepository
+	contextTimeout        time.Duration
+}
+
+func NewTaskSearchUsecase(taskRepository domain.TaskRepository, commentRepository domain.CommentRepository, customFieldRepository domain.CustomFieldRepository, timeout time.Duration) domain.TaskSearchUsecase {
+	return &taskSearchUsecase{
+		taskRepository:        taskRepository,
+		commentRepository:     commentRepository,
+		customFieldRepository: customFieldRepository,
+		contextTimeout:        timeout,
+	}
+}
+
+// Search finds tasks whose title, description or comments match every part
+// of the query. The repositories only narrow down candidates; matching,
+// ranking, highlighting and facets are done here, so they behave the same
+// whatever the store.
+func (su *taskSearchUsecase) Search(c context.Context, request domain.TaskSearchRequest) (*domain.TaskSearchResult, error) {
+	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
+	defer cancel()
+
+	query, err := parseSearchQuery(request.Q)
+	if err != nil {
+		return nil, err
+	}
+	var narrow *domain.FilterExpr
+	if request.Filter != "" {
+		fields, err := su.customFieldRepository.FetchAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := parseTaskFilter(request.Filter, fields)
+		if err != nil {
+			return nil, err
+		}
+		narrow = &expr
+	}
+	page, limit := request.Page, request.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	search := domain.TaskSearch{Text: query.text, Prefixes: query.prefixes, Limit: searchCandidateLimit}
+	comments, err := su.commentRepository.Search(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	commentsByTask := map[string][]domain.Comment{}
+	for _, comment := range comments {
+		if _, ok := commentsByTask[comment.Task_id]; !ok {
+			search.Task_ids = append(search.Task_ids, comment.Task_id)
+		}
+		commentsByTask[comment.Task_id] = append(commentsByTask[comment.Task_id], comment)
+	}
+	tasks, err := su.taskRepository.Search(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.TaskSearchResult{
+		Hits:   []domain.TaskSearchHit{},
+		Page:   page,
+		Limit:  limit,
+		Facets: domain.TaskSearchFacets{Status: map[string]int{}, Labels: map[string]int{}},
+		// either lookup may have left matches out
+		Truncated: int64(len(comments)) >= searchCandidateLimit || int64(len(tasks)) >= searchCandidateLimit,
+	}
+	hits := []domain.TaskSearchHit{}
+	for _, task := range tasks {
+		hit, ok := query.match(task, commentsByTask[task.ID.Hex()])
+		if !ok {
+			continue
+		}
+		result.Facets.Status[task.Status]++
+		for _, label := range task.Labels {
+			result.Facets.Labels[label]++
+		}
+		if request.Status != "" && task.Status != request.Status {
+			continue
+		}
+		if request.Label != "" && !containsString(task.Labels, request.Label) {
+			continue
+		}
+		if narrow != nil && !matchesFilter(*narrow, task) {
+			continue
+		}
+		fillCompletion(&hit.Task)
+		hits = append(hits, hit)
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+
+	result.Total = len(hits)
+	from := (page - 1) * limit
+	if from < int64(len(hits)) {
+		to := from + limit
+		if to > int64(len(hits)) {
+			to = int64(len(hits))
+		}
+		result.Hits = hits[from:to]
+	}
+	return result, nil
+}
+
+// searchQuery is a parsed search. Words are compared after stemming,
+// phrases word by word and prefixes against the start of words.
+type searchQuery struct {
+	terms    []string
+	phrases  [][]string
+	prefixes []string
+	// text is the MongoDB $text search string for the words and phrases.
+	text string
+}
+
+func parseSearchQuery(q string) (searchQuery, error) {
+	query := searchQuery{}
+	textParts := []string{}
+	seenTerms := map[string]bool{}
+	addTerm := func(word string) {
+		textParts = append(textParts, word)
+		if term := stem(word); !seenTerms[term] {
+			seenTerms[term] = true
+			query.terms = append(query.terms, term)
+		}
+	}
+
+	rest := q
+	for {
+		start := strings.IndexByte(rest, '"')
+		if start < 0 {
+			break
+		}
+		length := strings.IndexByte(rest[start+1:], '"')
+		if length < 0 {
+			break
+		}
+		words := []string{}
+		for _, word := range searchWords(rest[start+1 : start+1+length]) {
+			words = append(words, word.lower)
+		}
+		switch len(words) {
+		case 0:
+		case 1:
+			addTerm(words[0])
+		default:
+			query.phrases = append(query.phrases, words)
+			textParts = append(textParts, `"`+strings.Join(words, " ")+`"`)
+		}
+		rest = rest[:start] + " " + rest[start+2+length:]
+	}
+
+	for _, field := range strings.Fields(strings.ReplaceAll(rest, `"`, " ")) {
+		if prefix, ok := strings.CutSuffix(field, "*"); ok {
+			words := searchWords(prefix)
+			if len(words) != 1 || len(words[0].lower) < minSearchPrefix {
+				return searchQuery{}, fmt.Errorf("%w: %q needs at least %d letters before the *", domain.ErrInvalidSearch, field, minSearchPrefix)
+			}
+			query.prefixes = append(query.prefixes, words[0].lower)
+			continue
+		}
+		for _, word := range searchWords(field) {
+			addTerm(word.lower)
+		}
+	}
+
+	parts := query.parts()
+	if parts == 0 {
+		return searchQuery{}, fmt.Errorf("%w: the query is empty", domain.ErrInvalidSearch)
+	}
+	if parts > maxSearchParts {
+		return searchQuery{}, fmt.Errorf("%w: use at most %d words, phrases and prefixes", domain.ErrInvalidSearch, maxSearchParts)
+	}
+	query.text = strings.Join(textParts, " ")
+	return query, nil
+}
+
+func (q searchQuery) parts() int {
+	return len(q.terms) + len(q.phrases) + len(q.prefixes)
+}
+
+type searchField struct {
+	name      string
+	commentID string
+	text      string
+}
+
+// match scores task and builds its highlights. It reports false unless
+// every part of the query matches some field.
+func (q searchQuery) match(task domain.Task, comments []domain.Comment) (domain.TaskSearchHit, bool) {
+	fields := []searchField{{name: "title", text: task.Title}, {name: "description", text: task.Description}}
+	for _, comment := range comments {
+		fields = append(fields, searchField{name: "comment", commentID: comment.ID.Hex(), text: comment.Body})
+	}
+
+	hit := domain.TaskSearchHit{Task: task, Highlights: []domain.SearchHighlight{}}
+	matched := make([]bool, q.parts())
+	commentHighlights := 0
+	for _, field := range fields {
+		counts, spans := q.scan(field.text)
+		if len(spans) == 0 {
+			continue
+		}
+		for part, count := range counts {
+			if count == 0 {
+				continue
+			}
+			matched[part] = true
+			weight := searchFieldWeights[field.name]
+			if part >= len(q.terms) && part < len(q.terms)+len(q.phrases) {
+				weight *= 2
+			}
+			hit.Score += weight * math.Min(float64(count), 3)
+		}
+		if field.name == "comment" {
+			if commentHighlights == maxCommentHighlights {
+				continue
+			}
+			commentHighlights++
+		}
+		hit.Highlights = append(hit.Highlights, domain.SearchHighlight{
+			Field:      field.name,
+			Comment_id: field.commentID,
+			Snippet:    searchSnippet(field.text, spans, field.name == "title"),
+		})
+	}
+	for _, ok := range matched {
+		if !ok {
+			return domain.TaskSearchHit{}, false
+		}
+	}
+	hit.Score = math.Round(hit.Score*100) / 100
+	return hit, true
+}
+
+// scan counts the matches of each query part in text, in the order terms,
+// phrases, prefixes, and returns the byte ranges they cover.
+func (q searchQuery) scan(text string) ([]int, [][2]int) {
+	words := searchWords(text)
+	counts := make([]int, q.parts())
+	spans := [][2]int{}
+	for i, word := range words {
+		stemmed := stem(word.lower)
+		for part, term := range q.terms {
+			if stemmed == term {
+				counts[part]++
+				spans = append(spans, [2]int{word.start, word.end})
+			}
+		}
+		for part, phrase := range q.phrases {
+			if i+len(phrase) > len(words) {
+				continue
+			}
+			found := true
+			for j, phraseWord := range phrase {
+				if words[i+j].lower != phraseWord {
+					found = false
+					break
+				}
+			}
+			if found {
+				counts[len(q.terms)+part]++
+				spans = append(spans, [2]int{word.start, words[i+len(phrase)-1].end})
+			}
+		}
+		for part, prefix := range q.prefixes {
+			if strings.HasPrefix(word.lower, prefix) {
+				counts[len(q.terms)+len(q.phrases)+part]++
+				spans = append(spans, [2]int{word.start, word.end})
+			}
+		}
+	}
+	return counts, spans
+}
+
+type searchWord struct {
+	start int
+	end   int
+	lower string
+}
+
+// searchWords splits text into runs of letters and digits.
+func searchWords(text string) []searchWord {
+	words := []searchWord{}
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, searchWord{start: start, end: i, lower: strings.ToLower(text[start:i])})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, searchWord{start: start, end: len(text), lower: strings.ToLower(text[start:])})
+	}
+	return words
+}
+
+// stem strips common English endings so "tests", "tested" and "testing"
+// match "test", roughly as the text index does.
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
+
+// searchSnippet escapes text, marks spans and, unless whole is set, cuts it
+// down to the neighbourhood of the first match.
+func searchSnippet(text string, spans [][2]int, whole bool) string {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := [][2]int{spans[0]}
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span[0] <= last[1] {
+			if span[1] > last[1] {
+				last[1] = span[1]
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	from, to := 0, len(text)
+	if !whole && len(text) > snippetLength {
+		first := merged[0]
+		from = first[0] - snippetContext
+		if from < 0 {
+			from = 0
+		}
+		to = from + snippetLength
+		if to < first[1] {
+			to = first[1]
+		}
+		if to > len(text) {
+			to = len(text)
+		}
+		if from > 0 {
+			if space := strings.IndexByte(text[from:first[0]], ' '); space >= 0 {
+				from += space + 1
+			}
+			for from > 0 && !utf8.RuneStart(text[from]) {
+				from--
+			}
+		}
+		if to < len(text) {
+			if space := strings.LastIndexByte(text[first[1]:to], ' '); space >= 0 {
+				to = first[1] + space
+			}
+			for to < len(text) && !utf8.RuneStart(text[to]) {
+				to++
+			}
+		}
+	}
+
+	var snippet strings.Builder
+	if from > 0 {
+		snippet.WriteString("…")
+	}
+	position := from
+	for _, span := range merged {
+		if span[1] <= from || span[0] >= to {
+			continue
+		}
+		start, end := max(span[0], from), min(span[1], to)
+		snippet.WriteString(html.EscapeString(text[position:start]))
+		snippet.WriteString("<mark>")
+		snippet.WriteString(html.EscapeString(text[start:end]))
+		snippet.WriteString("</mark>")
+		position = end
+	}
+	snippet.WriteString(html.EscapeString(text[position:to]))
+	if to < len(text) {
+		snippet.WriteString("…")
+	}
+	return snippet.String()
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type taskSearchUsecaseSuite struct {
+	suite.Suite
+	tasks    *mocks.TaskRepository
+	comments *mocks.CommentRepository
+	fields   *mocks.CustomFieldRepository
+	usecase  domain.TaskSearchUsecase
+}
+
+func (suite *taskSearchUsecaseSuite) SetupTest() {
+	suite.tasks = new(mocks.TaskRepository)
+	suite.comments = new(mocks.CommentRepository)
+	suite.fields = new(mocks.CustomFieldRepository)
+	suite.usecase = NewTaskSearchUsecase(suite.tasks, suite.comments, suite.fields, 10*time.Second)
+}
+
+func (suite *taskSearchUsecaseSuite) TestParseSearchQuery() {
+	query, err := parseSearchQuery(`Deploying "release notes" api* deploy`)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"deploy"}, query.terms)
+	suite.Equal([][]string{{"release", "notes"}}, query.phrases)
+	suite.Equal([]string{"api"}, query.prefixes)
+	suite.Equal(`"release notes" deploying deploy`, query.text)
+
+	for _, q := range []string{"", `  "" `, "a*", "one two three four five six seven eight nine ten eleven"} {
+		_, err := parseSearchQuery(q)
+		suite.ErrorIs(err, domain.ErrInvalidSearch, q)
+	}
+}
+
+func (suite *taskSearchUsecaseSuite) TestSearch_RanksHighlightsAndFacets() {
+	inTitle := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy the API", Status: "Pending", Labels: []string{"ops"}}
+	inDescription := domain.Task{ID: primitive.NewObjectID(), Title: "Weekly chores", Description: "Remember to <deploy> the api gateway", Status: "Completed", Labels: []string{"ops", "web"}}
+	inComment := domain.Task{ID: primitive.NewObjectID(), Title: "Gateway", Status: "Pending"}
+	partial := domain.Task{ID: primitive.NewObjectID(), Title: "Deploy docs", Status: "Pending"}
+	comment := domain.Comment{ID: primitive.NewObjectID(), Task_id: inComment.ID.Hex(), Body: "We deployed the APIs yesterday"}
+
+	suite.comments.On("Search", mock.Anything, mock.MatchedBy(func(search domain.TaskSearch) bool {
+		return search.Text == "deploy" && search.Prefixes[0] == "ap"
+	})).Return([]domain.Comment{comment}, nil)
+	suite.tasks.On("Search", mock.Anything, mock.MatchedBy(func(search domain.TaskSearch) bool {
+		return len(search.Task_ids) == 1 && search.Task_ids[0] == inComment.ID.Hex()
+	})).Return([]domain.Task{inComment, partial, inDescription, inTitle}, nil)
+
+	result, err := suite.usecase.Search(context.TODO(), domain.TaskSearchRequest{Q: "deploy ap*"})
+	suite.Require().NoError(err)
+
+	suite.Equal(3, result.Total, "every part must match")
+	suite.Equal(inTitle.ID, result.Hits[0].Task.ID, "title matches rank first")
+	suite.Equal(inDescription.ID, result.Hits[1].Task.ID)
+	suite.Equal(inComment.ID, result.Hits[2].Task.ID)
+	suite.Equal("<mark>Deploy</mark> the <mark>API</mark>", result.Hits[0].Highlights[0].Snippet)
+	suite.Equal("Remember to &lt;<mark>deploy</mark>&gt; the <mark>api</mark> gateway", result.Hits[1].Highlights[0].Snippet)
+	suite.Equal(comment.ID.Hex(), result.Hits[2].Highlights[0].Comment_id)
+	suite.Equal(map[string]int{"Pending": 2, "Completed": 1}, result.Facets.Status)
+	suite.Equal(map[string]int{"ops": 2, "web": 1}, result.Facets.Labels)
+	suite.False(result.Truncated)
+
+	narrowed, err := suite.usecase.Search(context.TODO(), domain.TaskSearchRequest{Q: "deploy ap*", Label: "web"})
+	suite.Require().NoError(err)
+	suite.Equal(1, narrowed.Total)
+	suite.Equal(result.Facets, narrowed.Facets, "facets ignore the narrowing")
+
+	suite.fields.On("FetchAll", mock.Anything).Return([]domain.CustomField{}, nil)
+	filtered, err := suite.usecase.Search(context.TODO(), domain.TaskSearchRequest{Q: "deploy ap*", Filter: `status = Pending and not label = ops`})
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, filtered.Total)
+	suite.Equal(inComment.ID, filtered.Hits[0].Task.ID)
+}
+
+func (suite *taskSearchUsecaseSuite) TestSearch_Phrase() {
+	exact := domain.Task{ID: primitive.NewObjectID(), Title: "Write release notes"}
+	apart := domain.Task{ID: primitive.NewObjectID(), Title: "Notes on the release"}
+	suite.comments.On("Search", mock.Anything, mock.Anything).Return([]domain.Comment{}, nil)
+	suite.tasks.On("Search", mock.Anything, mock.Anything).Return([]domain.Task{apart, exact}, nil)
+
+	result, err := suite.usecase.Search(context.TODO(), domain.TaskSearchRequest{Q: `"Release notes"`})
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, result.Total)
+	suite.Equal("Write <mark>release notes</mark>", result.Hits[0].Highlights[0].Snippet)
+}
+
+func (suite *taskSearchUsecaseSuite) TestSearch_ReportsTruncatedCandidates() {
+	tasks := make([]domain.Task, searchCandidateLimit)
+	for i := range tasks {
+		tasks[i] = domain.Task{ID: primitive.NewObjectID(), Title: "Deploy", Status: "Pending"}
+	}
+	suite.comments.On("Search", mock.Anything, mock.Anything).Return([]domain.Comment{}, nil)
+	suite.tasks.On("Search", mock.Anything, mock.Anything).Return(tasks, nil)
+
+	result, err := suite.usecase.Search(context.TODO(), domain.TaskSearchRequest{Q: "deploy"})
+	suite.Require().NoError(err)
+	suite.Equal(searchCandidateLimit, result.Total)
+	suite.True(result.Truncated, "there may be more matches than were counted")
+}
+
+func (suite *taskSearchUsecaseSuite) TestSearchSnippet_Truncates() {
+	text := "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua. " +
+		"The needle is here. Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat. Duis aute irure."
+	query, err := parseSearchQuery("needle")
+	suite.Require().NoError(err)
+	_, spans := query.scan(text)
+
+	snippet := searchSnippet(text, spans, false)
+	suite.Contains(snippet, "The <mark>needle</mark> is here.")
+	suite.True(len(snippet) < len(text))
+	suite.Equal("…", snippet[:len("…")])
+	suite.Equal("…", snippet[len(snippet)-len("…"):])
+}
+
+func TestTaskSearchUsecase(t *testing.T) {
+	suite.Run(t, new(taskSearchUsecaseSuite))
+}
@@ -0,0 +1,343 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxTemplateDepth bounds how deep subtasks nest, the top task included.
+	maxTemplateDepth = 3
+	// maxTemplateTasks bounds how many tasks one instantiation creates.
+	maxTemplateTasks = 50
+	// templateStartDate is the built-in placeholder for the start date.
+	templateStartDate = "start_date"
+)
+
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+type taskTemplateUsecase struct {
+	taskTemplateRepository domain.TaskTemplateRepository
+	taskRepository         domain.TaskRepository
+	customFieldRepository  domain.CustomFieldRepository
+	organizationRepository domain.OrganizationRepository
+	outboxRepository       domain.OutboxRepository
+	transactor             domain.Transactor
+	contextTimeout         time.Duration
+	now                    func() time.Time
+}
+
+func NewTaskTemplateUsecase(taskTemplateRepository domain.TaskTemplateRepository, taskRepository domain.TaskRepository, customFieldRepository domain.CustomFieldRepository, organizationRepository domain.OrganizationRepository, outboxRepository domain.OutboxRepository, transactor domain.Transactor, timeout time.Duration) domain.TaskTemplateUsecase {
+	return &taskTemplateUsecase{
+		taskTemplateRepository: taskTemplateRepository,
+		taskRepository:         taskRepository,
+		customFieldRepository:  customFieldRepository,
+		organizationRepository: organizationRepository,
+		outboxRepository:       outboxRepository,
+		transactor:             transactor,
+		contextTimeout:         timeout,
+		now:                    time.Now,
+	}
+}
+
+func (tu *taskTemplateUsecase) Create(c context.Context, template *domain.TaskTemplate) error {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	variables, err := checkTemplate(template.Task)
+	if err != nil {
+		return err
+	}
+	template.Variables = variables
+	return tu.taskTemplateRepository.Create(ctx, template)
+}
+
+func (tu *taskTemplateUsecase) FetchAll(c context.Context) (*[]domain.TaskTemplate, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	templates, err := tu.taskTemplateRepository.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &templates, nil
+}
+
+func (tu *taskTemplateUsecase) FetchByID(c context.Context, templateID string) (*domain.TaskTemplate, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	template, err := tu.taskTemplateRepository.FetchByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// Update replaces the template's name and task tree.
+func (tu *taskTemplateUsecase) Update(c context.Context, templateID string, template domain.TaskTemplate) (*domain.TaskTemplate, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	stored, err := tu.taskTemplateRepository.FetchByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	variables, err := checkTemplate(template.Task)
+	if err != nil {
+		return nil, err
+	}
+	stored.Name = template.Name
+	stored.Task = template.Task
+	stored.Variables = variables
+	if err := tu.taskTemplateRepository.Update(ctx, stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (tu *taskTemplateUsecase) Delete(c context.Context, templateID string) error {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+	return tu.taskTemplateRepository.Delete(ctx, templateID)
+}
+
+// Instantiate builds and checks every task of the tree before storing any,
+// then stores them parents first in one transaction, so a failure part way
+// stores none of them.
+func (tu *taskTemplateUsecase) Instantiate(c context.Context, templateID string, request domain.TemplateInstantiation, createdBy string) (*domain.TaskTree, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	template, err := tu.taskTemplateRepository.FetchByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := tu.now()
+	start := now
+	if request.Start != "" {
+		if start, err = parseCustomDate(request.Start); err != nil {
+			return nil, fmt.Errorf("%w: start must be a date like 2006-01-02", domain.ErrInvalidTaskTemplate)
+		}
+	}
+	variables := map[string]string{}
+	for name, value := range request.Variables {
+		variables[name] = value
+	}
+	variables[templateStartDate] = start.Format(time.DateOnly)
+
+	missing := []string{}
+	for _, name := range templateVariables(template.Task) {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrMissingTemplateVariable, strings.Join(missing, ", "))
+	}
+
+	fields, err := tu.customFieldRepository.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	builder := templateBuilder{
+		ctx:       ctx,
+		orgs:      tu.organizationRepository,
+		fields:    fields,
+		variables: variables,
+		start:     start,
+		now:       now,
+		createdBy: createdBy,
+		assignee:  request.Assignee_id,
+	}
+	tree, err := builder.build(template.Task, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = tu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		return tu.store(tx, tree)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tree, nil
+}
+
+// store creates the tree's tasks, parents first, and records their events.
+func (tu *taskTemplateUsecase) store(tx context.Context, tree domain.TaskTree) error {
+	if err := tu.taskRepository.Create(tx, &tree.Task); err != nil {
+		return err
+	}
+	created := tree.Task
+	fillCompletion(&created)
+	if err := recordEvents(tx, tu.outboxRepository, domain.TaskCreated{Task: created}); err != nil {
+		return err
+	}
+	for _, subtask := range tree.Subtasks {
+		if err := tu.store(tx, subtask); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateBuilder turns a TemplateTask into the tasks to store.
+type templateBuilder struct {
+	ctx       context.Context
+	orgs      domain.OrganizationRepository
+	fields    []domain.CustomField
+	variables map[string]string
+	start     time.Time
+	now       time.Time
+	createdBy string
+	assignee  string
+}
+
+func (b templateBuilder) build(template domain.TemplateTask, parentID string) (domain.TaskTree, error) {
+	task := domain.Task{
+		ID:          primitive.NewObjectID(),
+		Title:       b.fill(template.Title),
+		Description: b.fill(template.Description),
+		Status:      template.Status,
+		Created_by:  b.createdBy,
+		Assignee_id: b.assignee,
+		Parent_id:   parentID,
+	}
+	if len(template.Labels) > 0 {
+		task.Labels = append([]string{}, template.Labels...)
+	}
+	for _, text := range template.Checklist {
+		task.Checklist = append(task.Checklist, domain.ChecklistItem{Text: b.fill(text)})
+	}
+	if template.Due_in != "" {
+		offset, err := parseDueIn(template.Due_in)
+		if err != nil {
+			return domain.TaskTree{}, err
+		}
+		task.DueDate = b.start.Add(offset)
+	}
+	if len(template.Custom_fields) > 0 {
+		task.Custom_fields = make(map[string]interface{}, len(template.Custom_fields))
+		for key, value := range template.Custom_fields {
+			task.Custom_fields[key] = value
+		}
+	}
+	if err := prepareTask(b.ctx, b.orgs, b.fields, &task, b.now); err != nil {
+		return domain.TaskTree{}, err
+	}
+
+	tree := domain.TaskTree{Task: task}
+	for _, subtask := range template.Subtasks {
+		child, err := b.build(subtask, task.ID.Hex())
+		if err != nil {
+			return domain.TaskTree{}, err
+		}
+		tree.Subtasks = append(tree.Subtasks, child)
+	}
+	return tree, nil
+}
+
+func (b templateBuilder) fill(text string) string {
+	return templatePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		return b.variables[templatePlaceholder.FindStringSubmatch(placeholder)[1]]
+	})
+}
+
+// checkTemplate validates the shape of a template and returns the
+// placeholders it uses.
+func checkTemplate(task domain.TemplateTask) ([]string, error) {
+	count := 0
+	var walk func(task domain.TemplateTask, depth int) error
+	walk = func(task domain.TemplateTask, depth int) error {
+		count++
+		if depth > maxTemplateDepth {
+			return fmt.Errorf("%w: subtasks nest at most %d levels deep", domain.ErrInvalidTaskTemplate, maxTemplateDepth)
+		}
+		if count > maxTemplateTasks {
+			return fmt.Errorf("%w: a template holds at most %d tasks", domain.ErrInvalidTaskTemplate, maxTemplateTasks)
+		}
+		if strings.TrimSpace(task.Title) == "" {
+			return fmt.Errorf("%w: every task needs a title", domain.ErrInvalidTaskTemplate)
+		}
+		if task.Due_in != "" {
+			if _, err := parseDueIn(task.Due_in); err != nil {
+				return err
+			}
+		}
+		for _, subtask := range task.Subtasks {
+			if err := walk(subtask, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(task, 1); err != nil {
+		return nil, err
+	}
+
+	variables := []string{}
+	for _, name := range templateVariables(task) {
+		if name != templateStartDate {
+			variables = append(variables, name)
+		}
+	}
+	return variables, nil
+}
+
+// templateVariables returns the sorted, distinct placeholders of a tree.
+func templateVariables(task domain.TemplateTask) []string {
+	seen := map[string]bool{}
+	var walk func(task domain.TemplateTask)
+	walk = func(task domain.TemplateTask) {
+		texts := append([]string{task.Title, task.Description}, task.Checklist...)
+		for _, text := range texts {
+			for _, match := range templatePlaceholder.FindAllStringSubmatch(text, -1) {
+				seen[match[1]] = true
+			}
+		}
+		for _, subtask := range task.Subtasks {
+			walk(subtask)
+		}
+	}
+	walk(task)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseDueIn reads a relative due date: whole days ("3d"), whole weeks
+// ("2w") or a Go duration ("36h").
+func parseDueIn(dueIn string) (time.Duration, error) {
+	invalid := fmt.Errorf("%w: due_in %q must look like 3d, 2w or 36h", domain.ErrInvalidTaskTemplate, dueIn)
+	unit := 24 * time.Hour
+	switch {
+	case strings.HasSuffix(dueIn, "w"):
+		unit *= 7
+		fallthrough
+	case strings.HasSuffix(dueIn, "d"):
+		count, err := strconv.Atoi(dueIn[:len(dueIn)-1])
+		if err != nil || count < 0 {
+			return 0, invalid
+		}
+		return time.Duration(count) * unit, nil
+	}
+	offset, err := time.ParseDuration(dueIn)
+	if err != nil || offset < 0 {
+		return 0, invalid
+	}
+	return offset, nil
+}
@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type taskTemplateUsecaseSuite struct {
+	suite.Suite
+	templates *mocks.TaskTemplateRepository
+	tasks     *mocks.TaskRepository
+	fields    *mocks.CustomFieldRepository
+	orgs      *mocks.OrganizationRepository
+	outbox    *mocks.OutboxRepository
+	usecase   *taskTemplateUsecase
+	now       time.Time
+	template  domain.TaskTemplate
+}
+
+func (suite *taskTemplateUsecaseSuite) SetupTest() {
+	suite.templates = new(mocks.TaskTemplateRepository)
+	suite.tasks = new(mocks.TaskRepository)
+	suite.fields = new(mocks.CustomFieldRepository)
+	suite.orgs = new(mocks.OrganizationRepository)
+	suite.outbox = new(mocks.OutboxRepository)
+	suite.outbox.On("Append", mock.Anything, mock.Anything).Return(nil).Maybe()
+	suite.usecase = NewTaskTemplateUsecase(suite.templates, suite.tasks, suite.fields, suite.orgs, suite.outbox, passThroughTransactor(), 10*time.Second).(*taskTemplateUsecase)
+	suite.now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.usecase.now = func() time.Time { return suite.now }
+
+	suite.template = domain.TaskTemplate{
+		ID:   primitive.NewObjectID(),
+		Name: "Onboarding",
+		Task: domain.TemplateTask{
+			Title:     "Onboard {{ name }}",
+			Status:    "Pending",
+			Labels:    []string{"onboarding"},
+			Checklist: []string{"Create {{name}}'s account"},
+			Due_in:    "2w",
+			Subtasks: []domain.TemplateTask{
+				{Title: "Laptop for {{name}}", Description: "Ordered on {{start_date}}", Due_in: "3d"},
+				{Title: "Intro meeting"},
+			},
+		},
+	}
+	suite.templates.On("FetchByID", mock.Anything, suite.template.ID.Hex()).Return(suite.template, nil).Maybe()
+	suite.fields.On("FetchAll", mock.Anything).Return([]domain.CustomField{}, nil).Maybe()
+}
+
+func (suite *taskTemplateUsecaseSuite) TestCreate_ListsVariables() {
+	template := suite.template
+	suite.templates.On("Create", mock.Anything, &template).Return(nil)
+
+	suite.Require().NoError(suite.usecase.Create(context.TODO(), &template))
+	suite.Equal([]string{"name"}, template.Variables, "start_date is built in")
+}
+
+func (suite *taskTemplateUsecaseSuite) TestCreate_InvalidTemplates() {
+	deep := domain.TemplateTask{Title: "1", Subtasks: []domain.TemplateTask{{Title: "2", Subtasks: []domain.TemplateTask{{Title: "3", Subtasks: []domain.TemplateTask{{Title: "4"}}}}}}}
+	wide := domain.TemplateTask{Title: "root", Subtasks: make([]domain.TemplateTask, maxTemplateTasks)}
+	for i := range wide.Subtasks {
+		wide.Subtasks[i].Title = "child"
+	}
+	for _, task := range []domain.TemplateTask{
+		deep,
+		wide,
+		{Title: "due", Due_in: "soon"},
+		{Title: "due", Due_in: "-3d"},
+		{Title: "blank child", Subtasks: []domain.TemplateTask{{Title: " "}}},
+	} {
+		err := suite.usecase.Create(context.TODO(), &domain.TaskTemplate{Name: "bad", Task: task})
+		suite.ErrorIs(err, domain.ErrInvalidTaskTemplate)
+	}
+	suite.templates.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *taskTemplateUsecaseSuite) TestInstantiate_BuildsTree() {
+	stored := []domain.Task{}
+	suite.tasks.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Run(func(args mock.Arguments) {
+		stored = append(stored, *args.Get(1).(*domain.Task))
+	}).Return(nil)
+
+	tree, err := suite.usecase.Instantiate(context.TODO(), suite.template.ID.Hex(), domain.TemplateInstantiation{
+		Variables: map[string]string{"name": "Ada"}, Start: "2024-04-01", Assignee_id: "u2",
+	}, "u1")
+	suite.Require().NoError(err)
+
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	suite.Equal("Onboard Ada", tree.Title)
+	suite.Equal(start.AddDate(0, 0, 14), tree.DueDate)
+	suite.Equal("Create Ada's account", tree.Checklist[0].Text)
+	suite.False(tree.Checklist[0].ID.IsZero())
+	suite.Require().Len(tree.Subtasks, 2)
+	suite.Equal("Ordered on 2024-04-01", tree.Subtasks[0].Description)
+	suite.Equal(start.AddDate(0, 0, 3), tree.Subtasks[0].DueDate)
+	suite.Equal(tree.ID.Hex(), tree.Subtasks[1].Parent_id)
+	suite.Equal("u2", tree.Subtasks[1].Assignee_id)
+
+	suite.Require().Len(stored, 3)
+	suite.Equal(tree.ID, stored[0].ID, "parents are stored first")
+	suite.Equal("u1", stored[2].Created_by)
+	suite.outbox.AssertNumberOfCalls(suite.T(), "Append", 3)
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskCreated) bool {
+		return event.Task.ID == tree.Subtasks[1].ID && event.Task.Parent_id == tree.ID.Hex()
+	}))
+}
+
+func (suite *taskTemplateUsecaseSuite) TestInstantiate_MissingVariable() {
+	_, err := suite.usecase.Instantiate(context.TODO(), suite.template.ID.Hex(), domain.TemplateInstantiation{}, "u1")
+	suite.ErrorIs(err, domain.ErrMissingTemplateVariable)
+	suite.ErrorContains(err, "name")
+	suite.tasks.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *taskTemplateUsecaseSuite) TestInstantiate_RollsBackOnFailure() {
+	transactor := new(mocks.Transactor)
+	var failed error
+	transactor.On("WithTransaction", mock.Anything, mock.Anything).Return(func(c context.Context, fn func(context.Context) error) error {
+		failed = fn(c)
+		return failed
+	})
+	suite.usecase.transactor = transactor
+	created := 0
+	suite.tasks.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(func(ctx context.Context, task *domain.Task) error {
+		created++
+		if created == 3 {
+			return errors.New("write failed")
+		}
+		return nil
+	})
+
+	_, err := suite.usecase.Instantiate(context.TODO(), suite.template.ID.Hex(), domain.TemplateInstantiation{Variables: map[string]string{"name": "Ada"}}, "u1")
+	suite.EqualError(err, "write failed")
+	suite.EqualError(failed, "write failed", "the transaction is aborted, taking the stored tasks and their events with it")
+	transactor.AssertNumberOfCalls(suite.T(), "WithTransaction", 1)
+	suite.tasks.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+}
+
+func TestTaskTemplateUsecase(t *testing.T) {
+	suite.Run(t, new(taskTemplateUsecaseSuite))
+}
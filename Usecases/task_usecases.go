@@ -2,48 +2,403 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
 	domain "task-manger-api_test/Domain"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type taskUsecase struct {
-	taskRepository domain.TaskRepository
-	contextTimeout time.Duration
+	taskRepository       domain.TaskRepository
+	attachmentRepository domain.AttachmentRepository
+	blobStore              domain.BlobStore
+	customFieldRepository  domain.CustomFieldRepository
+	organizationRepository domain.OrganizationRepository
+	outboxRepository       domain.OutboxRepository
+	transactor             domain.Transactor
+	contextTimeout         time.Duration
 }
 
-func NewTaskUsecase(taskRepository domain.TaskRepository, timeout time.Duration) domain.TaskUsecase {
+func NewTaskUsecase(taskRepository domain.TaskRepository, attachmentRepository domain.AttachmentRepository, blobStore domain.BlobStore, customFieldRepository domain.CustomFieldRepository, organizationRepository domain.OrganizationRepository, outboxRepository domain.OutboxRepository, transactor domain.Transactor, timeout time.Duration) domain.TaskUsecase {
 	return &taskUsecase{
-		taskRepository: taskRepository,
-		contextTimeout: timeout,
+		taskRepository:         taskRepository,
+		attachmentRepository:   attachmentRepository,
+		blobStore:              blobStore,
+		customFieldRepository:  customFieldRepository,
+		organizationRepository: organizationRepository,
+		outboxRepository:       outboxRepository,
+		transactor:             transactor,
+		contextTimeout:         timeout,
 	}
 }
 
 func (tu *taskUsecase) Create(c context.Context, task *domain.Task) error {
 	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
 	defer cancel()
-	return tu.taskRepository.Create(ctx, task)
+
+	fields, err := tu.customFieldRepository.FetchAll(ctx)
+	if err != nil {
+		return err
+	}
+	if err := prepareTask(ctx, tu.organizationRepository, fields, task, time.Now()); err != nil {
+		return err
+	}
+	if task.ID.IsZero() {
+		task.ID = primitive.NewObjectID()
+	}
+	return tu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := tu.taskRepository.Create(tx, task); err != nil {
+			return err
+		}
+		created := *task
+		fillCompletion(&created)
+		return recordEvents(tx, tu.outboxRepository, domain.TaskCreated{Task: created})
+	})
 }
 
-func (tu *taskUsecase) FetchAll(c context.Context) (*[]domain.Task, error) {
+// prepareTask readies a new task for storage: checklist items get IDs and
+// custom field values are checked against fields.
+func prepareTask(ctx context.Context, organizationRepository domain.OrganizationRepository, fields []domain.CustomField, task *domain.Task, now time.Time) error {
+	for i := range task.Checklist {
+		task.Checklist[i].ID = primitive.NewObjectID()
+		task.Checklist[i].Done_at = nil
+		if task.Checklist[i].Done {
+			task.Checklist[i].Done_at = &now
+		}
+	}
+	values, err := resolveCustomFields(ctx, organizationRepository, fields, task.Custom_fields)
+	if err != nil {
+		return err
+	}
+	task.Custom_fields = values
+	return nil
+}
+
+// FetchAll lists tasks matching the custom field values and the filter
+// expression in query, sorted by title, status, due_date or cf.<key>; a
+// leading "-" sorts descending.
+func (tu *taskUsecase) FetchAll(c context.Context, query domain.TaskQuery) (*[]domain.Task, error) {
 	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
 	defer cancel()
-	return tu.taskRepository.FetchAll(ctx)
+
+	filter, err := buildTaskFilter(ctx, tu.customFieldRepository, query)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := tu.taskRepository.FetchAll(ctx, filter)
+	if err != nil || tasks == nil {
+		return tasks, err
+	}
+	for i := range *tasks {
+		fillCompletion(&(*tasks)[i])
+	}
+	return tasks, nil
 }
 
 func (tu *taskUsecase) FetchByTaskID(c context.Context, taskID string) (*domain.Task, error) {
 	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
 	defer cancel()
-	return tu.taskRepository.FetchByTaskID(ctx, taskID)
+
+	task, err := tu.taskRepository.FetchByTaskID(ctx, taskID)
+	if err != nil {
+		return task, err
+	}
+	fillCompletion(task)
+	return task, nil
 }
 
+// Update replaces the task's fields. Custom fields are left alone when the
+// update carries none.
 func (tu *taskUsecase) Update(c context.Context, taskID string, updatedTask domain.Task) error {
 	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
 	defer cancel()
-	return tu.taskRepository.Update(ctx, taskID, updatedTask)
+
+	previous, err := tu.fetchTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if updatedTask.Custom_fields != nil {
+		fields, err := tu.customFieldRepository.FetchAll(ctx)
+		if err != nil {
+			return err
+		}
+		values, err := resolveCustomFields(ctx, tu.organizationRepository, fields, updatedTask.Custom_fields)
+		if err != nil {
+			return err
+		}
+		updatedTask.Custom_fields = values
+	}
+	return tu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := tu.taskRepository.Update(tx, taskID, updatedTask); err != nil {
+			return err
+		}
+		task, err := tu.fetchTask(tx, taskID)
+		if err != nil {
+			return err
+		}
+		return recordEvents(tx, tu.outboxRepository, taskUpdatedEvents(*task, previous.Status)...)
+	})
+}
+
+// taskUpdatedEvents are the events of an update that left task as it is,
+// its status having been previousStatus.
+func taskUpdatedEvents(task domain.Task, previousStatus string) []domain.DomainEvent {
+	events := []domain.DomainEvent{domain.TaskUpdated{Task: task}}
+	if task.Status != previousStatus {
+		events = append(events, domain.TaskStatusChanged{Task: task, Previous_status: previousStatus})
+	}
+	return events
 }
 
 func (tu *taskUsecase) Delete(c context.Context, taskID string) error {
 	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
 	defer cancel()
-	return tu.taskRepository.Delete(ctx, taskID)
+	task, err := tu.fetchTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	err = tu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := tu.taskRepository.Delete(tx, taskID); err != nil {
+			return err
+		}
+		return recordEvents(tx, tu.outboxRepository, domain.TaskDeleted{Task: *task})
+	})
+	if err != nil {
+		return err
+	}
+	// the task is gone either way; leftovers are logged for manual cleanup
+	if err := purgeAttachments(ctx, tu.attachmentRepository, tu.blobStore, taskID); err != nil {
+		log.Printf("failed to purge attachments of task %s: %v", taskID, err)
+	}
+	return nil
+}
+
+// AddChecklistItem inserts item at position (0 is the top), appending when
+// position is nil or past the end.
+func (tu *taskUsecase) AddChecklistItem(c context.Context, taskID string, item domain.ChecklistItem, position *int) (*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	item.ID = primitive.NewObjectID()
+	item.Done_at = nil
+	if item.Done {
+		now := time.Now()
+		item.Done_at = &now
+	}
+	at := -1
+	if position != nil && *position >= 0 {
+		at = *position
+	}
+	return tu.change(ctx, taskID, func(tx context.Context) error {
+		return tu.taskRepository.AddChecklistItem(tx, taskID, item, at)
+	})
+}
+
+func (tu *taskUsecase) UpdateChecklistItem(c context.Context, taskID string, itemID string, update domain.ChecklistItemUpdate) (*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	if _, err := tu.fetchTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return tu.change(ctx, taskID, func(tx context.Context) error {
+		return tu.taskRepository.UpdateChecklistItem(tx, taskID, itemID, update, time.Now())
+	})
+}
+
+func (tu *taskUsecase) ToggleChecklistItem(c context.Context, taskID string, itemID string) (*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	task, err := tu.fetchTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range task.Checklist {
+		if item.ID.Hex() != itemID {
+			continue
+		}
+		done := !item.Done
+		return tu.change(ctx, taskID, func(tx context.Context) error {
+			return tu.taskRepository.UpdateChecklistItem(tx, taskID, itemID, domain.ChecklistItemUpdate{Done: &done}, time.Now())
+		})
+	}
+	return nil, domain.ErrChecklistItemNotFound
+}
+
+// ReorderChecklist puts the items in the order of itemIDs, which must name
+// every item exactly once.
+func (tu *taskUsecase) ReorderChecklist(c context.Context, taskID string, itemIDs []string) (*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	task, err := tu.fetchTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(itemIDs) != len(task.Checklist) {
+		return nil, domain.ErrInvalidChecklistOrder
+	}
+	items := make(map[string]domain.ChecklistItem, len(task.Checklist))
+	for _, item := range task.Checklist {
+		items[item.ID.Hex()] = item
+	}
+	reordered := make([]domain.ChecklistItem, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		item, ok := items[itemID]
+		if !ok {
+			return nil, domain.ErrInvalidChecklistOrder
+		}
+		delete(items, itemID)
+		reordered = append(reordered, item)
+	}
+	if len(reordered) == 0 {
+		return task, nil
+	}
+
+	err = tu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := tu.taskRepository.ReplaceChecklist(tx, taskID, task.Checklist, reordered); err != nil {
+			return err
+		}
+		changed := *task
+		changed.Checklist = reordered
+		return recordEvents(tx, tu.outboxRepository, domain.TaskUpdated{Task: changed})
+	})
+	if err != nil {
+		return nil, err
+	}
+	task.Checklist = reordered
+	return task, nil
+}
+
+func (tu *taskUsecase) DeleteChecklistItem(c context.Context, taskID string, itemID string) (*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	if _, err := tu.fetchTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return tu.change(ctx, taskID, func(tx context.Context) error {
+		return tu.taskRepository.DeleteChecklistItem(tx, taskID, itemID)
+	})
+}
+
+func (tu *taskUsecase) fetchTask(ctx context.Context, taskID string) (*domain.Task, error) {
+	task, err := tu.taskRepository.FetchByTaskID(ctx, taskID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	fillCompletion(task)
+	return task, nil
+}
+
+// change applies a change to a task and reloads it, recording the
+// TaskUpdated event in the same transaction.
+func (tu *taskUsecase) change(ctx context.Context, taskID string, apply func(tx context.Context) error) (*domain.Task, error) {
+	var task *domain.Task
+	err := tu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := apply(tx); err != nil {
+			return err
+		}
+		var err error
+		task, err = tu.fetchTask(tx, taskID)
+		if err != nil {
+			return err
+		}
+		return recordEvents(tx, tu.outboxRepository, domain.TaskUpdated{Task: *task})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// fillCompletion derives the completion percentage from the checklist,
+// rounding down so a task only shows 100 once every item is done.
+func fillCompletion(task *domain.Task) {
+	task.Completion = nil
+	if len(task.Checklist) == 0 {
+		task.Checklist = []domain.ChecklistItem{}
+		return
+	}
+	done := 0
+	for _, item := range task.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	completion := done * 100 / len(task.Checklist)
+	task.Completion = &completion
+}
+
+var taskSortFields = map[string]string{
+	"title":    "title",
+	"status":   "status",
+	"due_date": "duedate",
+}
+
+// buildTaskFilter checks a TaskQuery against the organization's custom
+// fields and turns it into a TaskFilter.
+func buildTaskFilter(ctx context.Context, customFieldRepository domain.CustomFieldRepository, query domain.TaskQuery) (domain.TaskFilter, error) {
+	filter := domain.TaskFilter{}
+	sortKey := strings.TrimPrefix(query.Sort, "-")
+	filter.Descending = sortKey != query.Sort
+	customSort, sortsByCustomField := strings.CutPrefix(sortKey, "cf.")
+
+	fields := []domain.CustomField{}
+	if len(query.Custom_fields) > 0 || sortsByCustomField || query.Filter != "" {
+		var err error
+		if fields, err = customFieldRepository.FetchAll(ctx); err != nil {
+			return filter, err
+		}
+	}
+	byKey := make(map[string]domain.CustomField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+	if query.Filter != "" {
+		expr, err := parseTaskFilter(query.Filter, fields)
+		if err != nil {
+			return filter, err
+		}
+		filter.Expression = &expr
+	}
+
+	for key, raw := range query.Custom_fields {
+		field, ok := byKey[key]
+		if !ok {
+			return filter, fmt.Errorf("%w: unknown custom field %q", domain.ErrInvalidTaskQuery, key)
+		}
+		value, err := customFieldQueryValue(field, raw)
+		if err != nil {
+			return filter, fmt.Errorf("%w: bad value for custom field %q", domain.ErrInvalidTaskQuery, key)
+		}
+		if filter.Custom_fields == nil {
+			filter.Custom_fields = map[string]interface{}{}
+		}
+		filter.Custom_fields[key] = value
+	}
+
+	switch {
+	case sortsByCustomField:
+		if _, ok := byKey[customSort]; !ok {
+			return filter, fmt.Errorf("%w: unknown custom field %q", domain.ErrInvalidTaskQuery, customSort)
+		}
+		filter.Sort_by = "custom_fields." + customSort
+	case sortKey != "":
+		column, ok := taskSortFields[sortKey]
+		if !ok {
+			return filter, fmt.Errorf("%w: cannot sort by %q", domain.ErrInvalidTaskQuery, sortKey)
+		}
+		filter.Sort_by = column
+	}
+	return filter, nil
 }
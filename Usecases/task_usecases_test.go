@@ -10,21 +10,39 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type taskUsecaseSuite struct{
 	suite.Suite
 	repository *mocks.TaskRepository
+	attachments *mocks.AttachmentRepository
+	blobs *mocks.BlobStore
+	customFields *mocks.CustomFieldRepository
+	orgs *mocks.OrganizationRepository
+	outbox *mocks.OutboxRepository
 	usecase domain.TaskUsecase
 }
 
 func (suite *taskUsecaseSuite) SetupTest(){
 	
 	repository := new(mocks.TaskRepository)
-	usecase := NewTaskUsecase(repository, 10)
+	attachments := new(mocks.AttachmentRepository)
+	blobs := new(mocks.BlobStore)
+	customFields := new(mocks.CustomFieldRepository)
+	orgs := new(mocks.OrganizationRepository)
+	outbox := new(mocks.OutboxRepository)
+	usecase := NewTaskUsecase(repository, attachments, blobs, customFields, orgs, outbox, passThroughTransactor(), 10)
 
 	suite.repository = repository
+	suite.attachments = attachments
+	suite.blobs = blobs
+	suite.customFields = customFields
+	suite.orgs = orgs
+	suite.outbox = outbox
 	suite.usecase = usecase
+	customFields.On("FetchAll", mock.Anything).Return([]domain.CustomField{}, nil).Maybe()
+	outbox.On("Append", mock.Anything, mock.Anything).Return(nil).Maybe()
 }
 
 // create task test
@@ -42,6 +60,10 @@ func (suite *taskUsecaseSuite) TestCreateTask_Positive(){
 	// assertions to make sure our operation does the right thing
 	suite.Nil(err, "err is a nil pointer so no error in this process")
 	suite.repository.AssertExpectations(suite.T())
+	suite.False(task.ID.IsZero(), "the ID is known before the insert so the event can carry it")
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskCreated) bool {
+		return event.Task.ID == task.ID
+	}))
 
 }
 
@@ -72,9 +94,9 @@ func (suite *taskUsecaseSuite) TestFetchAll_Positive() {
 		},
 	}
 
-	suite.repository.On("FetchAll", mock.Anything).Return(tasks, nil)
+	suite.repository.On("FetchAll", mock.Anything, domain.TaskFilter{}).Return(tasks, nil)
 
-	result, err := suite.usecase.FetchAll(context.TODO())
+	result, err := suite.usecase.FetchAll(context.TODO(), domain.TaskQuery{})
 
 	// Assertions
 	suite.NoError(err)
@@ -152,13 +174,23 @@ func (suite *taskUsecaseSuite) TestUpdate_Positive() {
 		Status:      "Completed",
 	}
 
+	suite.repository.On("FetchByTaskID", mock.Anything, taskID.Hex()).Return(&task, nil).Once()
 	suite.repository.On("Update", mock.Anything, taskID.Hex(), updatedTask).Return(nil)
+	updated := updatedTask
+	updated.ID = taskID
+	suite.repository.On("FetchByTaskID", mock.Anything, taskID.Hex()).Return(&updated, nil).Once()
 
 	err = suite.usecase.Update(context.TODO(), taskID.Hex(), updatedTask)
 
 	// Assertions
 	suite.NoError(err)
 	suite.repository.AssertExpectations(suite.T())
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskUpdated) bool {
+		return event.Task.Title == "Updated Title"
+	}))
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskStatusChanged) bool {
+		return event.Previous_status == "Pending" && event.Task.Status == "Completed"
+	}))
 }
 
 // Test Update - Negative case (Task not found)
@@ -170,14 +202,15 @@ func (suite *taskUsecaseSuite) TestUpdate_TaskNotFound() {
 		Status:      "Completed",
 	}
 
-	suite.repository.On("Update", mock.Anything, taskID, updatedTask).Return(errors.New("task not found"))
+	suite.repository.On("FetchByTaskID", mock.Anything, taskID).Return(nil, mongo.ErrNoDocuments)
 
 	err := suite.usecase.Update(context.TODO(), taskID, updatedTask)
 
 	// Assertions
 	suite.Error(err)
 	suite.EqualError(err, "task not found")
-	suite.repository.AssertExpectations(suite.T())
+	suite.repository.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+	suite.outbox.AssertNotCalled(suite.T(), "Append", mock.Anything, mock.Anything)
 }
 
 // Test Delete - Positive case
@@ -199,13 +232,18 @@ func (suite *taskUsecaseSuite) TestDelete_Positive() {
     suite.Nil(err, "task created successfully")
     suite.repository.AssertExpectations(suite.T())
 
+	suite.repository.On("FetchByTaskID", mock.Anything, taskID.Hex()).Return(&task, nil).Once()
 	suite.repository.On("Delete", mock.Anything, taskID.Hex()).Return(nil)
+	suite.attachments.On("FetchByTask", mock.Anything, taskID.Hex()).Return([]domain.Attachment{}, nil)
 
 	err = suite.usecase.Delete(context.TODO(), taskID.Hex())
 
 	// Assertions
 	suite.NoError(err)
 	suite.repository.AssertExpectations(suite.T())
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.TaskDeleted) bool {
+		return event.Task.ID == taskID
+	}))
 
 	suite.repository.On("FetchByTaskID", mock.Anything, taskID.Hex()).Return(&domain.Task{}, errors.New("task not found"))
 
@@ -221,18 +259,141 @@ func (suite *taskUsecaseSuite) TestDelete_Positive() {
 func (suite *taskUsecaseSuite) TestDelete_TaskNotFound() {
 	taskID := primitive.NewObjectID().Hex()
 
-	suite.repository.On("Delete", mock.Anything, taskID).Return(errors.New("task not found"))
+	suite.repository.On("FetchByTaskID", mock.Anything, taskID).Return(nil, mongo.ErrNoDocuments)
 
 	err := suite.usecase.Delete(context.TODO(), taskID)
 
 	// Assertions
 	suite.Error(err)
 	suite.EqualError(err, "task not found")
-	suite.repository.AssertExpectations(suite.T())
+	suite.repository.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
 
 	
 }
 
+func (suite *taskUsecaseSuite) TestDelete_PurgesAttachments() {
+	taskID := primitive.NewObjectID().Hex()
+	kept := domain.Attachment{ID: primitive.NewObjectID(), Storage_key: "attachments/o/t/kept"}
+	removed := domain.Attachment{ID: primitive.NewObjectID(), Storage_key: "attachments/o/t/removed"}
+
+	suite.repository.On("FetchByTaskID", mock.Anything, taskID).Return(&domain.Task{}, nil)
+	suite.repository.On("Delete", mock.Anything, taskID).Return(nil)
+	suite.attachments.On("FetchByTask", mock.Anything, taskID).Return([]domain.Attachment{kept, removed}, nil)
+	suite.blobs.On("Delete", mock.Anything, kept.Storage_key).Return(errors.New("store unavailable"))
+	suite.blobs.On("Delete", mock.Anything, removed.Storage_key).Return(nil)
+	suite.attachments.On("Delete", mock.Anything, removed.ID.Hex()).Return(nil)
+
+	err := suite.usecase.Delete(context.TODO(), taskID)
+
+	suite.NoError(err, "the task is deleted even when a blob cannot be")
+	suite.attachments.AssertNotCalled(suite.T(), "Delete", mock.Anything, kept.ID.Hex())
+	suite.attachments.AssertExpectations(suite.T())
+}
+
+func (suite *taskUsecaseSuite) TestFetchAll_Completion() {
+	tasks := &[]domain.Task{
+		{Title: "no checklist"},
+		{Title: "one of three", Checklist: []domain.ChecklistItem{{Done: true}, {}, {}}},
+	}
+	suite.repository.On("FetchAll", mock.Anything, domain.TaskFilter{}).Return(tasks, nil)
+
+	result, err := suite.usecase.FetchAll(context.TODO(), domain.TaskQuery{})
+
+	suite.NoError(err)
+	suite.Nil((*result)[0].Completion)
+	suite.NotNil((*result)[0].Checklist, "an empty checklist is a list, not null")
+	suite.Require().NotNil((*result)[1].Completion)
+	suite.Equal(33, *(*result)[1].Completion)
+}
+
+func (suite *taskUsecaseSuite) TestToggleChecklistItem() {
+	taskID := primitive.NewObjectID().Hex()
+	item := domain.ChecklistItem{ID: primitive.NewObjectID(), Text: "write tests", Done: true}
+	suite.repository.On("FetchByTaskID", mock.Anything, taskID).Return(&domain.Task{Checklist: []domain.ChecklistItem{item}}, nil)
+	suite.repository.On("UpdateChecklistItem", mock.Anything, taskID, item.ID.Hex(), mock.MatchedBy(func(update domain.ChecklistItemUpdate) bool {
+		return update.Text == nil && update.Done != nil && !*update.Done
+	}), mock.Anything).Return(nil)
+
+	_, err := suite.usecase.ToggleChecklistItem(context.TODO(), taskID, item.ID.Hex())
+
+	suite.NoError(err)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *taskUsecaseSuite) TestReorderChecklist() {
+	taskID := primitive.NewObjectID().Hex()
+	first := domain.ChecklistItem{ID: primitive.NewObjectID(), Text: "first"}
+	second := domain.ChecklistItem{ID: primitive.NewObjectID(), Text: "second"}
+	current := []domain.ChecklistItem{first, second}
+	suite.repository.On("FetchByTaskID", mock.Anything, taskID).Return(&domain.Task{Checklist: current}, nil)
+	suite.repository.On("ReplaceChecklist", mock.Anything, taskID, current, []domain.ChecklistItem{second, first}).Return(nil)
+
+	task, err := suite.usecase.ReorderChecklist(context.TODO(), taskID, []string{second.ID.Hex(), first.ID.Hex()})
+	suite.Require().NoError(err)
+	suite.Equal("second", task.Checklist[0].Text)
+
+	_, err = suite.usecase.ReorderChecklist(context.TODO(), taskID, []string{second.ID.Hex(), second.ID.Hex()})
+	suite.ErrorIs(err, domain.ErrInvalidChecklistOrder)
+	_, err = suite.usecase.ReorderChecklist(context.TODO(), taskID, []string{first.ID.Hex()})
+	suite.ErrorIs(err, domain.ErrInvalidChecklistOrder)
+	suite.repository.AssertNumberOfCalls(suite.T(), "ReplaceChecklist", 1)
+}
+
+func (suite *taskUsecaseSuite) TestAddChecklistItem_UnknownTask() {
+	taskID := primitive.NewObjectID().Hex()
+	suite.repository.On("AddChecklistItem", mock.Anything, taskID, mock.Anything, -1).Return(domain.ErrTaskNotFound)
+
+	_, err := suite.usecase.AddChecklistItem(context.TODO(), taskID, domain.ChecklistItem{Text: "step"}, nil)
+	suite.ErrorIs(err, domain.ErrTaskNotFound)
+}
+
+func (suite *taskUsecaseSuite) TestFetchAll_CustomFieldQuery() {
+	customFields := new(mocks.CustomFieldRepository)
+	suite.usecase = NewTaskUsecase(suite.repository, suite.attachments, suite.blobs, customFields, suite.orgs, suite.outbox, passThroughTransactor(), 10)
+	customFields.On("FetchAll", mock.Anything).Return([]domain.CustomField{
+		{Key: "points", Type: domain.CustomFieldNumber},
+		{Key: "priority", Type: domain.CustomFieldEnum, Options: []string{"low", "high"}},
+	}, nil)
+	expected := domain.TaskFilter{Custom_fields: map[string]interface{}{"points": float64(3), "priority": "high"}, Sort_by: "custom_fields.points", Descending: true}
+	suite.repository.On("FetchAll", mock.Anything, expected).Return(&[]domain.Task{}, nil)
+
+	_, err := suite.usecase.FetchAll(context.TODO(), domain.TaskQuery{Custom_fields: map[string]string{"points": "3", "priority": "high"}, Sort: "-cf.points"})
+	suite.NoError(err)
+	suite.repository.AssertExpectations(suite.T())
+
+	for _, query := range []domain.TaskQuery{
+		{Custom_fields: map[string]string{"points": "many"}},
+		{Custom_fields: map[string]string{"unknown": "x"}},
+		{Sort: "cf.unknown"},
+		{Sort: "created_by"},
+	} {
+		_, err := suite.usecase.FetchAll(context.TODO(), query)
+		suite.ErrorIs(err, domain.ErrInvalidTaskQuery, query)
+	}
+}
+
+func (suite *taskUsecaseSuite) TestFetchAll_FilterExpression() {
+	suite.repository.On("FetchAll", mock.Anything, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+		return filter.Expression != nil && filter.Expression.Field == "status"
+	})).Return(&[]domain.Task{}, nil)
+
+	_, err := suite.usecase.FetchAll(context.TODO(), domain.TaskQuery{Filter: "status = todo"})
+	suite.NoError(err)
+
+	_, err = suite.usecase.FetchAll(context.TODO(), domain.TaskQuery{Filter: "status = "})
+	suite.ErrorIs(err, domain.ErrInvalidFilter)
+	suite.repository.AssertNumberOfCalls(suite.T(), "FetchAll", 1)
+}
+
+func (suite *taskUsecaseSuite) TestCreateTask_RequiredCustomField() {
+	customFields := new(mocks.CustomFieldRepository)
+	suite.usecase = NewTaskUsecase(suite.repository, suite.attachments, suite.blobs, customFields, suite.orgs, suite.outbox, passThroughTransactor(), 10)
+	customFields.On("FetchAll", mock.Anything).Return([]domain.CustomField{{Key: "points", Type: domain.CustomFieldNumber, Required: true}}, nil)
+
+	err := suite.usecase.Create(context.TODO(), &domain.Task{Title: "no points"})
+	suite.ErrorIs(err, domain.ErrInvalidCustomField)
+	suite.repository.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
 
 func TestTaskUsecase(t *testing.T) {
 	suite.Run(t, new(taskUsecaseSuite))
@@ -0,0 +1,181 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"time"
+)
+
+const (
+	// maxTimeEntry bounds a single logged entry.
+	maxTimeEntry = 24 * time.Hour
+	// defaultReportRange is used when a report has no start date.
+	defaultReportRange = 30 * 24 * time.Hour
+)
+
+type timeTrackingUsecase struct {
+	timeEntryRepository domain.TimeEntryRepository
+	taskRepository      domain.TaskRepository
+	contextTimeout      time.Duration
+	now                 func() time.Time
+}
+
+func NewTimeTrackingUsecase(timeEntryRepository domain.TimeEntryRepository, taskRepository domain.TaskRepository, timeout time.Duration) domain.TimeTrackingUsecase {
+	return &timeTrackingUsecase{
+		timeEntryRepository: timeEntryRepository,
+		taskRepository:      taskRepository,
+		contextTimeout:      timeout,
+		now:                 time.Now,
+	}
+}
+
+func (tu *timeTrackingUsecase) StartTimer(c context.Context, taskID string, userID string, note string) (*domain.TimeEntry, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	if err := ensureTask(ctx, tu.taskRepository, taskID); err != nil {
+		return nil, err
+	}
+	entry := domain.TimeEntry{
+		Task_id:    taskID,
+		User_id:    userID,
+		Started_at: tu.now(),
+		Note:       note,
+	}
+	if err := tu.timeEntryRepository.StartTimer(ctx, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// StopTimer ends the running entry no more than a day after it started, so
+// a forgotten timer logs no more than LogTime would accept.
+func (tu *timeTrackingUsecase) StopTimer(c context.Context, userID string) (*domain.TimeEntry, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	entry, err := tu.timeEntryRepository.StopTimer(ctx, userID, tu.now(), maxTimeEntry)
+	if err != nil {
+		return nil, err
+	}
+	fillDuration(&entry, tu.now())
+	return &entry, nil
+}
+
+func (tu *timeTrackingUsecase) FetchRunning(c context.Context, userID string) (*domain.TimeEntry, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	entry, err := tu.timeEntryRepository.FetchRunning(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	fillDuration(&entry, tu.now())
+	return &entry, nil
+}
+
+// LogTime records time after the fact. The entry needs Started_at and
+// either Ended_at or Duration_seconds, and may not be longer than a day or
+// end in the future.
+func (tu *timeTrackingUsecase) LogTime(c context.Context, taskID string, entry *domain.TimeEntry) error {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	if entry.Started_at.IsZero() {
+		return domain.ErrInvalidTimeEntry
+	}
+	if entry.Ended_at == nil {
+		if entry.Duration_seconds <= 0 {
+			return domain.ErrInvalidTimeEntry
+		}
+		ended := entry.Started_at.Add(time.Duration(entry.Duration_seconds) * time.Second)
+		entry.Ended_at = &ended
+	}
+	duration := entry.Ended_at.Sub(entry.Started_at)
+	if duration <= 0 || duration > maxTimeEntry || entry.Ended_at.After(tu.now()) {
+		return domain.ErrInvalidTimeEntry
+	}
+
+	if err := ensureTask(ctx, tu.taskRepository, taskID); err != nil {
+		return err
+	}
+	entry.Task_id = taskID
+	entry.Running = false
+	if err := tu.timeEntryRepository.Create(ctx, entry); err != nil {
+		return err
+	}
+	fillDuration(entry, tu.now())
+	return nil
+}
+
+func (tu *timeTrackingUsecase) FetchByTask(c context.Context, taskID string) (*[]domain.TimeEntry, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	if err := ensureTask(ctx, tu.taskRepository, taskID); err != nil {
+		return nil, err
+	}
+	entries, err := tu.timeEntryRepository.FetchByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	now := tu.now()
+	for i := range entries {
+		fillDuration(&entries[i], now)
+	}
+	return &entries, nil
+}
+
+// DeleteEntry lets users remove their own entries, and moderators (org owners
+// and admins) remove anyone's.
+func (tu *timeTrackingUsecase) DeleteEntry(c context.Context, entryID string, actorID string, moderator bool) error {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	entry, err := tu.timeEntryRepository.FetchByID(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	if entry.User_id != actorID && !moderator {
+		return domain.ErrNotTimeEntryOwner
+	}
+	return tu.timeEntryRepository.Delete(ctx, entryID)
+}
+
+// Report totals logged time. Without a range it covers the last 30 days.
+func (tu *timeTrackingUsecase) Report(c context.Context, filter domain.TimeReportFilter) (*domain.TimeReport, error) {
+	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
+	defer cancel()
+
+	if filter.Group_by == "" {
+		filter.Group_by = domain.TimeReportByUser
+	}
+	if filter.To.IsZero() {
+		filter.To = tu.now()
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.Add(-defaultReportRange)
+	}
+	if !filter.From.Before(filter.To) {
+		return nil, domain.ErrInvalidTimeRange
+	}
+
+	rows, err := tu.timeEntryRepository.Report(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	report := domain.TimeReport{Group_by: filter.Group_by, From: filter.From, To: filter.To, Rows: rows}
+	for _, row := range rows {
+		report.Total_seconds += row.Seconds
+	}
+	return &report, nil
+}
+
+// fillDuration derives Duration_seconds, counting running timers up to now.
+func fillDuration(entry *domain.TimeEntry, now time.Time) {
+	end := now
+	if entry.Ended_at != nil {
+		end = *entry.Ended_at
+	}
+	entry.Duration_seconds = int64(end.Sub(entry.Started_at) / time.Second)
+}
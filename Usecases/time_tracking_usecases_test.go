@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type timeTrackingUsecaseSuite struct {
+	suite.Suite
+	entries *mocks.TimeEntryRepository
+	tasks   *mocks.TaskRepository
+	usecase *timeTrackingUsecase
+	now     time.Time
+}
+
+func (suite *timeTrackingUsecaseSuite) SetupTest() {
+	suite.entries = new(mocks.TimeEntryRepository)
+	suite.tasks = new(mocks.TaskRepository)
+	suite.usecase = NewTimeTrackingUsecase(suite.entries, suite.tasks, 10*time.Second).(*timeTrackingUsecase)
+	suite.now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.usecase.now = func() time.Time { return suite.now }
+
+	suite.tasks.On("FetchByTaskID", mock.Anything, "t1").Return(&domain.Task{}, nil).Maybe()
+	suite.tasks.On("FetchByTaskID", mock.Anything, "missing").Return(nil, mongo.ErrNoDocuments).Maybe()
+}
+
+func (suite *timeTrackingUsecaseSuite) TestStartTimer_AlreadyRunning() {
+	suite.entries.On("StartTimer", mock.Anything, mock.AnythingOfType("*domain.TimeEntry")).Return(domain.ErrTimerRunning)
+
+	_, err := suite.usecase.StartTimer(context.TODO(), "t1", "u1", "")
+	suite.ErrorIs(err, domain.ErrTimerRunning)
+}
+
+func (suite *timeTrackingUsecaseSuite) TestStartTimer_UnknownTask() {
+	_, err := suite.usecase.StartTimer(context.TODO(), "missing", "u1", "")
+	suite.ErrorIs(err, domain.ErrTaskNotFound)
+	suite.entries.AssertNotCalled(suite.T(), "StartTimer", mock.Anything, mock.Anything)
+}
+
+func (suite *timeTrackingUsecaseSuite) TestStopTimer_Duration() {
+	ended := suite.now
+	suite.entries.On("StopTimer", mock.Anything, "u1", suite.now, maxTimeEntry).Return(domain.TimeEntry{Started_at: suite.now.Add(-90 * time.Minute), Ended_at: &ended}, nil)
+
+	entry, err := suite.usecase.StopTimer(context.TODO(), "u1")
+	suite.Require().NoError(err)
+	suite.Equal(int64(90*60), entry.Duration_seconds)
+}
+
+func (suite *timeTrackingUsecaseSuite) TestLogTime_DurationSetsEnd() {
+	suite.entries.On("Create", mock.Anything, mock.AnythingOfType("*domain.TimeEntry")).Return(nil)
+
+	entry := domain.TimeEntry{User_id: "u1", Started_at: suite.now.Add(-2 * time.Hour), Duration_seconds: 3600}
+	suite.Require().NoError(suite.usecase.LogTime(context.TODO(), "t1", &entry))
+	suite.Equal(suite.now.Add(-time.Hour), *entry.Ended_at)
+	suite.Equal("t1", entry.Task_id)
+}
+
+func (suite *timeTrackingUsecaseSuite) TestLogTime_Invalid() {
+	future := suite.now.Add(time.Hour)
+	before := suite.now.Add(-3 * time.Hour)
+	for name, entry := range map[string]domain.TimeEntry{
+		"no start":       {Duration_seconds: 60},
+		"no end":         {Started_at: suite.now.Add(-time.Hour)},
+		"end before":     {Started_at: suite.now.Add(-time.Hour), Ended_at: &before},
+		"in the future":  {Started_at: suite.now, Ended_at: &future},
+		"longer than 1d": {Started_at: suite.now.Add(-48 * time.Hour), Duration_seconds: 30 * 3600},
+	} {
+		suite.ErrorIs(suite.usecase.LogTime(context.TODO(), "t1", &entry), domain.ErrInvalidTimeEntry, name)
+	}
+	suite.entries.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *timeTrackingUsecaseSuite) TestReport_DefaultsAndTotal() {
+	suite.entries.On("Report", mock.Anything, domain.TimeReportFilter{
+		Group_by: domain.TimeReportByUser,
+		From:     suite.now.Add(-30 * 24 * time.Hour),
+		To:       suite.now,
+	}).Return([]domain.TimeReportRow{{Key: "u1", Seconds: 600}, {Key: "u2", Seconds: 300}}, nil)
+
+	report, err := suite.usecase.Report(context.TODO(), domain.TimeReportFilter{})
+	suite.Require().NoError(err)
+	suite.Equal(int64(900), report.Total_seconds)
+
+	_, err = suite.usecase.Report(context.TODO(), domain.TimeReportFilter{From: suite.now, To: suite.now})
+	suite.ErrorIs(err, domain.ErrInvalidTimeRange)
+}
+
+func (suite *timeTrackingUsecaseSuite) TestDeleteEntry_OnlyOwnerOrModerator() {
+	suite.entries.On("FetchByID", mock.Anything, "e1").Return(domain.TimeEntry{User_id: "u1"}, nil)
+	suite.entries.On("Delete", mock.Anything, "e1").Return(nil)
+
+	suite.ErrorIs(suite.usecase.DeleteEntry(context.TODO(), "e1", "u2", false), domain.ErrNotTimeEntryOwner)
+	suite.NoError(suite.usecase.DeleteEntry(context.TODO(), "e1", "u1", false))
+	suite.entries.AssertNumberOfCalls(suite.T(), "Delete", 1)
+}
+
+func TestTimeTrackingUsecase(t *testing.T) {
+	suite.Run(t, new(timeTrackingUsecaseSuite))
+}
@@ -3,52 +3,498 @@ package usecases
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
 	domain "task-manger-api_test/Domain"
 	infrastructure "task-manger-api_test/Infrastructure"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type userUsecase struct {
 	userRepository domain.UserRepository
+	organizationRepository domain.OrganizationRepository
+	sessionRepository domain.SessionRepository
+	outboxRepository domain.OutboxRepository
+	transactor domain.Transactor
 	contextTimeout time.Duration
+	// bootstrapAdminEmail is promoted to ADMIN on signup, so a fresh
+	// deployment can get its first platform admin.
+	bootstrapAdminEmail string
+	// openRegistration lets anyone sign up. When off, only invited users
+	// and the bootstrap admin can create accounts.
+	openRegistration bool
 }
 
-func NewUserUsecase(userRepository domain.UserRepository, timeout time.Duration) domain.UserUsecase {
+func NewUserUsecase(userRepository domain.UserRepository, organizationRepository domain.OrganizationRepository, sessionRepository domain.SessionRepository, outboxRepository domain.OutboxRepository, transactor domain.Transactor, timeout time.Duration) domain.UserUsecase {
 	return &userUsecase{
 		userRepository: userRepository,
+		organizationRepository: organizationRepository,
+		sessionRepository: sessionRepository,
+		outboxRepository: outboxRepository,
+		transactor: transactor,
 		contextTimeout: timeout,
+		bootstrapAdminEmail: os.Getenv("BOOTSTRAP_ADMIN_EMAIL"),
+		openRegistration: openRegistrationFromEnv(),
 	}
 }
 
+// openRegistrationFromEnv reads OPEN_REGISTRATION, defaulting to open.
+func openRegistrationFromEnv() bool {
+	open, err := strconv.ParseBool(os.Getenv("OPEN_REGISTRATION"))
+	return err != nil || open
+}
+
+func (uu *userUsecase) isBootstrapAdmin(user *domain.User) bool {
+	return uu.bootstrapAdminEmail != "" && user.Email != nil && strings.EqualFold(*user.Email, uu.bootstrapAdminEmail)
+}
+
 func (uu *userUsecase) Create(c context.Context, user *domain.User) error {
 	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
 	defer cancel()
-	return uu.userRepository.Create(ctx, user)
+
+	if !uu.openRegistration && !uu.isBootstrapAdmin(user) {
+		return domain.ErrRegistrationClosed
+	}
+	if user.Password != nil {
+		if err := infrastructure.CheckPasswordPolicy(*user.Password); err != nil {
+			return err
+		}
+	}
+	err := uu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := uu.userRepository.Create(tx, user); err != nil {
+			return err
+		}
+		if uu.isBootstrapAdmin(user) {
+			if err := uu.userRepository.Update(tx, user.User_id); err != nil {
+				return err
+			}
+			user.User_type = "ADMIN"
+		}
+		return recordEvents(tx, uu.outboxRepository, registered(user))
+	})
+	if err != nil {
+		return err
+	}
+	return createPersonalOrganization(ctx, uu.organizationRepository, user)
+}
+
+func registered(user *domain.User) domain.UserRegistered {
+	event := domain.UserRegistered{User_id: user.User_id, User_type: user.User_type}
+	if user.Username != nil {
+		event.Username = *user.Username
+	}
+	return event
+}
+
+// createPersonalOrganization gives a new user a workspace they own, so they
+// can start creating tasks straight away.
+func createPersonalOrganization(ctx context.Context, organizationRepository domain.OrganizationRepository, user *domain.User) error {
+	org := &domain.Organization{
+		Name: fmt.Sprintf("%s's workspace", *user.Username),
+		Created_by: user.User_id,
+	}
+	if err := organizationRepository.Create(ctx, org); err != nil {
+		return err
+	}
+	return organizationRepository.AddMember(ctx, &domain.Membership{
+		Org_id: org.ID.Hex(),
+		User_id: user.User_id,
+		Role: domain.OrgRoleOwner,
+	})
 }
 
 func (uu *userUsecase) HandleLogin(c context.Context, user *domain.User) (signedToken, signedRefreshToken string, err error) {
 	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
 	defer cancel()
 
+	if user.Username == nil || user.Password == nil {
+		return "", "", errors.New("username and password are required")
+	}
 	foundUser, err := uu.userRepository.FindByUsername(ctx, *user.Username)
 	if err != nil{
 		return "", "", errors.New("user not found")
 	}
-	check, verifMsg := infrastructure.VerifyPassword(*user.Password, *foundUser.Password)
+	if foundUser.Password == nil {
+		return "", "", domain.ErrIncorrectPassword
+	}
+	check, rehash, verifMsg := infrastructure.VerifyPassword(*user.Password, *foundUser.Password)
 	if !check{
 		return "", "", errors.New(verifMsg)
 	}
-	if foundUser.Username == nil || foundUser.Email == nil || foundUser.User_type == "" {
+	if rehash {
+		uu.rehashPassword(ctx, foundUser.User_id, *user.Password)
+	}
+	return uu.issueTokens(ctx, foundUser, "", "")
+}
+
+// rehashPassword upgrades a stored hash made with an outdated algorithm or
+// cost. Failures are logged; the old hash keeps working.
+func (uu *userUsecase) rehashPassword(ctx context.Context, userID string, password string) {
+	hash, err := infrastructure.HashPassword(password)
+	if err == nil {
+		err = uu.userRepository.UpdatePassword(ctx, userID, hash)
+	}
+	if err != nil {
+		log.Printf("rehashing password for user %s failed: %v", userID, err)
+	}
+}
+
+// HandleExternalLogin signs in the user linked to an identity verified by an
+// external provider. Unknown identities are linked to the user with the same
+// email only when both the provider and that user have verified it, since
+// anyone could have signed up with an address they do not own. Otherwise a
+// new USER is provisioned.
+func (uu *userUsecase) HandleExternalLogin(c context.Context, identity *domain.ExternalIdentity) (signedToken, signedRefreshToken string, err error) {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	foundUser, err := uu.userRepository.FindByIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return uu.issueTokens(ctx, foundUser, "", "")
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return "", "", err
+	}
+
+	if !identity.Email_verified || identity.Email == "" {
+		return "", "", errors.New("the identity provider did not return a verified email")
+	}
+	identity.Linked_at = time.Now()
+
+	foundUser, err = uu.userRepository.FindByEmail(ctx, identity.Email)
+	if err == nil {
+		if foundUser.Email_verified_at == nil {
+			return "", "", domain.ErrEmailNotVerified
+		}
+		if err := uu.userRepository.AddIdentity(ctx, foundUser.User_id, *identity); err != nil {
+			return "", "", err
+		}
+		return uu.issueTokens(ctx, foundUser, "", "")
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return "", "", err
+	}
+
+	if !uu.openRegistration {
+		return "", "", domain.ErrRegistrationClosed
+	}
+	newUser, err := uu.newExternalUser(ctx, identity)
+	if err != nil {
+		return "", "", err
+	}
+	err = uu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := uu.userRepository.CreateExternal(tx, newUser); err != nil {
+			return err
+		}
+		return recordEvents(tx, uu.outboxRepository, registered(newUser))
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if err := createPersonalOrganization(ctx, uu.organizationRepository, newUser); err != nil {
+		return "", "", err
+	}
+	return uu.issueTokens(ctx, *newUser, "", "")
+}
+
+func (uu *userUsecase) newExternalUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	base := identity.Username
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	name := identity.Name
+	if name == "" {
+		name = base
+	}
+
+	username := base
+	for attempt := 0; ; attempt++ {
+		_, err := uu.userRepository.FindByUsername(ctx, username)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if attempt == 10 {
+			return nil, errors.New("could not pick a unique username")
+		}
+		username = fmt.Sprintf("%s-%s", base, primitive.NewObjectID().Hex()[18:])
+	}
+
+	email := identity.Email
+	verifiedAt := identity.Linked_at
+	return &domain.User{
+		Name:              &name,
+		Username:          &username,
+		Email:             &email,
+		Identities:        []domain.ExternalIdentity{*identity},
+		Email_verified_at: &verifiedAt,
+	}, nil
+}
+
+// issueTokens signs tokens for user with orgID as the active organization,
+// defaulting to the first organization they joined. Without a sessionID a
+// new session is started for the client in ctx.
+func (uu *userUsecase) issueTokens(ctx context.Context, user domain.User, orgID string, sessionID string) (string, string, error) {
+	if !user.IsActive() {
+		return "", "", domain.ErrUserDeactivated
+	}
+	if user.Username == nil || user.Email == nil || user.User_type == "" {
 		return "", "", errors.New("invalid user data")
-	}	
-	token, refreshToken, err := infrastructure.GenerateJWTToken(foundUser.User_id, *foundUser.Username, *foundUser.Email,  foundUser.User_type)
-	return token, refreshToken, err
+	}
+	if orgID == "" {
+		var err error
+		if orgID, err = defaultOrganization(ctx, uu.organizationRepository, user.User_id); err != nil {
+			return "", "", err
+		}
+	}
+	if sessionID == "" {
+		client := domain.ClientFromContext(ctx)
+		now := time.Now()
+		session := &domain.Session{
+			User_id: user.User_id,
+			User_agent: client.User_agent,
+			Ip: client.Ip,
+			Created_at: now,
+			Last_seen_at: now,
+			Expires_at: now.Add(infrastructure.RefreshTokenTTL),
+		}
+		if err := uu.sessionRepository.Create(ctx, session); err != nil {
+			return "", "", err
+		}
+		sessionID = session.ID.Hex()
+	}
+	return infrastructure.GenerateJWTToken(user.User_id, *user.Username, *user.Email, user.User_type, orgID, sessionID)
+}
+
+// defaultOrganization is the first organization userID joined, or "" if none.
+func defaultOrganization(ctx context.Context, organizationRepository domain.OrganizationRepository, userID string) (string, error) {
+	memberships, err := organizationRepository.FetchMemberships(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if len(memberships) == 0 {
+		return "", nil
+	}
+	return memberships[0].Org_id, nil
+}
+
+// SwitchOrganization reissues tokens for the same session with orgID active.
+func (uu *userUsecase) SwitchOrganization(c context.Context, userID string, sessionID string, orgID string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	if _, err := uu.organizationRepository.FetchMembership(ctx, orgID, userID); err != nil {
+		return "", "", err
+	}
+	user, err := uu.userRepository.FetchByUserID(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+	return uu.issueTokens(ctx, user, orgID, sessionID)
+}
+
+func (uu *userUsecase) FetchSessions(c context.Context, userID string, currentSessionID string) (*[]domain.Session, error) {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	sessions, err := uu.sessionRepository.FetchActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.Hex() == currentSessionID
+	}
+	return &sessions, nil
+}
+
+func (uu *userUsecase) RevokeSession(c context.Context, userID string, sessionID string) error {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+	return uu.sessionRepository.Revoke(ctx, userID, sessionID, time.Now())
 }
 
 func (uu *userUsecase) Update(c context.Context, userID string) error {
 	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
 	defer cancel()
-	return uu.userRepository.Update(ctx, userID)
+	return uu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := uu.userRepository.Update(tx, userID); err != nil {
+			return err
+		}
+		return recordEvents(tx, uu.outboxRepository, domain.UserPromoted{User_id: userID})
+	})
+}
+
+
+
+func (uu *userUsecase) FetchByUserID(c context.Context, userID string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	user, err := uu.userRepository.FetchByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (uu *userUsecase) FetchAll(c context.Context, filter domain.UserFilter) (*domain.UserPage, error) {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	users, total, err := uu.userRepository.FetchAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserPage{Users: users, Total: total, Page: filter.Page, Limit: filter.Limit}, nil
+}
+
+func (uu *userUsecase) UpdateProfile(c context.Context, userID string, update domain.ProfileUpdate) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	if err := uu.userRepository.UpdateProfile(ctx, userID, update); err != nil {
+		return nil, err
+	}
+	user, err := uu.userRepository.FetchByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (uu *userUsecase) ChangePassword(c context.Context, userID string, change domain.PasswordChange) error {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	if _, err := reauthenticate(ctx, uu.userRepository, userID, change.Current_password); err != nil {
+		return err
+	}
+	if err := infrastructure.CheckPasswordPolicy(change.New_password); err != nil {
+		return err
+	}
+	hash, err := infrastructure.HashPassword(change.New_password)
+	if err != nil {
+		return err
+	}
+	return uu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := uu.userRepository.UpdatePassword(tx, userID, hash); err != nil {
+			return err
+		}
+		return recordEvents(tx, uu.outboxRepository, domain.UserPasswordChanged{User_id: userID})
+	})
+}
+
+func (uu *userUsecase) DeleteAccount(c context.Context, userID string, password string) error {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	if _, err := reauthenticate(ctx, uu.userRepository, userID, password); err != nil {
+		return err
+	}
+	if err := uu.delete(ctx, userID); err != nil {
+		return err
+	}
+	if err := uu.organizationRepository.RemoveUser(ctx, userID); err != nil {
+		return err
+	}
+	return uu.sessionRepository.RevokeAll(ctx, userID, time.Now())
 }
 
+func (uu *userUsecase) SetActive(c context.Context, userID string, active bool) error {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
 
+	if active {
+		return uu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+			if err := uu.userRepository.SetDeactivated(tx, userID, nil); err != nil {
+				return err
+			}
+			return recordEvents(tx, uu.outboxRepository, domain.UserReactivated{User_id: userID})
+		})
+	}
+	now := time.Now()
+	err := uu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := uu.userRepository.SetDeactivated(tx, userID, &now); err != nil {
+			return err
+		}
+		return recordEvents(tx, uu.outboxRepository, domain.UserDeactivated{User_id: userID})
+	})
+	if err != nil {
+		return err
+	}
+	return uu.sessionRepository.RevokeAll(ctx, userID, now)
+}
+
+// Demote revokes the user's sessions too, so tokens issued while they were
+// an admin stop working.
+func (uu *userUsecase) Demote(c context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+	err := uu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := uu.userRepository.SetUserType(tx, userID, "USER"); err != nil {
+			return err
+		}
+		return recordEvents(tx, uu.outboxRepository, domain.UserDemoted{User_id: userID})
+	})
+	if err != nil {
+		return err
+	}
+	return uu.sessionRepository.RevokeAll(ctx, userID, time.Now())
+}
+
+func (uu *userUsecase) Delete(c context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	if err := uu.delete(ctx, userID); err != nil {
+		return err
+	}
+	if err := uu.organizationRepository.RemoveUser(ctx, userID); err != nil {
+		return err
+	}
+	return uu.sessionRepository.RevokeAll(ctx, userID, time.Now())
+}
+
+// delete removes the user and records UserDeleted with it.
+func (uu *userUsecase) delete(ctx context.Context, userID string) error {
+	return uu.transactor.WithTransaction(ctx, func(tx context.Context) error {
+		if err := uu.userRepository.Delete(tx, userID); err != nil {
+			return err
+		}
+		return recordEvents(tx, uu.outboxRepository, domain.UserDeleted{User_id: userID})
+	})
+}
+
+// reauthenticate checks password against the stored hash before a sensitive
+// account change.
+func reauthenticate(ctx context.Context, userRepository domain.UserRepository, userID string, password string) (domain.User, error) {
+	user, err := userRepository.FetchByUserID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.Password == nil {
+		return domain.User{}, errors.New("this account has no password; sign in with your identity provider")
+	}
+	if check, _, _ := infrastructure.VerifyPassword(password, *user.Password); !check {
+		return domain.User{}, domain.ErrIncorrectPassword
+	}
+	return user, nil
+}
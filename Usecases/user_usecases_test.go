@@ -3,8 +3,10 @@ package usecases
 import (
 	"context"
 	"log"
+	"strings"
 	domain "task-manger-api_test/Domain"
 	"task-manger-api_test/Domain/mocks"
+	infrastructure "task-manger-api_test/Infrastructure"
 	repositories "task-manger-api_test/Repositories"
 	"task-manger-api_test/config"
 	"testing"
@@ -14,7 +16,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type userUsecaseSuite struct{
@@ -32,7 +36,7 @@ func (suite *userUsecaseSuite) SetupSuite(){
 	client, db := config.ConnectDB(configs)
 
 	repository := new(mocks.UserRepository)
-	usecase := NewUserUsecase(repository, 10)
+	usecase := NewUserUsecase(repository, new(mocks.OrganizationRepository), new(mocks.SessionRepository), new(mocks.OutboxRepository), new(mocks.Transactor), 10)
 
 	suite.client = client
 	suite.db = db
@@ -53,7 +57,7 @@ func (suite *userUsecaseSuite) TearDownSuite() {
 
 func (suite *userUsecaseSuite) TearDownTest() {
 	// List of collections you might want to clear after each test
-	collections := []string{"users"}
+	collections := []string{"users", "organizations", "memberships", "sessions"}
 
 	for _, collection := range collections {
 		_, err := suite.db.Collection(collection).DeleteMany(context.TODO(), bson.D{})
@@ -65,7 +69,9 @@ func (suite *userUsecaseSuite) TearDownTest() {
 func (suite *userUsecaseSuite) SetupTest() {
 	// Initialize the repository with the real database
 	repository := repositories.NewUserRepository(suite.db, "users")
-	suite.usecase = NewUserUsecase(repository, 10*time.Second)
+	organizations := repositories.NewOrganizationRepository(suite.db, domain.CollectionOrganization, domain.CollectionMembership)
+	sessions := repositories.NewSessionRepository(suite.db, domain.CollectionSession)
+	suite.usecase = NewUserUsecase(repository, organizations, sessions, repositories.NewOutboxRepository(suite.db, domain.CollectionOutbox), repositories.NewTransactor(suite.db), 10*time.Second)
 }
 
 // Create user test
@@ -154,6 +160,321 @@ func (suite *userUsecaseSuite) TestUserValidation() {
 	}
 }
 
+type externalLoginSuite struct{
+	suite.Suite
+	repository *mocks.UserRepository
+	organizations *mocks.OrganizationRepository
+	sessions *mocks.SessionRepository
+	outbox *mocks.OutboxRepository
+	usecase domain.UserUsecase
+}
+
+func (suite *externalLoginSuite) SetupTest() {
+	suite.repository = new(mocks.UserRepository)
+	suite.organizations = new(mocks.OrganizationRepository)
+	suite.organizations.On("FetchMemberships", mock.Anything, mock.Anything).Return([]domain.Membership{{Org_id: "org1", Role: domain.OrgRoleOwner}}, nil).Maybe()
+	suite.sessions = new(mocks.SessionRepository)
+	suite.sessions.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Session).ID = primitive.NewObjectID()
+	}).Return(nil).Maybe()
+	suite.outbox = new(mocks.OutboxRepository)
+	suite.outbox.On("Append", mock.Anything, mock.Anything).Return(nil).Maybe()
+	suite.usecase = NewUserUsecase(suite.repository, suite.organizations, suite.sessions, suite.outbox, passThroughTransactor(), 10*time.Second)
+}
+
+func (suite *externalLoginSuite) identity() *domain.ExternalIdentity {
+	return &domain.ExternalIdentity{
+		Provider: "stub",
+		Subject: "provider-user-1",
+		Email: "jane@example.com",
+		Email_verified: true,
+		Name: "Jane Doe",
+	}
+}
+
+func (suite *externalLoginSuite) TestLinkedIdentity() {
+	user := domain.User{User_id: "u1", Username: ptr("jane"), Email: ptr("jane@example.com"), User_type: "USER"}
+	suite.repository.On("FindByIdentity", mock.Anything, "stub", "provider-user-1").Return(user, nil)
+
+	token, refreshToken, err := suite.usecase.HandleExternalLogin(context.TODO(), suite.identity())
+	suite.NoError(err)
+	suite.NotEmpty(token)
+	suite.NotEmpty(refreshToken)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *externalLoginSuite) TestLinksExistingUserByVerifiedEmail() {
+	verifiedAt := time.Now().Add(-time.Hour)
+	user := domain.User{User_id: "u1", Username: ptr("jane"), Email: ptr("jane@example.com"), User_type: "USER", Email_verified_at: &verifiedAt}
+	suite.repository.On("FindByIdentity", mock.Anything, "stub", "provider-user-1").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.repository.On("FindByEmail", mock.Anything, "jane@example.com").Return(user, nil)
+	suite.repository.On("AddIdentity", mock.Anything, "u1", mock.MatchedBy(func(identity domain.ExternalIdentity) bool {
+		return identity.Subject == "provider-user-1" && !identity.Linked_at.IsZero()
+	})).Return(nil)
+
+	_, _, err := suite.usecase.HandleExternalLogin(context.TODO(), suite.identity())
+	suite.NoError(err)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *externalLoginSuite) TestUnverifiedLocalEmailIsNotLinked() {
+	user := domain.User{User_id: "u1", Username: ptr("jane"), Email: ptr("Jane@Example.com"), User_type: "USER"}
+	suite.repository.On("FindByIdentity", mock.Anything, "stub", "provider-user-1").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.repository.On("FindByEmail", mock.Anything, "jane@example.com").Return(user, nil)
+
+	_, _, err := suite.usecase.HandleExternalLogin(context.TODO(), suite.identity())
+	suite.ErrorIs(err, domain.ErrEmailNotVerified)
+	suite.repository.AssertNotCalled(suite.T(), "AddIdentity", mock.Anything, mock.Anything, mock.Anything)
+	suite.repository.AssertNotCalled(suite.T(), "CreateExternal", mock.Anything, mock.Anything)
+}
+
+func (suite *externalLoginSuite) TestUnverifiedEmailIsNotLinked() {
+	identity := suite.identity()
+	identity.Email_verified = false
+	suite.repository.On("FindByIdentity", mock.Anything, "stub", "provider-user-1").Return(domain.User{}, mongo.ErrNoDocuments)
+
+	_, _, err := suite.usecase.HandleExternalLogin(context.TODO(), identity)
+	suite.EqualError(err, "the identity provider did not return a verified email")
+	suite.repository.AssertNotCalled(suite.T(), "FindByEmail", mock.Anything, mock.Anything)
+}
+
+func (suite *externalLoginSuite) TestProvisionsNewUser() {
+	suite.repository.On("FindByIdentity", mock.Anything, "stub", "provider-user-1").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.repository.On("FindByEmail", mock.Anything, "jane@example.com").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.repository.On("FindByUsername", mock.Anything, "jane").Return(domain.User{Username: ptr("jane")}, nil).Once()
+	suite.repository.On("FindByUsername", mock.Anything, mock.Anything).Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.repository.On("CreateExternal", mock.Anything, mock.AnythingOfType("*domain.User")).Run(func(args mock.Arguments) {
+		user := args.Get(1).(*domain.User)
+		user.User_id = "new-id"
+		user.User_type = "USER"
+	}).Return(nil)
+	suite.organizations.On("Create", mock.Anything, mock.MatchedBy(func(org *domain.Organization) bool {
+		return org.Created_by == "new-id"
+	})).Return(nil)
+	suite.organizations.On("AddMember", mock.Anything, mock.MatchedBy(func(membership *domain.Membership) bool {
+		return membership.User_id == "new-id" && membership.Role == domain.OrgRoleOwner
+	})).Return(nil)
+
+	_, _, err := suite.usecase.HandleExternalLogin(context.TODO(), suite.identity())
+	suite.NoError(err)
+
+	created := suite.repository.Calls[len(suite.repository.Calls)-1].Arguments.Get(1).(*domain.User)
+	suite.Equal("Jane Doe", *created.Name)
+	suite.NotEqual("jane", *created.Username, "a taken username gets a suffix")
+	suite.Len(created.Identities, 1)
+	suite.NotNil(created.Email_verified_at, "the provider verified the email")
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.UserRegistered) bool {
+		return event == domain.UserRegistered{User_id: "new-id", Username: *created.Username, User_type: "USER"}
+	}))
+	suite.repository.AssertExpectations(suite.T())
+	suite.organizations.AssertExpectations(suite.T())
+}
+
+func TestExternalLogin(t *testing.T) {
+	suite.Run(t, new(externalLoginSuite))
+}
+
+type accountSuite struct{
+	suite.Suite
+	repository *mocks.UserRepository
+	organizations *mocks.OrganizationRepository
+	sessions *mocks.SessionRepository
+	outbox *mocks.OutboxRepository
+	usecase domain.UserUsecase
+	passwordHash string
+}
+
+func (suite *accountSuite) SetupSuite() {
+	config := infrastructure.DefaultPasswordConfig()
+	config.Argon2id.Memory, config.Argon2id.Iterations = 1024, 1
+	config.Bcrypt.Cost = 4
+	suite.Require().NoError(infrastructure.InitPasswords(config))
+
+	hash, err := infrastructure.HashPassword("strongpassword")
+	suite.Require().NoError(err)
+	suite.passwordHash = hash
+}
+
+func (suite *accountSuite) SetupTest() {
+	suite.repository = new(mocks.UserRepository)
+	suite.organizations = new(mocks.OrganizationRepository)
+	suite.organizations.On("FetchMemberships", mock.Anything, mock.Anything).Return([]domain.Membership{{Org_id: "org1", Role: domain.OrgRoleOwner}}, nil).Maybe()
+	suite.sessions = new(mocks.SessionRepository)
+	suite.sessions.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Session).ID = primitive.NewObjectID()
+	}).Return(nil).Maybe()
+	suite.outbox = new(mocks.OutboxRepository)
+	suite.outbox.On("Append", mock.Anything, mock.Anything).Return(nil).Maybe()
+	suite.usecase = NewUserUsecase(suite.repository, suite.organizations, suite.sessions, suite.outbox, passThroughTransactor(), 10*time.Second)
+}
+
+func (suite *accountSuite) user() domain.User {
+	return domain.User{User_id: "u1", Username: ptr("johndoe"), Email: ptr("john.doe@example.com"), Password: ptr(suite.passwordHash), User_type: "USER"}
+}
+
+func (suite *accountSuite) TestChangePassword_Positive() {
+	suite.repository.On("FetchByUserID", mock.Anything, "u1").Return(suite.user(), nil)
+	suite.repository.On("UpdatePassword", mock.Anything, "u1", mock.MatchedBy(func(hash string) bool {
+		check, rehash, _ := infrastructure.VerifyPassword("newpassword", hash)
+		return check && !rehash
+	})).Return(nil)
+
+	err := suite.usecase.ChangePassword(context.TODO(), "u1", domain.PasswordChange{Current_password: "strongpassword", New_password: "newpassword"})
+	suite.NoError(err)
+	suite.repository.AssertExpectations(suite.T())
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.UserPasswordChanged) bool {
+		return event.User_id == "u1"
+	}))
+}
+
+func (suite *accountSuite) TestChangePassword_Policy() {
+	suite.repository.On("FetchByUserID", mock.Anything, "u1").Return(suite.user(), nil)
+
+	err := suite.usecase.ChangePassword(context.TODO(), "u1", domain.PasswordChange{Current_password: "strongpassword", New_password: "short"})
+	suite.EqualError(err, "password must be at least 8 characters")
+	suite.repository.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *accountSuite) TestLogin_RehashesLegacyBcrypt() {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("strongpassword"), 4)
+	suite.Require().NoError(err)
+	user := suite.user()
+	user.Password = ptr(string(legacy))
+	suite.repository.On("FindByUsername", mock.Anything, "johndoe").Return(user, nil)
+	suite.repository.On("UpdatePassword", mock.Anything, "u1", mock.MatchedBy(func(hash string) bool {
+		return strings.HasPrefix(hash, "$argon2id$")
+	})).Return(nil)
+
+	_, _, err = suite.usecase.HandleLogin(context.TODO(), &domain.User{Username: ptr("johndoe"), Password: ptr("strongpassword")})
+	suite.NoError(err)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *accountSuite) TestLogin_CurrentHashIsNotRehashed() {
+	suite.repository.On("FindByUsername", mock.Anything, "johndoe").Return(suite.user(), nil)
+
+	_, _, err := suite.usecase.HandleLogin(context.TODO(), &domain.User{Username: ptr("johndoe"), Password: ptr("strongpassword")})
+	suite.NoError(err)
+	suite.repository.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *accountSuite) TestChangePassword_WrongCurrentPassword() {
+	suite.repository.On("FetchByUserID", mock.Anything, "u1").Return(suite.user(), nil)
+
+	err := suite.usecase.ChangePassword(context.TODO(), "u1", domain.PasswordChange{Current_password: "wrong", New_password: "newpassword"})
+	suite.ErrorIs(err, domain.ErrIncorrectPassword)
+	suite.repository.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *accountSuite) TestDeleteAccount_RequiresPassword() {
+	suite.repository.On("FetchByUserID", mock.Anything, "u1").Return(suite.user(), nil)
+	suite.repository.On("Delete", mock.Anything, "u1").Return(nil).Once()
+	suite.organizations.On("RemoveUser", mock.Anything, "u1").Return(nil).Once()
+	suite.sessions.On("RevokeAll", mock.Anything, "u1", mock.Anything).Return(nil).Once()
+
+	suite.ErrorIs(suite.usecase.DeleteAccount(context.TODO(), "u1", "wrong"), domain.ErrIncorrectPassword)
+	suite.NoError(suite.usecase.DeleteAccount(context.TODO(), "u1", "strongpassword"))
+	suite.repository.AssertExpectations(suite.T())
+	suite.sessions.AssertExpectations(suite.T())
+}
+
+func (suite *accountSuite) TestLogin_Deactivated() {
+	user := suite.user()
+	deactivatedAt := time.Now()
+	user.Deactivated_at = &deactivatedAt
+	suite.repository.On("FindByUsername", mock.Anything, "johndoe").Return(user, nil)
+
+	_, _, err := suite.usecase.HandleLogin(context.TODO(), &domain.User{Username: ptr("johndoe"), Password: ptr("strongpassword")})
+	suite.ErrorIs(err, domain.ErrUserDeactivated)
+}
+
+func (suite *accountSuite) TestCreate_RegistrationClosed() {
+	suite.usecase.(*userUsecase).openRegistration = false
+	user := suite.user()
+	password := "strongpassword"
+	user.Password = &password
+
+	err := suite.usecase.Create(context.TODO(), &user)
+	suite.ErrorIs(err, domain.ErrRegistrationClosed)
+	suite.repository.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.outbox.AssertNotCalled(suite.T(), "Append", mock.Anything, mock.Anything)
+}
+
+func (suite *accountSuite) TestLogin_StartsSessionForClient() {
+	suite.repository.On("FindByUsername", mock.Anything, "johndoe").Return(suite.user(), nil)
+	ctx := domain.WithClient(context.TODO(), domain.ClientInfo{User_agent: "Firefox", Ip: "203.0.113.7"})
+
+	token, _, err := suite.usecase.HandleLogin(ctx, &domain.User{Username: ptr("johndoe"), Password: ptr("strongpassword")})
+	suite.Require().NoError(err)
+
+	suite.sessions.AssertCalled(suite.T(), "Create", mock.Anything, mock.MatchedBy(func(session *domain.Session) bool {
+		return session.User_id == "u1" && session.User_agent == "Firefox" && session.Ip == "203.0.113.7" && session.Expires_at.After(session.Created_at)
+	}))
+	claims, err := infrastructure.ValidateToken(token)
+	suite.Require().NoError(err)
+	suite.NotEmpty(claims.Session_id)
+}
+
+func (suite *accountSuite) TestSwitchOrganization_KeepsSession() {
+	suite.organizations.On("FetchMembership", mock.Anything, "org2", "u1").Return(domain.Membership{Org_id: "org2", User_id: "u1"}, nil)
+	suite.repository.On("FetchByUserID", mock.Anything, "u1").Return(suite.user(), nil)
+
+	token, _, err := suite.usecase.SwitchOrganization(context.TODO(), "u1", "session-1", "org2")
+	suite.Require().NoError(err)
+	suite.sessions.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+
+	claims, err := infrastructure.ValidateToken(token)
+	suite.Require().NoError(err)
+	suite.Equal("session-1", claims.Session_id)
+	suite.Equal("org2", claims.Org_id)
+}
+
+func (suite *accountSuite) TestFetchSessions_MarksCurrent() {
+	current, other := primitive.NewObjectID(), primitive.NewObjectID()
+	suite.sessions.On("FetchActiveByUser", mock.Anything, "u1").Return([]domain.Session{{ID: other}, {ID: current}}, nil)
+
+	sessions, err := suite.usecase.FetchSessions(context.TODO(), "u1", current.Hex())
+	suite.Require().NoError(err)
+	suite.False((*sessions)[0].Current)
+	suite.True((*sessions)[1].Current)
+}
+
+func (suite *accountSuite) TestDeactivate_RevokesSessions() {
+	suite.repository.On("SetDeactivated", mock.Anything, "u1", mock.Anything).Return(nil)
+	suite.sessions.On("RevokeAll", mock.Anything, "u1", mock.Anything).Return(nil)
+
+	suite.NoError(suite.usecase.SetActive(context.TODO(), "u1", false))
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.UserDeactivated) bool {
+		return event.User_id == "u1"
+	}))
+	suite.sessions.AssertExpectations(suite.T())
+}
+
+func (suite *accountSuite) TestDemote_RevokesSessions() {
+	suite.repository.On("SetUserType", mock.Anything, "u1", "USER").Return(nil)
+	suite.sessions.On("RevokeAll", mock.Anything, "u1", mock.Anything).Return(nil)
+
+	suite.NoError(suite.usecase.Demote(context.TODO(), "u1"))
+	suite.outbox.AssertCalled(suite.T(), "Append", mock.Anything, recordedEvent(func(event domain.UserDemoted) bool {
+		return event.User_id == "u1"
+	}))
+	suite.sessions.AssertExpectations(suite.T())
+}
+
+func (suite *accountSuite) TestFetchAll_ClampsPagination() {
+	suite.repository.On("FetchAll", mock.Anything, domain.UserFilter{Query: "john", Page: 1, Limit: 100}).Return([]domain.User{suite.user()}, int64(1), nil)
+
+	page, err := suite.usecase.FetchAll(context.TODO(), domain.UserFilter{Query: "john", Page: 0, Limit: 500})
+	suite.NoError(err)
+	suite.Equal(int64(1), page.Total)
+	suite.Equal(int64(100), page.Limit)
+	suite.Len(page.Users, 1)
+}
+
+func TestAccount(t *testing.T) {
+	suite.Run(t, new(accountSuite))
+}
+
 func TestUserUsecaseSuite(t *testing.T) {
 	suite.Run(t, new(userUsecaseSuite))
 }
@@ -0,0 +1,222 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	domain "task-manger-api_test/Domain"
+	"time"
+)
+
+// viewColumns are the task columns a view may show, besides cf.<key>.
+var viewColumns = []string{
+	"title", "description", "status", "due_date", "assignee", "created_by", "labels",
+	"original_estimate", "remaining_estimate", "completion", "parent",
+}
+
+// defaultViewColumns are shown when a view names none.
+var defaultViewColumns = []string{"title", "status", "due_date", "assignee"}
+
+type viewUsecase struct {
+	viewRepository        domain.ViewRepository
+	taskRepository        domain.TaskRepository
+	customFieldRepository domain.CustomFieldRepository
+	contextTimeout        time.Duration
+	now                   func() time.Time
+}
+
+func NewViewUsecase(viewRepository domain.ViewRepository, taskRepository domain.TaskRepository, customFieldRepository domain.CustomFieldRepository, timeout time.Duration) domain.ViewUsecase {
+	return &viewUsecase{
+		viewRepository:        viewRepository,
+		taskRepository:        taskRepository,
+		customFieldRepository: customFieldRepository,
+		contextTimeout:        timeout,
+		now:                   time.Now,
+	}
+}
+
+func (vu *viewUsecase) Create(c context.Context, view *domain.View) error {
+	ctx, cancel := context.WithTimeout(c, vu.contextTimeout)
+	defer cancel()
+
+	if view.Visibility == "" {
+		view.Visibility = domain.ViewPrivate
+	}
+	if err := vu.check(ctx, view); err != nil {
+		return err
+	}
+	return vu.viewRepository.Create(ctx, view)
+}
+
+// FetchAll lists the user's own views and the shared ones, marking the
+// user's default.
+func (vu *viewUsecase) FetchAll(c context.Context, userID string) (*[]domain.View, error) {
+	ctx, cancel := context.WithTimeout(c, vu.contextTimeout)
+	defer cancel()
+
+	views, err := vu.viewRepository.FetchVisible(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defaultID, err := vu.viewRepository.FetchDefault(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range views {
+		views[i].Default = views[i].ID.Hex() == defaultID
+	}
+	return &views, nil
+}
+
+func (vu *viewUsecase) FetchByID(c context.Context, viewID string, userID string) (*domain.View, error) {
+	ctx, cancel := context.WithTimeout(c, vu.contextTimeout)
+	defer cancel()
+
+	view, err := vu.fetchVisible(ctx, viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (vu *viewUsecase) Update(c context.Context, viewID string, userID string, update domain.ViewUpdate) (*domain.View, error) {
+	ctx, cancel := context.WithTimeout(c, vu.contextTimeout)
+	defer cancel()
+
+	view, err := vu.fetchVisible(ctx, viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if view.Owner_id != userID {
+		return nil, domain.ErrNotViewOwner
+	}
+	if update.Name != nil {
+		view.Name = *update.Name
+	}
+	if update.Filter != nil {
+		view.Filter = *update.Filter
+	}
+	if update.Sort != nil {
+		view.Sort = *update.Sort
+	}
+	if update.Columns != nil {
+		view.Columns = *update.Columns
+	}
+	if update.Visibility != nil {
+		view.Visibility = *update.Visibility
+	}
+	if err := vu.check(ctx, &view); err != nil {
+		return nil, err
+	}
+	view.Updated_at = vu.now()
+	if err := vu.viewRepository.Update(ctx, view); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (vu *viewUsecase) Delete(c context.Context, viewID string, userID string, moderator bool) error {
+	ctx, cancel := context.WithTimeout(c, vu.contextTimeout)
+	defer cancel()
+
+	view, err := vu.fetchVisible(ctx, viewID, userID)
+	if err != nil {
+		return err
+	}
+	if view.Owner_id != userID && !moderator {
+		return domain.ErrNotViewOwner
+	}
+	return vu.viewRepository.Delete(ctx, viewID)
+}
+
+func (vu *viewUsecase) SetDefault(c context.Context, viewID string, userID string) error {
+	ctx, cancel := context.WithTimeout(c, vu.contextTimeout)
+	defer cancel()
+
+	if _, err := vu.fetchVisible(ctx, viewID, userID); err != nil {
+		return err
+	}
+	return vu.viewRepository.SetDefault(ctx, userID, viewID)
+}
+
+func (vu *viewUsecase) ClearDefault(c context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(c, vu.contextTimeout)
+	defer cancel()
+	return vu.viewRepository.ClearDefault(ctx, userID)
+}
+
+func (vu *viewUsecase) FetchTasks(c context.Context, viewID string, userID string) (*domain.ViewTasks, error) {
+	ctx, cancel := context.WithTimeout(c, vu.contextTimeout)
+	defer cancel()
+
+	view, err := vu.fetchVisible(ctx, viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := buildTaskFilter(ctx, vu.customFieldRepository, domain.TaskQuery{Filter: view.Filter, Sort: view.Sort})
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := vu.taskRepository.FetchAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.ViewTasks{View: view, Tasks: []domain.Task{}}
+	if tasks != nil {
+		result.Tasks = append(result.Tasks, *tasks...)
+	}
+	for i := range result.Tasks {
+		fillCompletion(&result.Tasks[i])
+	}
+	return result, nil
+}
+
+// fetchVisible hides other users' private views as if they did not exist.
+func (vu *viewUsecase) fetchVisible(ctx context.Context, viewID string, userID string) (domain.View, error) {
+	view, err := vu.viewRepository.FetchByID(ctx, viewID)
+	if err != nil {
+		return domain.View{}, err
+	}
+	if view.Owner_id != userID && view.Visibility != domain.ViewShared {
+		return domain.View{}, domain.ErrViewNotFound
+	}
+	return view, nil
+}
+
+// check validates the filter and sort the way GET /tasks would, and the
+// columns against the task fields and custom fields.
+func (vu *viewUsecase) check(ctx context.Context, view *domain.View) error {
+	if strings.TrimSpace(view.Name) == "" {
+		return fmt.Errorf("%w: a view needs a name", domain.ErrInvalidView)
+	}
+	if _, err := buildTaskFilter(ctx, vu.customFieldRepository, domain.TaskQuery{Filter: view.Filter, Sort: view.Sort}); err != nil {
+		return err
+	}
+	fields, err := vu.customFieldRepository.FetchAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(view.Columns) == 0 {
+		view.Columns = append([]string{}, defaultViewColumns...)
+	}
+	seen := map[string]bool{}
+	for _, column := range view.Columns {
+		if seen[column] {
+			return fmt.Errorf("%w: column %q is listed twice", domain.ErrInvalidView, column)
+		}
+		seen[column] = true
+		if containsString(viewColumns, column) {
+			continue
+		}
+		key, ok := strings.CutPrefix(column, "cf.")
+		known := false
+		for _, field := range fields {
+			known = known || (ok && field.Key == key)
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown column %q", domain.ErrInvalidView, column)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	domain "task-manger-api_test/Domain"
+	"task-manger-api_test/Domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type viewUsecaseSuite struct {
+	suite.Suite
+	views   *mocks.ViewRepository
+	tasks   *mocks.TaskRepository
+	fields  *mocks.CustomFieldRepository
+	usecase domain.ViewUsecase
+	private domain.View
+	shared  domain.View
+}
+
+func (suite *viewUsecaseSuite) SetupTest() {
+	suite.views = new(mocks.ViewRepository)
+	suite.tasks = new(mocks.TaskRepository)
+	suite.fields = new(mocks.CustomFieldRepository)
+	suite.usecase = NewViewUsecase(suite.views, suite.tasks, suite.fields, 10*time.Second)
+
+	suite.fields.On("FetchAll", mock.Anything).Return([]domain.CustomField{{Key: "priority", Type: domain.CustomFieldEnum, Options: []string{"low", "high"}}}, nil).Maybe()
+	suite.private = domain.View{ID: primitive.NewObjectID(), Owner_id: "owner", Name: "Mine", Filter: "status = todo", Visibility: domain.ViewPrivate}
+	suite.shared = domain.View{ID: primitive.NewObjectID(), Owner_id: "owner", Name: "Team", Filter: "priority = high", Sort: "-due_date", Visibility: domain.ViewShared}
+	suite.views.On("FetchByID", mock.Anything, suite.private.ID.Hex()).Return(suite.private, nil).Maybe()
+	suite.views.On("FetchByID", mock.Anything, suite.shared.ID.Hex()).Return(suite.shared, nil).Maybe()
+}
+
+func (suite *viewUsecaseSuite) TestCreate_DefaultsAndValidation() {
+	view := domain.View{Name: "Hot", Filter: "priority = high", Columns: nil}
+	suite.views.On("Create", mock.Anything, &view).Return(nil)
+
+	suite.Require().NoError(suite.usecase.Create(context.TODO(), &view))
+	suite.Equal(domain.ViewPrivate, view.Visibility)
+	suite.Equal(defaultViewColumns, view.Columns)
+
+	for _, bad := range []domain.View{
+		{Name: "bad filter", Filter: "priority = urgent"},
+		{Name: "bad sort", Sort: "owner"},
+		{Name: "bad column", Columns: []string{"title", "cf.missing"}},
+		{Name: "twice", Columns: []string{"title", "title"}},
+	} {
+		err := suite.usecase.Create(context.TODO(), &bad)
+		suite.Error(err, bad.Name)
+		suite.True(errors.Is(err, domain.ErrInvalidFilter) || errors.Is(err, domain.ErrInvalidTaskQuery) || errors.Is(err, domain.ErrInvalidView), bad.Name)
+	}
+	suite.views.AssertNumberOfCalls(suite.T(), "Create", 1)
+}
+
+func (suite *viewUsecaseSuite) TestPrivateViewsAreHidden() {
+	_, err := suite.usecase.FetchByID(context.TODO(), suite.private.ID.Hex(), "someone")
+	suite.ErrorIs(err, domain.ErrViewNotFound)
+	suite.ErrorIs(suite.usecase.SetDefault(context.TODO(), suite.private.ID.Hex(), "someone"), domain.ErrViewNotFound)
+
+	view, err := suite.usecase.FetchByID(context.TODO(), suite.shared.ID.Hex(), "someone")
+	suite.Require().NoError(err)
+	suite.Equal("Team", view.Name)
+}
+
+func (suite *viewUsecaseSuite) TestUpdateAndDelete_Ownership() {
+	name := "Renamed"
+	_, err := suite.usecase.Update(context.TODO(), suite.shared.ID.Hex(), "someone", domain.ViewUpdate{Name: &name})
+	suite.ErrorIs(err, domain.ErrNotViewOwner)
+	suite.ErrorIs(suite.usecase.Delete(context.TODO(), suite.shared.ID.Hex(), "someone", false), domain.ErrNotViewOwner)
+
+	suite.views.On("Delete", mock.Anything, suite.shared.ID.Hex()).Return(nil)
+	suite.NoError(suite.usecase.Delete(context.TODO(), suite.shared.ID.Hex(), "someone", true), "moderators may remove shared views")
+}
+
+func (suite *viewUsecaseSuite) TestFetchAll_MarksDefault() {
+	suite.views.On("FetchVisible", mock.Anything, "owner").Return([]domain.View{suite.private, suite.shared}, nil)
+	suite.views.On("FetchDefault", mock.Anything, "owner").Return(suite.shared.ID.Hex(), nil)
+
+	views, err := suite.usecase.FetchAll(context.TODO(), "owner")
+	suite.Require().NoError(err)
+	suite.False((*views)[0].Default)
+	suite.True((*views)[1].Default)
+}
+
+func (suite *viewUsecaseSuite) TestFetchTasks_RunsFilterAndSort() {
+	suite.tasks.On("FetchAll", mock.Anything, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+		return filter.Sort_by == "duedate" && filter.Descending && filter.Expression != nil && filter.Expression.Field == "custom_fields.priority"
+	})).Return(&[]domain.Task{{Title: "hot", Checklist: []domain.ChecklistItem{{Done: true}}}}, nil)
+
+	result, err := suite.usecase.FetchTasks(context.TODO(), suite.shared.ID.Hex(), "someone")
+	suite.Require().NoError(err)
+	suite.Equal("Team", result.View.Name)
+	suite.Require().Len(result.Tasks, 1)
+	suite.Equal(100, *result.Tasks[0].Completion)
+}
+
+func TestViewUsecase(t *testing.T) {
+	suite.Run(t, new(viewUsecaseSuite))
+}
@@ -0,0 +1,525 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	domain "task-manger-api_test/Domain"
+	infrastructure "task-manger-api_test/Infrastructure"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultWebhookAttempts     = 5
+	defaultWebhookDisableAfter = 10
+	// maxWebhookAttempts bounds WEBHOOK_MAX_ATTEMPTS.
+	maxWebhookAttempts = 20
+	// webhookRetryBase is the wait before the first retry; each retry
+	// after it waits twice as long as the one before, up to
+	// webhookMaxRetryWait.
+	webhookRetryBase       = 10 * time.Second
+	webhookMaxRetryWait    = time.Hour
+	minWebhookSecretLength = 16
+	defaultDeliveryLimit   = 20
+	maxDeliveryLimit       = 100
+)
+
+type webhookUsecase struct {
+	webhookRepository  domain.WebhookRepository
+	deliveryRepository domain.WebhookDeliveryRepository
+	sender             domain.WebhookSender
+	contextTimeout     time.Duration
+	// maxAttempts bounds the POSTs of one delivery, the first included.
+	maxAttempts int
+	// disableAfter is how many deliveries in a row may fail before the
+	// webhook is disabled.
+	disableAfter int
+	retryBase    time.Duration
+	now          func() time.Time
+	// run starts deliveries in the background and sleep waits between
+	// retries; tests replace both.
+	run   func(func())
+	sleep func(time.Duration)
+}
+
+func NewWebhookUsecase(webhookRepository domain.WebhookRepository, deliveryRepository domain.WebhookDeliveryRepository, sender domain.WebhookSender, timeout time.Duration) domain.WebhookUsecase {
+	return &webhookUsecase{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		sender:             sender,
+		contextTimeout:     timeout,
+		maxAttempts:        min(positiveIntFromEnv("WEBHOOK_MAX_ATTEMPTS", defaultWebhookAttempts), maxWebhookAttempts),
+		disableAfter:       positiveIntFromEnv("WEBHOOK_DISABLE_AFTER", defaultWebhookDisableAfter),
+		retryBase:          webhookRetryBase,
+		now:                time.Now,
+		run:                func(f func()) { go f() },
+		sleep:              time.Sleep,
+	}
+}
+
+// positiveIntFromEnv reads a positive integer setting, falling back when it
+// is unset or invalid.
+func positiveIntFromEnv(name string, fallback int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		log.Printf("ignoring invalid %s %q", name, raw)
+		return fallback
+	}
+	return value
+}
+
+// Create subscribes a new webhook, generating a secret when none is given.
+// The secret is returned this once.
+func (wu *webhookUsecase) Create(c context.Context, webhook *domain.Webhook) error {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+
+	if err := checkWebhook(webhook.URL, webhook.Events); err != nil {
+		return err
+	}
+	if err := wu.sender.Check(ctx, webhook.URL); err != nil {
+		return err
+	}
+	webhook.Events = uniqueStrings(webhook.Events)
+	if webhook.Secret == "" {
+		secret, _, err := infrastructure.NewOpaqueToken()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	} else if err := checkWebhookSecret(webhook.Secret); err != nil {
+		return err
+	}
+	webhook.Active = true
+	webhook.Failure_count = 0
+	webhook.Disabled_at = nil
+	webhook.Disabled_reason = ""
+	return wu.webhookRepository.Create(ctx, webhook)
+}
+
+func (wu *webhookUsecase) FetchAll(c context.Context) (*[]domain.Webhook, error) {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+
+	webhooks, err := wu.webhookRepository.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return &webhooks, nil
+}
+
+func (wu *webhookUsecase) FetchByID(c context.Context, webhookID string) (*domain.Webhook, error) {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+
+	webhook, err := wu.webhookRepository.FetchByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return &webhook, nil
+}
+
+// Update applies the fields that are set. Enabling a webhook clears its
+// failures; a new secret is returned once, like on creation.
+func (wu *webhookUsecase) Update(c context.Context, webhookID string, update domain.WebhookUpdate) (*domain.Webhook, error) {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+
+	webhook, err := wu.webhookRepository.FetchByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if update.URL != nil {
+		webhook.URL = *update.URL
+	}
+	if update.Events != nil {
+		webhook.Events = *update.Events
+	}
+	if err := checkWebhook(webhook.URL, webhook.Events); err != nil {
+		return nil, err
+	}
+	if update.URL != nil {
+		if err := wu.sender.Check(ctx, webhook.URL); err != nil {
+			return nil, err
+		}
+	}
+	webhook.Events = uniqueStrings(webhook.Events)
+	if update.Secret != nil {
+		if err := checkWebhookSecret(*update.Secret); err != nil {
+			return nil, err
+		}
+		webhook.Secret = *update.Secret
+	}
+	now := wu.now()
+	if update.Active != nil && *update.Active != webhook.Active {
+		webhook.Active = *update.Active
+		webhook.Failure_count = 0
+		webhook.Disabled_at = nil
+		webhook.Disabled_reason = ""
+		if !webhook.Active {
+			webhook.Disabled_at = &now
+			webhook.Disabled_reason = "disabled by a user"
+		}
+	}
+	webhook.Updated_at = now
+	if err := wu.webhookRepository.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	if update.Secret == nil {
+		webhook.Secret = ""
+	}
+	return &webhook, nil
+}
+
+// Delete removes the webhook and its delivery log. Retries already waiting
+// notice it is gone and stop.
+func (wu *webhookUsecase) Delete(c context.Context, webhookID string) error {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+
+	if err := wu.webhookRepository.Delete(ctx, webhookID); err != nil {
+		return err
+	}
+	return wu.deliveryRepository.DeleteByWebhook(ctx, webhookID)
+}
+
+func (wu *webhookUsecase) FetchDeliveries(c context.Context, webhookID string, limit int64) (*[]domain.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+
+	if _, err := wu.webhookRepository.FetchByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	if limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+	deliveries, err := wu.deliveryRepository.FetchByWebhook(ctx, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &deliveries, nil
+}
+
+// Redeliver queues the payload of a past delivery again, with the same
+// event ID, signed with the webhook's current secret.
+func (wu *webhookUsecase) Redeliver(c context.Context, webhookID string, deliveryID string) (*domain.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+
+	webhook, err := wu.webhookRepository.FetchByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Active {
+		return nil, domain.ErrWebhookDisabled
+	}
+	original, err := wu.deliveryRepository.FetchByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.Webhook_id != webhookID {
+		return nil, domain.ErrWebhookDeliveryNotFound
+	}
+
+	delivery := domain.WebhookDelivery{
+		Webhook_id:    webhookID,
+		Event_id:      original.Event_id,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        domain.DeliveryPending,
+		Redelivery_of: original.ID.Hex(),
+	}
+	if err := wu.deliveryRepository.Create(ctx, &delivery); err != nil {
+		return nil, err
+	}
+	queued := delivery
+	background := domain.WithTenant(context.Background(), webhook.Org_id)
+	wu.run(func() { wu.deliver(background, webhook, queued) })
+	return &delivery, nil
+}
+
+// SubscribeWebhooks forwards the task events published on bus to the
+// webhooks subscribed to them. The webhook event keeps the domain event's
+// ID. The handler runs synchronously and fails when Dispatch could not
+// record every delivery, so the outbox relay publishes the event again
+// rather than marking it delivered.
+func SubscribeWebhooks(bus domain.EventBus, dispatcher domain.WebhookDispatcher) {
+	handler := func(c context.Context, event domain.Event) error {
+		webhookEvent := domain.WebhookEvent{ID: event.ID, Type: event.Payload.EventName(), Occurred_at: event.Occurred_at}
+		switch payload := event.Payload.(type) {
+		case domain.TaskCreated:
+			webhookEvent.Task = payload.Task
+		case domain.TaskUpdated:
+			webhookEvent.Task = payload.Task
+		case domain.TaskStatusChanged:
+			webhookEvent.Task = payload.Task
+			webhookEvent.Previous_status = payload.Previous_status
+		case domain.TaskDeleted:
+			webhookEvent.Task = payload.Task
+		default:
+			return fmt.Errorf("no webhook payload for %s", webhookEvent.Type)
+		}
+		return dispatcher.Dispatch(c, webhookEvent)
+	}
+	for _, name := range domain.TaskEvents {
+		bus.Subscribe(name, handler)
+	}
+}
+
+// Dispatch records a delivery for every webhook subscribed to the event
+// before returning, then sends them in the background, away from the
+// request that raised it. Webhooks that already have a delivery of the
+// event are skipped, so an event dispatched again after an error only
+// reaches the webhooks it missed.
+func (wu *webhookUsecase) Dispatch(c context.Context, event domain.WebhookEvent) error {
+	orgID, ok := domain.TenantFromContext(c)
+	if !ok {
+		return nil
+	}
+	if event.ID == "" {
+		event.ID = primitive.NewObjectID().Hex()
+	}
+	if event.Occurred_at.IsZero() {
+		event.Occurred_at = wu.now()
+	}
+	event.Org_id = orgID
+
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+	webhooks, err := wu.webhookRepository.FetchSubscribed(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	background := domain.WithTenant(context.Background(), orgID)
+	for _, webhook := range webhooks {
+		_, err := wu.deliveryRepository.FetchByEvent(ctx, webhook.ID.Hex(), event.ID)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, domain.ErrWebhookDeliveryNotFound) {
+			return err
+		}
+		delivery := domain.WebhookDelivery{
+			Webhook_id: webhook.ID.Hex(),
+			Event_id:   event.ID,
+			Event:      event.Type,
+			Payload:    string(payload),
+			Status:     domain.DeliveryPending,
+		}
+		err = wu.deliveryRepository.Create(ctx, &delivery)
+		if errors.Is(err, domain.ErrWebhookDeliveryExists) {
+			// another relay got to it first
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		webhook, delivery := webhook, delivery
+		wu.run(func() { wu.deliver(background, webhook, delivery) })
+	}
+	return nil
+}
+
+// ResumePending picks up the deliveries still pending from before the
+// process started, which a restart cut off, carrying on from their last
+// attempt. Another instance may be sending some of them still; receivers
+// tell the repeats apart by X-Webhook-Id.
+func (wu *webhookUsecase) ResumePending(c context.Context, startedAt time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+
+	deliveries, err := wu.deliveryRepository.FetchPending(ctx, startedAt)
+	if err != nil {
+		return 0, err
+	}
+	resumed := 0
+	for _, delivery := range deliveries {
+		background := domain.WithTenant(context.Background(), delivery.Org_id)
+		webhook, err := wu.fetchWebhook(background, delivery.Webhook_id)
+		if errors.Is(err, domain.ErrWebhookNotFound) || (err == nil && !webhook.Active) {
+			wu.finish(background, &delivery, domain.DeliveryFailed)
+			continue
+		}
+		if err != nil {
+			log.Printf("failed to resume delivery %s: %v", delivery.ID.Hex(), err)
+			continue
+		}
+		delivery := delivery
+		wu.run(func() { wu.deliver(background, webhook, delivery) })
+		resumed++
+	}
+	return resumed, nil
+}
+
+// deliver POSTs the delivery until the webhook answers with a 2xx status
+// or maxAttempts is reached, backing off exponentially between attempts.
+// Every attempt is added to the delivery log. Before each retry the
+// webhook is read again, so edits apply and deleted or disabled webhooks
+// stop receiving.
+func (wu *webhookUsecase) deliver(c context.Context, webhook domain.Webhook, delivery domain.WebhookDelivery) {
+	// resumed deliveries carry on after the attempts they already made
+	for attempt := len(delivery.Attempts); attempt < wu.maxAttempts; attempt++ {
+		if attempt > 0 {
+			wu.sleep(wu.retryWait(attempt))
+			current, err := wu.fetchWebhook(c, webhook.ID.Hex())
+			if errors.Is(err, domain.ErrWebhookNotFound) || (err == nil && !current.Active) {
+				wu.finish(c, &delivery, domain.DeliveryFailed)
+				return
+			}
+			if err == nil {
+				webhook = current
+			}
+		}
+
+		result := wu.attempt(c, webhook, delivery)
+		delivery.Attempts = append(delivery.Attempts, result)
+		if result.Error == "" && result.Response_code >= 200 && result.Response_code < 300 {
+			wu.finish(c, &delivery, domain.DeliverySucceeded)
+			ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+			if err := wu.webhookRepository.ResetFailures(ctx, webhook.ID.Hex()); err != nil {
+				log.Printf("failed to reset failures of webhook %s: %v", webhook.ID.Hex(), err)
+			}
+			cancel()
+			return
+		}
+		if attempt < wu.maxAttempts-1 {
+			wu.save(c, delivery)
+		}
+	}
+
+	wu.finish(c, &delivery, domain.DeliveryFailed)
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+	updated, err := wu.webhookRepository.RecordFailure(ctx, webhook.ID.Hex(), wu.disableAfter, wu.now())
+	if err != nil {
+		log.Printf("failed to record failure of webhook %s: %v", webhook.ID.Hex(), err)
+		return
+	}
+	if !updated.Active && webhook.Active {
+		log.Printf("disabled webhook %s: %s", webhook.ID.Hex(), updated.Disabled_reason)
+	}
+}
+
+// retryWait is the back-off before the given retry.
+func (wu *webhookUsecase) retryWait(retry int) time.Duration {
+	wait := wu.retryBase
+	for i := 1; i < retry && wait < webhookMaxRetryWait; i++ {
+		wait *= 2
+	}
+	if wait > webhookMaxRetryWait {
+		return webhookMaxRetryWait
+	}
+	return wait
+}
+
+func (wu *webhookUsecase) attempt(c context.Context, webhook domain.Webhook, delivery domain.WebhookDelivery) domain.WebhookAttempt {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+
+	at := wu.now()
+	body := []byte(delivery.Payload)
+	headers := map[string]string{
+		"X-Webhook-Id":        delivery.Event_id,
+		"X-Webhook-Event":     delivery.Event,
+		"X-Webhook-Delivery":  delivery.ID.Hex(),
+		"X-Webhook-Timestamp": strconv.FormatInt(at.Unix(), 10),
+		"X-Webhook-Signature": infrastructure.SignWebhook(webhook.Secret, at, body),
+	}
+	resp, err := wu.sender.Send(ctx, webhook.URL, headers, body)
+	result := domain.WebhookAttempt{
+		At:            at,
+		Response_code: resp.Status_code,
+		Response_body: resp.Body,
+		Duration_ms:   wu.now().Sub(at).Milliseconds(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (wu *webhookUsecase) fetchWebhook(c context.Context, webhookID string) (domain.Webhook, error) {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+	return wu.webhookRepository.FetchByID(ctx, webhookID)
+}
+
+func (wu *webhookUsecase) finish(c context.Context, delivery *domain.WebhookDelivery, status string) {
+	now := wu.now()
+	delivery.Status = status
+	delivery.Completed_at = &now
+	wu.save(c, *delivery)
+}
+
+func (wu *webhookUsecase) save(c context.Context, delivery domain.WebhookDelivery) {
+	ctx, cancel := context.WithTimeout(c, wu.contextTimeout)
+	defer cancel()
+	if err := wu.deliveryRepository.Update(ctx, delivery); err != nil {
+		log.Printf("failed to record delivery %s: %v", delivery.ID.Hex(), err)
+	}
+}
+
+// checkWebhook accepts absolute http(s) URLs and known task events.
+func checkWebhook(rawURL string, events []string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: the URL must be an absolute http or https URL", domain.ErrInvalidWebhook)
+	}
+	if target.User != nil {
+		return fmt.Errorf("%w: put credentials in the secret, not the URL", domain.ErrInvalidWebhook)
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("%w: subscribe to at least one event", domain.ErrInvalidWebhook)
+	}
+	for _, event := range events {
+		if !containsString(domain.TaskEvents, event) {
+			return fmt.Errorf("%w: unknown event %q", domain.ErrInvalidWebhook, event)
+		}
+	}
+	return nil
+}
+
+func checkWebhookSecret(secret string) error {
+	if len(secret) < minWebhookSecretLength || len(secret) > 200 {
+		return fmt.Errorf("%w: secrets are %d to 200 characters long", domain.ErrInvalidWebhook, minWebhookSecretLength)
+	}
+	return nil
+}
+
+// uniqueStrings drops repeated values, keeping the first of each.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
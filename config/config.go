@@ -29,7 +29,6 @@ func GetConfig() *domain.Config {
 		MongoDBURI: "mongodb://localhost:27017/taskmanager",
 		Port:       port,
 		TimeZone:   "Asia/Jakarta",
-		SecretKey:  os.Getenv("SECRET_KEY"),
 		DatabaseName: "test_db",
 	}
 